@@ -14,18 +14,25 @@ const (
 	SyncTopic0Hex = "0xcf2aa50876cdfbb541206f89af0ee78d44a2abf8d328e37fa4917f982149848a"
 	BurnTopic0Hex = "0x5d624aa9c148153ab3446c1b154f660ee7701e549fe9b62dab7171b1c80e6fa2"
 	MintTopic0Hex = "0x4c209b5fc8ad50758f13e2e1088ba56a560dff690a1c6fef26394f4c03821c4f"
+	// the fees of a swap, kept apart from the reserves, and the fees claimed by an LP
+	FeesTopic0Hex  = "0x112c256902bf554b6ed882d2936687aaeb4225e8cd5b51303c90ca6cf43a8602"
+	ClaimTopic0Hex = "0x865ca08d59f5cb456e85cd2f7ef63664ea4f73327414e9d8152c4158b0e94645"
 )
 
 var (
-	PairAbi    *abi.ABI
-	SwapTopic0 = common.HexToHash(SwapTopic0Hex)
-	SwapEvent  *abi.Event
-	SyncTopic0 = common.HexToHash(SyncTopic0Hex)
-	SyncEvent  *abi.Event
-	BurnTopic0 = common.HexToHash(BurnTopic0Hex)
-	BurnEvent  *abi.Event
-	MintTopic0 = common.HexToHash(MintTopic0Hex)
-	MintEvent  *abi.Event
+	PairAbi     *abi.ABI
+	SwapTopic0  = common.HexToHash(SwapTopic0Hex)
+	SwapEvent   *abi.Event
+	SyncTopic0  = common.HexToHash(SyncTopic0Hex)
+	SyncEvent   *abi.Event
+	BurnTopic0  = common.HexToHash(BurnTopic0Hex)
+	BurnEvent   *abi.Event
+	MintTopic0  = common.HexToHash(MintTopic0Hex)
+	MintEvent   *abi.Event
+	FeesTopic0  = common.HexToHash(FeesTopic0Hex)
+	FeesEvent   *abi.Event
+	ClaimTopic0 = common.HexToHash(ClaimTopic0Hex)
+	ClaimEvent  *abi.Event
 )
 
 func init() {
@@ -58,4 +65,16 @@ func init() {
 		log.Logger.Fatal("Failed to find MintTopic0", zap.Error(err))
 	}
 	MintEvent = mintEvent
+
+	feesEvent, err := pairAbi.EventByID(FeesTopic0)
+	if err != nil {
+		log.Logger.Fatal("Failed to find FeesTopic0", zap.Error(err))
+	}
+	FeesEvent = feesEvent
+
+	claimEvent, err := pairAbi.EventByID(ClaimTopic0)
+	if err != nil {
+		log.Logger.Fatal("Failed to find ClaimTopic0", zap.Error(err))
+	}
+	ClaimEvent = claimEvent
 }
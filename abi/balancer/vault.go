@@ -0,0 +1,43 @@
+package balancer
+
+import (
+	"base_scan/log"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+	"strings"
+)
+
+// the Balancer V2 Vault holds the tokens of every pool and emits their swaps
+const (
+	VaultAbiJson    = `[{"anonymous":false,"inputs":[{"indexed":true,"internalType":"bytes32","name":"poolId","type":"bytes32"},{"indexed":true,"internalType":"contract IERC20","name":"tokenIn","type":"address"},{"indexed":true,"internalType":"contract IERC20","name":"tokenOut","type":"address"},{"indexed":false,"internalType":"uint256","name":"amountIn","type":"uint256"},{"indexed":false,"internalType":"uint256","name":"amountOut","type":"uint256"}],"name":"Swap","type":"event"},{"inputs":[{"internalType":"bytes32","name":"poolId","type":"bytes32"}],"name":"getPoolTokens","outputs":[{"internalType":"contract IERC20[]","name":"tokens","type":"address[]"},{"internalType":"uint256[]","name":"balances","type":"uint256[]"},{"internalType":"uint256","name":"lastChangeBlock","type":"uint256"}],"stateMutability":"view","type":"function"}]`
+	VaultAddressHex = "0xBA12222222228d8Ba445958a75a0704d566BF2C8"
+	SwapTopic0Hex   = "0x2170c741c41531aec20e7c107c24eecfdd15e69c9bb0a8dd37b1840b9e0b207b"
+)
+
+var (
+	VaultAbi *abi.ABI
+	// the same on every chain
+	VaultAddress = common.HexToAddress(VaultAddressHex)
+	SwapTopic0   = common.HexToHash(SwapTopic0Hex)
+	SwapEvent    *abi.Event
+)
+
+// PoolAddress returns the pool of a pool id, its first 20 bytes.
+func PoolAddress(poolId common.Hash) common.Address {
+	return common.BytesToAddress(poolId[:common.AddressLength])
+}
+
+func init() {
+	vaultAbi, err := abi.JSON(strings.NewReader(VaultAbiJson))
+	if err != nil {
+		log.Logger.Fatal("Failed to parse vault ABI", zap.Error(err))
+	}
+	VaultAbi = &vaultAbi
+
+	swapEvent, err := vaultAbi.EventByID(SwapTopic0)
+	if err != nil {
+		log.Logger.Fatal("Failed to find SwapTopic0", zap.Error(err))
+	}
+	SwapEvent = swapEvent
+}
@@ -0,0 +1,59 @@
+package curve
+
+import (
+	"base_scan/log"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+	"strings"
+)
+
+/*
+the TokenExchange events of the curve pools, the coins are identified by their index in the pool, see coins
+- stableswap: plain and ng stableswap pools, int128 indexes
+- cryptoswap: v2 cryptoswap pools
+- cryptoswap ng: twocrypto-ng and tricrypto-ng pools, with the fee and the packed price scale
+*/
+const (
+	PoolAbiJson              = `[{"anonymous":false,"inputs":[{"indexed":true,"name":"buyer","type":"address"},{"indexed":false,"name":"sold_id","type":"int128"},{"indexed":false,"name":"tokens_sold","type":"uint256"},{"indexed":false,"name":"bought_id","type":"int128"},{"indexed":false,"name":"tokens_bought","type":"uint256"}],"name":"TokenExchange","type":"event"},{"anonymous":false,"inputs":[{"indexed":true,"name":"buyer","type":"address"},{"indexed":false,"name":"sold_id","type":"uint256"},{"indexed":false,"name":"tokens_sold","type":"uint256"},{"indexed":false,"name":"bought_id","type":"uint256"},{"indexed":false,"name":"tokens_bought","type":"uint256"}],"name":"TokenExchange","type":"event"},{"anonymous":false,"inputs":[{"indexed":true,"name":"buyer","type":"address"},{"indexed":false,"name":"sold_id","type":"uint256"},{"indexed":false,"name":"tokens_sold","type":"uint256"},{"indexed":false,"name":"bought_id","type":"uint256"},{"indexed":false,"name":"tokens_bought","type":"uint256"},{"indexed":false,"name":"fee","type":"uint256"},{"indexed":false,"name":"packed_price_scale","type":"uint256"}],"name":"TokenExchange","type":"event"},{"inputs":[{"name":"arg0","type":"uint256"}],"name":"coins","outputs":[{"name":"","type":"address"}],"stateMutability":"view","type":"function"}]`
+	TokenExchangeTopic0Hex   = "0x8b3e96f2b889fa771c53c981b40daf005f63f637f1869f707052d15a3dd97140"
+	TokenExchangeV2Topic0Hex = "0xb2e76ae99761dc136e598d4a629bb347eccb9532a5f8bbd72e18467c3c34cc98"
+	TokenExchangeNGTopic0Hex = "0x143f1f8e861fbdeddd5b46e844b7d3ac7b86a122f36e8c463859ee6811b1f29c"
+	MaxCoins                 = 8
+)
+
+var (
+	PoolAbi               *abi.ABI
+	TokenExchangeTopic0   = common.HexToHash(TokenExchangeTopic0Hex)
+	TokenExchangeEvent    *abi.Event
+	TokenExchangeV2Topic0 = common.HexToHash(TokenExchangeV2Topic0Hex)
+	TokenExchangeV2Event  *abi.Event
+	TokenExchangeNGTopic0 = common.HexToHash(TokenExchangeNGTopic0Hex)
+	TokenExchangeNGEvent  *abi.Event
+)
+
+func init() {
+	poolAbi, err := abi.JSON(strings.NewReader(PoolAbiJson))
+	if err != nil {
+		log.Logger.Fatal("Failed to parse curve pool ABI", zap.Error(err))
+	}
+	PoolAbi = &poolAbi
+
+	tokenExchangeEvent, err := poolAbi.EventByID(TokenExchangeTopic0)
+	if err != nil {
+		log.Logger.Fatal("Failed to find TokenExchangeTopic0", zap.Error(err))
+	}
+	TokenExchangeEvent = tokenExchangeEvent
+
+	tokenExchangeV2Event, err := poolAbi.EventByID(TokenExchangeV2Topic0)
+	if err != nil {
+		log.Logger.Fatal("Failed to find TokenExchangeV2Topic0", zap.Error(err))
+	}
+	TokenExchangeV2Event = tokenExchangeV2Event
+
+	tokenExchangeNGEvent, err := poolAbi.EventByID(TokenExchangeNGTopic0)
+	if err != nil {
+		log.Logger.Fatal("Failed to find TokenExchangeNGTopic0", zap.Error(err))
+	}
+	TokenExchangeNGEvent = tokenExchangeNGEvent
+}
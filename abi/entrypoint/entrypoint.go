@@ -0,0 +1,52 @@
+package entrypoint
+
+import (
+	"base_scan/log"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+	"strings"
+)
+
+// the events EntryPoint v0.6, v0.7 and v0.8 share
+const (
+	EntryPointAbiJson           = `[{"anonymous":false,"inputs":[],"name":"BeforeExecution","type":"event"},{"anonymous":false,"inputs":[{"indexed":true,"internalType":"bytes32","name":"userOpHash","type":"bytes32"},{"indexed":true,"internalType":"address","name":"sender","type":"address"},{"indexed":true,"internalType":"address","name":"paymaster","type":"address"},{"indexed":false,"internalType":"uint256","name":"nonce","type":"uint256"},{"indexed":false,"internalType":"bool","name":"success","type":"bool"},{"indexed":false,"internalType":"uint256","name":"actualGasCost","type":"uint256"},{"indexed":false,"internalType":"uint256","name":"actualGasUsed","type":"uint256"}],"name":"UserOperationEvent","type":"event"}]`
+	UserOperationEventTopic0Hex = "0x49628fd1471006c1482da88028e9ce4dbb080b815c9b0344d39e5a8e6ec1419f"
+	BeforeExecutionTopic0Hex    = "0xbb47ee3e183a558b1a2ff0874b079f3fc5478b7454eacf2bfc5af2ff5878f972"
+)
+
+var (
+	EntryPointAbi            *abi.ABI
+	UserOperationEventTopic0 = common.HexToHash(UserOperationEventTopic0Hex)
+	UserOperationEvent       *abi.Event
+	BeforeExecutionTopic0    = common.HexToHash(BeforeExecutionTopic0Hex)
+
+	// the same on every chain
+	EntryPointV06Address = common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
+	EntryPointV07Address = common.HexToAddress("0x0000000071727De22E5E9d8BAf0edAc6f37da032")
+	EntryPointV08Address = common.HexToAddress("0x4337084D9E255Ff0702461CF8895CE9E3b5Ff108")
+
+	EntryPointAddresses = map[common.Address]struct{}{
+		EntryPointV06Address: {},
+		EntryPointV07Address: {},
+		EntryPointV08Address: {},
+	}
+)
+
+func init() {
+	entryPointAbi, err := abi.JSON(strings.NewReader(EntryPointAbiJson))
+	if err != nil {
+		log.Logger.Fatal("Failed to parse entry point ABI", zap.Error(err))
+	}
+	EntryPointAbi = &entryPointAbi
+
+	userOperationEvent, err := entryPointAbi.EventByID(UserOperationEventTopic0)
+	if err != nil {
+		log.Logger.Fatal("Failed to find UserOperationEventTopic0", zap.Error(err))
+	}
+	UserOperationEvent = userOperationEvent
+
+	if _, err = entryPointAbi.EventByID(BeforeExecutionTopic0); err != nil {
+		log.Logger.Fatal("Failed to find BeforeExecutionTopic0", zap.Error(err))
+	}
+}
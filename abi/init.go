@@ -2,17 +2,21 @@ package abi
 
 import (
 	"base_scan/abi/aerodrome"
+	"base_scan/abi/balancer"
+	"base_scan/abi/curve"
 	pancakev2 "base_scan/abi/pancake/v2"
 	pancakev3 "base_scan/abi/pancake/v3"
 	uniswapv2 "base_scan/abi/uniswap/v2"
 	uniswapv3 "base_scan/abi/uniswap/v3"
+	"base_scan/abi/wow"
 	"base_scan/types"
 	"github.com/ethereum/go-ethereum/common"
 )
 
 var Topic2ProtocolIds = map[common.Hash][]int{}
-var FactoryAddress2ProtocolId = map[common.Address]int{}
-var Topic2FactoryAddresses = map[common.Hash]map[common.Address]struct{}{}
+
+// BaseFactoryProtocolIds are the factories deployed on base
+var BaseFactoryProtocolIds = map[common.Address]int{}
 
 func mapTopicToProtocolId(topic common.Hash, protocolId int) {
 	protocolIds, ok := Topic2ProtocolIds[topic]
@@ -23,13 +27,21 @@ func mapTopicToProtocolId(topic common.Hash, protocolId int) {
 	Topic2ProtocolIds[topic] = protocolIds
 }
 
-func mapTopicToFactoryAddress(topic common.Hash, factoryAddress common.Address) {
-	factoryAddresses, ok := Topic2FactoryAddresses[topic]
-	if !ok {
-		factoryAddresses = make(map[common.Address]struct{})
+/*
+FactoryProtocolIdsOfTopic returns the factories emitting the pair created event topic,
+by the protocols the topic belongs to, a fork factory emits the topics of the protocol it forks.
+*/
+func FactoryProtocolIdsOfTopic(topic common.Hash, factoryProtocolIds map[common.Address]int) map[common.Address]int {
+	topicFactoryProtocolIds := make(map[common.Address]int)
+	for factory, protocolId := range factoryProtocolIds {
+		for _, topicProtocolId := range Topic2ProtocolIds[topic] {
+			if topicProtocolId == types.BaseProtocolId(protocolId) {
+				topicFactoryProtocolIds[factory] = protocolId
+				break
+			}
+		}
 	}
-	factoryAddresses[factoryAddress] = struct{}{}
-	Topic2FactoryAddresses[topic] = factoryAddresses
+	return topicFactoryProtocolIds
 }
 
 func init() {
@@ -43,6 +55,7 @@ func init() {
 	mapTopicToProtocolId(uniswapv3.SwapTopic0, types.ProtocolIdUniswapV3)
 	mapTopicToProtocolId(uniswapv3.MintTopic0, types.ProtocolIdUniswapV3)
 	mapTopicToProtocolId(uniswapv3.BurnTopic0, types.ProtocolIdUniswapV3)
+	mapTopicToProtocolId(uniswapv3.InitializeTopic0, types.ProtocolIdUniswapV3)
 
 	mapTopicToProtocolId(pancakev2.PairCreatedTopic0, types.ProtocolIdPancakeV2)
 	mapTopicToProtocolId(pancakev2.SwapTopic0, types.ProtocolIdPancakeV2)
@@ -54,22 +67,32 @@ func init() {
 	mapTopicToProtocolId(pancakev3.SwapTopic0, types.ProtocolIdPancakeV3)
 	mapTopicToProtocolId(pancakev3.MintTopic0, types.ProtocolIdPancakeV3)
 	mapTopicToProtocolId(pancakev3.BurnTopic0, types.ProtocolIdPancakeV3)
+	mapTopicToProtocolId(pancakev3.InitializeTopic0, types.ProtocolIdPancakeV3)
 
 	mapTopicToProtocolId(aerodrome.PoolCreatedTopic0, types.ProtocolIdAerodrome)
 	mapTopicToProtocolId(aerodrome.SwapTopic0, types.ProtocolIdAerodrome)
 	mapTopicToProtocolId(aerodrome.SyncTopic0, types.ProtocolIdAerodrome)
 	mapTopicToProtocolId(aerodrome.BurnTopic0, types.ProtocolIdAerodrome)
 	mapTopicToProtocolId(aerodrome.MintTopic0, types.ProtocolIdAerodrome)
+	mapTopicToProtocolId(aerodrome.SetCustomFeeTopic0, types.ProtocolIdAerodrome)
+	mapTopicToProtocolId(aerodrome.FeesTopic0, types.ProtocolIdAerodrome)
+	mapTopicToProtocolId(aerodrome.ClaimTopic0, types.ProtocolIdAerodrome)
+
+	mapTopicToProtocolId(curve.TokenExchangeTopic0, types.ProtocolIdCurve)
+	mapTopicToProtocolId(curve.TokenExchangeV2Topic0, types.ProtocolIdCurve)
+	mapTopicToProtocolId(curve.TokenExchangeNGTopic0, types.ProtocolIdCurve)
+
+	mapTopicToProtocolId(balancer.SwapTopic0, types.ProtocolIdBalancerV2)
 
-	FactoryAddress2ProtocolId[uniswapv2.FactoryAddress] = types.ProtocolIdUniswapV2
-	FactoryAddress2ProtocolId[uniswapv3.FactoryAddress] = types.ProtocolIdUniswapV3
-	FactoryAddress2ProtocolId[pancakev2.FactoryAddress] = types.ProtocolIdPancakeV2
-	FactoryAddress2ProtocolId[pancakev3.FactoryAddress] = types.ProtocolIdPancakeV3
-	FactoryAddress2ProtocolId[aerodrome.FactoryAddress] = types.ProtocolIdAerodrome
+	mapTopicToProtocolId(wow.WowTokenCreatedTopic0, types.ProtocolIdWow)
+	mapTopicToProtocolId(wow.WowTokenBuyTopic0, types.ProtocolIdWow)
+	mapTopicToProtocolId(wow.WowTokenSellTopic0, types.ProtocolIdWow)
+	mapTopicToProtocolId(wow.WowMarketGraduatedTopic0, types.ProtocolIdWow)
 
-	mapTopicToFactoryAddress(uniswapv2.PairCreatedTopic0, uniswapv2.FactoryAddress)
-	mapTopicToFactoryAddress(uniswapv3.PoolCreatedTopic0, uniswapv3.FactoryAddress)
-	mapTopicToFactoryAddress(pancakev2.PairCreatedTopic0, pancakev2.FactoryAddress)
-	mapTopicToFactoryAddress(pancakev3.PoolCreatedTopic0, pancakev3.FactoryAddress)
-	mapTopicToFactoryAddress(aerodrome.PoolCreatedTopic0, aerodrome.FactoryAddress)
+	BaseFactoryProtocolIds[uniswapv2.FactoryAddress] = types.ProtocolIdUniswapV2
+	BaseFactoryProtocolIds[uniswapv3.FactoryAddress] = types.ProtocolIdUniswapV3
+	BaseFactoryProtocolIds[pancakev2.FactoryAddress] = types.ProtocolIdPancakeV2
+	BaseFactoryProtocolIds[pancakev3.FactoryAddress] = types.ProtocolIdPancakeV3
+	BaseFactoryProtocolIds[aerodrome.FactoryAddress] = types.ProtocolIdAerodrome
+	BaseFactoryProtocolIds[wow.FactoryAddress] = types.ProtocolIdWow
 }
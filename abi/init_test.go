@@ -1,7 +1,12 @@
 package abi
 
 import (
+	"base_scan/abi/aerodrome"
+	pancakev2 "base_scan/abi/pancake/v2"
+	uniswapv2 "base_scan/abi/uniswap/v2"
+	"base_scan/types"
 	"encoding/json"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/require"
 	"testing"
 )
@@ -11,11 +16,18 @@ func TestInit(t *testing.T) {
 	require.Nil(t, e)
 	t.Log(string(b))
 
-	b, e = json.Marshal(FactoryAddress2ProtocolId)
+	b, e = json.Marshal(BaseFactoryProtocolIds)
 	require.Nil(t, e)
 	t.Log(string(b))
+}
 
-	b, e = json.Marshal(Topic2FactoryAddresses)
-	require.Nil(t, e)
-	t.Log(string(b))
+func TestFactoryProtocolIdsOfTopic(t *testing.T) {
+	factoryProtocolIds := FactoryProtocolIdsOfTopic(uniswapv2.PairCreatedTopic0, BaseFactoryProtocolIds)
+	require.Equal(t, map[common.Address]int{
+		uniswapv2.FactoryAddress: types.ProtocolIdUniswapV2,
+		pancakev2.FactoryAddress: types.ProtocolIdPancakeV2,
+	}, factoryProtocolIds)
+
+	factoryProtocolIds = FactoryProtocolIdsOfTopic(aerodrome.PoolCreatedTopic0, BaseFactoryProtocolIds)
+	require.Equal(t, map[common.Address]int{aerodrome.FactoryAddress: types.ProtocolIdAerodrome}, factoryProtocolIds)
 }
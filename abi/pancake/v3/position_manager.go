@@ -0,0 +1,12 @@
+package v3
+
+import "github.com/ethereum/go-ethereum/common"
+
+// the position manager deployed on base, its events are the ones of uniswap v3
+const (
+	PositionManagerAddressHex = "0x46A15B0b27311cedF172AB29E4f4766fbE7F4364"
+)
+
+var (
+	PositionManagerAddress = common.HexToAddress(PositionManagerAddressHex)
+)
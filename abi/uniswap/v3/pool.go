@@ -13,6 +13,8 @@ const (
 	SwapTopic0Hex = "0xc42079f94a6350d7e6235f29174924f928cc2ac818eb64fed8004e115fbcca67"
 	MintTopic0Hex = "0x7a53080ba414158be7ec69b987b5fb7d07dee101fe85488f0853ae16239d0bde"
 	BurnTopic0Hex = "0x0c396cd989a39f4459b5fa1aed6a9a8dcdbc45908acfd67e028cd568da98982c"
+
+	InitializeTopic0Hex = "0x98636036cb66a9c19a37435efc1e90142190214e8abeb821bdba3f2990dd4c95"
 )
 
 var (
@@ -26,6 +28,9 @@ var (
 
 	BurnTopic0 = common.HexToHash(BurnTopic0Hex)
 	BurnEvent  *abi.Event
+
+	InitializeTopic0 = common.HexToHash(InitializeTopic0Hex)
+	InitializeEvent  *abi.Event
 )
 
 func init() {
@@ -52,4 +57,10 @@ func init() {
 		log.Logger.Fatal("load abi[PancakeV3Pool] event[burn] err", zap.Error(err))
 	}
 	BurnEvent = burnEvent
+
+	initializeEvent, err := poolAbi.EventByID(InitializeTopic0)
+	if err != nil {
+		log.Logger.Fatal("load abi[UniswapV3Pool] event[initialize] err", zap.Error(err))
+	}
+	InitializeEvent = initializeEvent
 }
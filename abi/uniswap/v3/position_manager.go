@@ -0,0 +1,74 @@
+package v3
+
+import (
+	"base_scan/log"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+	"strings"
+)
+
+// the events of NonfungiblePositionManager, the pancake v3 fork emits the same
+const (
+	PositionManagerAbiJson     = `[{"anonymous":false,"inputs":[{"indexed":true,"internalType":"uint256","name":"tokenId","type":"uint256"},{"indexed":false,"internalType":"uint128","name":"liquidity","type":"uint128"},{"indexed":false,"internalType":"uint256","name":"amount0","type":"uint256"},{"indexed":false,"internalType":"uint256","name":"amount1","type":"uint256"}],"name":"IncreaseLiquidity","type":"event"},{"anonymous":false,"inputs":[{"indexed":true,"internalType":"uint256","name":"tokenId","type":"uint256"},{"indexed":false,"internalType":"uint128","name":"liquidity","type":"uint128"},{"indexed":false,"internalType":"uint256","name":"amount0","type":"uint256"},{"indexed":false,"internalType":"uint256","name":"amount1","type":"uint256"}],"name":"DecreaseLiquidity","type":"event"},{"anonymous":false,"inputs":[{"indexed":true,"internalType":"uint256","name":"tokenId","type":"uint256"},{"indexed":false,"internalType":"address","name":"recipient","type":"address"},{"indexed":false,"internalType":"uint256","name":"amount0","type":"uint256"},{"indexed":false,"internalType":"uint256","name":"amount1","type":"uint256"}],"name":"Collect","type":"event"},{"anonymous":false,"inputs":[{"indexed":true,"internalType":"address","name":"from","type":"address"},{"indexed":true,"internalType":"address","name":"to","type":"address"},{"indexed":true,"internalType":"uint256","name":"tokenId","type":"uint256"}],"name":"Transfer","type":"event"}]`
+	PositionManagerAddressHex  = "0x03a520b32C04BF3bEEf7BEb72E919cf822Ed34f1"
+	IncreaseLiquidityTopic0Hex = "0x3067048beee31b25b2f1681f88dac838c8bba36af25bfb2b7cf7473a5847e35f"
+	DecreaseLiquidityTopic0Hex = "0x26f6a048ee9138f2c0ce266f322cb99228e8d619ae2bff30c67f8dcf9d2377b4"
+	PositionCollectTopic0Hex   = "0x40d0efd1a53d60ecbf40971b9daf7dc90178c3aadc7aab1765632738fa8b8f01"
+	TransferTopic0Hex          = "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+	// Collect of the pool, not of the position manager
+	CollectTopic0Hex = "0x70935338e69775456a85ddef226c395fb668b63fa0115f5f20610b388e6ca9c0"
+)
+
+var (
+	PositionManagerAbi *abi.ABI
+	// the position manager deployed on base
+	PositionManagerAddress = common.HexToAddress(PositionManagerAddressHex)
+
+	IncreaseLiquidityTopic0 = common.HexToHash(IncreaseLiquidityTopic0Hex)
+	IncreaseLiquidityEvent  *abi.Event
+
+	DecreaseLiquidityTopic0 = common.HexToHash(DecreaseLiquidityTopic0Hex)
+	DecreaseLiquidityEvent  *abi.Event
+
+	PositionCollectTopic0 = common.HexToHash(PositionCollectTopic0Hex)
+	PositionCollectEvent  *abi.Event
+
+	TransferTopic0 = common.HexToHash(TransferTopic0Hex)
+
+	CollectTopic0 = common.HexToHash(CollectTopic0Hex)
+)
+
+func init() {
+	positionManagerAbi, err := abi.JSON(strings.NewReader(PositionManagerAbiJson))
+	if err != nil {
+		log.Logger.Fatal("load abi[NonfungiblePositionManager] err", zap.Error(err))
+	}
+	PositionManagerAbi = &positionManagerAbi
+
+	increaseLiquidityEvent, err := positionManagerAbi.EventByID(IncreaseLiquidityTopic0)
+	if err != nil {
+		log.Logger.Fatal("load abi[NonfungiblePositionManager] event[IncreaseLiquidity] err", zap.Error(err))
+	}
+	IncreaseLiquidityEvent = increaseLiquidityEvent
+
+	decreaseLiquidityEvent, err := positionManagerAbi.EventByID(DecreaseLiquidityTopic0)
+	if err != nil {
+		log.Logger.Fatal("load abi[NonfungiblePositionManager] event[DecreaseLiquidity] err", zap.Error(err))
+	}
+	DecreaseLiquidityEvent = decreaseLiquidityEvent
+
+	positionCollectEvent, err := positionManagerAbi.EventByID(PositionCollectTopic0)
+	if err != nil {
+		log.Logger.Fatal("load abi[NonfungiblePositionManager] event[Collect] err", zap.Error(err))
+	}
+	PositionCollectEvent = positionCollectEvent
+
+	if _, err = positionManagerAbi.EventByID(TransferTopic0); err != nil {
+		log.Logger.Fatal("load abi[NonfungiblePositionManager] event[Transfer] err", zap.Error(err))
+	}
+
+	if _, err = PoolAbi.EventByID(CollectTopic0); err != nil {
+		log.Logger.Fatal("load abi[UniswapV3Pool] event[Collect] err", zap.Error(err))
+	}
+}
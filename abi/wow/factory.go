@@ -0,0 +1,37 @@
+package wow
+
+import (
+	"base_scan/log"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+	"strings"
+)
+
+// the factory of the wow launchpad, it deploys every token with its uniswap v3 pool against WETH
+const (
+	FactoryAbiJson           = `[{"anonymous":false,"inputs":[{"indexed":true,"internalType":"address","name":"factoryAddress","type":"address"},{"indexed":true,"internalType":"address","name":"tokenCreator","type":"address"},{"indexed":false,"internalType":"address","name":"platformReferrer","type":"address"},{"indexed":false,"internalType":"address","name":"protocolFeeRecipient","type":"address"},{"indexed":false,"internalType":"address","name":"bondingCurve","type":"address"},{"indexed":false,"internalType":"string","name":"tokenURI","type":"string"},{"indexed":false,"internalType":"string","name":"name","type":"string"},{"indexed":false,"internalType":"string","name":"symbol","type":"string"},{"indexed":false,"internalType":"address","name":"tokenAddress","type":"address"},{"indexed":false,"internalType":"address","name":"poolAddress","type":"address"}],"name":"WowTokenCreated","type":"event"}]`
+	FactoryAddressHex        = "0x997020E5F59cCB79C74D527Be492Cc610CB9fA2B"
+	WowTokenCreatedTopic0Hex = "0xc14d4a89f40f2ad9a3bacaae76b1d8567b797e367ed13e62996afbb52625457f"
+)
+
+var (
+	FactoryAbi            *abi.ABI
+	FactoryAddress        = common.HexToAddress(FactoryAddressHex)
+	WowTokenCreatedTopic0 = common.HexToHash(WowTokenCreatedTopic0Hex)
+	WowTokenCreatedEvent  *abi.Event
+)
+
+func init() {
+	factoryAbi, err := abi.JSON(strings.NewReader(FactoryAbiJson))
+	if err != nil {
+		log.Logger.Fatal("Failed to parse wow factory ABI", zap.Error(err))
+	}
+	FactoryAbi = &factoryAbi
+
+	wowTokenCreatedEvent, err := factoryAbi.EventByID(WowTokenCreatedTopic0)
+	if err != nil {
+		log.Logger.Fatal("Failed to find WowTokenCreatedTopic0", zap.Error(err))
+	}
+	WowTokenCreatedEvent = wowTokenCreatedEvent
+}
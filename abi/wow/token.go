@@ -0,0 +1,62 @@
+package wow
+
+import (
+	"base_scan/log"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+	"strings"
+)
+
+/*
+the events of the wow tokens, a token is its own bonding curve, it holds the ETH of the curve.
+Buys and sells are logged for both markets, the ones of MarketTypeUniswapPool are routed through the pool of the
+token and are indexed as swaps of the pool.
+*/
+const (
+	TokenAbiJson                = `[{"anonymous":false,"inputs":[{"indexed":true,"internalType":"address","name":"buyer","type":"address"},{"indexed":true,"internalType":"address","name":"recipient","type":"address"},{"indexed":true,"internalType":"address","name":"orderReferrer","type":"address"},{"indexed":false,"internalType":"uint256","name":"totalEth","type":"uint256"},{"indexed":false,"internalType":"uint256","name":"ethFee","type":"uint256"},{"indexed":false,"internalType":"uint256","name":"ethSold","type":"uint256"},{"indexed":false,"internalType":"uint256","name":"tokensBought","type":"uint256"},{"indexed":false,"internalType":"uint256","name":"buyerTokenBalance","type":"uint256"},{"indexed":false,"internalType":"string","name":"comment","type":"string"},{"indexed":false,"internalType":"uint256","name":"totalSupply","type":"uint256"},{"indexed":false,"internalType":"enum IWow.MarketType","name":"marketType","type":"uint8"}],"name":"WowTokenBuy","type":"event"},{"anonymous":false,"inputs":[{"indexed":true,"internalType":"address","name":"seller","type":"address"},{"indexed":true,"internalType":"address","name":"recipient","type":"address"},{"indexed":true,"internalType":"address","name":"orderReferrer","type":"address"},{"indexed":false,"internalType":"uint256","name":"totalEth","type":"uint256"},{"indexed":false,"internalType":"uint256","name":"ethFee","type":"uint256"},{"indexed":false,"internalType":"uint256","name":"ethBought","type":"uint256"},{"indexed":false,"internalType":"uint256","name":"tokensSold","type":"uint256"},{"indexed":false,"internalType":"uint256","name":"sellerTokenBalance","type":"uint256"},{"indexed":false,"internalType":"string","name":"comment","type":"string"},{"indexed":false,"internalType":"uint256","name":"totalSupply","type":"uint256"},{"indexed":false,"internalType":"enum IWow.MarketType","name":"marketType","type":"uint8"}],"name":"WowTokenSell","type":"event"},{"anonymous":false,"inputs":[{"indexed":true,"internalType":"address","name":"tokenAddress","type":"address"},{"indexed":true,"internalType":"address","name":"poolAddress","type":"address"},{"indexed":false,"internalType":"uint256","name":"totalEthLiquidity","type":"uint256"},{"indexed":false,"internalType":"uint256","name":"totalTokenLiquidity","type":"uint256"},{"indexed":false,"internalType":"uint256","name":"lpPositionId","type":"uint256"},{"indexed":false,"internalType":"enum IWow.MarketType","name":"marketType","type":"uint8"}],"name":"WowMarketGraduated","type":"event"}]`
+	WowTokenBuyTopic0Hex        = "0x49c4606c4f7f601127761fb65a512e6bca424f62b165476cf1cfdfa51772a6ab"
+	WowTokenSellTopic0Hex       = "0x0693c83d190a3d36d2ed6c3ac51b1335d2a3588d96f3f3601c8b1e780d0f952a"
+	WowMarketGraduatedTopic0Hex = "0x9b932ef08aec7b34ee4d1c09579d92521b437379b5cab356f34588f1cdbbf968"
+)
+
+const (
+	MarketTypeBondingCurve uint8 = iota
+	MarketTypeUniswapPool
+)
+
+var (
+	TokenAbi                 *abi.ABI
+	WowTokenBuyTopic0        = common.HexToHash(WowTokenBuyTopic0Hex)
+	WowTokenBuyEvent         *abi.Event
+	WowTokenSellTopic0       = common.HexToHash(WowTokenSellTopic0Hex)
+	WowTokenSellEvent        *abi.Event
+	WowMarketGraduatedTopic0 = common.HexToHash(WowMarketGraduatedTopic0Hex)
+	WowMarketGraduatedEvent  *abi.Event
+)
+
+func init() {
+	tokenAbi, err := abi.JSON(strings.NewReader(TokenAbiJson))
+	if err != nil {
+		log.Logger.Fatal("Failed to parse wow token ABI", zap.Error(err))
+	}
+	TokenAbi = &tokenAbi
+
+	wowTokenBuyEvent, err := tokenAbi.EventByID(WowTokenBuyTopic0)
+	if err != nil {
+		log.Logger.Fatal("Failed to find WowTokenBuyTopic0", zap.Error(err))
+	}
+	WowTokenBuyEvent = wowTokenBuyEvent
+
+	wowTokenSellEvent, err := tokenAbi.EventByID(WowTokenSellTopic0)
+	if err != nil {
+		log.Logger.Fatal("Failed to find WowTokenSellTopic0", zap.Error(err))
+	}
+	WowTokenSellEvent = wowTokenSellEvent
+
+	wowMarketGraduatedEvent, err := tokenAbi.EventByID(WowMarketGraduatedTopic0)
+	if err != nil {
+		log.Logger.Fatal("Failed to find WowMarketGraduatedTopic0", zap.Error(err))
+	}
+	WowMarketGraduatedEvent = wowMarketGraduatedEvent
+}
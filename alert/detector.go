@@ -0,0 +1,308 @@
+package alert
+
+import (
+	"base_scan/config"
+	"base_scan/repository/orm"
+	"base_scan/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"sort"
+)
+
+var (
+	hundred = decimal.NewFromInt(100)
+	two     = decimal.NewFromInt(2)
+)
+
+// window remembers the deployments and the native transfers of the last blocks, in memory only.
+type window struct {
+	blocks []*windowBlock
+	// deployments and transfers of the blocks
+	entries   int
+	deployers map[common.Address]*windowDeployer
+	// funder to funded wallets
+	funded map[common.Address]map[common.Address]uint64
+}
+
+type windowBlock struct {
+	height      uint64
+	deployments []*types.Deployment
+	transfers   []*types.NativeTransfer
+}
+
+type windowDeployer struct {
+	deployer common.Address
+	height   uint64
+}
+
+func newWindow() *window {
+	return &window{
+		blocks:    make([]*windowBlock, 0),
+		deployers: make(map[common.Address]*windowDeployer),
+		funded:    make(map[common.Address]map[common.Address]uint64),
+	}
+}
+
+// add remembers the deployments and the transfers of a block, the first deployment of a contract is kept.
+func (w *window) add(br *types.BlockResult) {
+	w.blocks = append(w.blocks, &windowBlock{height: br.Height, deployments: br.Deployments, transfers: br.NativeTransfers})
+	w.entries += len(br.Deployments) + len(br.NativeTransfers)
+	for _, deployment := range br.Deployments {
+		if _, ok := w.deployers[deployment.Contract]; !ok {
+			w.deployers[deployment.Contract] = &windowDeployer{deployer: deployment.Deployer, height: br.Height}
+		}
+	}
+	for _, transfer := range br.NativeTransfers {
+		wallets, ok := w.funded[transfer.From]
+		if !ok {
+			wallets = make(map[common.Address]uint64)
+			w.funded[transfer.From] = wallets
+		}
+		wallets[transfer.To] = br.Height
+	}
+}
+
+// drop forgets the oldest block, a contract deployed or a wallet funded again later is kept.
+func (w *window) drop() {
+	b := w.blocks[0]
+	for _, deployment := range b.deployments {
+		if deployer, ok := w.deployers[deployment.Contract]; ok && deployer.height == b.height {
+			delete(w.deployers, deployment.Contract)
+		}
+	}
+	for _, transfer := range b.transfers {
+		wallets := w.funded[transfer.From]
+		if wallets[transfer.To] == b.height {
+			delete(wallets, transfer.To)
+		}
+		if len(wallets) == 0 {
+			delete(w.funded, transfer.From)
+		}
+	}
+	w.entries -= len(b.deployments) + len(b.transfers)
+	w.blocks = w.blocks[1:]
+}
+
+// expire forgets the blocks before from.
+func (w *window) expire(from uint64) {
+	for len(w.blocks) > 0 && w.blocks[0].height < from {
+		w.drop()
+	}
+}
+
+// limit forgets the oldest blocks until at most maxEntries are remembered, the last block is always kept.
+func (w *window) limit(maxEntries int) {
+	for len(w.blocks) > 1 && w.entries > maxEntries {
+		w.drop()
+	}
+}
+
+// fundedBy returns the wallets funded by any of funders, ordered by address.
+func (w *window) fundedBy(funders ...common.Address) []common.Address {
+	set := make(map[common.Address]struct{})
+	for _, funder := range funders {
+		if funder == types.ZeroAddress {
+			continue
+		}
+		for wallet := range w.funded[funder] {
+			set[wallet] = struct{}{}
+		}
+	}
+
+	wallets := make([]common.Address, 0, len(set))
+	for wallet := range set {
+		wallets = append(wallets, wallet)
+	}
+	sort.Slice(wallets, func(i, j int) bool {
+		return wallets[i].Cmp(wallets[j]) < 0
+	})
+	return wallets
+}
+
+/*
+Detector finds the launches of new tokens, the first liquidity of a pair of a token against a base token
+where the token is new in the block, and describes them as launch alerts.
+Blocks must be detected in order, it remembers the deployments and the native transfers of the last
+config.LaunchAlertConf WindowBlocks blocks, WindowMaxEntries at most, to know the deployer of a token and the wallets
+funded by the creator or the deployer, wallets funded by a factory deploying the token are not known.
+The window is in memory, it starts empty after a restart and the deployers of the tokens deployed before are unknown.
+*/
+type Detector struct {
+	conf                   *config.LaunchAlertConf
+	minLiquidityUsd        decimal.Decimal
+	minSupplyInPoolPercent decimal.Decimal
+	window                 *window
+}
+
+func NewDetector(conf *config.LaunchAlertConf) *Detector {
+	return &Detector{
+		conf:                   conf,
+		minLiquidityUsd:        decimal.NewFromFloat(conf.MinLiquidityUsd),
+		minSupplyInPoolPercent: decimal.NewFromFloat(conf.MinSupplyInPoolPercent),
+		window:                 newWindow(),
+	}
+}
+
+/*
+Detect returns the launch alerts of a block worth at least MinLiquidityUsd, ordered by position in block.
+txs are the trades of the block, the buys of a launched pair in the same block are its snipers.
+The events of br must be linked already, see BlockResult.GetKafkaMessage.
+*/
+func (d *Detector) Detect(br *types.BlockResult, txs []*orm.Tx) []*types.LaunchAlert {
+	d.window.add(br)
+	if br.Height >= d.conf.WindowBlocks {
+		d.window.expire(br.Height - d.conf.WindowBlocks + 1)
+	}
+	d.window.limit(d.conf.WindowMaxEntries)
+
+	pair2Buys := make(map[string][]*orm.Tx)
+	for _, tx := range txs {
+		if tx.Event == types.Buy {
+			pair2Buys[tx.PairAddress] = append(pair2Buys[tx.PairAddress], tx)
+		}
+	}
+
+	alerts := make([]*types.LaunchAlert, 0)
+	for _, txResult := range br.TxResults {
+		for _, event := range txResult.PairCreatedEvents {
+			firstLiquidityEvent, ok := event.(types.FirstLiquidityEvent)
+			if !ok {
+				continue
+			}
+			firstLiquidity := firstLiquidityEvent.GetFirstLiquidity()
+			if firstLiquidity == nil || !isLaunch(br, firstLiquidity.Pair) {
+				continue
+			}
+
+			price, ok := quotePrice(br, firstLiquidity.Pair.Token1Core.Address)
+			if !ok {
+				continue
+			}
+			alert := d.newAlert(br, firstLiquidity, price, pair2Buys[firstLiquidity.Pair.Address.String()])
+			if alert.LiquidityUSD.LessThan(d.minLiquidityUsd) {
+				continue
+			}
+			alerts = append(alerts, alert)
+		}
+	}
+	return alerts
+}
+
+// isLaunch tells whether the pair is of a token new in the block against a base token.
+func isLaunch(br *types.BlockResult, pair *types.Pair) bool {
+	if pair.Filtered || pair.Token0 == nil || pair.Token1 == nil {
+		return false
+	}
+	if br.BaseTokens.IsBase(pair.Token0Core.Address) || !br.BaseTokens.IsBase(pair.Token1Core.Address) {
+		return false
+	}
+	_, ok := br.NewTokens[pair.Token0Core.Address]
+	return ok
+}
+
+/*
+quotePrice returns the usd price of the quote token of a launch, as the trades of the block are priced, see
+event.CalcAmountAndPrice: the native token is priced by the price pair of the chain in the stable token, so the stable
+token is the usd. A quote token priced neither way has no price and its launches no alert.
+*/
+func quotePrice(br *types.BlockResult, quoteToken common.Address) (decimal.Decimal, bool) {
+	switch {
+	case br.BaseTokens.IsNative(quoteToken):
+		return br.NativeTokenPrice, br.NativeTokenPrice.IsPositive()
+	case br.BaseTokens.IsStable(quoteToken):
+		return decimal.NewFromInt(1), true
+	default:
+		return decimal.Zero, false
+	}
+}
+
+func (d *Detector) newAlert(br *types.BlockResult, firstLiquidity *types.FirstLiquidity, quotePrice decimal.Decimal, buys []*orm.Tx) *types.LaunchAlert {
+	pair := firstLiquidity.Pair
+	token := pair.Token0
+	deployer := types.ZeroAddress
+	if windowDeployer, ok := d.window.deployers[token.Address]; ok {
+		deployer = windowDeployer.deployer
+	}
+	fundedWallets := d.window.fundedBy(firstLiquidity.Creator, deployer)
+
+	alert := &types.LaunchAlert{
+		TokenAddress:      token.Address.String(),
+		TokenName:         token.Name,
+		TokenSymbol:       token.Symbol,
+		TokenDecimals:     token.Decimals,
+		TokenTotalSupply:  token.TotalSupply,
+		PairAddress:       pair.Address.String(),
+		Program:           types.GetProtocolName(pair.ProtocolId),
+		QuoteTokenAddress: pair.Token1Core.Address.String(),
+		QuoteTokenSymbol:  pair.Token1Core.Symbol,
+		TokenAmount:       pair.Token0InitAmount,
+		QuoteAmount:       pair.Token1InitAmount,
+		LiquidityUSD:      pair.Token1InitAmount.Mul(quotePrice).Mul(two),
+		Creator:           firstLiquidity.Creator.String(),
+		FundedWallets:     make([]string, 0, len(fundedWallets)),
+		Snipers:           make([]string, 0),
+		Risks:             make([]string, 0),
+		TxHash:            firstLiquidity.TxHash.String(),
+		Block:             br.Height,
+		BlockAt:           br.BlockTime,
+	}
+	if deployer != types.ZeroAddress {
+		alert.Deployer = deployer.String()
+	} else {
+		alert.Risks = append(alert.Risks, types.LaunchRiskUnknownDeployer)
+	}
+	if token.TotalSupply.IsPositive() {
+		alert.SupplyInPoolPercent = pair.Token0InitAmount.Div(token.TotalSupply).Mul(hundred)
+		if alert.SupplyInPoolPercent.LessThan(d.minSupplyInPoolPercent) {
+			alert.Risks = append(alert.Risks, types.LaunchRiskLowSupplyInPool)
+		}
+	}
+
+	funded := make(map[string]struct{}, len(fundedWallets))
+	for _, wallet := range fundedWallets {
+		alert.FundedWallets = append(alert.FundedWallets, wallet.String())
+		funded[wallet.String()] = struct{}{}
+	}
+
+	var creatorBought, fundedBought, sniped bool
+	seen := make(map[string]struct{})
+	for _, buy := range sortBuys(buys) {
+		if buy.Maker == alert.Creator || buy.Maker == alert.Deployer {
+			creatorBought = true
+			continue
+		}
+		if _, ok := seen[buy.Maker]; ok {
+			continue
+		}
+		seen[buy.Maker] = struct{}{}
+		alert.Snipers = append(alert.Snipers, buy.Maker)
+		if _, ok := funded[buy.Maker]; ok {
+			fundedBought = true
+		} else {
+			sniped = true
+		}
+	}
+	if sniped {
+		alert.Risks = append(alert.Risks, types.LaunchRiskSniped)
+	}
+	if fundedBought {
+		alert.Risks = append(alert.Risks, types.LaunchRiskFundedWalletsBought)
+	}
+	if creatorBought {
+		alert.Risks = append(alert.Risks, types.LaunchRiskCreatorBought)
+	}
+	return alert
+}
+
+// sortBuys orders the buys of a pair by position in block, BlockResult.GetKafkaMessage returns them unordered.
+func sortBuys(buys []*orm.Tx) []*orm.Tx {
+	sorted := make([]*orm.Tx, len(buys))
+	copy(sorted, buys)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].BlockIndex != sorted[j].BlockIndex {
+			return sorted[i].BlockIndex < sorted[j].BlockIndex
+		}
+		return sorted[i].TxIndex < sorted[j].TxIndex
+	})
+	return sorted
+}
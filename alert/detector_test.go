@@ -0,0 +1,189 @@
+package alert
+
+import (
+	"base_scan/config"
+	"base_scan/parser/event_parser/event"
+	"base_scan/repository/orm"
+	"base_scan/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+)
+
+var (
+	testToken    = common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	testPair     = common.HexToAddress("0x00000000000000000000000000000000000000b1")
+	testDeployer = common.HexToAddress("0x00000000000000000000000000000000000000c1")
+	testCreator  = common.HexToAddress("0x00000000000000000000000000000000000000c2")
+	testFunded   = common.HexToAddress("0x00000000000000000000000000000000000000d1")
+	testSniper   = common.HexToAddress("0x00000000000000000000000000000000000000d2")
+)
+
+func testConf() *config.LaunchAlertConf {
+	return &config.LaunchAlertConf{
+		Enabled:                true,
+		MinLiquidityUsd:        1000,
+		WebhookMinLiquidityUsd: 10000,
+		WindowBlocks:           10,
+		WindowMaxEntries:       100,
+		MinSupplyInPoolPercent: 50,
+	}
+}
+
+// launchBlock adds 1000 of 1000000 tokens and 1 WETH to a new pair, with the mint sent by testCreator.
+func launchBlock(height uint64) *types.BlockResult {
+	token := &types.Token{Address: testToken, Name: "Test", Symbol: "T", Decimals: 18, TotalSupply: decimal.NewFromInt(1000000)}
+	weth := &types.Token{Address: types.WETHAddress, Symbol: "WETH", Decimals: 18}
+	pair := &types.Pair{
+		Address:    testPair,
+		Token0Core: &types.TokenCore{Address: testToken, Symbol: "T", Decimals: 18},
+		Token1Core: &types.TokenCore{Address: types.WETHAddress, Symbol: "WETH", Decimals: 18},
+		Token0:     token,
+		Token1:     weth,
+		ProtocolId: types.ProtocolIdUniswapV2,
+	}
+
+	br := types.NewBlockResult(8453, types.DefaultBaseTokens, height, 1700000000, decimal.NewFromInt(2000))
+	br.NewTokens[testToken] = token
+
+	pairCreated := &event.PairCreatedEvent{EventCommon: &types.EventCommon{Pair: pair}}
+	mint := &event.MintEvent{
+		EventCommon: &types.EventCommon{Pair: pair, Maker: testCreator, TxHash: common.HexToHash("0x01")},
+		Amount0Wei:  new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e18)),
+		Amount1Wei:  big.NewInt(1e18),
+	}
+	pairCreated.LinkEvent(mint)
+
+	tr := types.NewTxResult(testCreator, &types.TxMeta{}, nil)
+	tr.PairCreatedEvents = append(tr.PairCreatedEvents, pairCreated)
+	br.AddTxResult(tr)
+	return br
+}
+
+func buy(maker common.Address, blockIndex uint) *orm.Tx {
+	return &orm.Tx{Event: types.Buy, Maker: maker.String(), PairAddress: testPair.String(), BlockIndex: blockIndex}
+}
+
+func TestDetect(t *testing.T) {
+	d := NewDetector(testConf())
+
+	deployBlock := types.NewBlockResult(8453, types.DefaultBaseTokens, 100, 1699999990, decimal.NewFromInt(2000))
+	deployBlock.Deployments = append(deployBlock.Deployments, &types.Deployment{Contract: testToken, Deployer: testDeployer})
+	deployBlock.NativeTransfers = append(deployBlock.NativeTransfers, &types.NativeTransfer{From: testDeployer, To: testFunded, Value: big.NewInt(1e17)})
+	require.Empty(t, d.Detect(deployBlock, nil))
+
+	alerts := d.Detect(launchBlock(105), []*orm.Tx{buy(testSniper, 3), buy(testFunded, 2), buy(testCreator, 1)})
+	require.Len(t, alerts, 1)
+
+	alert := alerts[0]
+	require.Equal(t, testToken.String(), alert.TokenAddress)
+	require.Equal(t, "T", alert.TokenSymbol)
+	require.Equal(t, testPair.String(), alert.PairAddress)
+	require.Equal(t, types.WETHAddress.String(), alert.QuoteTokenAddress)
+	require.True(t, decimal.NewFromInt(1000).Equal(alert.TokenAmount), alert.TokenAmount.String())
+	// 1 WETH * 2000 usd, both sides
+	require.True(t, decimal.NewFromInt(4000).Equal(alert.LiquidityUSD), alert.LiquidityUSD.String())
+	require.True(t, decimal.NewFromFloat(0.1).Equal(alert.SupplyInPoolPercent), alert.SupplyInPoolPercent.String())
+	require.Equal(t, testCreator.String(), alert.Creator)
+	require.Equal(t, testDeployer.String(), alert.Deployer)
+	require.Equal(t, []string{testFunded.String()}, alert.FundedWallets)
+	require.Equal(t, []string{testFunded.String(), testSniper.String()}, alert.Snipers)
+	require.Equal(t, []string{
+		types.LaunchRiskLowSupplyInPool,
+		types.LaunchRiskSniped,
+		types.LaunchRiskFundedWalletsBought,
+		types.LaunchRiskCreatorBought,
+	}, alert.Risks)
+	require.Equal(t, common.HexToHash("0x01").String(), alert.TxHash)
+}
+
+func TestDetectExpiredDeployment(t *testing.T) {
+	d := NewDetector(testConf())
+
+	deployBlock := types.NewBlockResult(8453, types.DefaultBaseTokens, 100, 1699999990, decimal.NewFromInt(2000))
+	deployBlock.Deployments = append(deployBlock.Deployments, &types.Deployment{Contract: testToken, Deployer: testDeployer})
+	d.Detect(deployBlock, nil)
+
+	alerts := d.Detect(launchBlock(110), nil)
+	require.Len(t, alerts, 1)
+	require.Empty(t, alerts[0].Deployer)
+	require.Contains(t, alerts[0].Risks, types.LaunchRiskUnknownDeployer)
+}
+
+func TestDetectWindowMaxEntries(t *testing.T) {
+	conf := testConf()
+	conf.WindowMaxEntries = 2
+	d := NewDetector(conf)
+
+	deployBlock := types.NewBlockResult(8453, types.DefaultBaseTokens, 100, 1699999990, decimal.NewFromInt(2000))
+	deployBlock.Deployments = append(deployBlock.Deployments, &types.Deployment{Contract: testToken, Deployer: testDeployer})
+	d.Detect(deployBlock, nil)
+
+	transferBlock := types.NewBlockResult(8453, types.DefaultBaseTokens, 101, 1699999992, decimal.NewFromInt(2000))
+	transferBlock.NativeTransfers = append(transferBlock.NativeTransfers,
+		&types.NativeTransfer{From: testCreator, To: testFunded, Value: big.NewInt(1e17)},
+		&types.NativeTransfer{From: testCreator, To: testSniper, Value: big.NewInt(1e17)},
+	)
+	d.Detect(transferBlock, nil)
+
+	// the deployment is forgotten for the transfers, the last block is kept
+	alerts := d.Detect(launchBlock(102), nil)
+	require.Len(t, alerts, 1)
+	require.Empty(t, alerts[0].Deployer)
+	require.Equal(t, []string{testFunded.String(), testSniper.String()}, alerts[0].FundedWallets)
+}
+
+func TestDetectFirstDeployment(t *testing.T) {
+	d := NewDetector(testConf())
+
+	deployBlock := types.NewBlockResult(8453, types.DefaultBaseTokens, 100, 1699999990, decimal.NewFromInt(2000))
+	deployBlock.Deployments = append(deployBlock.Deployments, &types.Deployment{Contract: testToken, Deployer: testDeployer})
+	d.Detect(deployBlock, nil)
+
+	// a later mint of the token doesn't replace its deployer
+	mintBlock := types.NewBlockResult(8453, types.DefaultBaseTokens, 101, 1699999992, decimal.NewFromInt(2000))
+	mintBlock.Deployments = append(mintBlock.Deployments, &types.Deployment{Contract: testToken, Deployer: testSniper})
+	d.Detect(mintBlock, nil)
+
+	alerts := d.Detect(launchBlock(105), nil)
+	require.Len(t, alerts, 1)
+	require.Equal(t, testDeployer.String(), alerts[0].Deployer)
+}
+
+func TestDetectStableQuote(t *testing.T) {
+	br := launchBlock(100)
+	pair := br.TxResults[0].PairCreatedEvents[0].GetPair()
+	pair.Token1Core.Address = types.USDCAddress
+	conf := testConf()
+	conf.MinLiquidityUsd = 1
+
+	alerts := NewDetector(conf).Detect(br, nil)
+	require.Len(t, alerts, 1)
+	// 1 USDC, both sides
+	require.True(t, decimal.NewFromInt(2).Equal(alerts[0].LiquidityUSD), alerts[0].LiquidityUSD.String())
+}
+
+func TestDetectMinLiquidity(t *testing.T) {
+	conf := testConf()
+	conf.MinLiquidityUsd = 5000
+	require.Empty(t, NewDetector(conf).Detect(launchBlock(100), nil))
+}
+
+func TestDetectOldToken(t *testing.T) {
+	br := launchBlock(100)
+	delete(br.NewTokens, testToken)
+	require.Empty(t, NewDetector(testConf()).Detect(br, nil))
+}
+
+func TestWebhookFilter(t *testing.T) {
+	w := NewWebhook(testConf())
+	small := &types.LaunchAlert{LiquidityUSD: decimal.NewFromInt(4000)}
+	large := &types.LaunchAlert{LiquidityUSD: decimal.NewFromInt(10000)}
+
+	require.Nil(t, w.Filter(&types.LaunchAlertInfo{Height: 1, Alerts: []*types.LaunchAlert{small}}))
+	filtered := w.Filter(&types.LaunchAlertInfo{ChainId: 8453, Height: 1, Alerts: []*types.LaunchAlert{small, large}})
+	require.Equal(t, []*types.LaunchAlert{large}, filtered.Alerts)
+	require.Equal(t, uint64(8453), filtered.ChainId)
+}
@@ -0,0 +1,77 @@
+package alert
+
+import (
+	"base_scan/config"
+	"base_scan/log"
+	"base_scan/types"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+/*
+Webhook posts the launch alerts worth at least config.LaunchAlertConf WebhookMinLiquidityUsd as a json
+types.LaunchAlertInfo. Posts don't hold up the commit of blocks, a failed post is logged and dropped.
+*/
+type Webhook struct {
+	url             string
+	minLiquidityUsd decimal.Decimal
+	client          *http.Client
+}
+
+func NewWebhook(conf *config.LaunchAlertConf) *Webhook {
+	return &Webhook{
+		url:             conf.WebhookUrl,
+		minLiquidityUsd: decimal.NewFromFloat(conf.WebhookMinLiquidityUsd),
+		client:          &http.Client{Timeout: time.Duration(conf.WebhookTimeoutMs) * time.Millisecond},
+	}
+}
+
+// Filter returns the alerts of info worth posting, nil when there are none.
+func (w *Webhook) Filter(info *types.LaunchAlertInfo) *types.LaunchAlertInfo {
+	alerts := make([]*types.LaunchAlert, 0, len(info.Alerts))
+	for _, alert := range info.Alerts {
+		if !alert.LiquidityUSD.LessThan(w.minLiquidityUsd) {
+			alerts = append(alerts, alert)
+		}
+	}
+	if len(alerts) == 0 {
+		return nil
+	}
+	return &types.LaunchAlertInfo{ChainId: info.ChainId, Height: info.Height, Timestamp: info.Timestamp, Alerts: alerts}
+}
+
+func (w *Webhook) Send(info *types.LaunchAlertInfo) {
+	filtered := w.Filter(info)
+	if filtered == nil {
+		return
+	}
+
+	go func() {
+		if err := w.post(filtered); err != nil {
+			log.Logger.Warn("post launch alerts err", zap.Uint64("height", filtered.Height), zap.Error(err))
+		}
+	}()
+}
+
+func (w *Webhook) post(info *types.LaunchAlertInfo) error {
+	data, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+
+	resp, err := w.client.Post(w.url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook status %s", resp.Status)
+	}
+	return nil
+}
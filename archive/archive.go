@@ -0,0 +1,144 @@
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/trie"
+	"io"
+	"strings"
+)
+
+var (
+	ErrHashMismatch = errors.New("archived block doesn't match its hash")
+)
+
+// heightsPerDir keeps the number of objects of a directory of the local store reasonable.
+const heightsPerDir = 10000
+
+// record is what is archived of a block, the json of the rpc types, the block is rebuilt from its parts.
+type record struct {
+	Header       *ethtypes.Header        `json:"header"`
+	Transactions []*ethtypes.Transaction `json:"transactions"`
+	Uncles       []*ethtypes.Header      `json:"uncles"`
+	Withdrawals  ethtypes.Withdrawals    `json:"withdrawals"`
+	Receipts     []*ethtypes.Receipt     `json:"receipts"`
+}
+
+/*
+Archive keeps raw blocks and their receipts of a chain in a Store, gzipped json content addressed by height and hash:
+- <chain id>/blocks/<height / 10000>/<height>-<hash>.json.gz: the block and its receipts
+- <chain id>/heights/<height / 10000>/<height>: the hash of the block last put at the height
+The chains of the pipelines of a process can share a store. Blocks of the same height replaced by a reorg stay
+archived, Get returns the last one put.
+*/
+type Archive struct {
+	store   Store
+	chainId uint64
+}
+
+func NewArchive(store Store, chainId uint64) *Archive {
+	return &Archive{store: store, chainId: chainId}
+}
+
+func BlockKey(chainId, height uint64, hash common.Hash) string {
+	return fmt.Sprintf("%d/blocks/%d/%d-%s.json.gz", chainId, height/heightsPerDir, height, hash.Hex())
+}
+
+func HeightKey(chainId, height uint64) string {
+	return fmt.Sprintf("%d/heights/%d/%d", chainId, height/heightsPerDir, height)
+}
+
+// Put archives a block and its receipts, the block first so a height never points to a missing block.
+func (a *Archive) Put(block *ethtypes.Block, receipts []*ethtypes.Receipt) error {
+	data, err := encode(&record{
+		Header:       block.Header(),
+		Transactions: block.Transactions(),
+		Uncles:       block.Uncles(),
+		Withdrawals:  block.Withdrawals(),
+		Receipts:     receipts,
+	})
+	if err != nil {
+		return err
+	}
+
+	height := block.NumberU64()
+	if err = a.store.Put(BlockKey(a.chainId, height, block.Hash()), data); err != nil {
+		return err
+	}
+	return a.store.Put(HeightKey(a.chainId, height), []byte(block.Hash().Hex()))
+}
+
+// Get returns the block last archived at a height and its receipts, ErrNotFound when there is none.
+func (a *Archive) Get(height uint64) (*ethtypes.Block, []*ethtypes.Receipt, error) {
+	hash, err := a.store.Get(HeightKey(a.chainId, height))
+	if err != nil {
+		return nil, nil, err
+	}
+	return a.GetByHash(height, common.HexToHash(strings.TrimSpace(string(hash))))
+}
+
+// GetByHash returns an archived block and its receipts, the block is checked against its hash.
+func (a *Archive) GetByHash(height uint64, hash common.Hash) (*ethtypes.Block, []*ethtypes.Receipt, error) {
+	data, err := a.store.Get(BlockKey(a.chainId, height, hash))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r, err := decode(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode block %d: %w", height, err)
+	}
+	if r.Header == nil {
+		return nil, nil, fmt.Errorf("decode block %d: no header", height)
+	}
+
+	block := ethtypes.NewBlockWithHeader(r.Header).WithBody(ethtypes.Body{
+		Transactions: r.Transactions,
+		Uncles:       r.Uncles,
+		Withdrawals:  r.Withdrawals,
+	})
+	// the header hash covers the txs by their root
+	if block.Hash() != hash || block.NumberU64() != height ||
+		ethtypes.DeriveSha(block.Transactions(), trie.NewStackTrie(nil)) != block.TxHash() {
+		return nil, nil, fmt.Errorf("%w: block %d %s", ErrHashMismatch, height, hash.Hex())
+	}
+	if len(r.Receipts) != len(r.Transactions) {
+		return nil, nil, fmt.Errorf("block %d has %d txs and %d receipts", height, len(r.Transactions), len(r.Receipts))
+	}
+	return block, r.Receipts, nil
+}
+
+func encode(r *record) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	writer := gzip.NewWriter(buf)
+	if err := json.NewEncoder(writer).Encode(r); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decode(data []byte) (*record, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	raw, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	r := &record{}
+	if err = json.Unmarshal(raw, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
@@ -0,0 +1,163 @@
+package archive
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var (
+	testChainId  = big.NewInt(8453)
+	testDeployer = common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	testPool     = common.HexToAddress("0x00000000000000000000000000000000000000b1")
+)
+
+// testBlock is a block with a deposit tx and a signed tx, like the blocks of op chains.
+func testBlock(t *testing.T, height uint64, extra string) (*ethtypes.Block, []*ethtypes.Receipt) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	deposit := ethtypes.NewTx(&ethtypes.DepositTx{
+		SourceHash: common.HexToHash("0x01"),
+		From:       testDeployer,
+		To:         &testPool,
+		Value:      big.NewInt(0),
+		Gas:        1000000,
+		Data:       []byte{0x01, 0x02},
+	})
+	swap := ethtypes.MustSignNewTx(key, ethtypes.LatestSignerForChainID(testChainId), &ethtypes.DynamicFeeTx{
+		ChainID:   testChainId,
+		Nonce:     7,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(100),
+		Gas:       200000,
+		To:        &testPool,
+		Value:     big.NewInt(0),
+		Data:      []byte{0x12, 0x34},
+	})
+
+	receipts := make([]*ethtypes.Receipt, 0, 2)
+	for i, tx := range []*ethtypes.Transaction{deposit, swap} {
+		receipts = append(receipts, &ethtypes.Receipt{
+			Type:              tx.Type(),
+			Status:            ethtypes.ReceiptStatusSuccessful,
+			CumulativeGasUsed: uint64(i+1) * 50000,
+			Logs: []*ethtypes.Log{{
+				Address:     testPool,
+				Topics:      []common.Hash{common.HexToHash("0xd78ad95f")},
+				Data:        []byte{0x05},
+				BlockNumber: height,
+				TxHash:      tx.Hash(),
+				TxIndex:     uint(i),
+				Index:       uint(i),
+			}},
+			TxHash:           tx.Hash(),
+			GasUsed:          50000,
+			BlockNumber:      new(big.Int).SetUint64(height),
+			TransactionIndex: uint(i),
+		})
+	}
+
+	header := &ethtypes.Header{
+		Number:     new(big.Int).SetUint64(height),
+		Time:       1700000000,
+		GasLimit:   30000000,
+		GasUsed:    100000,
+		BaseFee:    big.NewInt(10),
+		Difficulty: big.NewInt(0),
+		Extra:      []byte(extra),
+	}
+	body := &ethtypes.Body{Transactions: []*ethtypes.Transaction{deposit, swap}, Withdrawals: []*ethtypes.Withdrawal{}}
+	return ethtypes.NewBlock(header, body, receipts, trie.NewStackTrie(nil), ethtypes.DefaultBlockConfig), receipts
+}
+
+func TestArchiveRoundTrip(t *testing.T) {
+	a := NewArchive(NewLocalStore(t.TempDir()), 8453)
+	block, receipts := testBlock(t, 30255154, "")
+	require.NoError(t, a.Put(block, receipts))
+
+	got, gotReceipts, err := a.Get(30255154)
+	require.NoError(t, err)
+	require.Equal(t, block.Hash(), got.Hash())
+	require.Equal(t, block.Time(), got.Time())
+	require.Equal(t, block.BaseFee(), got.BaseFee())
+	require.Len(t, got.Transactions(), 2)
+
+	deposit := got.Transactions()[0]
+	require.True(t, deposit.IsDepositTx())
+	sender, err := ethtypes.Sender(ethtypes.NewLondonSigner(testChainId), deposit)
+	require.NoError(t, err)
+	require.Equal(t, testDeployer, sender)
+	require.Equal(t, block.Transactions()[1].Hash(), got.Transactions()[1].Hash())
+	require.Equal(t, block.Transactions()[1].Data(), got.Transactions()[1].Data())
+
+	require.Len(t, gotReceipts, 2)
+	for i, receipt := range gotReceipts {
+		require.Equal(t, receipts[i].TxHash, receipt.TxHash)
+		require.Equal(t, receipts[i].TransactionIndex, receipt.TransactionIndex)
+		require.Equal(t, receipts[i].Status, receipt.Status)
+		require.Equal(t, receipts[i].Logs[0].Topics, receipt.Logs[0].Topics)
+		require.Equal(t, receipts[i].Logs[0].Address, receipt.Logs[0].Address)
+	}
+
+	_, _, err = a.Get(30255155)
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestArchiveReorg(t *testing.T) {
+	a := NewArchive(NewLocalStore(t.TempDir()), 8453)
+	replaced, replacedReceipts := testBlock(t, 100, "a")
+	block, receipts := testBlock(t, 100, "b")
+	require.NoError(t, a.Put(replaced, replacedReceipts))
+	require.NoError(t, a.Put(block, receipts))
+
+	got, _, err := a.Get(100)
+	require.NoError(t, err)
+	require.Equal(t, block.Hash(), got.Hash())
+
+	got, _, err = a.GetByHash(100, replaced.Hash())
+	require.NoError(t, err)
+	require.Equal(t, replaced.Hash(), got.Hash())
+}
+
+func TestArchiveSharedStore(t *testing.T) {
+	store := NewLocalStore(t.TempDir())
+	base, other := NewArchive(store, 8453), NewArchive(store, 10)
+	block, receipts := testBlock(t, 100, "a")
+	otherBlock, otherReceipts := testBlock(t, 100, "b")
+	require.NoError(t, base.Put(block, receipts))
+	require.NoError(t, other.Put(otherBlock, otherReceipts))
+
+	got, _, err := base.Get(100)
+	require.NoError(t, err)
+	require.Equal(t, block.Hash(), got.Hash())
+	got, _, err = other.Get(100)
+	require.NoError(t, err)
+	require.Equal(t, otherBlock.Hash(), got.Hash())
+
+	_, _, err = NewArchive(store, 1).Get(100)
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestArchiveHashMismatch(t *testing.T) {
+	dir := t.TempDir()
+	a := NewArchive(NewLocalStore(dir), 8453)
+	block, receipts := testBlock(t, 100, "a")
+	other, otherReceipts := testBlock(t, 100, "b")
+	require.NoError(t, a.Put(block, receipts))
+	require.NoError(t, a.Put(other, otherReceipts))
+
+	// the object of a block replaced by another one
+	data, err := os.ReadFile(filepath.Join(dir, BlockKey(8453, 100, other.Hash())))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, BlockKey(8453, 100, block.Hash())), data, 0644))
+
+	_, _, err = a.GetByHash(100, block.Hash())
+	require.ErrorIs(t, err, ErrHashMismatch)
+}
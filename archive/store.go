@@ -0,0 +1,132 @@
+package archive
+
+import (
+	"base_scan/config"
+	"bytes"
+	"context"
+	"errors"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+)
+
+var (
+	ErrNotFound       = errors.New("not found in archive")
+	ErrUnknownBackend = errors.New("unknown archive backend")
+)
+
+// Store keeps objects by slash separated keys, Get returns ErrNotFound for a key never put.
+type Store interface {
+	Put(key string, data []byte) error
+	Get(key string) ([]byte, error)
+}
+
+// NewStoreByConf creates the store of the configured backend.
+func NewStoreByConf(conf *config.ArchiveConf) (Store, error) {
+	switch conf.Backend {
+	case "", config.ArchiveBackendLocal:
+		return NewLocalStore(conf.Dir), nil
+	case config.ArchiveBackendS3:
+		return NewObjectStore(conf)
+	default:
+		return nil, ErrUnknownBackend
+	}
+}
+
+// LocalStore keeps the objects as files under dir, a key is the path of its file relative to dir.
+type LocalStore struct {
+	dir string
+}
+
+func NewLocalStore(dir string) *LocalStore {
+	return &LocalStore{dir: dir}
+}
+
+func (s *LocalStore) Put(key string, data []byte) error {
+	file := filepath.Join(s.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+
+	// written to a temp file first, a crash never leaves a partial object behind
+	tmp, err := os.CreateTemp(filepath.Dir(file), filepath.Base(file)+".tmp*")
+	if err != nil {
+		return err
+	}
+	if _, err = tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
+func (s *LocalStore) Get(key string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir, filepath.FromSlash(key)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return data, err
+}
+
+// ObjectStore keeps the objects in a bucket of an s3 compatible object store, under prefix.
+type ObjectStore struct {
+	client  *minio.Client
+	bucket  string
+	prefix  string
+	timeout time.Duration
+}
+
+func NewObjectStore(conf *config.ArchiveConf) (*ObjectStore, error) {
+	client, err := minio.New(conf.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(conf.AccessKey, conf.SecretKey, ""),
+		Secure: conf.UseSSL,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ObjectStore{
+		client:  client,
+		bucket:  conf.Bucket,
+		prefix:  conf.Prefix,
+		timeout: time.Duration(conf.TimeoutMs) * time.Millisecond,
+	}, nil
+}
+
+func (s *ObjectStore) Put(key string, data []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	_, err := s.client.PutObject(ctx, s.bucket, path.Join(s.prefix, key), bytes.NewReader(data), int64(len(data)),
+		minio.PutObjectOptions{ContentType: "application/octet-stream"})
+	return err
+}
+
+func (s *ObjectStore) Get(key string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	object, err := s.client.GetObject(ctx, s.bucket, path.Join(s.prefix, key), minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer object.Close()
+
+	data, err := io.ReadAll(object)
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return data, nil
+}
@@ -1,13 +1,16 @@
 package block_getter
 
 import (
+	"base_scan/archive"
 	"base_scan/cache"
+	"base_scan/chain"
 	"base_scan/config"
 	"base_scan/log"
 	"base_scan/metrics"
 	"base_scan/sequencer"
 	"base_scan/types"
 	"context"
+	"errors"
 	"github.com/avast/retry-go/v4"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
@@ -43,34 +46,69 @@ type blockGetter struct {
 	blockSequencer  sequencer.BlockSequencer
 	headerHeight    SafeVar[uint64]
 	retryParams     *config.RetryParams
+	profile         *chain.Profile
+	archive         *archive.Archive
+	reparse         bool
+	endBlockNumber  uint64
 }
 
 func NewBlockGetter(ethClient *ethclient.Client,
 	wsEthClient *ethclient.Client,
 	cache cache.BlockCache,
 	blockSequencer sequencer.BlockSequencer,
-	retryParams *config.RetryParams,
+	conf *config.BlockGetterConf,
+	archiveConf *config.ArchiveConf,
+	blockArchive *archive.Archive,
+	profile *chain.Profile,
 ) BlockGetter {
-	workPool, err := ants.NewPool(config.G.BlockGetter.PoolSize)
+	workPool, err := ants.NewPool(conf.PoolSize)
 	if err != nil {
 		log.Logger.Fatal("ants pool(BlockGetter) init err", zap.Error(err))
 	}
 
+	// a reparse ends at the end of the archived range
+	endBlockNumber := conf.EndBlockNumber
+	if archiveConf.Reparse {
+		endBlockNumber = archiveConf.ReparseEndBlock
+	}
+
 	return &blockGetter{
 		ctx:             context.Background(),
 		ethClient:       ethClient,
 		wsEthClient:     wsEthClient,
-		inputQueue:      make(chan uint64, config.G.BlockGetter.QueueSize),
+		inputQueue:      make(chan uint64, conf.QueueSize),
 		outputBuffer:    make(chan *types.ParseBlockContext, 10),
 		workPool:        workPool,
 		cache:           cache,
 		blockHeaderChan: make(chan *ethtypes.Header, 100),
 		blockSequencer:  blockSequencer,
-		retryParams:     retryParams,
+		retryParams:     conf.Retry.GetRetryParams(),
+		profile:         profile,
+		archive:         blockArchive,
+		reparse:         archiveConf.Reparse,
+		endBlockNumber:  endBlockNumber,
+	}
+}
+
+func (bg *blockGetter) newParseBlockContext(block *ethtypes.Block, blockReceipts []*ethtypes.Receipt) *types.ParseBlockContext {
+	return &types.ParseBlockContext{
+		ChainConfig:      bg.profile.ChainConfig,
+		Block:            block,
+		BlockReceipts:    blockReceipts,
+		HeightTime:       types.GetBlockHeightTime(block.Header()),
+		TxIndex2TxSender: make(map[uint]common.Address, block.Transactions().Len()),
 	}
 }
 
 func (bg *blockGetter) getBlock(blockNumber uint64) (*types.ParseBlockContext, error) {
+	if bg.reparse {
+		block, blockReceipts, err := bg.archive.Get(blockNumber)
+		if err != nil {
+			return nil, err
+		}
+		return bg.newParseBlockContext(block, blockReceipts), nil
+	}
+
 	var (
 		block          *ethtypes.Block
 		blockReceipts  []*ethtypes.Receipt
@@ -110,18 +148,22 @@ func (bg *blockGetter) getBlock(blockNumber uint64) (*types.ParseBlockContext, e
 
 	metrics.BlockDelay.Observe(time.Now().Sub(time.Unix((int64)(block.Time()), 0)).Seconds())
 
-	return &types.ParseBlockContext{
-		Block:            block,
-		BlockReceipts:    blockReceipts,
-		HeightTime:       types.GetBlockHeightTime(block.Header()),
-		TxIndex2TxSender: make(map[uint]common.Address, block.Transactions().Len()),
-	}, nil
+	// a block that can't be archived is still parsed, the archive only misses it
+	if bg.archive != nil {
+		if err := bg.archive.Put(block, blockReceipts); err != nil {
+			log.Logger.Error("archive block err", zap.Uint64("blockNumber", blockNumber), zap.Error(err))
+		}
+	}
+
+	return bg.newParseBlockContext(block, blockReceipts), nil
 }
 
 func (bg *blockGetter) getBlockWithRetry(blockNumber uint64) (*types.ParseBlockContext, error) {
 	return retry.DoWithData(func() (*types.ParseBlockContext, error) {
 		return bg.getBlock(blockNumber)
-	}, bg.retryParams.Attempts, bg.retryParams.Delay)
+	}, bg.retryParams.Attempts, bg.retryParams.Delay, retry.RetryIf(func(err error) bool {
+		return !errors.Is(err, archive.ErrNotFound)
+	}))
 }
 
 func (bg *blockGetter) GetBlockAsync(blockNumber uint64) {
@@ -151,12 +193,16 @@ func (bg *blockGetter) Start() {
 					log.Logger.Info("get block start", zap.Uint64("block_number", blockNumber))
 					bw, err := bg.getBlockWithRetry(blockNumber)
 					if err != nil {
+						// no other source has the block, the blocks after it would wait for it forever
+						if bg.reparse {
+							log.Logger.Fatal("get archived block err", zap.Uint64("blockNumber", blockNumber), zap.Error(err))
+						}
 						log.Logger.Error("get block err", zap.Uint64("blockNumber", blockNumber), zap.Error(err))
 						return
 					}
 
 					log.Logger.Info("get block success", zap.Uint64("blockNumber", blockNumber))
-					metrics.BlockQueueSize.Set(float64(len(bg.outputBuffer)))
+					metrics.BlockQueueSize.WithLabelValues(bg.profile.Name).Set(float64(len(bg.outputBuffer)))
 					bg.blockSequencer.Commit(bw, bg.outputBuffer)
 				})
 			}
@@ -265,7 +311,7 @@ func (bg *blockGetter) startSubscribeNewHead() {
 				height := blockHeader.Number.Uint64()
 				log.Logger.Info("New block", zap.Uint64("height", height))
 				bg.setHeaderHeight(height)
-				metrics.NewestHeight.Set(float64(height))
+				metrics.NewestHeight.WithLabelValues(bg.profile.Name).Set(float64(height))
 
 				noBlockTimeout.Stop()
 				select {
@@ -315,7 +361,7 @@ func (bg *blockGetter) startSubscribeNewHead2() {
 			case blockHeader := <-bg.blockHeaderChan:
 				log.Logger.Info("receive block header", zap.Any("height", blockHeader.Number))
 				headerHeight = blockHeader.Number.Uint64()
-				metrics.NewestHeight.Set(float64(headerHeight))
+				metrics.NewestHeight.WithLabelValues(bg.profile.Name).Set(float64(headerHeight))
 				bg.setHeaderHeight(headerHeight)
 			}
 		}
@@ -333,6 +379,11 @@ func (bg *blockGetter) dispatchRange(from, to uint64) (stopped bool, nextBlock u
 }
 
 func (bg *blockGetter) StartDispatch(startBlockNumber uint64) {
+	if bg.endBlockNumber > 0 {
+		bg.startDispatchToEnd(startBlockNumber)
+		return
+	}
+
 	bg.startSubscribeNewHead()
 
 	go func() {
@@ -356,6 +407,19 @@ func (bg *blockGetter) StartDispatch(startBlockNumber uint64) {
 	}()
 }
 
+// startDispatchToEnd dispatches the blocks up to the end block without following the chain head, then stops.
+func (bg *blockGetter) startDispatchToEnd(startBlockNumber uint64) {
+	go func() {
+		stopped, nextBlockHeight := bg.dispatchRange(startBlockNumber, bg.endBlockNumber)
+		if stopped {
+			log.Logger.Info("dispatch interrupted", zap.Uint64("nextBlockHeight", nextBlockHeight))
+		} else {
+			log.Logger.Info("all blocks dispatched", zap.Uint64("endBlockHeight", bg.endBlockNumber))
+		}
+		bg.doStop()
+	}()
+}
+
 func (bg *blockGetter) Stop() {
 	bg.stopped.Set(true)
 }
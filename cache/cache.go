@@ -33,16 +33,30 @@ type PairCache interface {
 	DelPair(address common.Address)
 }
 
+// PoolCache holds the multi-asset pools, the pairs of their traded tokens are not cached.
+type PoolCache interface {
+	SetPool(pool *types.Pool)
+	GetPool(address common.Address) (*types.Pool, bool)
+	DelPool(address common.Address)
+}
+
 type BlockCache interface {
 	SetFinishedBlock(blockNumber uint64)
 	GetFinishedBlock() uint64
 }
 
+type MakerCache interface {
+	SetMaker(maker *types.Maker)
+	GetMaker(address common.Address) (*types.Maker, bool)
+}
+
 type Cache interface {
 	PriceCache
 	TokenCache
 	PairCache
+	PoolCache
 	BlockCache
+	MakerCache
 }
 
 type twoTierCache struct {
@@ -71,6 +85,14 @@ func PairCacheKey(address common.Address) string {
 	return fmt.Sprintf("npr:%s", address.Hex())
 }
 
+func PoolCacheKey(address common.Address) string {
+	return fmt.Sprintf("pl:%s", address.Hex())
+}
+
+func MakerCacheKey(address common.Address) string {
+	return fmt.Sprintf("mk:%s", address.Hex())
+}
+
 func (c *twoTierCache) SetPrice(blockNumber *big.Int, price decimal.Decimal) {
 	k := PriceCacheKey(blockNumber)
 	c.memory.Set(k, price, cache.DefaultExpiration)
@@ -184,12 +206,51 @@ func (c *twoTierCache) DelPair(address common.Address) {
 	}
 }
 
+func (c *twoTierCache) SetPool(pool *types.Pool) {
+	pool.Timestamp = time.Now()
+	k := PoolCacheKey(pool.Address)
+	c.memory.Set(k, pool, cache.DefaultExpiration)
+	err := c.redis.Set(c.ctx, k, pool, 0).Err()
+	if err != nil {
+		log.Logger.Error("save pool failed", zap.Error(err))
+	}
+}
+
+func (c *twoTierCache) GetPool(address common.Address) (*types.Pool, bool) {
+	k := PoolCacheKey(address)
+	pool, ok := c.memory.Get(k)
+	if ok {
+		return pool.(*types.Pool), true
+	}
+
+	v := &types.Pool{}
+	err := c.redis.Get(c.ctx, k).Scan(v)
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Logger.Error("redis get err", zap.Error(err))
+		}
+		return nil, false
+	}
+
+	c.memory.Set(k, v, cache.DefaultExpiration)
+	return v, true
+}
+
+func (c *twoTierCache) DelPool(address common.Address) {
+	k := PoolCacheKey(address)
+	c.memory.Delete(k)
+	err := c.redis.Del(c.ctx, k).Err()
+	if err != nil {
+		log.Logger.Error("redis del err", zap.Error(err))
+	}
+}
+
 func (c *twoTierCache) SetFinishedBlock(blockNumber uint64) {
-	c.redis.Set(c.ctx, "fb", blockNumber, 0)
+	c.redis.Set(c.ctx, finishedBlockKey, blockNumber, 0)
 }
 
 func (c *twoTierCache) GetFinishedBlock() uint64 {
-	v, err := c.redis.Get(c.ctx, "fb").Uint64()
+	v, err := c.redis.Get(c.ctx, finishedBlockKey).Uint64()
 	if err != nil {
 		if !errors.Is(err, redis.Nil) {
 			log.Logger.Error("redis get err", zap.Error(err))
@@ -198,3 +259,33 @@ func (c *twoTierCache) GetFinishedBlock() uint64 {
 	}
 	return v
 }
+
+func (c *twoTierCache) SetMaker(maker *types.Maker) {
+	maker.Timestamp = time.Now()
+	k := MakerCacheKey(maker.Address)
+	c.memory.Set(k, maker, cache.DefaultExpiration)
+	err := c.redis.Set(c.ctx, k, maker, 0).Err()
+	if err != nil {
+		log.Logger.Error("save maker failed", zap.Error(err))
+	}
+}
+
+func (c *twoTierCache) GetMaker(address common.Address) (*types.Maker, bool) {
+	k := MakerCacheKey(address)
+	maker, ok := c.memory.Get(k)
+	if ok {
+		return maker.(*types.Maker), true
+	}
+
+	v := &types.Maker{}
+	err := c.redis.Get(c.ctx, k).Scan(v)
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Logger.Error("redis get err", zap.Error(err))
+		}
+		return nil, false
+	}
+
+	c.memory.Set(k, v, cache.DefaultExpiration)
+	return v, true
+}
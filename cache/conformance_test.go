@@ -0,0 +1,237 @@
+package cache
+
+import (
+	"base_scan/types"
+	"context"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-redis/redis/v8"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+	"time"
+)
+
+var (
+	conformanceAddress = common.HexToAddress("0xe76004cffcab665c4692f663b8fb2a2f66adda9b")
+	conformanceMissing = common.HexToAddress("0x000000000000000000000000000000000000dead")
+)
+
+func newConformanceToken() *types.Token {
+	return &types.Token{
+		Address:     conformanceAddress,
+		Creator:     conformanceAddress,
+		Name:        "test",
+		Symbol:      "test",
+		Decimals:    18,
+		TotalSupply: decimal.NewFromInt(1),
+		BlockNumber: 1,
+		BlockTime:   time.Unix(1000, 0).UTC(),
+		Program:     "test",
+	}
+}
+
+// the pair has the same address as the token to check that tokens and pairs don't share keys
+func newConformancePair() *types.Pair {
+	return &types.Pair{
+		Address:          conformanceAddress,
+		Token0Core:       &types.TokenCore{Address: conformanceAddress, Symbol: "test", Decimals: 18},
+		Token1Core:       &types.TokenCore{Address: types.WETHAddress, Symbol: "WETH", Decimals: 18},
+		Token0InitAmount: decimal.NewFromInt(100),
+		Token1InitAmount: decimal.NewFromInt(1),
+		Block:            1,
+		BlockAt:          time.Unix(1000, 0).UTC(),
+		ProtocolId:       types.ProtocolIdUniswapV2,
+	}
+}
+
+// the pool has the same address as the pair to check that pairs and pools don't share keys
+func newConformancePool() *types.Pool {
+	return &types.Pool{
+		Address:    conformanceAddress,
+		PoolId:     common.HexToHash("0x01"),
+		ProtocolId: types.ProtocolIdBalancerV2,
+		Tokens:     []common.Address{conformanceAddress, types.WETHAddress, types.USDCAddress},
+		Block:      1,
+		BlockAt:    time.Unix(1000, 0).UTC(),
+	}
+}
+
+func newConformanceMaker() *types.Maker {
+	return &types.Maker{
+		Address:          conformanceAddress,
+		Kind:             types.MakerKindEOA,
+		KindCheckedBlock: 1,
+		Class:            types.MakerClassBot,
+		FirstBlock:       1,
+		LastBlock:        2,
+		Buys:             3,
+		Sells:            2,
+		VolumeUsd:        decimal.RequireFromString("12.5"),
+		Total:            types.MakerActivity{Trades: 5, ActiveBlocks: 2, BusyBlocks: 1, RoundTrips: 1},
+		WindowStart:      1,
+		Window:           types.MakerActivity{Trades: 5, ActiveBlocks: 2, BusyBlocks: 1, RoundTrips: 1},
+	}
+}
+
+/*
+testCacheConformance checks the semantics every Cache backend must share.
+newCache must return an empty cache.
+*/
+func testCacheConformance(t *testing.T, newCache func(t *testing.T) Cache) {
+	t.Run("price", func(t *testing.T) {
+		c := newCache(t)
+		_, ok := c.GetPrice(big.NewInt(1))
+		require.False(t, ok)
+
+		price := decimal.RequireFromString("33.33")
+		c.SetPrice(big.NewInt(1), price)
+		getPrice, ok := c.GetPrice(big.NewInt(1))
+		require.True(t, ok)
+		require.True(t, price.Equal(getPrice))
+
+		_, ok = c.GetPrice(big.NewInt(2))
+		require.False(t, ok)
+	})
+
+	t.Run("token", func(t *testing.T) {
+		c := newCache(t)
+		_, ok := c.GetToken(conformanceAddress)
+		require.False(t, ok)
+
+		c.SetToken(newConformanceToken())
+		token, ok := c.GetToken(conformanceAddress)
+		require.True(t, ok)
+		require.True(t, token.Equal(newConformanceToken()))
+
+		_, ok = c.GetToken(conformanceMissing)
+		require.False(t, ok)
+
+		c.DelToken(conformanceAddress)
+		_, ok = c.GetToken(conformanceAddress)
+		require.False(t, ok)
+	})
+
+	t.Run("pair", func(t *testing.T) {
+		c := newCache(t)
+		require.False(t, c.PairExist(conformanceAddress))
+
+		c.SetToken(newConformanceToken())
+		c.SetPair(newConformancePair())
+		pair, ok := c.GetPair(conformanceAddress)
+		require.True(t, ok)
+		require.True(t, pair.Equal(newConformancePair()))
+		require.True(t, c.PairExist(conformanceAddress))
+		require.False(t, c.PairExist(conformanceMissing))
+
+		c.DelPair(conformanceAddress)
+		require.False(t, c.PairExist(conformanceAddress))
+		_, ok = c.GetToken(conformanceAddress)
+		require.True(t, ok)
+	})
+
+	t.Run("pool", func(t *testing.T) {
+		c := newCache(t)
+		_, ok := c.GetPool(conformanceAddress)
+		require.False(t, ok)
+
+		c.SetPair(newConformancePair())
+		c.SetPool(newConformancePool())
+		pool, ok := c.GetPool(conformanceAddress)
+		require.True(t, ok)
+		require.True(t, pool.Equal(newConformancePool()))
+
+		_, ok = c.GetPool(conformanceMissing)
+		require.False(t, ok)
+
+		c.DelPool(conformanceAddress)
+		_, ok = c.GetPool(conformanceAddress)
+		require.False(t, ok)
+		require.True(t, c.PairExist(conformanceAddress))
+	})
+
+	t.Run("maker", func(t *testing.T) {
+		c := newCache(t)
+		_, ok := c.GetMaker(conformanceAddress)
+		require.False(t, ok)
+
+		c.SetMaker(newConformanceMaker())
+		maker, ok := c.GetMaker(conformanceAddress)
+		require.True(t, ok)
+		require.True(t, maker.Equal(newConformanceMaker()))
+
+		_, ok = c.GetMaker(conformanceMissing)
+		require.False(t, ok)
+		_, ok = c.GetToken(conformanceAddress)
+		require.False(t, ok)
+	})
+
+	t.Run("finished block", func(t *testing.T) {
+		c := newCache(t)
+		require.Equal(t, uint64(0), c.GetFinishedBlock())
+
+		c.SetFinishedBlock(100)
+		require.Equal(t, uint64(100), c.GetFinishedBlock())
+
+		c.SetFinishedBlock(101)
+		require.Equal(t, uint64(101), c.GetFinishedBlock())
+	})
+}
+
+func TestMockCacheConformance(t *testing.T) {
+	testCacheConformance(t, func(t *testing.T) Cache {
+		return NewMockCache()
+	})
+}
+
+func TestPebbleCacheConformance(t *testing.T) {
+	testCacheConformance(t, func(t *testing.T) Cache {
+		c, err := NewPebbleCache(t.TempDir())
+		require.NoError(t, err)
+		t.Cleanup(func() { c.Close() })
+		return c
+	})
+}
+
+func TestPebbleCacheReopen(t *testing.T) {
+	dir := t.TempDir()
+	c, err := NewPebbleCache(dir)
+	require.NoError(t, err)
+	c.SetToken(newConformanceToken())
+	c.SetPair(newConformancePair())
+	c.SetPrice(big.NewInt(1), decimal.RequireFromString("33.33"))
+	c.SetFinishedBlock(100)
+	require.NoError(t, c.Close())
+
+	c, err = NewPebbleCache(dir)
+	require.NoError(t, err)
+	defer c.Close()
+
+	token, ok := c.GetToken(conformanceAddress)
+	require.True(t, ok)
+	require.True(t, token.Equal(newConformanceToken()))
+	pair, ok := c.GetPair(conformanceAddress)
+	require.True(t, ok)
+	require.True(t, pair.Equal(newConformancePair()))
+	price, ok := c.GetPrice(big.NewInt(1))
+	require.True(t, ok)
+	require.True(t, price.Equal(decimal.RequireFromString("33.33")))
+	require.Equal(t, uint64(100), c.GetFinishedBlock())
+}
+
+// runs on db 15 of a local redis, which is flushed before every case
+func TestTwoTierCacheConformance(t *testing.T) {
+	redisClient := redis.NewClient(&redis.Options{
+		Addr: "localhost:6379",
+		DB:   15,
+	})
+	defer redisClient.Close()
+	if err := redisClient.Ping(context.Background()).Err(); err != nil {
+		t.Skip("redis not available:", err)
+	}
+
+	testCacheConformance(t, func(t *testing.T) Cache {
+		require.NoError(t, redisClient.FlushDB(context.Background()).Err())
+		return NewTwoTierCache(redisClient)
+	})
+}
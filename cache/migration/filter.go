@@ -0,0 +1,56 @@
+package migration
+
+import (
+	"base_scan/types"
+	"time"
+)
+
+const (
+	filterReasonBeforeV2 = "filtered before cache schema v2"
+)
+
+/*
+v1 filtered values have no FilteredAt, they are stamped with the time they were cached, their Timestamp,
+or with the zero time when they have none, which makes them expire at once.
+The stamp only depends on the value, so migrating it again or on another replica gives the same value.
+Filtered tokens could only fail on decimals.
+*/
+func registerFilterMigrations() {
+	stampFilteredAt := func(value Value) {
+		if filtered, _ := value["Filtered"].(bool); filtered {
+			filteredAt := time.Time{}.Format(time.RFC3339Nano)
+			if timestamp, ok := value["Timestamp"].(string); ok {
+				if _, err := time.Parse(time.RFC3339Nano, timestamp); err == nil {
+					filteredAt = timestamp
+				}
+			}
+			value["FilteredAt"] = filteredAt
+			value["FilterReason"] = filterReasonBeforeV2
+		}
+	}
+
+	tokenV1 := &Migration{
+		From:        1,
+		Description: "record filter code and time of filtered tokens",
+		Up: func(value Value) error {
+			if filtered, _ := value["Filtered"].(bool); filtered {
+				value["FilterCode"] = types.TokenFilterCodeGetDecimals
+			}
+			stampFilteredAt(value)
+			return nil
+		},
+	}
+	Register(SchemaToken, tokenV1)
+	Register(SchemaLegacyToken, tokenV1)
+
+	pairV1 := &Migration{
+		From:        1,
+		Description: "record filter time of filtered pairs",
+		Up: func(value Value) error {
+			stampFilteredAt(value)
+			return nil
+		},
+	}
+	Register(SchemaPair, pairV1)
+	Register(SchemaLegacyPair, pairV1)
+}
@@ -0,0 +1,153 @@
+package migration
+
+import (
+	"base_scan/types"
+	"encoding/json"
+	"errors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"math/big"
+	"time"
+)
+
+var (
+	ErrLegacyPairTokenMissing = errors.New("legacy pair token missing")
+)
+
+// legacyToken is the token value cached under `t:` before the switch to `nt:`
+type legacyToken struct {
+	Address        common.Address
+	Creator        common.Address
+	Name           string
+	Symbol         string
+	Decimals       int16
+	TotalSupply    decimal.Decimal
+	BlockNumber    *big.Int
+	BlockTime      time.Time
+	Filtered       bool
+	FilteredReason int
+	Program        string
+	MainPair       common.Address
+}
+
+func (t *legacyToken) toToken() *types.Token {
+	token := &types.Token{
+		Address:     t.Address,
+		Creator:     t.Creator,
+		Name:        t.Name,
+		Symbol:      t.Symbol,
+		Decimals:    int8(t.Decimals),
+		TotalSupply: t.TotalSupply,
+		BlockTime:   t.BlockTime,
+		Program:     t.Program,
+		Filtered:    t.Filtered,
+	}
+
+	if t.BlockNumber != nil {
+		token.BlockNumber = t.BlockNumber.Uint64()
+	}
+
+	return token
+}
+
+type legacyTokenInfo struct {
+	Address     common.Address
+	Name        string
+	Symbol      string
+	Decimals    int16
+	TotalSupply decimal.Decimal
+}
+
+// legacyPair is the pair value cached under `pr:` before the switch to `npr:`
+type legacyPair struct {
+	Address    common.Address
+	Token0     *legacyTokenInfo
+	Token1     *legacyTokenInfo
+	Block      uint64
+	BlockAt    time.Time
+	TxIndex    uint
+	TxHash     common.Hash
+	From       common.Address
+	ProtocolId int
+	Filtered   bool
+	FilterCode int
+}
+
+func (p *legacyPair) toPair() (*types.Pair, error) {
+	if p.Token0 == nil || p.Token1 == nil {
+		return nil, ErrLegacyPairTokenMissing
+	}
+
+	pair := &types.Pair{
+		Address: p.Address,
+		Token0Core: &types.TokenCore{
+			Address:  p.Token0.Address,
+			Symbol:   p.Token0.Symbol,
+			Decimals: int8(p.Token0.Decimals),
+		},
+		Token1Core: &types.TokenCore{
+			Address:  p.Token1.Address,
+			Symbol:   p.Token1.Symbol,
+			Decimals: int8(p.Token1.Decimals),
+		},
+		Block:      p.Block,
+		BlockAt:    p.BlockAt,
+		ProtocolId: p.ProtocolId,
+		Filtered:   p.Filtered,
+		FilterCode: p.FilterCode,
+	}
+
+	// legacy values were only written by the base indexer
+	pair.OrderToken0Token1(types.DefaultBaseTokens)
+	return pair, nil
+}
+
+// convert decodes value as from, and replaces value with to(from) encoded by its MarshalBinary
+func convert[From any, To interface{ MarshalBinary() ([]byte, error) }](value Value, to func(*From) (To, error)) error {
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	from := new(From)
+	if err = json.Unmarshal(bytes, from); err != nil {
+		return err
+	}
+
+	converted, err := to(from)
+	if err != nil {
+		return err
+	}
+
+	bytes, err = converted.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	for k := range value {
+		delete(value, k)
+	}
+	return json.Unmarshal(bytes, &value)
+}
+
+func registerLegacyMigrations() {
+	Register(SchemaLegacyToken, &Migration{
+		From:        0,
+		Description: "convert `t:` token to `nt:` token",
+		Up: func(value Value) error {
+			return convert(value, func(t *legacyToken) (*types.Token, error) {
+				return t.toToken(), nil
+			})
+		},
+	})
+
+	Register(SchemaLegacyPair, &Migration{
+		From:        0,
+		Description: "convert `pr:` pair to `npr:` pair, base token ordered as token1",
+		Up: func(value Value) error {
+			return convert(value, func(p *legacyPair) (*types.Pair, error) {
+				return p.toPair()
+			})
+		},
+	})
+}
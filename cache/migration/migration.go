@@ -0,0 +1,147 @@
+package migration
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+const (
+	schemaVersionField = "SchemaVersion"
+)
+
+var (
+	ErrMigrationNotFound = errors.New("migration not found")
+	ErrVersionTooNew     = errors.New("value version is newer than schema version")
+	ErrSchemaNotFound    = errors.New("schema not found")
+	errUpToDate          = errors.New("up to date")
+)
+
+// Value is a cached json value decoded without a concrete type, so that any version can be read.
+type Value map[string]interface{}
+
+/*
+Migration upgrades a Value from version From to From+1 in place.
+Migrations must be deterministic, the runner may apply them again after an interrupted run.
+*/
+type Migration struct {
+	From        int
+	Description string
+	Up          func(value Value) error
+}
+
+/*
+Schema describes one kind of cached value.
+- Prefix: keys of this kind are scanned with `Prefix*`
+- DefaultVersion: version of values written before SchemaVersion existed
+- CurrentVersion: version written by the current code
+- TargetKey: optional, the migrated value is written to this key instead of the scanned one
+- Validate: decodes the migrated value with the current type
+*/
+type Schema struct {
+	Name           string
+	Prefix         string
+	DefaultVersion int
+	CurrentVersion int
+	TargetKey      func(value Value) (string, error)
+	Validate       func(data []byte) error
+	migrations     map[int]*Migration
+}
+
+var schemas = map[string]*Schema{}
+
+func RegisterSchema(schema *Schema) {
+	if _, ok := schemas[schema.Name]; ok {
+		panic(fmt.Sprintf("schema %s registered twice", schema.Name))
+	}
+	schema.migrations = make(map[int]*Migration)
+	schemas[schema.Name] = schema
+}
+
+func Register(schemaName string, migration *Migration) {
+	schema, ok := schemas[schemaName]
+	if !ok {
+		panic(fmt.Sprintf("schema %s not registered", schemaName))
+	}
+	if _, ok = schema.migrations[migration.From]; ok {
+		panic(fmt.Sprintf("schema %s migration from version %d registered twice", schemaName, migration.From))
+	}
+	schema.migrations[migration.From] = migration
+}
+
+func GetSchema(name string) (*Schema, error) {
+	schema, ok := schemas[name]
+	if !ok {
+		return nil, ErrSchemaNotFound
+	}
+	return schema, nil
+}
+
+func SchemaNames() []string {
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (v Value) Version(defaultVersion int) int {
+	switch version := v[schemaVersionField].(type) {
+	case float64:
+		return int(version)
+	case int:
+		return version
+	default:
+		return defaultVersion
+	}
+}
+
+func (v Value) SetVersion(version int) {
+	v[schemaVersionField] = version
+}
+
+/*
+Migrate upgrades data to CurrentVersion.
+It returns the migrated value, or nil if data is already up to date.
+*/
+func (s *Schema) Migrate(data []byte) (Value, error) {
+	value := Value{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+
+	version := value.Version(s.DefaultVersion)
+	if version == s.CurrentVersion {
+		return nil, nil
+	}
+
+	if version > s.CurrentVersion {
+		return nil, fmt.Errorf("%w: %d > %d", ErrVersionTooNew, version, s.CurrentVersion)
+	}
+
+	for ; version < s.CurrentVersion; version++ {
+		migration, ok := s.migrations[version]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s from version %d", ErrMigrationNotFound, s.Name, version)
+		}
+
+		if err := migration.Up(value); err != nil {
+			return nil, fmt.Errorf("%s migration from version %d: %w", s.Name, version, err)
+		}
+		value.SetVersion(version + 1)
+	}
+
+	return value, nil
+}
+
+// Check reports a missing step in the migration chain up to CurrentVersion.
+func (s *Schema) Check() error {
+	for version := s.DefaultVersion; version < s.CurrentVersion; version++ {
+		if _, ok := s.migrations[version]; !ok {
+			return fmt.Errorf("%w: %s from version %d", ErrMigrationNotFound, s.Name, version)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package migration
+
+import (
+	"base_scan/types"
+	"encoding/json"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestSchemasComplete(t *testing.T) {
+	for _, name := range SchemaNames() {
+		schema, err := GetSchema(name)
+		require.NoError(t, err)
+		require.NoError(t, schema.Check(), name)
+	}
+}
+
+func TestMigrateLegacyToken(t *testing.T) {
+	tokenString := `
+{
+  "Address": "0xf9ee4ce4ddbdd46bd01b55630ab98da2eddb4444",
+  "Creator": "0x0000000000000000000000000000000000000000",
+  "Name": "Arrion Knight",
+  "Symbol": "YNCODING",
+  "Decimals": 18,
+  "TotalSupply": "1000000000",
+  "BlockNumber": 123,
+  "BlockTime": "0001-01-01T00:00:00Z",
+  "Filtered": false,
+  "FilteredReason": 0,
+  "Program": "",
+  "MainPair": "0x0000000000000000000000000000000000000000"
+}`
+	schema, err := GetSchema(SchemaLegacyToken)
+	require.NoError(t, err)
+
+	value, err := schema.Migrate([]byte(tokenString))
+	require.NoError(t, err)
+	require.NotNil(t, value)
+	require.Equal(t, types.TokenSchemaVersion, value.Version(0))
+
+	key, err := schema.TargetKey(value)
+	require.NoError(t, err)
+	require.Equal(t, "nt:0xf9EE4ce4ddBdd46bD01B55630Ab98Da2eddb4444", key)
+
+	bytes, err := json.Marshal(value)
+	require.NoError(t, err)
+	token := &types.Token{}
+	require.NoError(t, token.UnmarshalBinary(bytes))
+	require.Equal(t, common.HexToAddress("0xf9ee4ce4ddbdd46bd01b55630ab98da2eddb4444"), token.Address)
+	require.Equal(t, "YNCODING", token.Symbol)
+	require.Equal(t, int8(18), token.Decimals)
+	require.True(t, token.TotalSupply.Equal(decimal.NewFromInt(1000000000)))
+	require.Equal(t, uint64(123), token.BlockNumber)
+}
+
+func TestMigrateLegacyPair(t *testing.T) {
+	pairString := `
+{
+  "Address": "0xcff245725bf2e1219171dcebbd793ac00fc99b09",
+  "Token0": {
+    "Address": "0x4200000000000000000000000000000000000006",
+    "Name": "",
+    "Symbol": "WETH",
+    "Decimals": 18,
+    "TotalSupply": "0"
+  },
+  "Token1": {
+    "Address": "0x73a3daba0801c4904cbb8d71353be40f574028e2",
+    "Name": "",
+    "Symbol": "GRDY",
+    "Decimals": 9,
+    "TotalSupply": "0"
+  },
+  "Block": 100,
+  "BlockAt": "2025-01-01T00:00:00Z",
+  "TxIndex": 0,
+  "TxHash": "0x0000000000000000000000000000000000000000000000000000000000000000",
+  "From": "0x0000000000000000000000000000000000000000",
+  "ProtocolId": 2,
+  "Filtered": false,
+  "FilterCode": 0
+}`
+	schema, err := GetSchema(SchemaLegacyPair)
+	require.NoError(t, err)
+
+	value, err := schema.Migrate([]byte(pairString))
+	require.NoError(t, err)
+	require.NotNil(t, value)
+
+	bytes, err := json.Marshal(value)
+	require.NoError(t, err)
+	pair := &types.Pair{}
+	require.NoError(t, pair.UnmarshalBinary(bytes))
+	require.True(t, pair.TokensReversed)
+	require.Equal(t, "GRDY", pair.Token0Core.Symbol)
+	require.Equal(t, int8(9), pair.Token0Core.Decimals)
+	require.True(t, types.DefaultBaseTokens.IsNative(pair.Token1Core.Address))
+	require.Equal(t, uint64(100), pair.Block)
+	require.Equal(t, 2, pair.ProtocolId)
+}
+
+func TestMigrateLegacyPairTokenMissing(t *testing.T) {
+	schema, err := GetSchema(SchemaLegacyPair)
+	require.NoError(t, err)
+
+	_, err = schema.Migrate([]byte(`{"Address": "0xcff245725bf2e1219171dcebbd793ac00fc99b09", "Token0": null}`))
+	require.ErrorIs(t, err, ErrLegacyPairTokenMissing)
+}
+
+func TestMigrateUpToDate(t *testing.T) {
+	token := &types.Token{
+		Address:   common.HexToAddress("0xf9ee4ce4ddbdd46bd01b55630ab98da2eddb4444"),
+		Symbol:    "YNCODING",
+		BlockTime: time.Unix(0, 0).UTC(),
+	}
+	bytes, err := token.MarshalBinary()
+	require.NoError(t, err)
+
+	schema, err := GetSchema(SchemaToken)
+	require.NoError(t, err)
+
+	value, err := schema.Migrate(bytes)
+	require.NoError(t, err)
+	require.Nil(t, value)
+
+	// values written before SchemaVersion existed
+	value, err = schema.Migrate([]byte(`{"Address": "0xf9ee4ce4ddbdd46bd01b55630ab98da2eddb4444"}`))
+	require.NoError(t, err)
+	require.Equal(t, schema.DefaultVersion == schema.CurrentVersion, value == nil)
+}
+
+func TestMigrateFilteredTokenV1(t *testing.T) {
+	schema, err := GetSchema(SchemaToken)
+	require.NoError(t, err)
+
+	migrate := func(raw string) *types.Token {
+		value, err := schema.Migrate([]byte(raw))
+		require.NoError(t, err)
+		bytes, err := json.Marshal(value)
+		require.NoError(t, err)
+		token := &types.Token{}
+		require.NoError(t, token.UnmarshalBinary(bytes))
+		return token
+	}
+
+	cachedAt := time.Now().Add(-10 * time.Minute).UTC()
+	raw := fmt.Sprintf(
+		`{"Address": "0xf9ee4ce4ddbdd46bd01b55630ab98da2eddb4444", "Filtered": true, "Timestamp": "%s"}`,
+		cachedAt.Format(time.RFC3339Nano),
+	)
+	token := migrate(raw)
+	require.True(t, token.Filtered)
+	require.Equal(t, types.TokenFilterCodeGetDecimals, token.FilterCode)
+	require.True(t, cachedAt.Equal(token.FilteredAt))
+	require.False(t, token.FilterExpired(time.Hour))
+	require.True(t, migrate(raw).FilteredAt.Equal(token.FilteredAt))
+
+	// no time the value was cached, it expires at once
+	token = migrate(`{"Address": "0xf9ee4ce4ddbdd46bd01b55630ab98da2eddb4444", "Filtered": true}`)
+	require.True(t, token.FilteredAt.IsZero())
+	require.True(t, token.FilterExpired(time.Hour))
+}
+
+func TestMigrateVersionTooNew(t *testing.T) {
+	schema, err := GetSchema(SchemaPair)
+	require.NoError(t, err)
+
+	_, err = schema.Migrate([]byte(`{"SchemaVersion": 1000}`))
+	require.ErrorIs(t, err, ErrVersionTooNew)
+}
+
+func TestMigratePairParams(t *testing.T) {
+	schema, err := GetSchema(SchemaPair)
+	require.NoError(t, err)
+	// the forks registered now don't change the migration
+	fork, err := types.RegisterFork("MigrationFork", types.ProtocolIdUniswapV2, 2000)
+	require.NoError(t, err)
+
+	for _, c := range []struct {
+		protocolId  int
+		fee         uint32
+		version     int
+		tickSpacing int32
+	}{
+		{protocolId: types.ProtocolIdUniswapV2, fee: 3000, version: types.ProtocolVersionV2},
+		{protocolId: types.ProtocolIdPancakeV2, fee: 2500, version: types.ProtocolVersionV2},
+		{protocolId: types.ProtocolIdUniswapV3, fee: 0, version: types.ProtocolVersionV3},
+		{protocolId: types.ProtocolIdAerodrome, fee: 0, version: types.ProtocolVersionV2},
+		{protocolId: fork.Id, fee: 0, version: 0},
+	} {
+		value, err := schema.Migrate([]byte(fmt.Sprintf(
+			`{"SchemaVersion": 3, "Address": "0xcff245725bf2e1219171dcebbd793ac00fc99b09", "ProtocolId": %d, "Fee": 0}`,
+			c.protocolId,
+		)))
+		require.NoError(t, err)
+
+		bytes, err := json.Marshal(value)
+		require.NoError(t, err)
+		pair := &types.Pair{}
+		require.NoError(t, pair.UnmarshalBinary(bytes))
+		require.Equal(t, c.fee, pair.Fee)
+		require.Equal(t, c.version, pair.Version)
+		require.Equal(t, c.tickSpacing, pair.TickSpacing)
+	}
+}
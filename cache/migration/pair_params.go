@@ -0,0 +1,68 @@
+package migration
+
+/*
+pairV3Versions and pairV3FeePips are the protocol versions and the v2 fees by protocol id when v4 pairs were added,
+frozen here so that the migration of a v3 pair doesn't change with the protocols added later. Other protocols,
+the forks included, have no version and no fee.
+*/
+var (
+	pairV3Versions = map[int]int{
+		1: 2, // UniswapV2
+		2: 3, // UniswapV3
+		3: 2, // PancakeV2
+		4: 3, // PancakeV3
+		5: 2, // Aerodrome
+	}
+	pairV3FeePips = map[int]uint32{
+		1: 3000, // UniswapV2
+		3: 2500, // PancakeV2
+	}
+)
+
+/*
+v3 pairs have the Stable flag and the Fee of aerodrome pools, both default to false and 0.
+The fee of an aerodrome pair cached before stays unknown until the pool fee changes.
+
+v4 pairs have the TickSpacing and the Version of their protocol, and every v2 pair has its Fee.
+The fee and the tick spacing of v3 pairs cached before stay unknown, the version and the fee of fork pairs too.
+*/
+func registerPairParamsMigrations() {
+	pairV2 := &Migration{
+		From:        2,
+		Description: "add stable flag and fee of aerodrome pairs",
+		Up: func(value Value) error {
+			if _, ok := value["Stable"]; !ok {
+				value["Stable"] = false
+			}
+			if _, ok := value["Fee"]; !ok {
+				value["Fee"] = 0
+			}
+			return nil
+		},
+	}
+	Register(SchemaPair, pairV2)
+	Register(SchemaLegacyPair, pairV2)
+
+	pairV3 := &Migration{
+		From:        3,
+		Description: "add tick spacing and protocol version of pairs, fee of v2 pairs",
+		Up: func(value Value) error {
+			protocolId := 0
+			if id, ok := value["ProtocolId"].(float64); ok {
+				protocolId = int(id)
+			}
+			if _, ok := value["TickSpacing"]; !ok {
+				value["TickSpacing"] = 0
+			}
+			if _, ok := value["Version"]; !ok {
+				value["Version"] = pairV3Versions[protocolId]
+			}
+			if fee, ok := value["Fee"].(float64); !ok || fee == 0 {
+				value["Fee"] = pairV3FeePips[protocolId]
+			}
+			return nil
+		},
+	}
+	Register(SchemaPair, pairV3)
+	Register(SchemaLegacyPair, pairV3)
+}
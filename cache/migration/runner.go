@@ -0,0 +1,186 @@
+package migration
+
+import (
+	"base_scan/log"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+	"strconv"
+)
+
+type Progress struct {
+	Scanned  int
+	Migrated int
+	UpToDate int
+	Failed   int
+}
+
+/*
+Runner migrates the values of one schema in redis.
+Keys are scanned in batches with SCAN, the scan cursor is saved in redis after every batch,
+so an interrupted run continues from the last finished batch.
+The runner is meant to run while the indexer is stopped, the indexer may overwrite values meanwhile.
+In dry run mode values are migrated and validated, but nothing is written.
+*/
+type Runner struct {
+	redisCli  *redis.Client
+	schema    *Schema
+	batchSize int64
+	dryRun    bool
+	ctx       context.Context
+}
+
+func NewRunner(redisCli *redis.Client, schema *Schema, batchSize int64, dryRun bool) *Runner {
+	return &Runner{
+		redisCli:  redisCli,
+		schema:    schema,
+		batchSize: batchSize,
+		dryRun:    dryRun,
+		ctx:       context.Background(),
+	}
+}
+
+func cursorKey(schemaName string) string {
+	return fmt.Sprintf("mg:%s:cursor", schemaName)
+}
+
+func (r *Runner) loadCursor() (uint64, error) {
+	value, err := r.redisCli.Get(r.ctx, cursorKey(r.schema.Name)).Result()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(value, 10, 64)
+}
+
+func (r *Runner) saveCursor(cursor uint64) error {
+	if r.dryRun {
+		return nil
+	}
+	if cursor == 0 {
+		return r.redisCli.Del(r.ctx, cursorKey(r.schema.Name)).Err()
+	}
+	return r.redisCli.Set(r.ctx, cursorKey(r.schema.Name), cursor, 0).Err()
+}
+
+func (r *Runner) Reset() error {
+	return r.redisCli.Del(r.ctx, cursorKey(r.schema.Name)).Err()
+}
+
+func (r *Runner) migrateKey(key string, data []byte, pipe redis.Pipeliner) error {
+	value, err := r.schema.Migrate(data)
+	if err != nil {
+		return err
+	}
+	if value == nil {
+		return errUpToDate
+	}
+
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	if r.schema.Validate != nil {
+		if err = r.schema.Validate(bytes); err != nil {
+			return err
+		}
+	}
+
+	targetKey := key
+	if r.schema.TargetKey != nil {
+		targetKey, err = r.schema.TargetKey(value)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !r.dryRun {
+		pipe.Set(r.ctx, targetKey, bytes, 0)
+	}
+	return nil
+}
+
+func (r *Runner) migrateBatch(keys []string, progress *Progress) error {
+	values, err := r.redisCli.MGet(r.ctx, keys...).Result()
+	if err != nil {
+		return err
+	}
+
+	pipe := r.redisCli.Pipeline()
+	for i, key := range keys {
+		data, ok := values[i].(string)
+		if !ok {
+			// deleted or expired since the scan
+			continue
+		}
+
+		progress.Scanned++
+		err = r.migrateKey(key, []byte(data), pipe)
+		switch err {
+		case nil:
+			progress.Migrated++
+		case errUpToDate:
+			progress.UpToDate++
+		default:
+			progress.Failed++
+			log.Logger.Warn("migrate key err", zap.String("schema", r.schema.Name), zap.String("key", key), zap.Error(err))
+		}
+	}
+
+	if pipe.Len() == 0 {
+		return nil
+	}
+	_, err = pipe.Exec(r.ctx)
+	return err
+}
+
+func (r *Runner) Run() (*Progress, error) {
+	if err := r.schema.Check(); err != nil {
+		return nil, err
+	}
+
+	cursor, err := r.loadCursor()
+	if err != nil {
+		return nil, err
+	}
+	if cursor != 0 {
+		log.Logger.Info("resume migration", zap.String("schema", r.schema.Name), zap.Uint64("cursor", cursor))
+	}
+
+	progress := &Progress{}
+	for {
+		var keys []string
+		keys, cursor, err = r.redisCli.Scan(r.ctx, cursor, r.schema.Prefix+"*", r.batchSize).Result()
+		if err != nil {
+			return progress, err
+		}
+
+		if len(keys) > 0 {
+			if err = r.migrateBatch(keys, progress); err != nil {
+				return progress, err
+			}
+		}
+
+		if err = r.saveCursor(cursor); err != nil {
+			return progress, err
+		}
+
+		log.Logger.Info("migrate",
+			zap.String("schema", r.schema.Name),
+			zap.Bool("dry run", r.dryRun),
+			zap.Int("scanned", progress.Scanned),
+			zap.Int("migrated", progress.Migrated),
+			zap.Int("up to date", progress.UpToDate),
+			zap.Int("failed", progress.Failed),
+		)
+
+		if cursor == 0 {
+			return progress, nil
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package migration
+
+import (
+	"base_scan/cache"
+	"base_scan/types"
+	"errors"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	SchemaToken       = "token"
+	SchemaPair        = "pair"
+	SchemaPool        = "pool"
+	SchemaLegacyToken = "legacy_token"
+	SchemaLegacyPair  = "legacy_pair"
+)
+
+var (
+	ErrAddressMissing = errors.New("address missing")
+)
+
+func validateToken(data []byte) error {
+	return (&types.Token{}).UnmarshalBinary(data)
+}
+
+func validatePair(data []byte) error {
+	return (&types.Pair{}).UnmarshalBinary(data)
+}
+
+func validatePool(data []byte) error {
+	return (&types.Pool{}).UnmarshalBinary(data)
+}
+
+func valueAddress(value Value) (common.Address, error) {
+	address, ok := value["Address"].(string)
+	if !ok || !common.IsHexAddress(address) {
+		return types.ZeroAddress, ErrAddressMissing
+	}
+	return common.HexToAddress(address), nil
+}
+
+func init() {
+	// values written before SchemaVersion existed are version 1
+	RegisterSchema(&Schema{
+		Name:           SchemaToken,
+		Prefix:         "nt:",
+		DefaultVersion: 1,
+		CurrentVersion: types.TokenSchemaVersion,
+		Validate:       validateToken,
+	})
+
+	RegisterSchema(&Schema{
+		Name:           SchemaPair,
+		Prefix:         "npr:",
+		DefaultVersion: 1,
+		CurrentVersion: types.PairSchemaVersion,
+		Validate:       validatePair,
+	})
+
+	RegisterSchema(&Schema{
+		Name:           SchemaPool,
+		Prefix:         "pl:",
+		DefaultVersion: 1,
+		CurrentVersion: types.PoolSchemaVersion,
+		Validate:       validatePool,
+	})
+
+	// legacy values live under the old key prefixes and are rewritten under the current keys
+	RegisterSchema(&Schema{
+		Name:           SchemaLegacyToken,
+		Prefix:         "t:",
+		DefaultVersion: 0,
+		CurrentVersion: types.TokenSchemaVersion,
+		TargetKey: func(value Value) (string, error) {
+			address, err := valueAddress(value)
+			if err != nil {
+				return "", err
+			}
+			return cache.TokenCacheKey(address), nil
+		},
+		Validate: validateToken,
+	})
+
+	RegisterSchema(&Schema{
+		Name:           SchemaLegacyPair,
+		Prefix:         "pr:",
+		DefaultVersion: 0,
+		CurrentVersion: types.PairSchemaVersion,
+		TargetKey: func(value Value) (string, error) {
+			address, err := valueAddress(value)
+			if err != nil {
+				return "", err
+			}
+			return cache.PairCacheKey(address), nil
+		},
+		Validate: validatePair,
+	})
+
+	registerLegacyMigrations()
+	registerFilterMigrations()
+	registerPairParamsMigrations()
+}
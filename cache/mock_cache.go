@@ -19,58 +19,88 @@ func NewMockCache() Cache {
 }
 
 func (c *MockCache) DelToken(address common.Address) {
-	c.memory.Delete(address.String())
+	c.memory.Delete(TokenCacheKey(address))
 }
 
 func (c *MockCache) DelPair(address common.Address) {
-	c.memory.Delete(address.String())
+	c.memory.Delete(PairCacheKey(address))
 }
 
 func (c *MockCache) SetPrice(blockNumber *big.Int, price decimal.Decimal) {
-	c.memory.Set(blockNumber.String(), price, 0)
+	c.memory.Set(PriceCacheKey(blockNumber), price, 0)
 }
 
 func (c *MockCache) GetPrice(blockNumber *big.Int) (decimal.Decimal, bool) {
-	if price, found := c.memory.Get(blockNumber.String()); found {
+	if price, found := c.memory.Get(PriceCacheKey(blockNumber)); found {
 		return price.(decimal.Decimal), true
 	}
 	return decimal.Decimal{}, false
 }
 
 func (c *MockCache) SetToken(token *types.Token) {
-	c.memory.Set(token.Address.String(), token, 0)
+	c.memory.Set(TokenCacheKey(token.Address), token, 0)
 }
 
 func (c *MockCache) GetToken(address common.Address) (*types.Token, bool) {
-	if token, found := c.memory.Get(address.String()); found {
+	if token, found := c.memory.Get(TokenCacheKey(address)); found {
 		return token.(*types.Token), true
 	}
 	return nil, false
 }
 
 func (c *MockCache) SetPair(pair *types.Pair) {
-	c.memory.Set(pair.Address.String(), pair, 0)
+	c.memory.Set(PairCacheKey(pair.Address), pair, 0)
 }
 
 func (c *MockCache) GetPair(address common.Address) (*types.Pair, bool) {
-	if pair, found := c.memory.Get(address.String()); found {
+	if pair, found := c.memory.Get(PairCacheKey(address)); found {
 		return pair.(*types.Pair), true
 	}
 	return nil, false
 }
 
 func (c *MockCache) PairExist(address common.Address) bool {
-	if _, found := c.memory.Get(address.String()); found {
+	if _, found := c.memory.Get(PairCacheKey(address)); found {
 		return true
 	}
 	return false
 }
 
+func (c *MockCache) SetPool(pool *types.Pool) {
+	c.memory.Set(PoolCacheKey(pool.Address), pool, 0)
+}
+
+func (c *MockCache) GetPool(address common.Address) (*types.Pool, bool) {
+	if pool, found := c.memory.Get(PoolCacheKey(address)); found {
+		return pool.(*types.Pool), true
+	}
+	return nil, false
+}
+
+func (c *MockCache) DelPool(address common.Address) {
+	c.memory.Delete(PoolCacheKey(address))
+}
+
 func (c *MockCache) SetFinishedBlock(blockNumber uint64) {
+	c.memory.Set(finishedBlockKey, blockNumber, 0)
 }
 
 func (c *MockCache) GetFinishedBlock() uint64 {
+	if blockNumber, found := c.memory.Get(finishedBlockKey); found {
+		return blockNumber.(uint64)
+	}
 	return 0
 }
 
+func (c *MockCache) SetMaker(maker *types.Maker) {
+	c.memory.Set(MakerCacheKey(maker.Address), maker, 0)
+}
+
+func (c *MockCache) GetMaker(address common.Address) (*types.Maker, bool) {
+	if maker, found := c.memory.Get(MakerCacheKey(address)); found {
+		return maker.(*types.Maker), true
+	}
+	return nil, false
+}
+
 var _ Cache = &MockCache{}
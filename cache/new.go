@@ -0,0 +1,34 @@
+package cache
+
+import (
+	"base_scan/config"
+	"errors"
+	"github.com/go-redis/redis/v8"
+	"io"
+)
+
+var (
+	ErrUnknownBackend = errors.New("unknown cache backend")
+)
+
+// NewCacheByConf creates the cache of the configured backend, the returned closer releases its connection or db.
+func NewCacheByConf(cacheConf *config.CacheConf, redisConf *config.RedisConf) (Cache, io.Closer, error) {
+	switch cacheConf.Backend {
+	case "", config.CacheBackendRedis:
+		redisCli := redis.NewClient(&redis.Options{
+			Addr:     redisConf.Addr,
+			Username: redisConf.Username,
+			Password: redisConf.Password,
+			DB:       redisConf.DB,
+		})
+		return NewTwoTierCache(redisCli), redisCli, nil
+	case config.CacheBackendPebble:
+		pebbleCache, err := NewPebbleCache(cacheConf.PebbleDir)
+		if err != nil {
+			return nil, nil, err
+		}
+		return pebbleCache, pebbleCache, nil
+	default:
+		return nil, nil, ErrUnknownBackend
+	}
+}
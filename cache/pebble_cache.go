@@ -0,0 +1,279 @@
+package cache
+
+import (
+	"base_scan/log"
+	"base_scan/types"
+	"errors"
+	"github.com/cockroachdb/pebble"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/patrickmn/go-cache"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+	"math/big"
+	"strconv"
+	"time"
+)
+
+const (
+	finishedBlockKey = "fb"
+)
+
+/*
+PebbleCache is a two tier cache like twoTierCache, with an embedded pebble db instead of redis,
+so that the indexer can run without a redis server.
+Keys and values are the same as in redis.
+Only the finished block is written with sync, which also flushes the earlier writes in the WAL,
+so after a crash every token, pair, price and maker before the finished block is on disk.
+*/
+type PebbleCache struct {
+	memory *cache.Cache
+	db     *pebble.DB
+}
+
+func NewPebbleCache(dir string) (*PebbleCache, error) {
+	db, err := pebble.Open(dir, &pebble.Options{Logger: log.Logger.Sugar()})
+	if err != nil {
+		return nil, err
+	}
+
+	return &PebbleCache{
+		memory: cache.New(time.Hour*24, time.Hour),
+		db:     db,
+	}, nil
+}
+
+func (c *PebbleCache) Close() error {
+	return c.db.Close()
+}
+
+func (c *PebbleCache) get(k string) ([]byte, bool) {
+	v, closer, err := c.db.Get([]byte(k))
+	if err != nil {
+		if !errors.Is(err, pebble.ErrNotFound) {
+			log.Logger.Error("pebble get err", zap.String("key", k), zap.Error(err))
+		}
+		return nil, false
+	}
+	defer closer.Close()
+
+	value := make([]byte, len(v))
+	copy(value, v)
+	return value, true
+}
+
+func (c *PebbleCache) set(k string, v []byte, opts *pebble.WriteOptions) {
+	err := c.db.Set([]byte(k), v, opts)
+	if err != nil {
+		log.Logger.Error("pebble set err", zap.String("key", k), zap.Error(err))
+	}
+}
+
+func (c *PebbleCache) del(k string) {
+	err := c.db.Delete([]byte(k), pebble.NoSync)
+	if err != nil {
+		log.Logger.Error("pebble del err", zap.String("key", k), zap.Error(err))
+	}
+}
+
+func (c *PebbleCache) SetPrice(blockNumber *big.Int, price decimal.Decimal) {
+	k := PriceCacheKey(blockNumber)
+	c.memory.Set(k, price, cache.DefaultExpiration)
+	c.set(k, []byte(price.String()), pebble.NoSync)
+}
+
+func (c *PebbleCache) GetPrice(blockNumber *big.Int) (decimal.Decimal, bool) {
+	k := PriceCacheKey(blockNumber)
+	price, ok := c.memory.Get(k)
+	if ok {
+		return price.(decimal.Decimal), true
+	}
+
+	v, ok := c.get(k)
+	if !ok {
+		return decimal.Zero, false
+	}
+
+	decimalPrice, err := decimal.NewFromString(string(v))
+	if err != nil {
+		return decimal.Decimal{}, false
+	}
+	c.memory.Set(k, decimalPrice, 0)
+	return decimalPrice, true
+}
+
+func (c *PebbleCache) SetToken(token *types.Token) {
+	token.Timestamp = time.Now()
+	k := TokenCacheKey(token.Address)
+	c.memory.Set(k, token, cache.DefaultExpiration)
+	v, err := token.MarshalBinary()
+	if err != nil {
+		log.Logger.Error("marshal token err", zap.Error(err))
+		return
+	}
+	c.set(k, v, pebble.NoSync)
+}
+
+func (c *PebbleCache) GetToken(address common.Address) (*types.Token, bool) {
+	k := TokenCacheKey(address)
+	tokenCache, ok := c.memory.Get(k)
+	if ok {
+		return tokenCache.(*types.Token), true
+	}
+
+	v, ok := c.get(k)
+	if !ok {
+		return nil, false
+	}
+
+	token := &types.Token{}
+	if err := token.UnmarshalBinary(v); err != nil {
+		log.Logger.Error("unmarshal token err", zap.String("key", k), zap.Error(err))
+		return nil, false
+	}
+	return token, true
+}
+
+func (c *PebbleCache) DelToken(address common.Address) {
+	k := TokenCacheKey(address)
+	c.memory.Delete(k)
+	c.del(k)
+}
+
+func (c *PebbleCache) SetPair(pair *types.Pair) {
+	pair.Timestamp = time.Now()
+	k := PairCacheKey(pair.Address)
+	c.memory.Set(k, pair, cache.DefaultExpiration)
+	v, err := pair.MarshalBinary()
+	if err != nil {
+		log.Logger.Error("marshal pair err", zap.Error(err))
+		return
+	}
+	c.set(k, v, pebble.NoSync)
+}
+
+func (c *PebbleCache) GetPair(address common.Address) (*types.Pair, bool) {
+	k := PairCacheKey(address)
+	pair, ok := c.memory.Get(k)
+	if ok {
+		return pair.(*types.Pair), true
+	}
+
+	v, ok := c.get(k)
+	if !ok {
+		return nil, false
+	}
+
+	p := &types.Pair{}
+	if err := p.UnmarshalBinary(v); err != nil {
+		log.Logger.Error("unmarshal pair err", zap.String("key", k), zap.Error(err))
+		return nil, false
+	}
+
+	c.memory.Set(k, p, 0)
+	return p, true
+}
+
+func (c *PebbleCache) PairExist(address common.Address) bool {
+	_, exist := c.GetPair(address)
+	return exist
+}
+
+func (c *PebbleCache) DelPair(address common.Address) {
+	k := PairCacheKey(address)
+	c.memory.Delete(k)
+	c.del(k)
+}
+
+func (c *PebbleCache) SetPool(pool *types.Pool) {
+	pool.Timestamp = time.Now()
+	k := PoolCacheKey(pool.Address)
+	c.memory.Set(k, pool, cache.DefaultExpiration)
+	v, err := pool.MarshalBinary()
+	if err != nil {
+		log.Logger.Error("marshal pool err", zap.Error(err))
+		return
+	}
+	c.set(k, v, pebble.NoSync)
+}
+
+func (c *PebbleCache) GetPool(address common.Address) (*types.Pool, bool) {
+	k := PoolCacheKey(address)
+	pool, ok := c.memory.Get(k)
+	if ok {
+		return pool.(*types.Pool), true
+	}
+
+	v, ok := c.get(k)
+	if !ok {
+		return nil, false
+	}
+
+	p := &types.Pool{}
+	if err := p.UnmarshalBinary(v); err != nil {
+		log.Logger.Error("unmarshal pool err", zap.String("key", k), zap.Error(err))
+		return nil, false
+	}
+
+	c.memory.Set(k, p, cache.DefaultExpiration)
+	return p, true
+}
+
+func (c *PebbleCache) DelPool(address common.Address) {
+	k := PoolCacheKey(address)
+	c.memory.Delete(k)
+	c.del(k)
+}
+
+func (c *PebbleCache) SetFinishedBlock(blockNumber uint64) {
+	c.set(finishedBlockKey, []byte(strconv.FormatUint(blockNumber, 10)), pebble.Sync)
+}
+
+func (c *PebbleCache) GetFinishedBlock() uint64 {
+	v, ok := c.get(finishedBlockKey)
+	if !ok {
+		return 0
+	}
+
+	blockNumber, err := strconv.ParseUint(string(v), 10, 64)
+	if err != nil {
+		log.Logger.Error("parse finished block err", zap.Error(err))
+		return 0
+	}
+	return blockNumber
+}
+
+var _ Cache = &PebbleCache{}
+
+func (c *PebbleCache) SetMaker(maker *types.Maker) {
+	maker.Timestamp = time.Now()
+	k := MakerCacheKey(maker.Address)
+	c.memory.Set(k, maker, cache.DefaultExpiration)
+	v, err := maker.MarshalBinary()
+	if err != nil {
+		log.Logger.Error("marshal maker err", zap.Error(err))
+		return
+	}
+	c.set(k, v, pebble.NoSync)
+}
+
+func (c *PebbleCache) GetMaker(address common.Address) (*types.Maker, bool) {
+	k := MakerCacheKey(address)
+	maker, ok := c.memory.Get(k)
+	if ok {
+		return maker.(*types.Maker), true
+	}
+
+	v, ok := c.get(k)
+	if !ok {
+		return nil, false
+	}
+
+	m := &types.Maker{}
+	if err := m.UnmarshalBinary(v); err != nil {
+		log.Logger.Error("unmarshal maker err", zap.String("key", k), zap.Error(err))
+		return nil, false
+	}
+
+	c.memory.Set(k, m, cache.DefaultExpiration)
+	return m, true
+}
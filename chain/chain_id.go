@@ -1,3 +0,0 @@
-package chain
-
-const Id = 8453
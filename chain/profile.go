@@ -0,0 +1,311 @@
+package chain
+
+import (
+	"base_scan/abi/aerodrome"
+	pancakev2 "base_scan/abi/pancake/v2"
+	pancakev3 "base_scan/abi/pancake/v3"
+	uniswapv2 "base_scan/abi/uniswap/v2"
+	uniswapv3 "base_scan/abi/uniswap/v3"
+	"base_scan/abi/wow"
+	"base_scan/config"
+	"base_scan/types"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/params"
+	"sort"
+)
+
+const (
+	ProfileBase     = "base"
+	ProfileOptimism = "optimism"
+	ProfileUnichain = "unichain"
+	ProfileEthereum = "ethereum"
+)
+
+var (
+	ErrProfileNotFound   = errors.New("chain profile not found")
+	ErrPricePairMissing  = errors.New("price pair missing, set chain.price_pair")
+	ErrInvalidAddress    = errors.New("invalid address")
+	ErrUnknownProtocol   = errors.New("unknown protocol")
+	ErrStableDecimalsBad = errors.New("stable_token_decimals must be > 0 when stable_token is set")
+	ErrInvalidHash       = errors.New("invalid hash")
+)
+
+/*
+Profile is everything the indexer needs to know about one chain.
+- ChainConfig: makes the tx signer
+- NativeToken, StableToken: the base tokens, pairs without one of them are filtered
+- Factories: dex deployments by protocol id, a protocol without factory is not indexed
+- PricePair: uniswap v2 like native/stable pair, its reserves price the native token
+- Routers: names of well known routers and aggregators by address, see package router
+- PositionManagers: v3 NonfungiblePositionManagers by address, their positions are tracked
+- InitCodeHashes: pool init code hashes of forks by protocol id, their pools are verified by create2 address
+- DiscoverForks: verify pools of unknown factories by the factory they return, see config.ChainConf
+*/
+type Profile struct {
+	Name                    string
+	Id                      uint64
+	ChainConfig             *params.ChainConfig
+	NativeToken             common.Address
+	StableToken             common.Address
+	StableTokenDecimals     int
+	Factories               map[int]common.Address
+	PricePair               common.Address
+	PricePairNativeIsToken0 bool
+	Routers                 map[common.Address]string
+	PositionManagers        map[common.Address]int
+	InitCodeHashes          map[int]common.Hash
+	DiscoverForks           bool
+}
+
+const nativeTokenDecimals = 18
+
+func (p *Profile) NativeTokenDecimals() int {
+	return nativeTokenDecimals
+}
+
+func (p *Profile) BaseTokens() *types.BaseTokens {
+	return &types.BaseTokens{Native: p.NativeToken, Stable: p.StableToken}
+}
+
+// FactoryProtocolIds maps every factory address back to its protocol id.
+func (p *Profile) FactoryProtocolIds() map[common.Address]int {
+	factoryProtocolIds := make(map[common.Address]int, len(p.Factories))
+	for protocolId, factory := range p.Factories {
+		factoryProtocolIds[factory] = protocolId
+	}
+	return factoryProtocolIds
+}
+
+func (p *Profile) copy() *Profile {
+	c := *p
+	c.Factories = make(map[int]common.Address, len(p.Factories))
+	for protocolId, factory := range p.Factories {
+		c.Factories[protocolId] = factory
+	}
+	c.Routers = make(map[common.Address]string, len(p.Routers))
+	for address, name := range p.Routers {
+		c.Routers[address] = name
+	}
+	c.PositionManagers = make(map[common.Address]int, len(p.PositionManagers))
+	for address, protocolId := range p.PositionManagers {
+		c.PositionManagers[address] = protocolId
+	}
+	c.InitCodeHashes = make(map[int]common.Hash, len(p.InitCodeHashes))
+	for protocolId, initCodeHash := range p.InitCodeHashes {
+		c.InitCodeHashes[protocolId] = initCodeHash
+	}
+	return &c
+}
+
+var (
+	opStackWETH = common.HexToAddress("0x4200000000000000000000000000000000000006")
+
+	profiles = map[string]*Profile{
+		ProfileBase: {
+			Name:                ProfileBase,
+			Id:                  8453,
+			ChainConfig:         newOPStackChainConfig(8453),
+			NativeToken:         opStackWETH,
+			StableToken:         common.HexToAddress("0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913"),
+			StableTokenDecimals: 6,
+			Factories: map[int]common.Address{
+				types.ProtocolIdUniswapV2: uniswapv2.FactoryAddress,
+				types.ProtocolIdUniswapV3: uniswapv3.FactoryAddress,
+				types.ProtocolIdPancakeV2: pancakev2.FactoryAddress,
+				types.ProtocolIdPancakeV3: pancakev3.FactoryAddress,
+				types.ProtocolIdAerodrome: aerodrome.FactoryAddress,
+				types.ProtocolIdWow:       wow.FactoryAddress,
+			},
+			PricePair:               common.HexToAddress("0x88A43bbDF9D098eEC7bCEda4e2494615dfD9bB9C"), // Uniswap v2 WETH/USDC
+			PricePairNativeIsToken0: true,
+			Routers: map[common.Address]string{
+				common.HexToAddress("0x3fC91A3afd70395Cd496C647d5a6CC9D4B2b7FAD"): "uniswap_universal_router",
+				common.HexToAddress("0x6fF5693b99212Da76ad316178A184AB56D299b43"): "uniswap_universal_router",
+				common.HexToAddress("0x4752ba5DBc23f44D87826276BF6Fd6b1C372aD24"): "uniswap_v2_router",
+				common.HexToAddress("0x2626664c2603336E57B271c5C0b26F421741e481"): "uniswap_swap_router",
+				common.HexToAddress("0xcF77a3Ba9A5CA399B7c97c74d54e5b1Beb874E43"): "aerodrome_router",
+				common.HexToAddress("0x6Cb442acF35158D5eDa88fe602221b67B400Be3E"): "aerodrome_universal_router",
+				common.HexToAddress("0x678Aa4bF4E210cf2166753e054d5b7c31cc7fa86"): "pancake_smart_router",
+				common.HexToAddress("0x1111111254EEB25477B68fb85Ed929f73A960582"): "1inch",
+				common.HexToAddress("0x111111125421cA6dc452d289314280a0f8842A65"): "1inch",
+				common.HexToAddress("0xDef1C0ded9bec7F1a1670819833240f027b25EfF"): "0x",
+				common.HexToAddress("0x19cEeAd7105607Cd444F5ad10dd51356436095a1"): "odos",
+				common.HexToAddress("0x6131B5fae19EA4f9D964eAc0408E4408b66337b5"): "kyberswap",
+				common.HexToAddress("0x6A000F20005980200259B80c5102003040001068"): "paraswap",
+			},
+			PositionManagers: map[common.Address]int{
+				uniswapv3.PositionManagerAddress: types.ProtocolIdUniswapV3,
+				pancakev3.PositionManagerAddress: types.ProtocolIdPancakeV3,
+			},
+		},
+		// no default price pair, set chain.price_pair
+		ProfileOptimism: {
+			Name:                ProfileOptimism,
+			Id:                  10,
+			ChainConfig:         newOPStackChainConfig(10),
+			NativeToken:         opStackWETH,
+			StableToken:         common.HexToAddress("0x0b2C639c533813f4Aa9D7837CAf62653d097Ff85"),
+			StableTokenDecimals: 6,
+			Factories: map[int]common.Address{
+				types.ProtocolIdUniswapV3: common.HexToAddress("0x1F98431c8aD98523631AE4a59f267346ea31F984"),
+			},
+			PositionManagers: map[common.Address]int{
+				common.HexToAddress("0xC36442b4a4522E871399CD717aBDD847Ab11FE88"): types.ProtocolIdUniswapV3,
+			},
+		},
+		// no default price pair, set chain.price_pair
+		ProfileUnichain: {
+			Name:                ProfileUnichain,
+			Id:                  130,
+			ChainConfig:         newOPStackChainConfig(130),
+			NativeToken:         opStackWETH,
+			StableToken:         common.HexToAddress("0x078D782b760474a361dDA0AF3839290b0EF57AD6"),
+			StableTokenDecimals: 6,
+			Factories: map[int]common.Address{
+				types.ProtocolIdUniswapV2: common.HexToAddress("0x1F98400000000000000000000000000000000002"),
+				types.ProtocolIdUniswapV3: common.HexToAddress("0x1F98400000000000000000000000000000000003"),
+			},
+		},
+		ProfileEthereum: {
+			Name:                ProfileEthereum,
+			Id:                  1,
+			ChainConfig:         params.MainnetChainConfig,
+			NativeToken:         common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"),
+			StableToken:         common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"),
+			StableTokenDecimals: 6,
+			Factories: map[int]common.Address{
+				types.ProtocolIdUniswapV2: common.HexToAddress("0x5C69bEe701ef814a2B6a3EDD4B1652CB9cc5aA6f"),
+				types.ProtocolIdUniswapV3: common.HexToAddress("0x1F98431c8aD98523631AE4a59f267346ea31F984"),
+			},
+			PricePair:               common.HexToAddress("0xB4e16d0168e52d35CaCD2c6185b44281Ec28C9Dc"), // Uniswap v2 USDC/WETH
+			PricePairNativeIsToken0: false,
+			PositionManagers: map[common.Address]int{
+				common.HexToAddress("0xC36442b4a4522E871399CD717aBDD847Ab11FE88"): types.ProtocolIdUniswapV3,
+			},
+		},
+	}
+)
+
+func ProfileNames() []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetProfile returns a copy of the named profile, so that callers can change it.
+func GetProfile(name string) (*Profile, error) {
+	profile, ok := profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s, known profiles %v", ErrProfileNotFound, name, ProfileNames())
+	}
+	return profile.copy(), nil
+}
+
+func parseAddress(name, hex string) (common.Address, error) {
+	if !common.IsHexAddress(hex) {
+		return common.Address{}, fmt.Errorf("%w: chain.%s %q", ErrInvalidAddress, name, hex)
+	}
+	return common.HexToAddress(hex), nil
+}
+
+func parseHash(name, hex string) (common.Hash, error) {
+	b, err := hexutil.Decode(hex)
+	if err != nil || len(b) != common.HashLength {
+		return common.Hash{}, fmt.Errorf("%w: chain.%s %q", ErrInvalidHash, name, hex)
+	}
+	return common.BytesToHash(b), nil
+}
+
+// forkBaseProtocolIds are the protocols forked by the fork versions of config.ForkConf
+var forkBaseProtocolIds = map[int]int{
+	2: types.ProtocolIdUniswapV2,
+	3: types.ProtocolIdUniswapV3,
+}
+
+// v2ForkDefaultFeePips is the fee of the pools of a v2 fork without fee in config, the one of uniswap v2
+const v2ForkDefaultFeePips = 3000
+
+func (p *Profile) addFork(conf *config.ForkConf) error {
+	base, ok := forkBaseProtocolIds[conf.Version]
+	if !ok {
+		return fmt.Errorf("%w: chain.forks %s version %d", ErrUnknownProtocol, conf.Name, conf.Version)
+	}
+	feePips := conf.Fee
+	if feePips == 0 && base == types.ProtocolIdUniswapV2 {
+		feePips = v2ForkDefaultFeePips
+	}
+	fork, err := types.RegisterFork(conf.Name, base, feePips)
+	if err != nil {
+		return err
+	}
+
+	if p.Factories[fork.Id], err = parseAddress("forks."+conf.Name+".factory", conf.Factory); err != nil {
+		return err
+	}
+	if conf.InitCodeHash != "" {
+		if p.InitCodeHashes[fork.Id], err = parseHash("forks."+conf.Name+".init_code_hash", conf.InitCodeHash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewProfile returns the profile selected by conf with the overrides of conf applied.
+func NewProfile(conf *config.ChainConf) (*Profile, error) {
+	profile, err := GetProfile(conf.Profile)
+	if err != nil {
+		return nil, err
+	}
+
+	if conf.NativeToken != "" {
+		if profile.NativeToken, err = parseAddress("native_token", conf.NativeToken); err != nil {
+			return nil, err
+		}
+	}
+
+	if conf.StableToken != "" {
+		if profile.StableToken, err = parseAddress("stable_token", conf.StableToken); err != nil {
+			return nil, err
+		}
+		if conf.StableTokenDecimals <= 0 {
+			return nil, ErrStableDecimalsBad
+		}
+		profile.StableTokenDecimals = conf.StableTokenDecimals
+	}
+
+	if conf.PricePair != "" {
+		if profile.PricePair, err = parseAddress("price_pair", conf.PricePair); err != nil {
+			return nil, err
+		}
+		profile.PricePairNativeIsToken0 = conf.PricePairNativeIsToken0
+	}
+
+	for protocolName, factoryHex := range conf.Factories {
+		protocolId := types.GetProtocolId(protocolName)
+		if protocolId == 0 || protocolId == types.ProtocolIdUniswapV2Fork || protocolId == types.ProtocolIdUniswapV3Fork {
+			return nil, fmt.Errorf("%w: chain.factories %q", ErrUnknownProtocol, protocolName)
+		}
+		if profile.Factories[protocolId], err = parseAddress("factories."+protocolName, factoryHex); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, fork := range conf.Forks {
+		if err = profile.addFork(fork); err != nil {
+			return nil, err
+		}
+	}
+	profile.DiscoverForks = conf.DiscoverForks
+
+	if profile.PricePair == (common.Address{}) {
+		return nil, fmt.Errorf("%w: profile %s", ErrPricePairMissing, profile.Name)
+	}
+
+	return profile, nil
+}
@@ -0,0 +1,109 @@
+package chain
+
+import (
+	uniswapv2 "base_scan/abi/uniswap/v2"
+	"base_scan/config"
+	"base_scan/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestNewProfileBase(t *testing.T) {
+	profile, err := NewProfile(&config.ChainConf{Profile: ProfileBase})
+	require.NoError(t, err)
+	require.Equal(t, uint64(8453), profile.Id)
+	require.Equal(t, uint64(8453), profile.ChainConfig.ChainID.Uint64())
+	require.True(t, profile.ChainConfig.IsOptimism())
+	require.Equal(t, types.WETHAddress, profile.NativeToken)
+	require.Equal(t, types.USDCAddress, profile.StableToken)
+	require.Equal(t, types.ProtocolIdUniswapV2, profile.FactoryProtocolIds()[uniswapv2.FactoryAddress])
+}
+
+func TestNewProfileOverrides(t *testing.T) {
+	profile, err := NewProfile(&config.ChainConf{
+		Profile:             ProfileOptimism,
+		StableToken:         "0x94b008aA00579c1307B0EF2c499aD98a8ce58e58",
+		StableTokenDecimals: 6,
+		PricePair:           "0x0000000000000000000000000000000000000001",
+		Factories:           map[string]string{types.ProtocolNameUniswapV2: "0x0000000000000000000000000000000000000002"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), profile.Id)
+	require.Equal(t, common.HexToAddress("0x94b008aA00579c1307B0EF2c499aD98a8ce58e58"), profile.StableToken)
+	require.Equal(t, common.HexToAddress("0x01"), profile.PricePair)
+	require.False(t, profile.PricePairNativeIsToken0)
+	require.Equal(t, common.HexToAddress("0x02"), profile.Factories[types.ProtocolIdUniswapV2])
+	require.Contains(t, profile.Factories, types.ProtocolIdUniswapV3)
+
+	// overrides must not leak into the builtin profile
+	optimism, err := GetProfile(ProfileOptimism)
+	require.NoError(t, err)
+	require.NotContains(t, optimism.Factories, types.ProtocolIdUniswapV2)
+}
+
+func TestNewProfileForks(t *testing.T) {
+	profile, err := NewProfile(&config.ChainConf{
+		Profile: ProfileBase,
+		Forks: []*config.ForkConf{
+			{Name: "BaseSwap", Factory: "0xFDa619b6d20975be80A10332cD39b9a4b0FAa8BB", Version: 2, Fee: 2500},
+			{Name: "SushiSwapV3", Factory: "0xc35DADB65012eC5796536bD9864eD8773aBc74C4", Version: 3,
+				InitCodeHash: "0xe04f5c05c62ddc0e97c1ebba6ee4e7d80f6d8849b58e3a0a8d69a16ff0c3c8b3"},
+		},
+		DiscoverForks: true,
+	})
+	require.NoError(t, err)
+	require.True(t, profile.DiscoverForks)
+
+	baseSwapId := types.GetProtocolId("BaseSwap")
+	require.Equal(t, types.ForkProtocolId("BaseSwap"), baseSwapId)
+	require.Equal(t, common.HexToAddress("0xFDa619b6d20975be80A10332cD39b9a4b0FAa8BB"), profile.Factories[baseSwapId])
+	require.Equal(t, types.ProtocolIdUniswapV2, types.BaseProtocolId(baseSwapId))
+	require.NotContains(t, profile.InitCodeHashes, baseSwapId)
+
+	sushiId := types.GetProtocolId("SushiSwapV3")
+	require.Equal(t, types.ProtocolIdUniswapV3, types.BaseProtocolId(sushiId))
+	require.Equal(t, common.HexToHash("0xe04f5c05c62ddc0e97c1ebba6ee4e7d80f6d8849b58e3a0a8d69a16ff0c3c8b3"), profile.InitCodeHashes[sushiId])
+
+	base, err := GetProfile(ProfileBase)
+	require.NoError(t, err)
+	require.NotContains(t, base.Factories, baseSwapId)
+	require.Empty(t, base.InitCodeHashes)
+}
+
+func TestNewProfileErr(t *testing.T) {
+	tests := []struct {
+		name string
+		conf *config.ChainConf
+		err  error
+	}{
+		{"unknown profile", &config.ChainConf{Profile: "solana"}, ErrProfileNotFound},
+		{"no price pair", &config.ChainConf{Profile: ProfileUnichain}, ErrPricePairMissing},
+		{"bad address", &config.ChainConf{Profile: ProfileBase, NativeToken: "weth"}, ErrInvalidAddress},
+		{"no decimals", &config.ChainConf{Profile: ProfileBase, StableToken: "0x0000000000000000000000000000000000000001"}, ErrStableDecimalsBad},
+		{"unknown protocol", &config.ChainConf{Profile: ProfileBase, Factories: map[string]string{"SushiV2": "0x0000000000000000000000000000000000000001"}}, ErrUnknownProtocol},
+		{"fork version", &config.ChainConf{Profile: ProfileBase, Forks: []*config.ForkConf{{Name: "SushiV4", Factory: "0x0000000000000000000000000000000000000001", Version: 4}}}, ErrUnknownProtocol},
+		{"fork builtin", &config.ChainConf{Profile: ProfileBase, Forks: []*config.ForkConf{{Name: types.ProtocolNamePancakeV2, Factory: "0x0000000000000000000000000000000000000001", Version: 2}}}, types.ErrForkConflict},
+		{"fork hash", &config.ChainConf{Profile: ProfileBase, Forks: []*config.ForkConf{{Name: "SushiV2", Factory: "0x0000000000000000000000000000000000000001", Version: 2, InitCodeHash: "0x01"}}}, ErrInvalidHash},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewProfile(tt.conf)
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestProfileBaseTokens(t *testing.T) {
+	base, err := NewProfile(&config.ChainConf{Profile: ProfileBase})
+	require.NoError(t, err)
+	ethereum, err := NewProfile(&config.ChainConf{Profile: ProfileEthereum})
+	require.NoError(t, err)
+
+	// the base tokens of a chain are not base tokens of the others
+	require.True(t, base.BaseTokens().IsStable(types.USDCAddress))
+	require.False(t, ethereum.BaseTokens().IsBase(types.USDCAddress))
+	require.False(t, ethereum.BaseTokens().IsBase(types.WETHAddress))
+	require.True(t, ethereum.BaseTokens().IsNative(ethereum.NativeToken))
+	require.False(t, base.BaseTokens().IsBase(ethereum.StableToken))
+}
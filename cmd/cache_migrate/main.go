@@ -0,0 +1,70 @@
+package main
+
+import (
+	"base_scan/cache/migration"
+	"base_scan/config"
+	"base_scan/log"
+	"flag"
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+	"strings"
+)
+
+/*
+cache_migrate upgrades the cached values in redis to the versions written by the current code.
+Stop the indexer before running it, and start the new indexer after it finished.
+Use -dry-run first to check how many values would be migrated and whether they decode.
+*/
+func main() {
+	var configFile string
+	flag.StringVar(&configFile, "c", "config.json", "config file")
+	var batchSize int64
+	flag.Int64Var(&batchSize, "b", 1000, "keys scanned per batch")
+	var dryRun bool
+	flag.BoolVar(&dryRun, "dry-run", false, "migrate and validate without writing")
+	var reset bool
+	flag.BoolVar(&reset, "reset", false, "ignore the saved cursor and start from the first key")
+	var schemaNames string
+	flag.StringVar(&schemaNames, "s", strings.Join(migration.SchemaNames(), ","), "comma separated schemas to migrate")
+	flag.Parse()
+
+	conf, err := config.LoadConfigFile(configFile)
+	if err != nil {
+		log.Logger.Fatal("load config file err", zap.Error(err))
+	}
+
+	redisCli := redis.NewClient(&redis.Options{
+		Addr:     conf.Redis.Addr,
+		Username: conf.Redis.Username,
+		Password: conf.Redis.Password,
+		DB:       conf.Redis.DB,
+	})
+	defer redisCli.Close()
+
+	for _, name := range strings.Split(schemaNames, ",") {
+		schema, err := migration.GetSchema(strings.TrimSpace(name))
+		if err != nil {
+			log.Logger.Fatal("get schema err", zap.String("schema", name), zap.Error(err))
+		}
+
+		runner := migration.NewRunner(redisCli, schema, batchSize, dryRun)
+		if reset {
+			if err = runner.Reset(); err != nil {
+				log.Logger.Fatal("reset cursor err", zap.String("schema", schema.Name), zap.Error(err))
+			}
+		}
+
+		progress, err := runner.Run()
+		if err != nil {
+			log.Logger.Fatal("migrate err", zap.String("schema", schema.Name), zap.Error(err))
+		}
+
+		log.Logger.Info("migrate done",
+			zap.String("schema", schema.Name),
+			zap.Int("scanned", progress.Scanned),
+			zap.Int("migrated", progress.Migrated),
+			zap.Int("up to date", progress.UpToDate),
+			zap.Int("failed", progress.Failed),
+		)
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"base_scan/cache"
+	"base_scan/chain"
+	"base_scan/config"
+	"base_scan/log"
+	"base_scan/repository"
+	"flag"
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func main() {
+	var configFile string
+	flag.StringVar(&configFile, "c", "config.json", "config file")
+	var batchSize int
+	flag.IntVar(&batchSize, "b", 1000, "rows loaded from db per batch")
+	var skipFinishedBlock bool
+	flag.BoolVar(&skipFinishedBlock, "skip-fb", false, "do not rebuild the finished block pointer")
+	flag.Parse()
+
+	conf, err := config.LoadConfigFile(configFile)
+	if err != nil {
+		log.Logger.Fatal("load config file err", zap.Error(err))
+	}
+
+	profile, err := chain.NewProfile(conf.Chain)
+	if err != nil {
+		log.Logger.Fatal("load chain profile err", zap.Error(err))
+	}
+
+	if !conf.TokenPairDatabase.Enabled {
+		log.Logger.Fatal("token_pair_database is disabled, nothing to rebuild from")
+	}
+
+	tokenPairDb, err := gorm.Open(postgres.Open(conf.TokenPairDatabase.DBDatasource.GetPostgresDsn()))
+	if err != nil {
+		log.Logger.Fatal("failed to connect to token_pair db", zap.Error(err))
+	}
+
+	c, cacheCloser, err := cache.NewCacheByConf(conf.Cache, conf.Redis)
+	if err != nil {
+		log.Logger.Fatal("create cache err", zap.String("backend", conf.Cache.Backend), zap.Error(err))
+	}
+	defer cacheCloser.Close()
+
+	rebuilder := NewRebuilder(
+		c,
+		repository.NewTokenRepository(tokenPairDb, profile.Id),
+		repository.NewPairRepository(tokenPairDb, profile.Id),
+		batchSize,
+	)
+
+	rebuilder.RebuildTokens()
+	rebuilder.RebuildPairs()
+
+	if skipFinishedBlock {
+		return
+	}
+
+	if !conf.TxDatabase.Enabled {
+		log.Logger.Warn("tx_database is disabled, finished block pointer not rebuilt")
+		return
+	}
+
+	txDb, err := gorm.Open(postgres.Open(conf.TxDatabase.DBDatasource.GetPostgresDsn()))
+	if err != nil {
+		log.Logger.Fatal("failed to connect to tx db", zap.Error(err))
+	}
+	rebuilder.RebuildFinishedBlock(repository.NewTxRepository(txDb, profile.Id))
+}
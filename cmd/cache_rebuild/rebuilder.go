@@ -0,0 +1,128 @@
+package main
+
+import (
+	"base_scan/cache"
+	"base_scan/log"
+	"base_scan/repository"
+	"base_scan/types"
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+)
+
+/*
+Rebuilder restores the token/pair cache entries and the finished block pointer from postgres.
+Only verified pairs are persisted in postgres, so filtered pairs are not restored
+and will be checked again on chain the next time they are seen.
+*/
+type Rebuilder struct {
+	cache           cache.Cache
+	tokenRepository *repository.TokenRepository
+	pairRepository  *repository.PairRepository
+	batchSize       int
+}
+
+func NewRebuilder(
+	cache cache.Cache,
+	tokenRepository *repository.TokenRepository,
+	pairRepository *repository.PairRepository,
+	batchSize int,
+) *Rebuilder {
+	return &Rebuilder{
+		cache:           cache,
+		tokenRepository: tokenRepository,
+		pairRepository:  pairRepository,
+		batchSize:       batchSize,
+	}
+}
+
+func (r *Rebuilder) RebuildTokens() {
+	lastAddress := ""
+	total := 0
+	for {
+		ormTokens, err := r.tokenRepository.GetBatchAfterAddress(lastAddress, r.batchSize)
+		if err != nil {
+			log.Logger.Fatal("load tokens err", zap.String("after", lastAddress), zap.Error(err))
+		}
+		if len(ormTokens) == 0 {
+			break
+		}
+
+		for _, ormToken := range ormTokens {
+			r.cache.SetToken(types.NewTokenFromOrm(ormToken))
+		}
+
+		total += len(ormTokens)
+		lastAddress = ormTokens[len(ormTokens)-1].Address
+		log.Logger.Info("rebuild tokens", zap.Int("total", total), zap.String("last", lastAddress))
+	}
+	log.Logger.Info("rebuild tokens done", zap.Int("total", total))
+}
+
+func (r *Rebuilder) getToken(address string) (*types.Token, bool) {
+	tokenAddress := common.HexToAddress(address)
+	token, ok := r.cache.GetToken(tokenAddress)
+	if ok {
+		return token, true
+	}
+
+	ormToken, err := r.tokenRepository.GetByAddressAndChainId(tokenAddress.String())
+	if err != nil {
+		return nil, false
+	}
+
+	token = types.NewTokenFromOrm(ormToken)
+	r.cache.SetToken(token)
+	return token, true
+}
+
+func (r *Rebuilder) RebuildPairs() {
+	lastAddress := ""
+	total := 0
+	skipped := 0
+	for {
+		ormPairs, err := r.pairRepository.GetBatchAfterAddress(lastAddress, r.batchSize)
+		if err != nil {
+			log.Logger.Fatal("load pairs err", zap.String("after", lastAddress), zap.Error(err))
+		}
+		if len(ormPairs) == 0 {
+			break
+		}
+
+		for _, ormPair := range ormPairs {
+			token0, ok0 := r.getToken(ormPair.Token0)
+			token1, ok1 := r.getToken(ormPair.Token1)
+			if !ok0 || !ok1 {
+				skipped++
+				log.Logger.Warn("pair token not found in db, skip", zap.String("pair", ormPair.Address))
+				continue
+			}
+
+			r.cache.SetPair(types.NewPairFromOrm(ormPair, token0, token1))
+		}
+
+		total += len(ormPairs)
+		lastAddress = ormPairs[len(ormPairs)-1].Address
+		log.Logger.Info("rebuild pairs", zap.Int("total", total), zap.String("last", lastAddress))
+	}
+	log.Logger.Info("rebuild pairs done", zap.Int("total", total), zap.Int("skipped", skipped))
+}
+
+func (r *Rebuilder) RebuildFinishedBlock(txRepository *repository.TxRepository) {
+	maxBlock, err := txRepository.GetMaxBlock()
+	if err != nil {
+		log.Logger.Fatal("get max tx block err", zap.Error(err))
+	}
+
+	if maxBlock == 0 {
+		log.Logger.Warn("no tx in db, finished block pointer not rebuilt")
+		return
+	}
+
+	if finishedBlock := r.cache.GetFinishedBlock(); finishedBlock >= maxBlock {
+		log.Logger.Info("finished block is up to date", zap.Uint64("finished block", finishedBlock))
+		return
+	}
+
+	r.cache.SetFinishedBlock(maxBlock)
+	log.Logger.Info("rebuild finished block done", zap.Uint64("finished block", maxBlock))
+}
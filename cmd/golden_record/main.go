@@ -0,0 +1,80 @@
+package main
+
+import (
+	"base_scan/config"
+	"base_scan/golden"
+	"base_scan/log"
+	"flag"
+	"fmt"
+	"go.uber.org/zap"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseHeights reads comma separated heights and ranges, e.g. 100,200-205.
+func parseHeights(value string) ([]uint64, error) {
+	heights := make([]uint64, 0)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		from, to, isRange := strings.Cut(part, "-")
+		start, err := strconv.ParseUint(from, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid height %q", part)
+		}
+		end := start
+		if isRange {
+			if end, err = strconv.ParseUint(to, 10, 64); err != nil || end < start {
+				return nil, fmt.Errorf("invalid height range %q", part)
+			}
+		}
+		for height := start; height <= end; height++ {
+			heights = append(heights, height)
+		}
+	}
+	return heights, nil
+}
+
+func main() {
+	// fixtures are replayed with the default config, so they are recorded with it
+	conf := config.Default()
+
+	var endpoint string
+	flag.StringVar(&endpoint, "endpoint", conf.Chain.EndpointArchive, "archive node to record from")
+	var heightsValue string
+	flag.StringVar(&heightsValue, "heights", "", "heights to record, comma separated, ranges like 100-105")
+	var outDir string
+	flag.StringVar(&outDir, "o", "golden/testdata", "fixtures dir, each block is recorded to <dir>/<height>")
+	var timeout time.Duration
+	flag.DurationVar(&timeout, "timeout", 2*time.Minute, "timeout to parse a block")
+	flag.Parse()
+
+	heights, err := parseHeights(heightsValue)
+	if err != nil {
+		log.Logger.Fatal("parse heights err", zap.Error(err))
+	}
+	if len(heights) == 0 {
+		log.Logger.Fatal("no heights to record, see -heights")
+	}
+
+	for _, height := range heights {
+		fixture, blockInfo, err := golden.Record(&conf, endpoint, height, timeout)
+		if err != nil {
+			log.Logger.Fatal("record block err", zap.Uint64("height", height), zap.Error(err))
+		}
+
+		dir := filepath.Join(outDir, strconv.FormatUint(height, 10))
+		if err = fixture.Save(dir); err != nil {
+			log.Logger.Fatal("save fixture err", zap.String("dir", dir), zap.Error(err))
+		}
+		if err = golden.SaveBlockInfo(dir, blockInfo); err != nil {
+			log.Logger.Fatal("save block info err", zap.String("dir", dir), zap.Error(err))
+		}
+		log.Logger.Info("block recorded", zap.Uint64("height", height), zap.Int("calls", len(fixture.Calls)), zap.Int("txs", len(blockInfo.Txs)))
+	}
+}
@@ -1,10 +1,8 @@
 package config
 
 import (
-	"encoding/json"
 	"fmt"
 	"github.com/avast/retry-go/v4"
-	"os"
 	"time"
 )
 
@@ -14,22 +12,75 @@ type LogConf struct {
 	AsyncFlushIntervalBySecond int  `json:"async_flush_interval_by_second"`
 }
 
+/*
+ChainConf selects the chain profile, see chain.NewProfile.
+The optional fields override the profile, for chains or deployments the profile doesn't know:
+- native_token: wrapped native token
+- stable_token, stable_token_decimals: usd stable coin
+- price_pair, price_pair_native_is_token0: uniswap v2 like native/stable pair to price the native token
+- factories: protocol name to factory address, e.g. {"UniswapV2": "0x..."}
+- forks: uniswap v2 and v3 forks to index besides the protocols of the profile, see ForkConf
+- discover_forks: index a pool of no known factory as UniswapV2Fork or UniswapV3Fork when its factory() verifies it
+*/
 type ChainConf struct {
-	Endpoint        string `json:"endpoint"`
-	EndpointArchive string `json:"endpoint_archive"`
-	WsEndpoint      string `json:"ws_endpoint"`
+	Profile                 string            `json:"profile"`
+	Endpoint                string            `json:"endpoint" secret:"url"`
+	EndpointArchive         string            `json:"endpoint_archive" secret:"url"`
+	WsEndpoint              string            `json:"ws_endpoint" secret:"url"`
+	NativeToken             string            `json:"native_token"`
+	StableToken             string            `json:"stable_token"`
+	StableTokenDecimals     int               `json:"stable_token_decimals"`
+	PricePair               string            `json:"price_pair"`
+	PricePairNativeIsToken0 bool              `json:"price_pair_native_is_token0"`
+	Factories               map[string]string `json:"factories"`
+	Forks                   []*ForkConf       `json:"forks"`
+	DiscoverForks           bool              `json:"discover_forks"`
+}
+
+/*
+ForkConf declares a fork of uniswap v2 (Version 2) or v3 (Version 3), its pools emit the events of the forked protocol.
+InitCodeHash is optional, with it pools are verified by their create2 address instead of calling the factory.
+Fee is the swap fee of the v2 pools in hundredths of a bip, e.g. 3000 for 0.3%, v3 pools have their own.
+*/
+type ForkConf struct {
+	Name         string `json:"name"`
+	Factory      string `json:"factory"`
+	Version      int    `json:"version"`
+	InitCodeHash string `json:"init_code_hash"`
+	Fee          uint32 `json:"fee"`
 }
 
 type RedisConf struct {
 	Addr     string `json:"addr"`
 	Username string `json:"username"`
-	Password string `json:"password"`
+	Password string `json:"password" secret:"true"`
+	DB       int    `json:"db"`
 }
 
+const (
+	CacheBackendRedis  = "redis"
+	CacheBackendPebble = "pebble"
+)
+
+/*
+CacheConf selects the cache backend.
+- redis: memory + redis, configured by RedisConf
+- pebble: memory + embedded pebble db in PebbleDir, for deployments without redis
+*/
+type CacheConf struct {
+	Backend   string `json:"backend"`
+	PebbleDir string `json:"pebble_dir"`
+}
+
+/*
+BlockGetterConf EndBlockNumber 0 follows the chain head, otherwise the indexer stops after the end block.
+A reparse from the archive ends at archive.reparse_end_block instead, see ArchiveConf.
+*/
 type BlockGetterConf struct {
 	PoolSize         int       `json:"pool_size"`
 	QueueSize        int       `json:"queue_size"`
 	StartBlockNumber uint64    `json:"start_block_number"`
+	EndBlockNumber   uint64    `json:"end_block_number"`
 	Retry            RetryConf `json:"retry"`
 }
 
@@ -66,11 +117,140 @@ type KafkaConf struct {
 	Enabled           bool     `json:"enabled"`
 	Brokers           []string `json:"brokers"`
 	Topic             string   `json:"topic"`
+	MevTopic          string   `json:"mev_topic"`
+	PositionTopic     string   `json:"position_topic"`
+	LaunchTopic       string   `json:"launch_topic"`
 	SendTimeoutByMs   int      `json:"send_timeout_by_ms"`
 	MaxRetry          int      `json:"max_retry"`
 	RetryIntervalByMs int      `json:"retry_interval_by_ms"`
 }
 
+/*
+FilterTTLConf is how long a filtered token or pair stays in cache before it is checked again, 0 means forever.
+Filters that can't change, like a pair without base token, never expire.
+*/
+type FilterTTLConf struct {
+	TokenGetDecimalsSec int `json:"token_get_decimals_sec"`
+	PairGetTokenSec     int `json:"pair_get_token_sec"`
+	PairVerifyFailedSec int `json:"pair_verify_failed_sec"`
+}
+
+/*
+MakerConf controls the maker classification, see package maker.
+Activity is counted in windows of WindowBlocks blocks, a maker is a bot when the current and the previous window
+together have BotBusyBlocks blocks with at least BotTradesPerBlock trades, or BotRoundTrips blocks where it bought
+and sold the same pool. The code of EOAs is checked again every KindRefreshBlocks blocks to notice 7702 delegations.
+*/
+type MakerConf struct {
+	Enabled           bool   `json:"enabled"`
+	WindowBlocks      uint64 `json:"window_blocks"`
+	BotTradesPerBlock int    `json:"bot_trades_per_block"`
+	BotBusyBlocks     int    `json:"bot_busy_blocks"`
+	BotRoundTrips     int    `json:"bot_round_trips"`
+	KindRefreshBlocks uint64 `json:"kind_refresh_blocks"`
+}
+
+/*
+RouterConf names the trades of txs calling one of Addresses, or when there are no addresses,
+calling any contract with one of Selectors, e.g. the 4-byte hex "0x3593564c".
+Routers of the config are matched before the ones of the chain profile.
+*/
+type RouterConf struct {
+	Name      string   `json:"name"`
+	Addresses []string `json:"addresses"`
+	Selectors []string `json:"selectors"`
+}
+
+/*
+QuoteConf controls the local quote engine, see package quote.
+Pools are tracked from the block they are created in, so a pool can only be quoted when it was created after the
+indexer started. The state is saved to SnapshotPath every SnapshotBlocks blocks and loaded at start, no snapshots
+are saved without a path. Listen is the address of the quote api, no api is served without it.
+Pipelines of one process must not share SnapshotPath or the port of Listen, the process doesn't start when they do.
+*/
+type QuoteConf struct {
+	Enabled        bool   `json:"enabled"`
+	SnapshotPath   string `json:"snapshot_path"`
+	SnapshotBlocks uint64 `json:"snapshot_blocks"`
+	Listen         string `json:"listen"`
+}
+
+/*
+LaunchAlertConf controls the launch alerts, see package alert.
+An alert is sent to kafka.launch_topic when the first liquidity of a pair is worth at least MinLiquidityUsd,
+and posted to WebhookUrl when it is worth at least WebhookMinLiquidityUsd, no webhook is called without a url.
+Deployments and native transfers are remembered for WindowBlocks blocks to find the deployer of a token and the
+wallets it funded, WindowMaxEntries of them at most, the oldest blocks are forgotten first past it. They are kept in
+memory only, after a restart the window starts empty. A pool holding less than MinSupplyInPoolPercent of the supply
+is a risk.
+*/
+type LaunchAlertConf struct {
+	Enabled                bool    `json:"enabled"`
+	MinLiquidityUsd        float64 `json:"min_liquidity_usd"`
+	WebhookUrl             string  `json:"webhook_url" secret:"true"`
+	WebhookMinLiquidityUsd float64 `json:"webhook_min_liquidity_usd"`
+	WebhookTimeoutMs       int     `json:"webhook_timeout_ms"`
+	WindowBlocks           uint64  `json:"window_blocks"`
+	WindowMaxEntries       int     `json:"window_max_entries"`
+	MinSupplyInPoolPercent float64 `json:"min_supply_in_pool_percent"`
+}
+
+/*
+PushConf controls the websocket push feed, see package push.
+The last ReplayBlocks committed blocks are kept for the clients connecting with a height to replay from.
+A client is disconnected when SendQueueSize blocks are queued for it, or when it misses the pongs of two pings.
+Pipelines of one process must not share the port of Listen.
+*/
+type PushConf struct {
+	Enabled        bool   `json:"enabled"`
+	Listen         string `json:"listen"`
+	ReplayBlocks   int    `json:"replay_blocks"`
+	SendQueueSize  int    `json:"send_queue_size"`
+	WriteTimeoutMs int    `json:"write_timeout_ms"`
+	PingIntervalMs int    `json:"ping_interval_ms"`
+}
+
+/*
+GrpcConf controls the grpc service, see package grpcapi.
+Streams replay the blocks committed before they started from postgres, ReplayBatchBlocks blocks per query,
+and are ended when SendQueueSize live blocks are queued for them. It needs tx_database and token_pair_database.
+Pipelines of one process must not share the port of Listen.
+*/
+type GrpcConf struct {
+	Enabled           bool   `json:"enabled"`
+	Listen            string `json:"listen"`
+	SendQueueSize     int    `json:"send_queue_size"`
+	ReplayBatchBlocks uint64 `json:"replay_batch_blocks"`
+}
+
+const (
+	ArchiveBackendLocal = "local"
+	ArchiveBackendS3    = "s3"
+)
+
+/*
+ArchiveConf controls the raw block archive, see package archive.
+Blocks and receipts are saved gzipped by chain id, height and hash, to Dir (local) or to Bucket under Prefix of an
+s3 compatible Endpoint (s3), the pipelines of several chains can share them.
+- write: save the blocks fetched from rpc
+- reparse: read the blocks from block_getter.start_block_number to ReparseEndBlock from the archive instead of rpc,
+the indexer stops after ReparseEndBlock
+*/
+type ArchiveConf struct {
+	Write           bool   `json:"write"`
+	Reparse         bool   `json:"reparse"`
+	ReparseEndBlock uint64 `json:"reparse_end_block"`
+	Backend         string `json:"backend"`
+	Dir             string `json:"dir"`
+	Endpoint        string `json:"endpoint"`
+	Bucket          string `json:"bucket"`
+	Prefix          string `json:"prefix"`
+	AccessKey       string `json:"access_key"`
+	SecretKey       string `json:"secret_key" secret:"true"`
+	UseSSL          bool   `json:"use_ssl"`
+	TimeoutMs       int    `json:"timeout_ms"`
+}
+
 type ContractCallerConf struct {
 	Retry *RetryConf `json:"retry"`
 }
@@ -79,7 +259,7 @@ type DBDatasourceConf struct {
 	Host     string `json:"host"`
 	Port     int    `json:"port"`
 	Username string `json:"username"`
-	Password string `json:"password"`
+	Password string `json:"password" secret:"true"`
 	DBName   string `json:"db_name"`
 }
 
@@ -97,37 +277,54 @@ type Config struct {
 	Log               *LogConf            `json:"log"`
 	Chain             *ChainConf          `json:"chain"`
 	Redis             *RedisConf          `json:"redis"`
+	Cache             *CacheConf          `json:"cache"`
 	BlockGetter       *BlockGetterConf    `json:"block_getter"`
 	BlockHandler      *BlockHandlerConf   `json:"block_handler"`
 	EnableSequencer   bool                `json:"enable_sequencer"`
 	PriceService      *PriceServiceConf   `json:"price_service"`
 	Kafka             *KafkaConf          `json:"kafka"`
 	ContractCaller    *ContractCallerConf `json:"contract_caller"`
+	FilterTTL         *FilterTTLConf      `json:"filter_ttl"`
+	Maker             *MakerConf          `json:"maker"`
+	Routers           []*RouterConf       `json:"routers"`
+	Quote             *QuoteConf          `json:"quote"`
+	LaunchAlert       *LaunchAlertConf    `json:"launch_alert"`
+	Push              *PushConf           `json:"push"`
+	Grpc              *GrpcConf           `json:"grpc"`
+	Archive           *ArchiveConf        `json:"archive"`
 	TxDatabase        *DBConf             `json:"tx_database"`
 	TokenPairDatabase *DBConf             `json:"token_pair_database"`
 }
 
-var (
-	defaultConfig = Config{
+// Default returns a new config with the default values, it shares no pointers with other configs.
+func Default() Config {
+	return Config{
 		Log: &LogConf{
 			Async:                      false,
 			AsyncBufferSizeByByte:      1000000,
 			AsyncFlushIntervalBySecond: 1,
 		},
 		Chain: &ChainConf{
+			Profile:         "base",
 			Endpoint:        "https://base-rpc.publicnode.com",
 			EndpointArchive: "https://base-rpc.publicnode.com",
 			WsEndpoint:      "wss://base-rpc.publicnode.com",
+			Forks:           []*ForkConf{},
 		},
 		Redis: &RedisConf{
 			Addr:     "localhost:6379",
 			Username: "",
 			Password: "",
 		},
+		Cache: &CacheConf{
+			Backend:   CacheBackendRedis,
+			PebbleDir: "data/cache",
+		},
 		BlockGetter: &BlockGetterConf{
 			PoolSize:         1,
 			QueueSize:        1,
 			StartBlockNumber: 48000000,
+			EndBlockNumber:   0,
 			Retry: RetryConf{
 				Attempts:  10,
 				DelayMs:   100,
@@ -146,6 +343,9 @@ var (
 			Enabled:           false,
 			Brokers:           []string{"localhost:9092"},
 			Topic:             "block",
+			MevTopic:          "mev",
+			PositionTopic:     "position",
+			LaunchTopic:       "launch",
 			SendTimeoutByMs:   5000,
 			MaxRetry:          10,
 			RetryIntervalByMs: 100,
@@ -157,6 +357,64 @@ var (
 				TimeoutMs: 3000,
 			},
 		},
+		FilterTTL: &FilterTTLConf{
+			TokenGetDecimalsSec: 3600,
+			PairGetTokenSec:     3600,
+			PairVerifyFailedSec: 86400,
+		},
+		Maker: &MakerConf{
+			Enabled:           false,
+			WindowBlocks:      43200,
+			BotTradesPerBlock: 3,
+			BotBusyBlocks:     10,
+			BotRoundTrips:     5,
+			KindRefreshBlocks: 43200,
+		},
+		Routers: []*RouterConf{},
+		Quote: &QuoteConf{
+			Enabled:        false,
+			SnapshotPath:   "data/quote.json",
+			SnapshotBlocks: 1000,
+			Listen:         "0.0.0.0:9200",
+		},
+		LaunchAlert: &LaunchAlertConf{
+			Enabled:                false,
+			MinLiquidityUsd:        1000,
+			WebhookUrl:             "",
+			WebhookMinLiquidityUsd: 10000,
+			WebhookTimeoutMs:       3000,
+			WindowBlocks:           43200,
+			WindowMaxEntries:       1000000,
+			MinSupplyInPoolPercent: 50,
+		},
+		Push: &PushConf{
+			Enabled:        false,
+			Listen:         "0.0.0.0:9300",
+			ReplayBlocks:   300,
+			SendQueueSize:  64,
+			WriteTimeoutMs: 5000,
+			PingIntervalMs: 30000,
+		},
+		Grpc: &GrpcConf{
+			Enabled:           false,
+			Listen:            "0.0.0.0:9400",
+			SendQueueSize:     256,
+			ReplayBatchBlocks: 1000,
+		},
+		Archive: &ArchiveConf{
+			Write:           false,
+			Reparse:         false,
+			ReparseEndBlock: 0,
+			Backend:         ArchiveBackendLocal,
+			Dir:             "data/archive",
+			Endpoint:        "",
+			Bucket:          "",
+			Prefix:          "",
+			AccessKey:       "",
+			SecretKey:       "",
+			UseSSL:          true,
+			TimeoutMs:       10000,
+		},
 		TxDatabase: &DBConf{
 			Enabled: false,
 			DBDatasource: &DBDatasourceConf{
@@ -178,21 +436,4 @@ var (
 			},
 		},
 	}
-
-	G = defaultConfig
-)
-
-func LoadConfigFile(configFilePath string) error {
-	file, err := os.Open(configFilePath)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	decoder := json.NewDecoder(file)
-	if err = decoder.Decode(&G); err != nil {
-		return err
-	}
-
-	return nil
 }
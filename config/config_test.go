@@ -7,6 +7,6 @@ import (
 )
 
 func TestGenerateConfig(t *testing.T) {
-	bs, _ := json.Marshal(G)
+	bs, _ := json.Marshal(Default())
 	log.Println(string(bs))
 }
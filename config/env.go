@@ -0,0 +1,143 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	EnvPrefix = "BASE_SCAN"
+)
+
+/*
+ApplyEnv overrides config fields with environment variables.
+The variable name is EnvPrefix and the json tags of the field path, upper cased and joined by `_`, e.g.
+- BASE_SCAN_REDIS_PASSWORD
+- BASE_SCAN_TX_DATABASE_DB_DATASOURCE_PASSWORD
+- BASE_SCAN_KAFKA_BROKERS=host1:9092,host2:9092
+- BASE_SCAN_CHAIN_FACTORIES=UniswapV2=0x...,UniswapV3=0x...
+- BASE_SCAN_CHAIN_FORKS='[{"name": "...", "factory": "0x...", "version": 2}]'
+
+Slices of strings are comma separated, other slices are json arrays, as in the config file.
+*/
+func ApplyEnv(c *Config, lookupEnv func(string) (string, bool)) error {
+	var errs []error
+	applyEnv(reflect.ValueOf(c).Elem(), EnvPrefix, lookupEnv, &errs)
+	return errors.Join(errs...)
+}
+
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	return name
+}
+
+// applyEnv reports whether any field under v was set, nil struct pointers are only allocated then.
+func applyEnv(v reflect.Value, name string, lookupEnv func(string) (string, bool), errs *[]error) bool {
+	applied := false
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		tag := jsonName(field)
+		if tag == "" || tag == "-" {
+			continue
+		}
+
+		fieldName := name + "_" + strings.ToUpper(tag)
+		fieldValue := v.Field(i)
+
+		if fieldValue.Kind() == reflect.Ptr && fieldValue.Type().Elem().Kind() == reflect.Struct {
+			if !fieldValue.IsNil() {
+				applied = applyEnv(fieldValue.Elem(), fieldName, lookupEnv, errs) || applied
+				continue
+			}
+
+			newValue := reflect.New(fieldValue.Type().Elem())
+			if applyEnv(newValue.Elem(), fieldName, lookupEnv, errs) {
+				fieldValue.Set(newValue)
+				applied = true
+			}
+			continue
+		}
+
+		if fieldValue.Kind() == reflect.Struct {
+			applied = applyEnv(fieldValue, fieldName, lookupEnv, errs) || applied
+			continue
+		}
+
+		env, ok := lookupEnv(fieldName)
+		if !ok {
+			continue
+		}
+
+		if err := setFromString(fieldValue, env); err != nil {
+			*errs = append(*errs, fmt.Errorf("env %s: %w", fieldName, err))
+			continue
+		}
+		applied = true
+	}
+	return applied
+}
+
+func setFromString(v reflect.Value, s string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			slice := reflect.New(v.Type())
+			if err := json.Unmarshal([]byte(s), slice.Interface()); err != nil {
+				return fmt.Errorf("%s must be a json array: %w", v.Type(), err)
+			}
+			v.Set(slice.Elem())
+			return nil
+		}
+		parts := strings.Split(s, ",")
+		for i := range parts {
+			parts[i] = strings.TrimSpace(parts[i])
+		}
+		v.Set(reflect.ValueOf(parts))
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String || v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported map type %s", v.Type())
+		}
+		m := make(map[string]string)
+		for _, part := range strings.Split(s, ",") {
+			key, value, ok := strings.Cut(part, "=")
+			if !ok {
+				return fmt.Errorf("map entry %q is not key=value", part)
+			}
+			m[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+		v.Set(reflect.ValueOf(m))
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
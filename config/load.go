@@ -0,0 +1,81 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported config file format")
+)
+
+// LoadConfigFile loads the config file over Default(), see Load.
+func LoadConfigFile(configFilePath string) (*Config, error) {
+	c := Default()
+	if err := Load(configFilePath, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+/*
+Load loads the config file into c in layers, later layers override earlier ones:
+- the values already in c, usually Default()
+- the config file, json, yaml or toml by extension, unknown keys are rejected
+- BASE_SCAN_* environment variables, see ApplyEnv
+The result is validated before it is returned.
+*/
+func Load(configFilePath string, c *Config) error {
+	data, err := os.ReadFile(configFilePath)
+	if err != nil {
+		return err
+	}
+
+	data, err = toJSON(data, filepath.Ext(configFilePath))
+	if err != nil {
+		return fmt.Errorf("parse %s: %w", configFilePath, err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(c); err != nil {
+		return fmt.Errorf("decode %s: %w", configFilePath, err)
+	}
+
+	if err = ApplyEnv(c, os.LookupEnv); err != nil {
+		return err
+	}
+
+	return c.Validate()
+}
+
+// toJSON converts yaml and toml to json, so that every format is decoded by the json tags.
+func toJSON(data []byte, ext string) ([]byte, error) {
+	var (
+		m   map[string]interface{}
+		err error
+	)
+
+	switch strings.ToLower(ext) {
+	case ".json", "":
+		return data, nil
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &m)
+	case ".toml":
+		err = toml.Unmarshal(data, &m)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, ext)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(m)
+}
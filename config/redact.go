@@ -0,0 +1,80 @@
+package config
+
+import (
+	"encoding/json"
+	"net/url"
+	"reflect"
+)
+
+const (
+	redacted = "******"
+)
+
+/*
+Redacted returns a deep copy of the config with the secret fields masked, for printing:
+- `secret:"true"`: the whole value
+- `secret:"url"`: the user info, path and query of the url, which may hold an api key
+*/
+func (c *Config) Redacted() (*Config, error) {
+	bytes, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+
+	copied := &Config{}
+	if err = json.Unmarshal(bytes, copied); err != nil {
+		return nil, err
+	}
+
+	redact(reflect.ValueOf(copied).Elem())
+	return copied, nil
+}
+
+func redact(v reflect.Value) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		fieldValue := v.Field(i)
+
+		if fieldValue.Kind() == reflect.Ptr {
+			if fieldValue.IsNil() || fieldValue.Elem().Kind() != reflect.Struct {
+				continue
+			}
+			fieldValue = fieldValue.Elem()
+		}
+
+		if fieldValue.Kind() == reflect.Struct {
+			redact(fieldValue)
+			continue
+		}
+
+		if fieldValue.Kind() != reflect.String || fieldValue.String() == "" {
+			continue
+		}
+		switch field.Tag.Get("secret") {
+		case "true":
+			fieldValue.SetString(redacted)
+		case "url":
+			fieldValue.SetString(redactURL(fieldValue.String()))
+		}
+	}
+}
+
+// redactURL keeps the scheme and host of a url, an unparsable one is masked whole.
+func redactURL(s string) string {
+	u, err := url.Parse(s)
+	if err != nil || u.Host == "" {
+		return redacted
+	}
+	masked := u.Scheme + "://"
+	if u.User != nil {
+		masked += redacted + "@"
+	}
+	masked += u.Host
+	if u.Path != "" && u.Path != "/" {
+		masked += "/" + redacted
+	}
+	if u.RawQuery != "" {
+		masked += "?" + redacted
+	}
+	return masked
+}
@@ -0,0 +1,193 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+type validator struct {
+	errs []error
+}
+
+func (v *validator) check(ok bool, format string, args ...interface{}) {
+	if !ok {
+		v.errs = append(v.errs, fmt.Errorf(format, args...))
+	}
+}
+
+func (v *validator) required(ok bool, name string) bool {
+	v.check(ok, "%s is required", name)
+	return ok
+}
+
+func (v *validator) validateRetry(name string, c *RetryConf) {
+	v.check(c.Attempts > 0, "%s.attempts must be > 0", name)
+	v.check(c.DelayMs >= 0, "%s.delay_ms must be >= 0", name)
+	v.check(c.TimeoutMs > 0, "%s.timeout_ms must be > 0", name)
+}
+
+func (v *validator) validateDB(name string, c *DBConf) {
+	if !v.required(c != nil, name) || !c.Enabled {
+		return
+	}
+
+	if !v.required(c.DBDatasource != nil, name+".db_datasource") {
+		return
+	}
+	v.check(c.DBDatasource.Host != "", "%s.db_datasource.host is required", name)
+	v.check(c.DBDatasource.Port > 0 && c.DBDatasource.Port < 65536, "%s.db_datasource.port must be in 1..65535", name)
+	v.check(c.DBDatasource.Username != "", "%s.db_datasource.username is required", name)
+	v.check(c.DBDatasource.DBName != "", "%s.db_datasource.db_name is required", name)
+}
+
+// Validate checks the whole config and reports every problem at once.
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	if v.required(c.Log != nil, "log") && c.Log.Async {
+		v.check(c.Log.AsyncBufferSizeByByte > 0, "log.async_buffer_size_by_byte must be > 0 when log.async")
+		v.check(c.Log.AsyncFlushIntervalBySecond > 0, "log.async_flush_interval_by_second must be > 0 when log.async")
+	}
+
+	if v.required(c.Chain != nil, "chain") {
+		v.check(c.Chain.Profile != "", "chain.profile is required")
+		v.check(c.Chain.Endpoint != "", "chain.endpoint is required")
+		v.check(c.Chain.EndpointArchive != "", "chain.endpoint_archive is required")
+		v.check(c.Chain.WsEndpoint != "", "chain.ws_endpoint is required")
+
+		names := make(map[string]bool, len(c.Chain.Forks))
+		for i, fork := range c.Chain.Forks {
+			if !v.required(fork != nil, fmt.Sprintf("chain.forks[%d]", i)) {
+				continue
+			}
+			v.check(fork.Name != "", "chain.forks[%d].name is required", i)
+			v.check(!names[fork.Name], "chain.forks[%d].name %q is duplicated", i, fork.Name)
+			names[fork.Name] = true
+			v.check(fork.Factory != "", "chain.forks[%d].factory is required", i)
+			v.check(fork.Version == 2 || fork.Version == 3, "chain.forks[%d].version must be 2 or 3, got %d", i, fork.Version)
+			v.check(fork.Fee < 1000000, "chain.forks[%d].fee must be < 1000000", i)
+		}
+	}
+
+	if v.required(c.Cache != nil, "cache") {
+		switch c.Cache.Backend {
+		case CacheBackendRedis:
+			if v.required(c.Redis != nil, "redis") {
+				v.check(c.Redis.Addr != "", "redis.addr is required when cache.backend is %s", CacheBackendRedis)
+			}
+		case CacheBackendPebble:
+			v.check(c.Cache.PebbleDir != "", "cache.pebble_dir is required when cache.backend is %s", CacheBackendPebble)
+		default:
+			v.check(false, "cache.backend must be %s or %s, got %q", CacheBackendRedis, CacheBackendPebble, c.Cache.Backend)
+		}
+	}
+
+	if v.required(c.BlockGetter != nil, "block_getter") {
+		v.check(c.BlockGetter.PoolSize > 0, "block_getter.pool_size must be > 0")
+		v.check(c.BlockGetter.QueueSize > 0, "block_getter.queue_size must be > 0")
+		v.check(c.BlockGetter.EndBlockNumber == 0 || c.BlockGetter.EndBlockNumber >= c.BlockGetter.StartBlockNumber,
+			"block_getter.end_block_number must be 0 or >= block_getter.start_block_number")
+		v.validateRetry("block_getter.retry", &c.BlockGetter.Retry)
+	}
+
+	if v.required(c.BlockHandler != nil, "block_handler") {
+		v.check(c.BlockHandler.PoolSize > 0, "block_handler.pool_size must be > 0")
+		v.check(c.BlockHandler.QueueSize > 0, "block_handler.queue_size must be > 0")
+	}
+
+	if v.required(c.PriceService != nil, "price_service") {
+		v.check(c.PriceService.PoolSize >= 0, "price_service.pool_size must be >= 0")
+	}
+
+	if v.required(c.Kafka != nil, "kafka") && c.Kafka.Enabled {
+		v.check(len(c.Kafka.Brokers) > 0, "kafka.brokers is required when kafka.enabled")
+		v.check(c.Kafka.Topic != "", "kafka.topic is required when kafka.enabled")
+		v.check(c.Kafka.SendTimeoutByMs > 0, "kafka.send_timeout_by_ms must be > 0")
+		v.check(c.Kafka.MaxRetry >= 0, "kafka.max_retry must be >= 0")
+		v.check(c.Kafka.RetryIntervalByMs >= 0, "kafka.retry_interval_by_ms must be >= 0")
+	}
+
+	if v.required(c.ContractCaller != nil, "contract_caller") && v.required(c.ContractCaller.Retry != nil, "contract_caller.retry") {
+		v.validateRetry("contract_caller.retry", c.ContractCaller.Retry)
+	}
+
+	if v.required(c.FilterTTL != nil, "filter_ttl") {
+		v.check(c.FilterTTL.TokenGetDecimalsSec >= 0, "filter_ttl.token_get_decimals_sec must be >= 0")
+		v.check(c.FilterTTL.PairGetTokenSec >= 0, "filter_ttl.pair_get_token_sec must be >= 0")
+		v.check(c.FilterTTL.PairVerifyFailedSec >= 0, "filter_ttl.pair_verify_failed_sec must be >= 0")
+	}
+
+	if v.required(c.Maker != nil, "maker") && c.Maker.Enabled {
+		v.check(c.Maker.WindowBlocks > 0, "maker.window_blocks must be > 0 when maker.enabled")
+		v.check(c.Maker.BotTradesPerBlock > 1, "maker.bot_trades_per_block must be > 1")
+		v.check(c.Maker.BotBusyBlocks > 0, "maker.bot_busy_blocks must be > 0")
+		v.check(c.Maker.BotRoundTrips > 0, "maker.bot_round_trips must be > 0")
+	}
+
+	for i, router := range c.Routers {
+		if !v.required(router != nil, fmt.Sprintf("routers[%d]", i)) {
+			continue
+		}
+		v.check(router.Name != "", "routers[%d].name is required", i)
+		v.check(len(router.Addresses) > 0 || len(router.Selectors) > 0, "routers[%d] needs addresses or selectors", i)
+	}
+
+	if v.required(c.Quote != nil, "quote") && c.Quote.Enabled && c.Quote.SnapshotPath != "" {
+		v.check(c.Quote.SnapshotBlocks > 0, "quote.snapshot_blocks must be > 0 when quote.snapshot_path is set")
+	}
+
+	if v.required(c.LaunchAlert != nil, "launch_alert") && c.LaunchAlert.Enabled {
+		v.check(c.LaunchAlert.MinLiquidityUsd >= 0, "launch_alert.min_liquidity_usd must be >= 0")
+		v.check(c.LaunchAlert.WebhookMinLiquidityUsd >= 0, "launch_alert.webhook_min_liquidity_usd must be >= 0")
+		v.check(c.LaunchAlert.WindowBlocks > 0, "launch_alert.window_blocks must be > 0 when launch_alert.enabled")
+		v.check(c.LaunchAlert.WindowMaxEntries > 0, "launch_alert.window_max_entries must be > 0 when launch_alert.enabled")
+		v.check(c.LaunchAlert.MinSupplyInPoolPercent >= 0 && c.LaunchAlert.MinSupplyInPoolPercent <= 100,
+			"launch_alert.min_supply_in_pool_percent must be in 0..100")
+		if c.LaunchAlert.WebhookUrl != "" {
+			v.check(c.LaunchAlert.WebhookTimeoutMs > 0, "launch_alert.webhook_timeout_ms must be > 0 when launch_alert.webhook_url is set")
+		}
+	}
+
+	if v.required(c.Push != nil, "push") && c.Push.Enabled {
+		v.check(c.Push.Listen != "", "push.listen is required when push.enabled")
+		v.check(c.Push.ReplayBlocks >= 0, "push.replay_blocks must be >= 0")
+		v.check(c.Push.SendQueueSize > 0, "push.send_queue_size must be > 0")
+		v.check(c.Push.WriteTimeoutMs > 0, "push.write_timeout_ms must be > 0")
+		v.check(c.Push.PingIntervalMs > 0, "push.ping_interval_ms must be > 0")
+	}
+
+	if v.required(c.Grpc != nil, "grpc") && c.Grpc.Enabled {
+		v.check(c.Grpc.Listen != "", "grpc.listen is required when grpc.enabled")
+		v.check(c.Grpc.SendQueueSize > 0, "grpc.send_queue_size must be > 0")
+		v.check(c.Grpc.ReplayBatchBlocks > 0, "grpc.replay_batch_blocks must be > 0")
+		// streams replay and lookups read from postgres
+		v.check(c.TxDatabase != nil && c.TxDatabase.Enabled, "tx_database.enabled is required when grpc.enabled")
+		v.check(c.TokenPairDatabase != nil && c.TokenPairDatabase.Enabled, "token_pair_database.enabled is required when grpc.enabled")
+	}
+
+	if v.required(c.Archive != nil, "archive") && (c.Archive.Write || c.Archive.Reparse) {
+		switch c.Archive.Backend {
+		case ArchiveBackendLocal:
+			v.check(c.Archive.Dir != "", "archive.dir is required when archive.backend is %s", ArchiveBackendLocal)
+		case ArchiveBackendS3:
+			v.check(c.Archive.Endpoint != "", "archive.endpoint is required when archive.backend is %s", ArchiveBackendS3)
+			v.check(c.Archive.Bucket != "", "archive.bucket is required when archive.backend is %s", ArchiveBackendS3)
+		default:
+			v.check(false, "archive.backend must be %s or %s, got %q", ArchiveBackendLocal, ArchiveBackendS3, c.Archive.Backend)
+		}
+		v.check(c.Archive.TimeoutMs > 0, "archive.timeout_ms must be > 0")
+		if c.Archive.Reparse {
+			v.check(!c.Archive.Write, "archive.write and archive.reparse can't both be set")
+			if c.BlockGetter != nil {
+				v.check(c.BlockGetter.StartBlockNumber > 0, "block_getter.start_block_number is required when archive.reparse")
+				v.check(c.Archive.ReparseEndBlock >= c.BlockGetter.StartBlockNumber, "archive.reparse_end_block must be >= block_getter.start_block_number")
+				v.check(c.BlockGetter.EndBlockNumber == 0, "block_getter.end_block_number can't be set when archive.reparse, see archive.reparse_end_block")
+			}
+		}
+	}
+
+	v.validateDB("tx_database", c.TxDatabase)
+	v.validateDB("token_pair_database", c.TokenPairDatabase)
+
+	return errors.Join(v.errs...)
+}
@@ -0,0 +1,103 @@
+package fanout
+
+import (
+	"base_scan/log"
+	"base_scan/types"
+	"go.uber.org/zap"
+	"sync"
+)
+
+/*
+Fanout hands the committed blocks to subscribers, each reading them from a queue of its own.
+Queues are bounded, a subscriber whose queue is full when a block is published is removed and its queue closed,
+so publishing never blocks on a subscriber. The servers built on it keep their own state next to the subscribers,
+updated and read under the same lock, see Publish and Subscribe.
+*/
+type Fanout struct {
+	name      string
+	queueSize int
+
+	mu          sync.Mutex
+	subscribers map[*Subscriber]struct{}
+}
+
+// Subscriber receives the published blocks from Queue until it is removed, then Queue is closed.
+type Subscriber struct {
+	id    string
+	queue chan *types.BlockInfo
+}
+
+// Queue is the channel of the published blocks, closed when the subscriber is removed.
+func (s *Subscriber) Queue() <-chan *types.BlockInfo {
+	return s.queue
+}
+
+// New returns a fanout named for its logs, queueSize blocks are queued at most for a subscriber.
+func New(name string, queueSize int) *Fanout {
+	return &Fanout{
+		name:        name,
+		queueSize:   queueSize,
+		subscribers: make(map[*Subscriber]struct{}),
+	}
+}
+
+/*
+Publish calls record, then queues a committed block to every subscriber, blocks must be published in order.
+record may be nil, it runs under the lock of Subscribe. blockInfo is shared and must not be changed afterward.
+*/
+func (f *Fanout) Publish(blockInfo *types.BlockInfo, record func()) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if record != nil {
+		record()
+	}
+	for s := range f.subscribers {
+		select {
+		case s.queue <- blockInfo:
+		default:
+			log.Logger.Info("subscriber too slow, removed", zap.String("fanout", f.name), zap.String("subscriber", s.id), zap.Uint64("height", blockInfo.Height))
+			f.removeLocked(s)
+		}
+	}
+}
+
+/*
+Subscribe calls snapshot, then adds a subscriber receiving the blocks published from then on, no block is
+published in between. snapshot may be nil, nothing is added when it fails. id names the subscriber in the logs.
+*/
+func (f *Fanout) Subscribe(id string, snapshot func() error) (*Subscriber, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if snapshot != nil {
+		if err := snapshot(); err != nil {
+			return nil, err
+		}
+	}
+	s := &Subscriber{id: id, queue: make(chan *types.BlockInfo, f.queueSize)}
+	f.subscribers[s] = struct{}{}
+	return s, nil
+}
+
+// Unsubscribe removes a subscriber, removing it again does nothing.
+func (f *Fanout) Unsubscribe(s *Subscriber) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.removeLocked(s)
+}
+
+func (f *Fanout) removeLocked(s *Subscriber) {
+	if _, ok := f.subscribers[s]; !ok {
+		return
+	}
+	delete(f.subscribers, s)
+	close(s.queue)
+}
+
+// Len is the number of subscribers.
+func (f *Fanout) Len() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.subscribers)
+}
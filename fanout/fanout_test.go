@@ -0,0 +1,48 @@
+package fanout
+
+import (
+	"base_scan/types"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestPublish(t *testing.T) {
+	f := New("test", 2)
+	fast, err := f.Subscribe("fast", nil)
+	require.NoError(t, err)
+	slow, err := f.Subscribe("slow", nil)
+	require.NoError(t, err)
+	require.Equal(t, 2, f.Len())
+
+	published := make([]uint64, 0)
+	for height := uint64(1); height <= 3; height++ {
+		f.Publish(&types.BlockInfo{Height: height}, func() { published = append(published, height) })
+		if height < 3 {
+			require.Equal(t, height, (<-fast.Queue()).Height)
+		}
+	}
+	require.Equal(t, []uint64{1, 2, 3}, published)
+
+	// the slow subscriber didn't take any block, its queue was full at the 3rd
+	require.Equal(t, 1, f.Len())
+	require.Equal(t, uint64(1), (<-slow.Queue()).Height)
+	require.Equal(t, uint64(2), (<-slow.Queue()).Height)
+	_, ok := <-slow.Queue()
+	require.False(t, ok)
+
+	require.Equal(t, uint64(3), (<-fast.Queue()).Height)
+	f.Unsubscribe(fast)
+	f.Unsubscribe(fast)
+	_, ok = <-fast.Queue()
+	require.False(t, ok)
+	require.Equal(t, 0, f.Len())
+}
+
+func TestSubscribeSnapshotErr(t *testing.T) {
+	f := New("test", 2)
+	errSnapshot := errors.New("snapshot")
+	_, err := f.Subscribe("failed", func() error { return errSnapshot })
+	require.ErrorIs(t, err, errSnapshot)
+	require.Equal(t, 0, f.Len())
+}
@@ -0,0 +1,292 @@
+package golden
+
+import (
+	"base_scan/chain"
+	"base_scan/types"
+	"encoding/json"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const fakeHeight = 30000000
+
+var (
+	fakeToken = common.HexToAddress("0x1111111111111111111111111111111111111111")
+	fakePair  = common.HexToAddress("0x2222222222222222222222222222222222222222")
+	// a fixed key, the block and so the fixture are the same every time
+	fakeKey, _ = crypto.HexToECDSA("8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45c72ce7b7a")
+
+	v2SyncTopic = common.HexToHash("0x1c411e9a96e071241c2f21f7726b17ae89e3cab4c78be50e062b03a9fffbbad1")
+	v2SwapTopic = common.HexToHash("0xd78ad95fa46c994b6551d0da85fc275fe613ce37657fb8d5e3d130840159d822")
+)
+
+func pack(t *testing.T, typeName string, value interface{}) string {
+	typ, err := abi.NewType(typeName, "", nil)
+	require.NoError(t, err)
+	bytes, err := abi.Arguments{{Type: typ}}.Pack(value)
+	require.NoError(t, err)
+	return hexutil.Encode(bytes)
+}
+
+func packUints(t *testing.T, values ...*big.Int) string {
+	typ, err := abi.NewType("uint256", "", nil)
+	require.NoError(t, err)
+	args := make(abi.Arguments, 0, len(values))
+	ifaces := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		args = append(args, abi.Argument{Type: typ})
+		ifaces = append(ifaces, value)
+	}
+	bytes, err := args.Pack(ifaces...)
+	require.NoError(t, err)
+	return hexutil.Encode(bytes)
+}
+
+func selector(signature string) string {
+	return hexutil.Encode(crypto.Keccak256([]byte(signature))[:4])
+}
+
+func ether(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), big.NewInt(1e18))
+}
+
+/*
+fakeNode is a scripted node of a chain with one block: a deposit tx and a buy of fakeToken with WETH on the
+uniswap v2 pair fakePair. Contract calls are answered by address and selector, the others revert.
+*/
+type fakeNode struct {
+	block    *ethtypes.Block
+	receipts []*ethtypes.Receipt
+	results  map[string]string
+}
+
+func newFakeNode(t *testing.T, profile *chain.Profile) *fakeNode {
+	signer := ethtypes.LatestSignerForChainID(profile.ChainConfig.ChainID)
+	deposit := ethtypes.NewTx(&ethtypes.DepositTx{
+		SourceHash: common.HexToHash("0x01"),
+		From:       common.HexToAddress("0xDeaDDEaDDeAdDeAdDEAdDEaddeAddEAdDEAd0001"),
+		To:         &profile.NativeToken,
+		Value:      big.NewInt(0),
+		Gas:        1000000,
+		Data:       []byte{0x01},
+	})
+	buy := ethtypes.MustSignNewTx(fakeKey, signer, &ethtypes.DynamicFeeTx{
+		ChainID:   profile.ChainConfig.ChainID,
+		Nonce:     3,
+		GasTipCap: big.NewInt(1000),
+		GasFeeCap: big.NewInt(100000000),
+		Gas:       300000,
+		To:        &fakePair,
+		Value:     big.NewInt(0),
+		Data:      []byte{0x02, 0x2c, 0x0d, 0x9f},
+	})
+	maker := crypto.PubkeyToAddress(fakeKey.PublicKey)
+
+	// fakeToken sorts before WETH, it is token0
+	reserve0, reserve1 := ether(999000), ether(101)
+	receipts := []*ethtypes.Receipt{
+		{
+			Type:              deposit.Type(),
+			Status:            ethtypes.ReceiptStatusSuccessful,
+			CumulativeGasUsed: 50000,
+			Logs:              []*ethtypes.Log{},
+			TxHash:            deposit.Hash(),
+			GasUsed:           50000,
+			TransactionIndex:  0,
+		},
+		{
+			Type:              buy.Type(),
+			Status:            ethtypes.ReceiptStatusSuccessful,
+			CumulativeGasUsed: 150000,
+			Logs: []*ethtypes.Log{
+				{
+					Address: fakePair,
+					Topics:  []common.Hash{v2SyncTopic},
+					Data:    hexutil.MustDecode(packUints(t, reserve0, reserve1)),
+					TxIndex: 1,
+					Index:   0,
+				},
+				{
+					Address: fakePair,
+					Topics:  []common.Hash{v2SwapTopic, common.BytesToHash(maker.Bytes()), common.BytesToHash(maker.Bytes())},
+					Data:    hexutil.MustDecode(packUints(t, big.NewInt(0), ether(1), ether(1000), big.NewInt(0))),
+					TxIndex: 1,
+					Index:   1,
+				},
+			},
+			TxHash:           buy.Hash(),
+			GasUsed:          100000,
+			TransactionIndex: 1,
+		},
+	}
+
+	header := &ethtypes.Header{
+		ParentHash: common.HexToHash("0x03"),
+		Number:     big.NewInt(fakeHeight),
+		Time:       1748000000,
+		GasLimit:   30000000,
+		GasUsed:    150000,
+		BaseFee:    big.NewInt(1000000),
+		Difficulty: big.NewInt(0),
+	}
+	body := &ethtypes.Body{Transactions: []*ethtypes.Transaction{deposit, buy}, Withdrawals: []*ethtypes.Withdrawal{}}
+	block := ethtypes.NewBlock(header, body, receipts, trie.NewStackTrie(nil), ethtypes.DefaultBlockConfig)
+
+	for _, receipt := range receipts {
+		receipt.BlockHash = block.Hash()
+		receipt.BlockNumber = block.Number()
+		for _, log := range receipt.Logs {
+			log.BlockNumber = fakeHeight
+			log.BlockHash = block.Hash()
+			log.TxHash = receipt.TxHash
+		}
+	}
+
+	usdcReserve := new(big.Int).Mul(big.NewInt(2500*1000), big.NewInt(1e6))
+	nativeReserve := ether(1000)
+	priceReserve0, priceReserve1 := nativeReserve, usdcReserve
+	if !profile.PricePairNativeIsToken0 {
+		priceReserve0, priceReserve1 = usdcReserve, nativeReserve
+	}
+
+	v2Factory := profile.Factories[types.ProtocolIdUniswapV2]
+	result := func(address common.Address, signature string) string {
+		return strings.ToLower(address.Hex()) + selector(signature)
+	}
+	results := map[string]string{
+		result(profile.PricePair, "getReserves()"):    packUints(t, priceReserve0, priceReserve1, big.NewInt(1748000000)),
+		result(fakePair, "token0()"):                  pack(t, "address", fakeToken),
+		result(fakePair, "token1()"):                  pack(t, "address", profile.NativeToken),
+		result(v2Factory, "getPair(address,address)"): pack(t, "address", fakePair),
+		result(fakeToken, "name()"):                   pack(t, "string", "Golden"),
+		result(fakeToken, "symbol()"):                 pack(t, "string", "GLD"),
+		result(fakeToken, "decimals()"):               pack(t, "uint8", uint8(18)),
+		result(fakeToken, "totalSupply()"):            packUints(t, ether(1000000000)),
+		result(profile.NativeToken, "name()"):         pack(t, "string", "Wrapped Ether"),
+		result(profile.NativeToken, "symbol()"):       pack(t, "string", "WETH"),
+		result(profile.NativeToken, "decimals()"):     pack(t, "uint8", uint8(18)),
+		result(profile.NativeToken, "totalSupply()"):  packUints(t, ether(100000)),
+	}
+
+	return &fakeNode{block: block, receipts: receipts, results: results}
+}
+
+// blockJSON is the block as returned by eth_getBlockByNumber with full txs.
+func (n *fakeNode) blockJSON() (json.RawMessage, error) {
+	fields := make(map[string]interface{})
+	header, err := json.Marshal(n.block.Header())
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(header, &fields); err != nil {
+		return nil, err
+	}
+
+	txs := make([]map[string]interface{}, 0, n.block.Transactions().Len())
+	signer := ethtypes.LatestSignerForChainID(n.block.Transactions()[1].ChainId())
+	for i, tx := range n.block.Transactions() {
+		txFields := make(map[string]interface{})
+		bytes, err := json.Marshal(tx)
+		if err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal(bytes, &txFields); err != nil {
+			return nil, err
+		}
+		// the json of a deposit tx has its sender already
+		if !tx.IsDepositTx() {
+			from, err := ethtypes.Sender(signer, tx)
+			if err != nil {
+				return nil, err
+			}
+			txFields["from"] = from
+		}
+		txFields["blockHash"] = n.block.Hash()
+		txFields["blockNumber"] = hexutil.EncodeBig(n.block.Number())
+		txFields["transactionIndex"] = hexutil.EncodeUint64(uint64(i))
+		txs = append(txs, txFields)
+	}
+
+	fields["hash"] = n.block.Hash()
+	fields["transactions"] = txs
+	fields["uncles"] = []common.Hash{}
+	fields["withdrawals"] = n.block.Withdrawals()
+	return json.Marshal(fields)
+}
+
+func (n *fakeNode) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var body json.RawMessage
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	requests, batch, err := parseMessages(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	responses := make([]*rpcMessage, 0, len(requests))
+	for _, request := range requests {
+		response := &rpcMessage{Version: "2.0", Id: request.Id}
+		response.Result, response.Error = n.answer(request)
+		responses = append(responses, response)
+	}
+	writeMessages(w, responses, batch)
+}
+
+func (n *fakeNode) answer(request *rpcMessage) (json.RawMessage, *rpcError) {
+	var params []json.RawMessage
+	_ = json.Unmarshal(request.Params, &params)
+	reverted := &rpcError{Code: 3, Message: "execution reverted"}
+
+	switch request.Method {
+	case "eth_blockNumber":
+		return json.RawMessage(`"` + hexutil.EncodeUint64(fakeHeight) + `"`), nil
+	case "eth_getBlockByNumber":
+		if len(params) == 0 || string(params[0]) != `"`+hexutil.EncodeUint64(fakeHeight)+`"` {
+			return json.RawMessage("null"), nil
+		}
+		block, err := n.blockJSON()
+		if err != nil {
+			return nil, &rpcError{Code: -32000, Message: err.Error()}
+		}
+		return block, nil
+	case "eth_getBlockReceipts":
+		receipts, _ := json.Marshal(n.receipts)
+		return receipts, nil
+	case "eth_getCode":
+		return json.RawMessage(`"0x"`), nil
+	case "eth_call":
+		var arg struct {
+			To    common.Address `json:"to"`
+			Input hexutil.Bytes  `json:"input"`
+			Data  hexutil.Bytes  `json:"data"`
+		}
+		if len(params) == 0 || json.Unmarshal(params[0], &arg) != nil {
+			return nil, reverted
+		}
+		input := arg.Input
+		if len(input) == 0 {
+			input = arg.Data
+		}
+		if len(input) < 4 {
+			return nil, reverted
+		}
+		result, ok := n.results[strings.ToLower(arg.To.Hex())+hexutil.Encode(input[:4])]
+		if !ok {
+			return nil, reverted
+		}
+		return json.RawMessage(`"` + result + `"`), nil
+	}
+	return nil, &rpcError{Code: -32601, Message: "method not found"}
+}
@@ -0,0 +1,88 @@
+package golden
+
+import (
+	"base_scan/types"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+const (
+	RpcFile       = "rpc.json.gz"
+	BlockInfoFile = "block_info.json"
+)
+
+/*
+Fixture is a block recorded once from a node, replayed offline through the block getter and the block parser to
+compare its BlockInfo to the expected one, see golden_test.go. Fixtures are recorded with cmd/golden_record into
+golden/testdata, a fixture is a directory with:
+- rpc.json.gz: the height and every rpc call made to parse it, gzipped json
+- block_info.json: the expected types.BlockInfo, normalized, reviewed like code
+*/
+type Fixture struct {
+	Height uint64  `json:"height"`
+	Calls  []*Call `json:"calls"`
+}
+
+func LoadFixture(dir string) (*Fixture, error) {
+	data, err := os.ReadFile(filepath.Join(dir, RpcFile))
+	if err != nil {
+		return nil, err
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	raw, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	fixture := &Fixture{}
+	if err = json.Unmarshal(raw, fixture); err != nil {
+		return nil, err
+	}
+	return fixture, nil
+}
+
+func (f *Fixture) Save(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	buf := &bytes.Buffer{}
+	writer := gzip.NewWriter(buf)
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", " ")
+	if err := encoder.Encode(f); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, RpcFile), buf.Bytes(), 0644)
+}
+
+func MarshalBlockInfo(blockInfo *types.BlockInfo) ([]byte, error) {
+	bytes, err := json.MarshalIndent(blockInfo, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+	return append(bytes, '\n'), nil
+}
+
+func LoadBlockInfo(dir string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(dir, BlockInfoFile))
+}
+
+func SaveBlockInfo(dir string, blockInfo *types.BlockInfo) error {
+	bytes, err := MarshalBlockInfo(blockInfo)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, BlockInfoFile), bytes, 0644)
+}
@@ -0,0 +1,49 @@
+package golden
+
+import (
+	"base_scan/config"
+	"base_scan/types"
+	"net"
+	"net/http"
+	"time"
+)
+
+// serve serves a handler on a local port until the returned func is called.
+func serve(handler http.Handler) (string, func(), error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", nil, err
+	}
+	server := &http.Server{Handler: handler}
+	go func() { _ = server.Serve(listener) }()
+	return "http://" + listener.Addr().String(), func() { _ = server.Close() }, nil
+}
+
+// Record parses a block with the node at endpoint and returns the fixture of the calls it took and the BlockInfo.
+func Record(conf *config.Config, endpoint string, height uint64, timeout time.Duration) (*Fixture, *types.BlockInfo, error) {
+	recorder := NewRecorder(endpoint)
+	url, stop, err := serve(recorder)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer stop()
+
+	blockInfo, err := Parse(conf, url, height, timeout)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &Fixture{Height: height, Calls: recorder.Calls()}, blockInfo, nil
+}
+
+// Replay parses the block of a fixture offline and returns the BlockInfo and the calls that were not recorded.
+func Replay(conf *config.Config, fixture *Fixture, timeout time.Duration) (*types.BlockInfo, []string, error) {
+	replayer := NewReplayer(fixture.Calls)
+	url, stop, err := serve(replayer)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer stop()
+
+	blockInfo, err := Parse(conf, url, fixture.Height, timeout)
+	return blockInfo, replayer.Misses(), err
+}
@@ -0,0 +1,89 @@
+package golden
+
+import (
+	"base_scan/chain"
+	"base_scan/config"
+	"flag"
+	"github.com/stretchr/testify/require"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "rewrite the expected block infos of the fixtures, and the fake_node fixture")
+
+const replayTimeout = 30 * time.Second
+
+/*
+TestGolden replays every fixture of testdata offline and compares the BlockInfo to the expected one.
+After a change of the parser, review the changes of the expected BlockInfos written by go test ./golden -update.
+*/
+func TestGolden(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "*", RpcFile))
+	require.NoError(t, err)
+	if len(files) == 0 {
+		t.Skip("no fixtures, record some with cmd/golden_record")
+	}
+
+	conf := config.Default()
+	for _, file := range files {
+		dir := filepath.Dir(file)
+		t.Run(filepath.Base(dir), func(t *testing.T) {
+			fixture, err := LoadFixture(dir)
+			require.NoError(t, err)
+
+			blockInfo, misses, err := Replay(&conf, fixture, replayTimeout)
+			require.NoError(t, err, "calls not recorded: %v", misses)
+			require.Empty(t, misses, "the parser made calls that were not recorded, record the fixture again")
+
+			if *update {
+				require.NoError(t, SaveBlockInfo(dir, blockInfo))
+				return
+			}
+			expected, err := LoadBlockInfo(dir)
+			require.NoError(t, err)
+			actual, err := MarshalBlockInfo(blockInfo)
+			require.NoError(t, err)
+			require.JSONEq(t, string(expected), string(actual))
+		})
+	}
+}
+
+// TestRecordReplay records the block of a fake node and checks the replay parses it the same without the node.
+func TestRecordReplay(t *testing.T) {
+	conf := config.Default()
+	profile, err := chain.NewProfile(conf.Chain)
+	require.NoError(t, err)
+
+	node := httptest.NewServer(newFakeNode(t, profile))
+	fixture, recorded, err := Record(&conf, node.URL, fakeHeight, replayTimeout)
+	node.Close()
+	require.NoError(t, err)
+
+	require.Len(t, recorded.Txs, 1)
+	require.Equal(t, fakePair.String(), recorded.Txs[0].PairAddress)
+	require.Len(t, recorded.NewPairs, 1)
+	require.Len(t, recorded.NewTokens, 2)
+	require.Equal(t, "2500", recorded.NativeTokenPrice)
+
+	replayed, misses, err := Replay(&conf, fixture, replayTimeout)
+	require.NoError(t, err)
+	require.Empty(t, misses)
+	recordedJSON, err := MarshalBlockInfo(recorded)
+	require.NoError(t, err)
+	replayedJSON, err := MarshalBlockInfo(replayed)
+	require.NoError(t, err)
+	require.Equal(t, string(recordedJSON), string(replayedJSON))
+
+	if *update {
+		dir := filepath.Join("testdata", "fake_node")
+		require.NoError(t, fixture.Save(dir))
+		require.NoError(t, SaveBlockInfo(dir, replayed))
+	}
+
+	// a call missing from the fixture is reported
+	fixture.Calls = fixture.Calls[1:]
+	_, misses, _ = Replay(&conf, fixture, 5*time.Second)
+	require.NotEmpty(t, misses)
+}
@@ -0,0 +1,186 @@
+package golden
+
+import (
+	"base_scan/block_getter"
+	"base_scan/cache"
+	"base_scan/chain"
+	"base_scan/config"
+	"base_scan/maker"
+	"base_scan/parser"
+	"base_scan/router"
+	"base_scan/sequencer"
+	"base_scan/service"
+	"base_scan/types"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	ErrNotParsed = errors.New("block not parsed")
+)
+
+// blockInfoSender is the kafka sender of the harness, it keeps the block infos instead of sending them.
+type blockInfoSender struct {
+	mu         sync.Mutex
+	blockInfos []*types.BlockInfo
+}
+
+func (s *blockInfoSender) Send(block *types.BlockInfo) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.blockInfos = append(s.blockInfos, block)
+	return nil
+}
+
+func (s *blockInfoSender) SendMev(*types.MevInfo) error {
+	return nil
+}
+
+func (s *blockInfoSender) SendPositions(*types.PositionInfo) error {
+	return nil
+}
+
+func (s *blockInfoSender) SendLaunchAlerts(*types.LaunchAlertInfo) error {
+	return nil
+}
+
+func (s *blockInfoSender) get() []*types.BlockInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.blockInfos
+}
+
+/*
+Parse runs a block through the block getter and the block parser of the indexer, with the node at endpoint and an
+empty cache, and returns the types.BlockInfo sent to kafka. Nothing is written to a db, the optional components
+not changing the BlockInfo (quotes, alerts, push, grpc) are left out.
+A block the node can't serve fails after timeout, the parser waits for the native token price forever.
+*/
+func Parse(conf *config.Config, endpoint string, height uint64, timeout time.Duration) (*types.BlockInfo, error) {
+	profile, err := chain.NewProfile(conf.Chain)
+	if err != nil {
+		return nil, err
+	}
+
+	ethClient, err := ethclient.Dial(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer ethClient.Close()
+
+	routers, err := router.NewRegistry(profile, conf.Routers)
+	if err != nil {
+		return nil, err
+	}
+
+	c := cache.NewMockCache()
+	contractCaller := service.NewContractCaller(ethClient, conf.ContractCaller.Retry.GetRetryParams())
+	dbService := service.NewDBService(nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	pairService := service.NewPairService(c, contractCaller, dbService, conf.FilterTTL, profile.BaseTokens(), profile.Factories, profile.InitCodeHashes, profile.DiscoverForks)
+	priceService := service.NewPriceService(c, contractCaller, ethClient, 0, profile)
+	var classifier *maker.Classifier
+	if conf.Maker.Enabled {
+		classifier = maker.NewClassifier(c, contractCaller, conf.Maker, profile.Id)
+	}
+	sender := &blockInfoSender{}
+
+	parserSequencer := sequencer.NewBlockSequencer(true)
+	parserSequencer.Init(height)
+	blockParser := parser.NewBlockParser(
+		c,
+		parserSequencer,
+		priceService,
+		pairService,
+		parser.NewTopicRouter(profile.FactoryProtocolIds(), profile.BaseTokens()),
+		sender,
+		dbService,
+		classifier,
+		routers,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		conf.BlockHandler,
+		profile,
+	)
+
+	getterConf := *conf.BlockGetter
+	getterConf.StartBlockNumber = height
+	getterConf.EndBlockNumber = height
+	getterSequencer := sequencer.NewBlockSequencer(true)
+	getterSequencer.Init(height)
+	blockGetter := block_getter.NewBlockGetter(ethClient, nil, c, getterSequencer, &getterConf, &config.ArchiveConf{}, nil, profile)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		wg := &sync.WaitGroup{}
+		wg.Add(1)
+		blockParser.Start(wg)
+		blockGetter.Start()
+		blockGetter.StartDispatch(height)
+		for blockCtx := blockGetter.Next(); blockCtx != nil; blockCtx = blockGetter.Next() {
+			blockParser.ParseBlockAsync(blockCtx)
+		}
+		blockParser.Stop()
+		wg.Wait()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		return nil, fmt.Errorf("%w: block %d timed out after %s", ErrNotParsed, height, timeout)
+	}
+
+	blockInfos := sender.get()
+	if len(blockInfos) != 1 {
+		return nil, fmt.Errorf("%w: block %d, the node failed to serve it", ErrNotParsed, height)
+	}
+	return Normalize(blockInfos[0]), nil
+}
+
+// sortByJSON sorts items by their json, for the items of a BlockInfo collected from maps in no particular order.
+func sortByJSON[T any](items []T) {
+	keys := make([]string, len(items))
+	for i, item := range items {
+		bytes, _ := json.Marshal(item)
+		keys[i] = string(bytes)
+	}
+	sort.Sort(&byKey[T]{items: items, keys: keys})
+}
+
+type byKey[T any] struct {
+	items []T
+	keys  []string
+}
+
+func (b *byKey[T]) Len() int {
+	return len(b.items)
+}
+
+func (b *byKey[T]) Less(i, j int) bool {
+	return b.keys[i] < b.keys[j]
+}
+
+func (b *byKey[T]) Swap(i, j int) {
+	b.items[i], b.items[j] = b.items[j], b.items[i]
+	b.keys[i], b.keys[j] = b.keys[j], b.keys[i]
+}
+
+// Normalize orders the items of a BlockInfo that are in no particular order, so the BlockInfos of a block are equal.
+func Normalize(blockInfo *types.BlockInfo) *types.BlockInfo {
+	sortByJSON(blockInfo.NewTokens)
+	sortByJSON(blockInfo.NewPairs)
+	sortByJSON(blockInfo.NewPools)
+	sortByJSON(blockInfo.Launches)
+	sortByJSON(blockInfo.PairFeeUpdates)
+	sortByJSON(blockInfo.PoolUpdates)
+	sortByJSON(blockInfo.PoolUpdateParameters)
+	return blockInfo
+}
@@ -0,0 +1,240 @@
+package golden
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+var (
+	ErrNotRecorded = errors.New("rpc call not recorded")
+)
+
+// codeNotRecorded is the json rpc error code of a call the Replayer has no response for.
+const codeNotRecorded = -32099
+
+type rpcError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+type rpcMessage struct {
+	Version string          `json:"jsonrpc"`
+	Id      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// Call is a json rpc call and its response, the result or the error of the node.
+type Call struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *rpcError       `json:"error,omitempty"`
+}
+
+// callKey identifies a call by its method and params, the params are compacted so their formatting doesn't matter.
+func callKey(method string, params json.RawMessage) string {
+	buf := &bytes.Buffer{}
+	if err := json.Compact(buf, params); err != nil {
+		return method + string(params)
+	}
+	return method + buf.String()
+}
+
+// parseMessages reads a json rpc request or response body, a single message or a batch.
+func parseMessages(body []byte) ([]*rpcMessage, bool, error) {
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		var messages []*rpcMessage
+		err := json.Unmarshal(body, &messages)
+		return messages, true, err
+	}
+
+	message := &rpcMessage{}
+	if err := json.Unmarshal(body, message); err != nil {
+		return nil, false, err
+	}
+	return []*rpcMessage{message}, false, nil
+}
+
+func writeMessages(w http.ResponseWriter, messages []*rpcMessage, batch bool) {
+	w.Header().Set("Content-Type", "application/json")
+	if batch {
+		_ = json.NewEncoder(w).Encode(messages)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(messages[0])
+}
+
+/*
+Recorder is a json rpc proxy recording the calls it forwards to a node and their responses.
+Calls the node fails to answer, e.g. with http status 429, are passed back to the client and not recorded.
+*/
+type Recorder struct {
+	upstream string
+	client   *http.Client
+
+	mu    sync.Mutex
+	calls map[string]*Call
+}
+
+func NewRecorder(upstream string) *Recorder {
+	return &Recorder{
+		upstream: upstream,
+		client:   &http.Client{},
+		calls:    make(map[string]*Call),
+	}
+}
+
+func (r *Recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	requests, _, err := parseMessages(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := r.client.Post(r.upstream, "application/json", bytes.NewReader(body))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		if responses, _, parseErr := parseMessages(respBody); parseErr == nil {
+			r.record(requests, responses)
+		}
+	}
+
+	w.Header().Set("Content-Type", resp.Header.Get("Content-Type"))
+	w.WriteHeader(resp.StatusCode)
+	_, _ = w.Write(respBody)
+}
+
+func (r *Recorder) record(requests, responses []*rpcMessage) {
+	id2Response := make(map[string]*rpcMessage, len(responses))
+	for _, response := range responses {
+		id2Response[string(response.Id)] = response
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, request := range requests {
+		response, ok := id2Response[string(request.Id)]
+		if !ok {
+			continue
+		}
+		r.calls[callKey(request.Method, request.Params)] = &Call{
+			Method: request.Method,
+			Params: request.Params,
+			Result: response.Result,
+			Error:  response.Error,
+		}
+	}
+}
+
+// Calls returns the recorded calls ordered by method and params, so fixtures of the same calls are the same.
+func (r *Recorder) Calls() []*Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	keys := make([]string, 0, len(r.calls))
+	for key := range r.calls {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	calls := make([]*Call, 0, len(keys))
+	for _, key := range keys {
+		calls = append(calls, r.calls[key])
+	}
+	return calls
+}
+
+// Replayer is a json rpc server answering the recorded calls, the others are answered with an error and kept as misses.
+type Replayer struct {
+	calls map[string]*Call
+
+	mu     sync.Mutex
+	misses map[string]struct{}
+}
+
+func NewReplayer(calls []*Call) *Replayer {
+	r := &Replayer{
+		calls:  make(map[string]*Call, len(calls)),
+		misses: make(map[string]struct{}),
+	}
+	for _, call := range calls {
+		r.calls[callKey(call.Method, call.Params)] = call
+	}
+	return r
+}
+
+func (r *Replayer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	requests, batch, err := parseMessages(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	responses := make([]*rpcMessage, 0, len(requests))
+	for _, request := range requests {
+		response := &rpcMessage{Version: "2.0", Id: request.Id}
+		key := callKey(request.Method, request.Params)
+		if call, ok := r.calls[key]; ok {
+			response.Result = call.Result
+			response.Error = call.Error
+			if response.Result == nil && response.Error == nil {
+				response.Result = json.RawMessage("null")
+			}
+		} else {
+			r.miss(key)
+			response.Error = &rpcError{Code: codeNotRecorded, Message: fmt.Sprintf("%s: %s", ErrNotRecorded, key)}
+		}
+		responses = append(responses, response)
+	}
+	writeMessages(w, responses, batch)
+}
+
+func (r *Replayer) miss(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.misses[key] = struct{}{}
+}
+
+// Misses returns the calls that were not recorded, sorted.
+func (r *Replayer) Misses() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	misses := make([]string, 0, len(r.misses))
+	for key := range r.misses {
+		misses = append(misses, key)
+	}
+	sort.Strings(misses)
+	return misses
+}
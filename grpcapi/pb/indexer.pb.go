@@ -0,0 +1,750 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: indexer.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type StreamBlocksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FromHeight    uint64                 `protobuf:"varint,1,opt,name=from_height,json=fromHeight,proto3" json:"from_height,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamBlocksRequest) Reset() {
+	*x = StreamBlocksRequest{}
+	mi := &file_indexer_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamBlocksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamBlocksRequest) ProtoMessage() {}
+
+func (x *StreamBlocksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_indexer_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamBlocksRequest.ProtoReflect.Descriptor instead.
+func (*StreamBlocksRequest) Descriptor() ([]byte, []int) {
+	return file_indexer_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *StreamBlocksRequest) GetFromHeight() uint64 {
+	if x != nil {
+		return x.FromHeight
+	}
+	return 0
+}
+
+// Block is a committed block, block_info is the json of the BlockInfo sent to the kafka block topic.
+// Replayed blocks are read from postgres, which doesn't keep every part of a block, they are partial:
+//   - only the blocks with trades, new pairs or new pools are replayed, the heights of the others are skipped
+//   - their BlockInfo has the Txs, NewPairs, NewPools, Routes and NativeTokenPrice only, the NewTokens,
+//     PoolUpdates, PoolUpdateParameters, PairFeeUpdates, Launches and PoolFees are empty
+type Block struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Height    uint64                 `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	Timestamp uint64                 `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Replay    bool                   `protobuf:"varint,3,opt,name=replay,proto3" json:"replay,omitempty"`
+	BlockInfo []byte                 `protobuf:"bytes,4,opt,name=block_info,json=blockInfo,proto3" json:"block_info,omitempty"`
+	// the block_info is partial, see Block
+	Partial       bool `protobuf:"varint,5,opt,name=partial,proto3" json:"partial,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Block) Reset() {
+	*x = Block{}
+	mi := &file_indexer_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Block) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Block) ProtoMessage() {}
+
+func (x *Block) ProtoReflect() protoreflect.Message {
+	mi := &file_indexer_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Block.ProtoReflect.Descriptor instead.
+func (*Block) Descriptor() ([]byte, []int) {
+	return file_indexer_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Block) GetHeight() uint64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *Block) GetTimestamp() uint64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *Block) GetReplay() bool {
+	if x != nil {
+		return x.Replay
+	}
+	return false
+}
+
+func (x *Block) GetBlockInfo() []byte {
+	if x != nil {
+		return x.BlockInfo
+	}
+	return nil
+}
+
+func (x *Block) GetPartial() bool {
+	if x != nil {
+		return x.Partial
+	}
+	return false
+}
+
+type GetTokenRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTokenRequest) Reset() {
+	*x = GetTokenRequest{}
+	mi := &file_indexer_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTokenRequest) ProtoMessage() {}
+
+func (x *GetTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_indexer_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTokenRequest.ProtoReflect.Descriptor instead.
+func (*GetTokenRequest) Descriptor() ([]byte, []int) {
+	return file_indexer_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetTokenRequest) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+type Token struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Symbol        string                 `protobuf:"bytes,3,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Decimals      int32                  `protobuf:"varint,4,opt,name=decimals,proto3" json:"decimals,omitempty"`
+	TotalSupply   string                 `protobuf:"bytes,5,opt,name=total_supply,json=totalSupply,proto3" json:"total_supply,omitempty"`
+	Block         uint64                 `protobuf:"varint,6,opt,name=block,proto3" json:"block,omitempty"`
+	Program       string                 `protobuf:"bytes,7,opt,name=program,proto3" json:"program,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Token) Reset() {
+	*x = Token{}
+	mi := &file_indexer_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Token) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Token) ProtoMessage() {}
+
+func (x *Token) ProtoReflect() protoreflect.Message {
+	mi := &file_indexer_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Token.ProtoReflect.Descriptor instead.
+func (*Token) Descriptor() ([]byte, []int) {
+	return file_indexer_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Token) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *Token) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Token) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *Token) GetDecimals() int32 {
+	if x != nil {
+		return x.Decimals
+	}
+	return 0
+}
+
+func (x *Token) GetTotalSupply() string {
+	if x != nil {
+		return x.TotalSupply
+	}
+	return ""
+}
+
+func (x *Token) GetBlock() uint64 {
+	if x != nil {
+		return x.Block
+	}
+	return 0
+}
+
+func (x *Token) GetProgram() string {
+	if x != nil {
+		return x.Program
+	}
+	return ""
+}
+
+type GetPairRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPairRequest) Reset() {
+	*x = GetPairRequest{}
+	mi := &file_indexer_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPairRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPairRequest) ProtoMessage() {}
+
+func (x *GetPairRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_indexer_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPairRequest.ProtoReflect.Descriptor instead.
+func (*GetPairRequest) Descriptor() ([]byte, []int) {
+	return file_indexer_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetPairRequest) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+// Pair has its tokens ordered, token1 is the base token the pair is priced in.
+type Pair struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Address        string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Token0         string                 `protobuf:"bytes,2,opt,name=token0,proto3" json:"token0,omitempty"`
+	Token1         string                 `protobuf:"bytes,3,opt,name=token1,proto3" json:"token1,omitempty"`
+	Token0Symbol   string                 `protobuf:"bytes,4,opt,name=token0_symbol,json=token0Symbol,proto3" json:"token0_symbol,omitempty"`
+	Token1Symbol   string                 `protobuf:"bytes,5,opt,name=token1_symbol,json=token1Symbol,proto3" json:"token1_symbol,omitempty"`
+	Token0Decimals int32                  `protobuf:"varint,6,opt,name=token0_decimals,json=token0Decimals,proto3" json:"token0_decimals,omitempty"`
+	Token1Decimals int32                  `protobuf:"varint,7,opt,name=token1_decimals,json=token1Decimals,proto3" json:"token1_decimals,omitempty"`
+	Program        string                 `protobuf:"bytes,8,opt,name=program,proto3" json:"program,omitempty"`
+	Block          uint64                 `protobuf:"varint,9,opt,name=block,proto3" json:"block,omitempty"`
+	Stable         bool                   `protobuf:"varint,10,opt,name=stable,proto3" json:"stable,omitempty"`
+	Fee            uint32                 `protobuf:"varint,11,opt,name=fee,proto3" json:"fee,omitempty"`
+	TickSpacing    int32                  `protobuf:"varint,12,opt,name=tick_spacing,json=tickSpacing,proto3" json:"tick_spacing,omitempty"`
+	Version        int32                  `protobuf:"varint,13,opt,name=version,proto3" json:"version,omitempty"`
+	Filtered       bool                   `protobuf:"varint,14,opt,name=filtered,proto3" json:"filtered,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Pair) Reset() {
+	*x = Pair{}
+	mi := &file_indexer_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Pair) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Pair) ProtoMessage() {}
+
+func (x *Pair) ProtoReflect() protoreflect.Message {
+	mi := &file_indexer_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Pair.ProtoReflect.Descriptor instead.
+func (*Pair) Descriptor() ([]byte, []int) {
+	return file_indexer_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Pair) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *Pair) GetToken0() string {
+	if x != nil {
+		return x.Token0
+	}
+	return ""
+}
+
+func (x *Pair) GetToken1() string {
+	if x != nil {
+		return x.Token1
+	}
+	return ""
+}
+
+func (x *Pair) GetToken0Symbol() string {
+	if x != nil {
+		return x.Token0Symbol
+	}
+	return ""
+}
+
+func (x *Pair) GetToken1Symbol() string {
+	if x != nil {
+		return x.Token1Symbol
+	}
+	return ""
+}
+
+func (x *Pair) GetToken0Decimals() int32 {
+	if x != nil {
+		return x.Token0Decimals
+	}
+	return 0
+}
+
+func (x *Pair) GetToken1Decimals() int32 {
+	if x != nil {
+		return x.Token1Decimals
+	}
+	return 0
+}
+
+func (x *Pair) GetProgram() string {
+	if x != nil {
+		return x.Program
+	}
+	return ""
+}
+
+func (x *Pair) GetBlock() uint64 {
+	if x != nil {
+		return x.Block
+	}
+	return 0
+}
+
+func (x *Pair) GetStable() bool {
+	if x != nil {
+		return x.Stable
+	}
+	return false
+}
+
+func (x *Pair) GetFee() uint32 {
+	if x != nil {
+		return x.Fee
+	}
+	return 0
+}
+
+func (x *Pair) GetTickSpacing() int32 {
+	if x != nil {
+		return x.TickSpacing
+	}
+	return 0
+}
+
+func (x *Pair) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Pair) GetFiltered() bool {
+	if x != nil {
+		return x.Filtered
+	}
+	return false
+}
+
+type GetNativeTokenPriceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Height        uint64                 `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetNativeTokenPriceRequest) Reset() {
+	*x = GetNativeTokenPriceRequest{}
+	mi := &file_indexer_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNativeTokenPriceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNativeTokenPriceRequest) ProtoMessage() {}
+
+func (x *GetNativeTokenPriceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_indexer_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNativeTokenPriceRequest.ProtoReflect.Descriptor instead.
+func (*GetNativeTokenPriceRequest) Descriptor() ([]byte, []int) {
+	return file_indexer_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetNativeTokenPriceRequest) GetHeight() uint64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+type GetPairPriceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pair          string                 `protobuf:"bytes,1,opt,name=pair,proto3" json:"pair,omitempty"`
+	Height        uint64                 `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPairPriceRequest) Reset() {
+	*x = GetPairPriceRequest{}
+	mi := &file_indexer_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPairPriceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPairPriceRequest) ProtoMessage() {}
+
+func (x *GetPairPriceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_indexer_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPairPriceRequest.ProtoReflect.Descriptor instead.
+func (*GetPairPriceRequest) Descriptor() ([]byte, []int) {
+	return file_indexer_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetPairPriceRequest) GetPair() string {
+	if x != nil {
+		return x.Pair
+	}
+	return ""
+}
+
+func (x *GetPairPriceRequest) GetHeight() uint64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+// Price is a usd price as a decimal string, height is the block it is of.
+type Price struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PriceUsd      string                 `protobuf:"bytes,1,opt,name=price_usd,json=priceUsd,proto3" json:"price_usd,omitempty"`
+	Height        uint64                 `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Price) Reset() {
+	*x = Price{}
+	mi := &file_indexer_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Price) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Price) ProtoMessage() {}
+
+func (x *Price) ProtoReflect() protoreflect.Message {
+	mi := &file_indexer_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Price.ProtoReflect.Descriptor instead.
+func (*Price) Descriptor() ([]byte, []int) {
+	return file_indexer_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Price) GetPriceUsd() string {
+	if x != nil {
+		return x.PriceUsd
+	}
+	return ""
+}
+
+func (x *Price) GetHeight() uint64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+var File_indexer_proto protoreflect.FileDescriptor
+
+const file_indexer_proto_rawDesc = "" +
+	"\n" +
+	"\rindexer.proto\x12\fbase_scan.v1\"6\n" +
+	"\x13StreamBlocksRequest\x12\x1f\n" +
+	"\vfrom_height\x18\x01 \x01(\x04R\n" +
+	"fromHeight\"\x8e\x01\n" +
+	"\x05Block\x12\x16\n" +
+	"\x06height\x18\x01 \x01(\x04R\x06height\x12\x1c\n" +
+	"\ttimestamp\x18\x02 \x01(\x04R\ttimestamp\x12\x16\n" +
+	"\x06replay\x18\x03 \x01(\bR\x06replay\x12\x1d\n" +
+	"\n" +
+	"block_info\x18\x04 \x01(\fR\tblockInfo\x12\x18\n" +
+	"\apartial\x18\x05 \x01(\bR\apartial\"+\n" +
+	"\x0fGetTokenRequest\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\"\xbc\x01\n" +
+	"\x05Token\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06symbol\x18\x03 \x01(\tR\x06symbol\x12\x1a\n" +
+	"\bdecimals\x18\x04 \x01(\x05R\bdecimals\x12!\n" +
+	"\ftotal_supply\x18\x05 \x01(\tR\vtotalSupply\x12\x14\n" +
+	"\x05block\x18\x06 \x01(\x04R\x05block\x12\x18\n" +
+	"\aprogram\x18\a \x01(\tR\aprogram\"*\n" +
+	"\x0eGetPairRequest\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\"\x9f\x03\n" +
+	"\x04Pair\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x12\x16\n" +
+	"\x06token0\x18\x02 \x01(\tR\x06token0\x12\x16\n" +
+	"\x06token1\x18\x03 \x01(\tR\x06token1\x12#\n" +
+	"\rtoken0_symbol\x18\x04 \x01(\tR\ftoken0Symbol\x12#\n" +
+	"\rtoken1_symbol\x18\x05 \x01(\tR\ftoken1Symbol\x12'\n" +
+	"\x0ftoken0_decimals\x18\x06 \x01(\x05R\x0etoken0Decimals\x12'\n" +
+	"\x0ftoken1_decimals\x18\a \x01(\x05R\x0etoken1Decimals\x12\x18\n" +
+	"\aprogram\x18\b \x01(\tR\aprogram\x12\x14\n" +
+	"\x05block\x18\t \x01(\x04R\x05block\x12\x16\n" +
+	"\x06stable\x18\n" +
+	" \x01(\bR\x06stable\x12\x10\n" +
+	"\x03fee\x18\v \x01(\rR\x03fee\x12!\n" +
+	"\ftick_spacing\x18\f \x01(\x05R\vtickSpacing\x12\x18\n" +
+	"\aversion\x18\r \x01(\x05R\aversion\x12\x1a\n" +
+	"\bfiltered\x18\x0e \x01(\bR\bfiltered\"4\n" +
+	"\x1aGetNativeTokenPriceRequest\x12\x16\n" +
+	"\x06height\x18\x01 \x01(\x04R\x06height\"A\n" +
+	"\x13GetPairPriceRequest\x12\x12\n" +
+	"\x04pair\x18\x01 \x01(\tR\x04pair\x12\x16\n" +
+	"\x06height\x18\x02 \x01(\x04R\x06height\"<\n" +
+	"\x05Price\x12\x1b\n" +
+	"\tprice_usd\x18\x01 \x01(\tR\bpriceUsd\x12\x16\n" +
+	"\x06height\x18\x02 \x01(\x04R\x06height2\xee\x02\n" +
+	"\aIndexer\x12H\n" +
+	"\fStreamBlocks\x12!.base_scan.v1.StreamBlocksRequest\x1a\x13.base_scan.v1.Block0\x01\x12>\n" +
+	"\bGetToken\x12\x1d.base_scan.v1.GetTokenRequest\x1a\x13.base_scan.v1.Token\x12;\n" +
+	"\aGetPair\x12\x1c.base_scan.v1.GetPairRequest\x1a\x12.base_scan.v1.Pair\x12T\n" +
+	"\x13GetNativeTokenPrice\x12(.base_scan.v1.GetNativeTokenPriceRequest\x1a\x13.base_scan.v1.Price\x12F\n" +
+	"\fGetPairPrice\x12!.base_scan.v1.GetPairPriceRequest\x1a\x13.base_scan.v1.PriceB\x16Z\x14base_scan/grpcapi/pbb\x06proto3"
+
+var (
+	file_indexer_proto_rawDescOnce sync.Once
+	file_indexer_proto_rawDescData []byte
+)
+
+func file_indexer_proto_rawDescGZIP() []byte {
+	file_indexer_proto_rawDescOnce.Do(func() {
+		file_indexer_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_indexer_proto_rawDesc), len(file_indexer_proto_rawDesc)))
+	})
+	return file_indexer_proto_rawDescData
+}
+
+var file_indexer_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_indexer_proto_goTypes = []any{
+	(*StreamBlocksRequest)(nil),        // 0: base_scan.v1.StreamBlocksRequest
+	(*Block)(nil),                      // 1: base_scan.v1.Block
+	(*GetTokenRequest)(nil),            // 2: base_scan.v1.GetTokenRequest
+	(*Token)(nil),                      // 3: base_scan.v1.Token
+	(*GetPairRequest)(nil),             // 4: base_scan.v1.GetPairRequest
+	(*Pair)(nil),                       // 5: base_scan.v1.Pair
+	(*GetNativeTokenPriceRequest)(nil), // 6: base_scan.v1.GetNativeTokenPriceRequest
+	(*GetPairPriceRequest)(nil),        // 7: base_scan.v1.GetPairPriceRequest
+	(*Price)(nil),                      // 8: base_scan.v1.Price
+}
+var file_indexer_proto_depIdxs = []int32{
+	0, // 0: base_scan.v1.Indexer.StreamBlocks:input_type -> base_scan.v1.StreamBlocksRequest
+	2, // 1: base_scan.v1.Indexer.GetToken:input_type -> base_scan.v1.GetTokenRequest
+	4, // 2: base_scan.v1.Indexer.GetPair:input_type -> base_scan.v1.GetPairRequest
+	6, // 3: base_scan.v1.Indexer.GetNativeTokenPrice:input_type -> base_scan.v1.GetNativeTokenPriceRequest
+	7, // 4: base_scan.v1.Indexer.GetPairPrice:input_type -> base_scan.v1.GetPairPriceRequest
+	1, // 5: base_scan.v1.Indexer.StreamBlocks:output_type -> base_scan.v1.Block
+	3, // 6: base_scan.v1.Indexer.GetToken:output_type -> base_scan.v1.Token
+	5, // 7: base_scan.v1.Indexer.GetPair:output_type -> base_scan.v1.Pair
+	8, // 8: base_scan.v1.Indexer.GetNativeTokenPrice:output_type -> base_scan.v1.Price
+	8, // 9: base_scan.v1.Indexer.GetPairPrice:output_type -> base_scan.v1.Price
+	5, // [5:10] is the sub-list for method output_type
+	0, // [0:5] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_indexer_proto_init() }
+func file_indexer_proto_init() {
+	if File_indexer_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_indexer_proto_rawDesc), len(file_indexer_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_indexer_proto_goTypes,
+		DependencyIndexes: file_indexer_proto_depIdxs,
+		MessageInfos:      file_indexer_proto_msgTypes,
+	}.Build()
+	File_indexer_proto = out.File
+	file_indexer_proto_goTypes = nil
+	file_indexer_proto_depIdxs = nil
+}
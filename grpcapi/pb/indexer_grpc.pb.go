@@ -0,0 +1,291 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: indexer.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Indexer_StreamBlocks_FullMethodName        = "/base_scan.v1.Indexer/StreamBlocks"
+	Indexer_GetToken_FullMethodName            = "/base_scan.v1.Indexer/GetToken"
+	Indexer_GetPair_FullMethodName             = "/base_scan.v1.Indexer/GetPair"
+	Indexer_GetNativeTokenPrice_FullMethodName = "/base_scan.v1.Indexer/GetNativeTokenPrice"
+	Indexer_GetPairPrice_FullMethodName        = "/base_scan.v1.Indexer/GetPairPrice"
+)
+
+// IndexerClient is the client API for Indexer service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Indexer serves the committed blocks of one chain and lookups of its tokens, pairs and prices.
+type IndexerClient interface {
+	// StreamBlocks sends the committed blocks from from_height on, replaying the blocks before the live ones from
+	// postgres, then the live blocks as they are committed. from_height 0 sends the live blocks only.
+	StreamBlocks(ctx context.Context, in *StreamBlocksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Block], error)
+	GetToken(ctx context.Context, in *GetTokenRequest, opts ...grpc.CallOption) (*Token, error)
+	GetPair(ctx context.Context, in *GetPairRequest, opts ...grpc.CallOption) (*Pair, error)
+	// GetNativeTokenPrice returns the usd price of the native token at a height, 0 is the last committed one.
+	GetNativeTokenPrice(ctx context.Context, in *GetNativeTokenPriceRequest, opts ...grpc.CallOption) (*Price, error)
+	// GetPairPrice returns the usd price of the token0 of a pair at its last trade at or before a height,
+	// 0 is the last committed one.
+	GetPairPrice(ctx context.Context, in *GetPairPriceRequest, opts ...grpc.CallOption) (*Price, error)
+}
+
+type indexerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewIndexerClient(cc grpc.ClientConnInterface) IndexerClient {
+	return &indexerClient{cc}
+}
+
+func (c *indexerClient) StreamBlocks(ctx context.Context, in *StreamBlocksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Block], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Indexer_ServiceDesc.Streams[0], Indexer_StreamBlocks_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamBlocksRequest, Block]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Indexer_StreamBlocksClient = grpc.ServerStreamingClient[Block]
+
+func (c *indexerClient) GetToken(ctx context.Context, in *GetTokenRequest, opts ...grpc.CallOption) (*Token, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Token)
+	err := c.cc.Invoke(ctx, Indexer_GetToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *indexerClient) GetPair(ctx context.Context, in *GetPairRequest, opts ...grpc.CallOption) (*Pair, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Pair)
+	err := c.cc.Invoke(ctx, Indexer_GetPair_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *indexerClient) GetNativeTokenPrice(ctx context.Context, in *GetNativeTokenPriceRequest, opts ...grpc.CallOption) (*Price, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Price)
+	err := c.cc.Invoke(ctx, Indexer_GetNativeTokenPrice_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *indexerClient) GetPairPrice(ctx context.Context, in *GetPairPriceRequest, opts ...grpc.CallOption) (*Price, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Price)
+	err := c.cc.Invoke(ctx, Indexer_GetPairPrice_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// IndexerServer is the server API for Indexer service.
+// All implementations must embed UnimplementedIndexerServer
+// for forward compatibility.
+//
+// Indexer serves the committed blocks of one chain and lookups of its tokens, pairs and prices.
+type IndexerServer interface {
+	// StreamBlocks sends the committed blocks from from_height on, replaying the blocks before the live ones from
+	// postgres, then the live blocks as they are committed. from_height 0 sends the live blocks only.
+	StreamBlocks(*StreamBlocksRequest, grpc.ServerStreamingServer[Block]) error
+	GetToken(context.Context, *GetTokenRequest) (*Token, error)
+	GetPair(context.Context, *GetPairRequest) (*Pair, error)
+	// GetNativeTokenPrice returns the usd price of the native token at a height, 0 is the last committed one.
+	GetNativeTokenPrice(context.Context, *GetNativeTokenPriceRequest) (*Price, error)
+	// GetPairPrice returns the usd price of the token0 of a pair at its last trade at or before a height,
+	// 0 is the last committed one.
+	GetPairPrice(context.Context, *GetPairPriceRequest) (*Price, error)
+	mustEmbedUnimplementedIndexerServer()
+}
+
+// UnimplementedIndexerServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedIndexerServer struct{}
+
+func (UnimplementedIndexerServer) StreamBlocks(*StreamBlocksRequest, grpc.ServerStreamingServer[Block]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamBlocks not implemented")
+}
+func (UnimplementedIndexerServer) GetToken(context.Context, *GetTokenRequest) (*Token, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetToken not implemented")
+}
+func (UnimplementedIndexerServer) GetPair(context.Context, *GetPairRequest) (*Pair, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPair not implemented")
+}
+func (UnimplementedIndexerServer) GetNativeTokenPrice(context.Context, *GetNativeTokenPriceRequest) (*Price, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNativeTokenPrice not implemented")
+}
+func (UnimplementedIndexerServer) GetPairPrice(context.Context, *GetPairPriceRequest) (*Price, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPairPrice not implemented")
+}
+func (UnimplementedIndexerServer) mustEmbedUnimplementedIndexerServer() {}
+func (UnimplementedIndexerServer) testEmbeddedByValue()                 {}
+
+// UnsafeIndexerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to IndexerServer will
+// result in compilation errors.
+type UnsafeIndexerServer interface {
+	mustEmbedUnimplementedIndexerServer()
+}
+
+func RegisterIndexerServer(s grpc.ServiceRegistrar, srv IndexerServer) {
+	// If the following call pancis, it indicates UnimplementedIndexerServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Indexer_ServiceDesc, srv)
+}
+
+func _Indexer_StreamBlocks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamBlocksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(IndexerServer).StreamBlocks(m, &grpc.GenericServerStream[StreamBlocksRequest, Block]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Indexer_StreamBlocksServer = grpc.ServerStreamingServer[Block]
+
+func _Indexer_GetToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IndexerServer).GetToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Indexer_GetToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IndexerServer).GetToken(ctx, req.(*GetTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Indexer_GetPair_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPairRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IndexerServer).GetPair(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Indexer_GetPair_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IndexerServer).GetPair(ctx, req.(*GetPairRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Indexer_GetNativeTokenPrice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNativeTokenPriceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IndexerServer).GetNativeTokenPrice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Indexer_GetNativeTokenPrice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IndexerServer).GetNativeTokenPrice(ctx, req.(*GetNativeTokenPriceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Indexer_GetPairPrice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPairPriceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IndexerServer).GetPairPrice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Indexer_GetPairPrice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IndexerServer).GetPairPrice(ctx, req.(*GetPairPriceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Indexer_ServiceDesc is the grpc.ServiceDesc for Indexer service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Indexer_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "base_scan.v1.Indexer",
+	HandlerType: (*IndexerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetToken",
+			Handler:    _Indexer_GetToken_Handler,
+		},
+		{
+			MethodName: "GetPair",
+			Handler:    _Indexer_GetPair_Handler,
+		},
+		{
+			MethodName: "GetNativeTokenPrice",
+			Handler:    _Indexer_GetNativeTokenPrice_Handler,
+		},
+		{
+			MethodName: "GetPairPrice",
+			Handler:    _Indexer_GetPairPrice_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamBlocks",
+			Handler:       _Indexer_StreamBlocks_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "indexer.proto",
+}
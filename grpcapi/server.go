@@ -0,0 +1,353 @@
+package grpcapi
+
+import (
+	"base_scan/cache"
+	"base_scan/config"
+	"base_scan/fanout"
+	"base_scan/grpcapi/pb"
+	"base_scan/log"
+	"base_scan/repository/orm"
+	"base_scan/types"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+	"math/big"
+	"sync/atomic"
+)
+
+// Store is what the server reads from postgres, service.DBService implements it.
+type Store interface {
+	GetToken(address common.Address) (*orm.Token, error)
+	GetPair(address common.Address) (*orm.Pair, error)
+	GetTxsByBlockRange(from, to uint64) ([]*orm.Tx, error)
+	GetPairsByBlockRange(from, to uint64) ([]*orm.Pair, error)
+	GetPoolsByBlockRange(from, to uint64) ([]*orm.Pool, error)
+	GetLastTx(pairAddress common.Address, block uint64) (*orm.Tx, error)
+}
+
+/*
+Server is the grpc service of an indexer, see indexer.proto.
+Lookups read the cache first and postgres when the cache doesn't know. A stream replays the blocks committed before
+it started from postgres, then sends the live blocks it takes from a fanout.Fanout of config.GrpcConf SendQueueSize
+blocks. A stream that can't keep up is ended with codes.ResourceExhausted and the height to reconnect from.
+*/
+type Server struct {
+	pb.UnimplementedIndexerServer
+	conf    *config.GrpcConf
+	cache   cache.Cache
+	store   Store
+	streams *fanout.Fanout
+	// last committed block, stored under the lock of streams
+	height atomic.Uint64
+}
+
+func NewServer(conf *config.GrpcConf, c cache.Cache, store Store) *Server {
+	s := &Server{
+		conf:    conf,
+		cache:   c,
+		store:   store,
+		streams: fanout.New("grpc", conf.SendQueueSize),
+	}
+	s.height.Store(c.GetFinishedBlock())
+	return s
+}
+
+// Publish makes a committed block the last one and sends it to the live streams.
+func (s *Server) Publish(blockInfo *types.BlockInfo) {
+	s.streams.Publish(blockInfo, func() {
+		s.height.Store(blockInfo.Height)
+	})
+}
+
+// Height is the last committed block.
+func (s *Server) Height() uint64 {
+	return s.height.Load()
+}
+
+// add starts a live stream of a peer and returns the last committed block, the stream receives the blocks after it.
+func (s *Server) add(peerAddr string) (*fanout.Subscriber, uint64) {
+	var committed uint64
+	stream, _ := s.streams.Subscribe(peerAddr, func() error {
+		committed = s.height.Load()
+		return nil
+	})
+	return stream, committed
+}
+
+func sendBlock(srv pb.Indexer_StreamBlocksServer, blockInfo *types.BlockInfo, replay bool) error {
+	data, err := json.Marshal(blockInfo)
+	if err != nil {
+		return status.Errorf(codes.Internal, "marshal block %d err: %v", blockInfo.Height, err)
+	}
+	return srv.Send(&pb.Block{
+		Height:    blockInfo.Height,
+		Timestamp: blockInfo.Timestamp,
+		Replay:    replay,
+		BlockInfo: data,
+		// the replayed blocks are rebuilt from postgres, see loadBlocks
+		Partial: replay,
+	})
+}
+
+func (s *Server) StreamBlocks(req *pb.StreamBlocksRequest, srv pb.Indexer_StreamBlocksServer) error {
+	peerAddr := ""
+	if p, ok := peer.FromContext(srv.Context()); ok {
+		peerAddr = p.Addr.String()
+	}
+	stream, committed := s.add(peerAddr)
+	defer s.streams.Unsubscribe(stream)
+
+	next := req.FromHeight
+	if next > 0 && next <= committed {
+		if err := s.replay(srv, next, committed); err != nil {
+			return err
+		}
+		next = committed + 1
+	}
+
+	for {
+		select {
+		case <-srv.Context().Done():
+			return srv.Context().Err()
+		case blockInfo, ok := <-stream.Queue():
+			if !ok {
+				return status.Errorf(codes.ResourceExhausted, "stream too slow, reconnect from height %d", next)
+			}
+			// blocks parsed again after a restart are sent once
+			if blockInfo.Height < next {
+				continue
+			}
+			if err := sendBlock(srv, blockInfo, false); err != nil {
+				return err
+			}
+			next = blockInfo.Height + 1
+		}
+	}
+}
+
+// replay sends the blocks from..to read from postgres, in batches of config.GrpcConf ReplayBatchBlocks blocks.
+func (s *Server) replay(srv pb.Indexer_StreamBlocksServer, from, to uint64) error {
+	for batchFrom := from; batchFrom <= to; batchFrom += s.conf.ReplayBatchBlocks {
+		batchTo := batchFrom + s.conf.ReplayBatchBlocks - 1
+		if batchTo > to {
+			batchTo = to
+		}
+
+		blockInfos, err := s.loadBlocks(batchFrom, batchTo)
+		if err != nil {
+			log.Logger.Error("load blocks to replay err", zap.Uint64("from", batchFrom), zap.Uint64("to", batchTo), zap.Error(err))
+			return status.Errorf(codes.Unavailable, "load blocks %d..%d err: %v", batchFrom, batchTo, err)
+		}
+		for _, blockInfo := range blockInfos {
+			if err = sendBlock(srv, blockInfo, true); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+/*
+loadBlocks rebuilds the blocks from..to with trades, new pairs or new pools from postgres, ordered by height.
+postgres doesn't keep the other parts of a BlockInfo, they are left empty, see Block in indexer.proto.
+*/
+func (s *Server) loadBlocks(from, to uint64) ([]*types.BlockInfo, error) {
+	txs, err := s.store.GetTxsByBlockRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+	pairs, err := s.store.GetPairsByBlockRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+	pools, err := s.store.GetPoolsByBlockRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	height2BlockInfo := make(map[uint64]*types.BlockInfo)
+	blockInfoOf := func(height uint64, timestamp int64) *types.BlockInfo {
+		blockInfo, ok := height2BlockInfo[height]
+		if !ok {
+			blockInfo = &types.BlockInfo{
+				Height:               height,
+				Timestamp:            uint64(timestamp),
+				Txs:                  make([]*orm.Tx, 0),
+				NewTokens:            make([]*orm.Token, 0),
+				NewPairs:             make([]*orm.Pair, 0),
+				NewPools:             make([]*orm.Pool, 0),
+				Launches:             make([]*orm.Launch, 0),
+				PairFeeUpdates:       make([]*types.PairFeeUpdate, 0),
+				PoolFees:             make([]*orm.PoolFee, 0),
+				PoolUpdates:          make([]*types.PoolUpdate, 0),
+				PoolUpdateParameters: make([]*types.PoolUpdateParameter, 0),
+			}
+			if price, found := s.cache.GetPrice(new(big.Int).SetUint64(height)); found {
+				blockInfo.NativeTokenPrice = price.String()
+			}
+			height2BlockInfo[height] = blockInfo
+		}
+		return blockInfo
+	}
+	for _, tx := range txs {
+		blockInfo := blockInfoOf(tx.Block, tx.BlockAt.Unix())
+		blockInfo.Txs = append(blockInfo.Txs, tx)
+	}
+	for _, pair := range pairs {
+		blockInfo := blockInfoOf(pair.Block, pair.BlockAt.Unix())
+		blockInfo.NewPairs = append(blockInfo.NewPairs, pair)
+	}
+	for _, pool := range pools {
+		blockInfo := blockInfoOf(pool.Block, pool.BlockAt.Unix())
+		blockInfo.NewPools = append(blockInfo.NewPools, pool)
+	}
+
+	blockInfos := make([]*types.BlockInfo, 0, len(height2BlockInfo))
+	for height := from; height <= to; height++ {
+		if blockInfo, ok := height2BlockInfo[height]; ok {
+			blockInfo.Routes = types.NewRoutes(blockInfo.Txs)
+			blockInfos = append(blockInfos, blockInfo)
+		}
+	}
+	return blockInfos, nil
+}
+
+func parseAddress(name, address string) (common.Address, error) {
+	if !common.IsHexAddress(address) {
+		return common.Address{}, status.Errorf(codes.InvalidArgument, "%s must be an address", name)
+	}
+	return common.HexToAddress(address), nil
+}
+
+func storeError(what string, err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return status.Errorf(codes.NotFound, "%s not found", what)
+	}
+	return status.Errorf(codes.Unavailable, "get %s err: %v", what, err)
+}
+
+func (s *Server) GetToken(ctx context.Context, req *pb.GetTokenRequest) (*pb.Token, error) {
+	address, err := parseAddress("address", req.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	if token, ok := s.cache.GetToken(address); ok {
+		return tokenToPb(token.GetOrmToken(0)), nil
+	}
+
+	ormToken, err := s.store.GetToken(address)
+	if err != nil {
+		return nil, storeError("token", err)
+	}
+	return tokenToPb(ormToken), nil
+}
+
+func (s *Server) GetPair(ctx context.Context, req *pb.GetPairRequest) (*pb.Pair, error) {
+	address, err := parseAddress("address", req.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	if pair, ok := s.cache.GetPair(address); ok && pair.Token0Core != nil && pair.Token1Core != nil {
+		return pairToPb(pair), nil
+	}
+
+	ormPair, err := s.store.GetPair(address)
+	if err != nil {
+		return nil, storeError("pair", err)
+	}
+	token0, err := s.GetToken(ctx, &pb.GetTokenRequest{Address: ormPair.Token0})
+	if err != nil {
+		return nil, err
+	}
+	token1, err := s.GetToken(ctx, &pb.GetTokenRequest{Address: ormPair.Token1})
+	if err != nil {
+		return nil, err
+	}
+	return ormPairToPb(ormPair, token0, token1), nil
+}
+
+func (s *Server) heightOrCommitted(height uint64) uint64 {
+	if height == 0 {
+		return s.Height()
+	}
+	return height
+}
+
+func (s *Server) GetNativeTokenPrice(ctx context.Context, req *pb.GetNativeTokenPriceRequest) (*pb.Price, error) {
+	height := s.heightOrCommitted(req.Height)
+	price, ok := s.cache.GetPrice(new(big.Int).SetUint64(height))
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "native token price of height %d not found", height)
+	}
+	return &pb.Price{PriceUsd: price.String(), Height: height}, nil
+}
+
+func (s *Server) GetPairPrice(ctx context.Context, req *pb.GetPairPriceRequest) (*pb.Price, error) {
+	pairAddress, err := parseAddress("pair", req.Pair)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.store.GetLastTx(pairAddress, s.heightOrCommitted(req.Height))
+	if err != nil {
+		return nil, storeError("trade of pair", err)
+	}
+	return &pb.Price{PriceUsd: tx.PriceUsd.String(), Height: tx.Block}, nil
+}
+
+func tokenToPb(token *orm.Token) *pb.Token {
+	return &pb.Token{
+		Address:     token.Address,
+		Name:        token.Name,
+		Symbol:      token.Symbol,
+		Decimals:    int32(token.Decimal),
+		TotalSupply: token.TotalSupply,
+		Block:       token.Block,
+		Program:     token.Program,
+	}
+}
+
+func pairToPb(pair *types.Pair) *pb.Pair {
+	return &pb.Pair{
+		Address:        pair.Address.String(),
+		Token0:         pair.Token0Core.Address.String(),
+		Token1:         pair.Token1Core.Address.String(),
+		Token0Symbol:   pair.Token0Core.Symbol,
+		Token1Symbol:   pair.Token1Core.Symbol,
+		Token0Decimals: int32(pair.Token0Core.Decimals),
+		Token1Decimals: int32(pair.Token1Core.Decimals),
+		Program:        types.GetProtocolName(pair.ProtocolId),
+		Block:          pair.Block,
+		Stable:         pair.Stable,
+		Fee:            pair.Fee,
+		TickSpacing:    pair.TickSpacing,
+		Version:        int32(pair.Version),
+		Filtered:       pair.Filtered,
+	}
+}
+
+func ormPairToPb(pair *orm.Pair, token0, token1 *pb.Token) *pb.Pair {
+	return &pb.Pair{
+		Address:        pair.Address,
+		Token0:         pair.Token0,
+		Token1:         pair.Token1,
+		Token0Symbol:   token0.Symbol,
+		Token1Symbol:   token1.Symbol,
+		Token0Decimals: token0.Decimals,
+		Token1Decimals: token1.Decimals,
+		Program:        pair.Program,
+		Block:          pair.Block,
+		Stable:         pair.Stable,
+		Fee:            pair.Fee,
+		TickSpacing:    pair.TickSpacing,
+		Version:        int32(pair.Version),
+	}
+}
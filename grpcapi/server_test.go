@@ -0,0 +1,180 @@
+package grpcapi
+
+import (
+	"base_scan/cache"
+	"base_scan/config"
+	"base_scan/grpcapi/pb"
+	"base_scan/repository/orm"
+	"base_scan/types"
+	"context"
+	"encoding/json"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"gorm.io/gorm"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+)
+
+var (
+	testToken = common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	testPair  = common.HexToAddress("0x00000000000000000000000000000000000000b1")
+)
+
+// testStore has one trade in every block from 1 to 10 and a pair created in block 2.
+type testStore struct{}
+
+func (s *testStore) GetToken(address common.Address) (*orm.Token, error) {
+	switch address {
+	case testToken:
+		return &orm.Token{Address: testToken.String(), Symbol: "T", Decimal: 18}, nil
+	case types.WETHAddress:
+		return &orm.Token{Address: types.WETH, Symbol: "WETH", Decimal: 18}, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (s *testStore) GetPair(address common.Address) (*orm.Pair, error) {
+	if address != testPair {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &orm.Pair{Address: testPair.String(), Token0: testToken.String(), Token1: types.WETH, Program: "UniswapV2", Block: 2}, nil
+}
+
+func (s *testStore) GetTxsByBlockRange(from, to uint64) ([]*orm.Tx, error) {
+	txs := make([]*orm.Tx, 0)
+	for block := from; block <= to && block <= 10; block++ {
+		txs = append(txs, &orm.Tx{TxHash: "0x1", Event: types.Buy, Block: block, BlockAt: time.Unix(int64(block), 0), PairAddress: testPair.String()})
+	}
+	return txs, nil
+}
+
+func (s *testStore) GetPairsByBlockRange(from, to uint64) ([]*orm.Pair, error) {
+	if from <= 2 && 2 <= to {
+		pair, _ := s.GetPair(testPair)
+		return []*orm.Pair{pair}, nil
+	}
+	return []*orm.Pair{}, nil
+}
+
+func (s *testStore) GetPoolsByBlockRange(from, to uint64) ([]*orm.Pool, error) {
+	return []*orm.Pool{}, nil
+}
+
+func (s *testStore) GetLastTx(pairAddress common.Address, block uint64) (*orm.Tx, error) {
+	if pairAddress != testPair {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &orm.Tx{Block: block, PriceUsd: decimal.NewFromFloat(1.5)}, nil
+}
+
+func newTestServer(t *testing.T) (*Server, pb.IndexerClient) {
+	c := cache.NewMockCache()
+	c.SetFinishedBlock(10)
+	c.SetPrice(big.NewInt(10), decimal.NewFromInt(2000))
+	s := NewServer(&config.GrpcConf{SendQueueSize: 4, ReplayBatchBlocks: 3}, c, &testStore{})
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	pb.RegisterIndexerServer(server, s)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return s, pb.NewIndexerClient(conn)
+}
+
+func TestStreamBlocksReplayThenLive(t *testing.T) {
+	s, client := newTestServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.StreamBlocks(ctx, &pb.StreamBlocksRequest{FromHeight: 2})
+	require.NoError(t, err)
+
+	for height := uint64(2); height <= 10; height++ {
+		block, err := stream.Recv()
+		require.NoError(t, err)
+		require.Equal(t, height, block.Height)
+		require.True(t, block.Replay)
+		require.True(t, block.Partial)
+
+		blockInfo := &types.BlockInfo{}
+		require.NoError(t, json.Unmarshal(block.BlockInfo, blockInfo))
+		require.Len(t, blockInfo.Txs, 1)
+		if height == 2 {
+			require.Len(t, blockInfo.NewPairs, 1)
+		}
+		if height == 10 {
+			require.Equal(t, "2000", blockInfo.NativeTokenPrice)
+		}
+	}
+
+	// a block parsed again after a restart is skipped
+	s.Publish(&types.BlockInfo{Height: 10})
+	s.Publish(&types.BlockInfo{Height: 11})
+	block, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, uint64(11), block.Height)
+	require.False(t, block.Replay)
+	require.False(t, block.Partial)
+}
+
+func TestStreamBlocksSlowClient(t *testing.T) {
+	s, client := newTestServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.StreamBlocks(ctx, &pb.StreamBlocksRequest{})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return s.streams.Len() == 1 }, time.Second, 10*time.Millisecond)
+
+	// the queue holds 4 blocks, the 5th can only be queued when the stream already took one
+	for height := uint64(11); height < 100; height++ {
+		s.Publish(&types.BlockInfo{Height: height})
+	}
+	for {
+		_, err = stream.Recv()
+		if err != nil {
+			break
+		}
+	}
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestLookups(t *testing.T) {
+	_, client := newTestServer(t)
+	ctx := context.Background()
+
+	pair, err := client.GetPair(ctx, &pb.GetPairRequest{Address: testPair.String()})
+	require.NoError(t, err)
+	require.Equal(t, "T", pair.Token0Symbol)
+	require.Equal(t, "WETH", pair.Token1Symbol)
+	require.Equal(t, int32(18), pair.Token0Decimals)
+
+	_, err = client.GetToken(ctx, &pb.GetTokenRequest{Address: "0x01"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.GetToken(ctx, &pb.GetTokenRequest{Address: common.HexToAddress("0x02").String()})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	price, err := client.GetNativeTokenPrice(ctx, &pb.GetNativeTokenPriceRequest{})
+	require.NoError(t, err)
+	require.Equal(t, "2000", price.PriceUsd)
+	require.Equal(t, uint64(10), price.Height)
+
+	price, err = client.GetPairPrice(ctx, &pb.GetPairPriceRequest{Pair: testPair.String(), Height: 5})
+	require.NoError(t, err)
+	require.Equal(t, "1.5", price.PriceUsd)
+	require.Equal(t, uint64(5), price.Height)
+}
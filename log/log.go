@@ -21,15 +21,15 @@ func InitLoggerForTest() {
 	return
 }
 
-func InitLogger() {
-	if !config.G.Log.Async {
+func InitLogger(conf *config.LogConf) {
+	if !conf.Async {
 		Logger, _ = zap.NewDevelopment()
 		return
 	}
 
 	buffer := &zapcore.BufferedWriteSyncer{
-		Size:          config.G.Log.AsyncBufferSizeByByte,
-		FlushInterval: time.Second * time.Duration(config.G.Log.AsyncFlushIntervalBySecond),
+		Size:          conf.AsyncBufferSizeByByte,
+		FlushInterval: time.Second * time.Duration(conf.AsyncFlushIntervalBySecond),
 		WS:            os.Stdout,
 	}
 	writeSyncer := zapcore.AddSync(buffer)
@@ -1,60 +1,31 @@
 package main
 
 import (
-	"base_scan/block_getter"
-	"base_scan/cache"
+	"base_scan/chain"
 	"base_scan/config"
 	"base_scan/log"
-	"base_scan/parser"
-	"base_scan/repository"
-	"base_scan/sequencer"
-	"base_scan/service"
-	"base_scan/types"
+	"encoding/json"
 	"flag"
 	"fmt"
-	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/go-redis/redis/v8"
 	"go.uber.org/zap"
-	"gorm.io/driver/postgres"
-	"gorm.io/gorm"
 	"os"
 	"os/signal"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 )
 
-func createDBService() service.DBService {
-	var (
-		txDb            *gorm.DB
-		txDbErr         error
-		tokenPairDb     *gorm.DB
-		tokenPairDbErr  error
-		tokenRepository *repository.TokenRepository
-		pairRepository  *repository.PairRepository
-		txRepository    *repository.TxRepository
-	)
-
-	if config.G.TxDatabase.Enabled {
-		txDb, txDbErr = gorm.Open(postgres.Open(config.G.TxDatabase.DBDatasource.GetPostgresDsn()))
-		if txDbErr != nil {
-			log.Logger.Fatal("failed to connect to tx db", zap.Error(txDbErr))
-		}
-
-		txRepository = repository.NewTxRepository(txDb)
-	}
+// configFiles collects repeated -c flags, one pipeline is run per config file.
+type configFiles []string
 
-	if config.G.TokenPairDatabase.Enabled {
-		tokenPairDb, tokenPairDbErr = gorm.Open(postgres.Open(config.G.TokenPairDatabase.DBDatasource.GetPostgresDsn()))
-		if tokenPairDbErr != nil {
-			log.Logger.Fatal("failed to connect to token_pair db", zap.Error(tokenPairDbErr))
-		}
-
-		tokenRepository = repository.NewTokenRepository(tokenPairDb)
-		pairRepository = repository.NewPairRepository(tokenPairDb)
-	}
+func (f *configFiles) String() string {
+	return strings.Join(*f, ",")
+}
 
-	return service.NewDBService(tokenRepository, pairRepository, txRepository)
+func (f *configFiles) Set(value string) error {
+	*f = append(*f, value)
+	return nil
 }
 
 func main() {
@@ -62,8 +33,10 @@ func main() {
 
 	var showVersion bool
 	flag.BoolVar(&showVersion, "v", false, "show version information")
-	var configFile string
-	flag.StringVar(&configFile, "c", "config.json", "config file")
+	var files configFiles
+	flag.Var(&files, "c", "config file, json, yaml or toml, repeat to index several chains (default config.json)")
+	var printConfig bool
+	flag.BoolVar(&printConfig, "print-config", false, "print the loaded configs with secrets redacted and exit")
 	flag.Parse()
 
 	if showVersion {
@@ -71,93 +44,70 @@ func main() {
 		os.Exit(0)
 	}
 
-	log.Logger.Info(GetVersion().String())
-	log.Logger.Info("config", zap.String("file path", configFile))
-	loadConfigErr := config.LoadConfigFile(configFile)
-	if loadConfigErr != nil {
-		log.Logger.Fatal("load config file err", zap.Error(loadConfigErr))
+	if len(files) == 0 {
+		files = configFiles{"config.json"}
 	}
 
-	ethClient, dialEthErr := ethclient.Dial(config.G.Chain.Endpoint)
-	if dialEthErr != nil {
-		log.Logger.Fatal("Failed to connect to the chain(http): %v", zap.Error(dialEthErr))
-	}
+	log.Logger.Info(GetVersion().String())
 
-	ethClientArchive, dialEthErrArchive := ethclient.Dial(config.G.Chain.EndpointArchive)
-	if dialEthErrArchive != nil {
-		log.Logger.Fatal("Failed to connect to the chain archive(http): %v", zap.Error(dialEthErrArchive))
-	}
+	confs := make([]*config.Config, 0, len(files))
+	profiles := make([]*chain.Profile, 0, len(files))
+	for _, configFile := range files {
+		log.Logger.Info("config", zap.String("file path", configFile))
+		conf, loadConfigErr := config.LoadConfigFile(configFile)
+		if loadConfigErr != nil {
+			log.Logger.Fatal("load config file err", zap.String("file path", configFile), zap.Error(loadConfigErr))
+		}
 
-	wsEthClient, dialEthWsErr := ethclient.Dial(config.G.Chain.WsEndpoint)
-	if dialEthWsErr != nil {
-		log.Logger.Fatal("Failed to connect to the chain(ws): %v", zap.Error(dialEthWsErr))
+		if printConfig {
+			redactedConfig, redactErr := conf.Redacted()
+			if redactErr != nil {
+				log.Logger.Fatal("redact config err", zap.Error(redactErr))
+			}
+			bytes, _ := json.MarshalIndent(redactedConfig, "", "    ")
+			fmt.Println(string(bytes))
+			continue
+		}
+
+		profile, profileErr := chain.NewProfile(conf.Chain)
+		if profileErr != nil {
+			log.Logger.Fatal("load chain profile err", zap.String("file path", configFile), zap.Error(profileErr))
+		}
+
+		confs = append(confs, conf)
+		profiles = append(profiles, profile)
 	}
 
-	redisCli := redis.NewClient(&redis.Options{
-		Addr:     config.G.Redis.Addr,
-		Username: config.G.Redis.Username,
-		Password: config.G.Redis.Password,
-	})
-	cache := cache.NewTwoTierCache(redisCli)
-
-	contractCaller := service.NewContractCaller(ethClient, config.G.ContractCaller.Retry.GetRetryParams())
-
-	pairService := service.NewPairService(cache, contractCaller)
-	contractCallerArchive := service.NewContractCaller(ethClientArchive, config.G.ContractCaller.Retry.GetRetryParams())
-	priceService := service.NewPriceService(cache, contractCallerArchive, ethClient, config.G.PriceService.PoolSize)
-
-	blockSequencerForBlockHandler := sequencer.NewBlockSequencer()
-
-	topicRouter := parser.NewTopicRouter()
-	kafkaSender := service.NewKafkaSender(config.G.Kafka)
-
-	blockParser := parser.NewBlockParser(
-		cache,
-		blockSequencerForBlockHandler,
-		priceService,
-		pairService,
-		topicRouter,
-		kafkaSender,
-		createDBService(),
-	)
-	wg := &sync.WaitGroup{}
-	wg.Add(1)
-	blockParser.Start(wg)
-
-	blockSequencerForBlockGetter := sequencer.NewBlockSequencer()
-	blockGetter := block_getter.NewBlockGetter(ethClient, wsEthClient, cache, blockSequencerForBlockGetter, config.G.BlockGetter.Retry.GetRetryParams())
-	startBlockNumber := blockGetter.GetStartBlockNumber(config.G.BlockGetter.StartBlockNumber)
-	if startBlockNumber == 0 {
-		log.Logger.Fatal("start block number is zero")
+	if printConfig {
+		os.Exit(0)
 	}
 
-	blockSequencerForBlockGetter.Init(startBlockNumber)
-	blockSequencerForBlockHandler.Init(startBlockNumber)
+	if err := checkTargets(confs); err != nil {
+		log.Logger.Fatal("check targets err", zap.Error(err))
+	}
 
-	priceService.Start(startBlockNumber)
-	blockGetter.Start()
-	blockGetter.StartDispatch(startBlockNumber)
+	pipelines := make([]*pipeline, 0, len(confs))
+	for i, conf := range confs {
+		pipelines = append(pipelines, newPipeline(conf, profiles[i]))
+	}
 
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 	go func() {
 		sig := <-sigChan
 		log.Logger.Info("receive signal", zap.String("signal", sig.String()))
-		blockGetter.Stop()
+		for _, p := range pipelines {
+			p.stop()
+		}
 	}()
 
-	var blockCtx *types.ParseBlockContext
-	for {
-		blockCtx = blockGetter.Next()
-		if blockCtx == nil {
-			log.Logger.Info("no more block to parse")
-			blockParser.Stop()
-			break
-		}
-		blockParser.ParseBlockAsync(blockCtx)
+	wg := &sync.WaitGroup{}
+	for _, p := range pipelines {
+		wg.Add(1)
+		go func(p *pipeline) {
+			defer wg.Done()
+			p.run()
+		}(p)
 	}
-
-	log.Logger.Info("wait all block commited")
 	wg.Wait()
-	log.Logger.Info("all block commited")
 }
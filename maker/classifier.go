@@ -0,0 +1,248 @@
+package maker
+
+import (
+	"base_scan/cache"
+	"base_scan/config"
+	"base_scan/log"
+	"base_scan/repository/orm"
+	"base_scan/types"
+	"bytes"
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+	"math/big"
+	"sort"
+)
+
+var (
+	// EIP-7702 delegation designator, followed by the address of the delegate
+	delegationPrefix = []byte{0xef, 0x01, 0x00}
+
+	// runtime code prefixes of smart wallet proxies
+	SmartWalletCodePrefixes = [][]byte{
+		// Safe proxy
+		common.FromHex("0x608060405273ffffffffffffffffffffffffffffffffffffffff600054167fa619486e"),
+		// Coinbase Smart Wallet, an ERC-1967 proxy cloned by LibClone
+		common.FromHex("0x363d3d373d3d363d7f360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc"),
+	}
+)
+
+type CodeReader interface {
+	CodeAt(address common.Address, blockNumber *big.Int) ([]byte, error)
+}
+
+/*
+Classifier keeps a profile of every maker in the cache and tags trades with the class of their maker.
+The kind comes from the code at the maker address at the block of its trades, the bot class from its recent activity.
+*/
+type Classifier struct {
+	cache      cache.MakerCache
+	codeReader CodeReader
+	conf       *config.MakerConf
+	chainId    uint64
+}
+
+func NewClassifier(cache cache.MakerCache, codeReader CodeReader, conf *config.MakerConf, chainId uint64) *Classifier {
+	return &Classifier{
+		cache:      cache,
+		codeReader: codeReader,
+		conf:       conf,
+		chainId:    chainId,
+	}
+}
+
+/*
+ReadCodes reads the code at block of the makers whose kind must be checked, for Classify.
+It is called when the block is parsed, so that the node is not called when blocks are committed.
+The profiles of the blocks not committed yet are not known, a maker first seen in them is read again.
+*/
+func (c *Classifier) ReadCodes(block uint64, makers []common.Address) map[common.Address][]byte {
+	codes := make(map[common.Address][]byte)
+	for _, address := range makers {
+		if _, ok := codes[address]; ok {
+			continue
+		}
+		maker, ok := c.cache.GetMaker(address)
+		if ok && !c.kindExpired(maker, block) {
+			continue
+		}
+		code, err := c.codeReader.CodeAt(address, new(big.Int).SetUint64(block))
+		if err != nil {
+			log.Logger.Warn("get maker code err", zap.String("maker", address.String()), zap.Error(err))
+			continue
+		}
+		codes[address] = code
+	}
+	return codes
+}
+
+/*
+Classify counts the trades of one block into the profiles of their makers, sets orm.Tx.MakerClass
+and returns the updated profiles ordered by address.
+codes are the codes read by ReadCodes, the code of a maker missing from them is read from the node.
+A block that is already counted for a maker, when blocks are parsed again after a restart, is not counted twice.
+*/
+func (c *Classifier) Classify(block uint64, txs []*orm.Tx, codes map[common.Address][]byte) []*orm.Maker {
+	maker2Txs := make(map[string][]*orm.Tx)
+	for _, tx := range txs {
+		if tx.Maker == "" {
+			continue
+		}
+		maker2Txs[tx.Maker] = append(maker2Txs[tx.Maker], tx)
+	}
+
+	addresses := make([]string, 0, len(maker2Txs))
+	for address := range maker2Txs {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+
+	makers := make([]*orm.Maker, 0, len(addresses))
+	for _, address := range addresses {
+		makerTxs := maker2Txs[address]
+		maker := c.update(common.HexToAddress(address), block, makerTxs, codes)
+		for _, tx := range makerTxs {
+			tx.MakerClass = maker.Class
+		}
+		makers = append(makers, maker.GetOrmMaker(c.chainId))
+	}
+	return makers
+}
+
+func (c *Classifier) update(address common.Address, block uint64, txs []*orm.Tx, codes map[common.Address][]byte) *types.Maker {
+	maker, ok := c.cache.GetMaker(address)
+	if !ok {
+		maker = &types.Maker{Address: address, FirstBlock: block}
+	}
+
+	if !ok || block > maker.LastBlock {
+		rollWindow(maker, block, c.conf.WindowBlocks)
+		activity := c.blockActivity(maker, txs)
+		maker.Total.Add(activity)
+		maker.Window.Add(activity)
+		maker.LastBlock = block
+	}
+
+	if c.kindExpired(maker, block) {
+		code, err := c.codeAt(address, block, codes)
+		if err != nil {
+			log.Logger.Warn("get maker code err", zap.String("maker", address.String()), zap.Error(err))
+		} else {
+			maker.Kind, maker.DelegateAddress = KindOfCode(code)
+			maker.KindCheckedBlock = block
+		}
+	}
+
+	maker.Class = c.classOf(maker)
+	c.cache.SetMaker(maker)
+	return maker
+}
+
+// codeAt returns the code read by ReadCodes, or reads it when it was not.
+func (c *Classifier) codeAt(address common.Address, block uint64, codes map[common.Address][]byte) ([]byte, error) {
+	if code, ok := codes[address]; ok {
+		return code, nil
+	}
+	return c.codeReader.CodeAt(address, new(big.Int).SetUint64(block))
+}
+
+// blockActivity counts the swaps of a maker in one block, and adds them to its buys, sells and volume.
+func (c *Classifier) blockActivity(maker *types.Maker, txs []*orm.Tx) *types.MakerActivity {
+	activity := &types.MakerActivity{}
+	pool2Events := make(map[string]map[string]struct{})
+	for _, tx := range txs {
+		switch tx.Event {
+		case types.Buy:
+			maker.Buys++
+		case types.Sell:
+			maker.Sells++
+		default:
+			continue
+		}
+
+		activity.Trades++
+		maker.VolumeUsd = maker.VolumeUsd.Add(tx.AmountUsd)
+		if _, ok := pool2Events[tx.PairAddress]; !ok {
+			pool2Events[tx.PairAddress] = make(map[string]struct{})
+		}
+		pool2Events[tx.PairAddress][tx.Event] = struct{}{}
+	}
+
+	if activity.Trades == 0 {
+		return activity
+	}
+
+	activity.ActiveBlocks = 1
+	if activity.Trades >= uint64(c.conf.BotTradesPerBlock) {
+		activity.BusyBlocks = 1
+	}
+	for _, events := range pool2Events {
+		if len(events) == 2 {
+			activity.RoundTrips = 1
+			break
+		}
+	}
+	return activity
+}
+
+// kindExpired reports if the code of a maker must be read, an EOA can delegate or change its delegate at any time.
+func (c *Classifier) kindExpired(maker *types.Maker, block uint64) bool {
+	switch maker.Kind {
+	case "":
+		return true
+	case types.MakerKindEOA, types.MakerKindDelegated:
+		return c.conf.KindRefreshBlocks > 0 && block >= maker.KindCheckedBlock+c.conf.KindRefreshBlocks
+	default:
+		return false
+	}
+}
+
+func (c *Classifier) classOf(maker *types.Maker) string {
+	recent := maker.Recent()
+	if recent.BusyBlocks >= uint64(c.conf.BotBusyBlocks) || recent.RoundTrips >= uint64(c.conf.BotRoundTrips) {
+		return types.MakerClassBot
+	}
+
+	switch maker.Kind {
+	case types.MakerKindSmartWallet:
+		return types.MakerClassSmartWallet
+	case types.MakerKindDelegated:
+		return types.MakerClassDelegated
+	case types.MakerKindContract:
+		return types.MakerClassContract
+	default:
+		return types.MakerClassTrader
+	}
+}
+
+// rollWindow moves the stats window of a maker to the one of block, windows are aligned to multiples of windowBlocks.
+func rollWindow(maker *types.Maker, block, windowBlocks uint64) {
+	start := block - block%windowBlocks
+	switch {
+	case start == maker.WindowStart:
+		return
+	case start == maker.WindowStart+windowBlocks:
+		maker.PrevWindow = maker.Window
+	default:
+		maker.PrevWindow = types.MakerActivity{}
+	}
+	maker.Window = types.MakerActivity{}
+	maker.WindowStart = start
+}
+
+// KindOfCode returns the maker kind of the code at an address, and the delegate of a 7702 delegated EOA.
+func KindOfCode(code []byte) (string, string) {
+	if len(code) == 0 {
+		return types.MakerKindEOA, ""
+	}
+
+	if len(code) == len(delegationPrefix)+common.AddressLength && bytes.HasPrefix(code, delegationPrefix) {
+		return types.MakerKindDelegated, common.BytesToAddress(code[len(delegationPrefix):]).String()
+	}
+
+	for _, prefix := range SmartWalletCodePrefixes {
+		if bytes.HasPrefix(code, prefix) {
+			return types.MakerKindSmartWallet, ""
+		}
+	}
+	return types.MakerKindContract, ""
+}
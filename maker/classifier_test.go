@@ -0,0 +1,149 @@
+package maker
+
+import (
+	"base_scan/cache"
+	"base_scan/config"
+	"base_scan/repository/orm"
+	"base_scan/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+)
+
+var (
+	bot      = common.HexToAddress("0x00000000000000000000000000000000000000b0")
+	trader   = common.HexToAddress("0x00000000000000000000000000000000000000c0")
+	delegate = common.HexToAddress("0x63c0c19a282a1B52b07dD5a65b58948A07DAE32B")
+)
+
+type codeReader struct {
+	codes  map[common.Address][]byte
+	reads  int
+	blocks []uint64
+}
+
+func (r *codeReader) CodeAt(address common.Address, blockNumber *big.Int) ([]byte, error) {
+	r.reads++
+	r.blocks = append(r.blocks, blockNumber.Uint64())
+	return r.codes[address], nil
+}
+
+func newTestClassifier(codes map[common.Address][]byte) (*Classifier, *codeReader) {
+	conf := config.Default().Maker
+	conf.WindowBlocks = 100
+	conf.BotTradesPerBlock = 2
+	conf.BotBusyBlocks = 2
+	conf.BotRoundTrips = 2
+	conf.KindRefreshBlocks = 50
+
+	reader := &codeReader{codes: codes}
+	return NewClassifier(cache.NewMockCache(), reader, conf, 8453), reader
+}
+
+func trade(maker common.Address, pool, event string) *orm.Tx {
+	return &orm.Tx{Maker: maker.String(), PairAddress: pool, Event: event, AmountUsd: decimal.NewFromInt(10)}
+}
+
+func TestKindOfCode(t *testing.T) {
+	kind, delegateAddress := KindOfCode(nil)
+	require.Equal(t, types.MakerKindEOA, kind)
+	require.Empty(t, delegateAddress)
+
+	kind, delegateAddress = KindOfCode(append([]byte{0xef, 0x01, 0x00}, delegate.Bytes()...))
+	require.Equal(t, types.MakerKindDelegated, kind)
+	require.Equal(t, delegate.String(), delegateAddress)
+
+	kind, _ = KindOfCode(append(SmartWalletCodePrefixes[0], 0x00, 0x01))
+	require.Equal(t, types.MakerKindSmartWallet, kind)
+
+	kind, _ = KindOfCode(common.FromHex("0x6080604052"))
+	require.Equal(t, types.MakerKindContract, kind)
+}
+
+func TestClassifyBot(t *testing.T) {
+	c, _ := newTestClassifier(nil)
+
+	buy, sell := trade(bot, "0xpool", types.Buy), trade(bot, "0xpool", types.Sell)
+	other := trade(trader, "0xpool", types.Buy)
+	makers := c.Classify(1000, []*orm.Tx{buy, sell, other}, nil)
+	require.Len(t, makers, 2)
+	require.Equal(t, bot.String(), makers[0].Address)
+	require.Equal(t, 8453, makers[0].ChainId)
+	require.Equal(t, uint64(2), makers[0].Trades)
+	require.Equal(t, uint64(1), makers[0].BusyBlocks)
+	require.Equal(t, uint64(1), makers[0].RoundTrips)
+	require.True(t, decimal.NewFromInt(20).Equal(makers[0].VolumeUsd))
+	require.Equal(t, types.MakerClassTrader, buy.MakerClass)
+	require.Equal(t, types.MakerClassTrader, other.MakerClass)
+
+	buy, sell = trade(bot, "0xpool", types.Buy), trade(bot, "0xpool", types.Sell)
+	c.Classify(1001, []*orm.Tx{buy, sell}, nil)
+	require.Equal(t, types.MakerClassBot, buy.MakerClass)
+	require.Equal(t, types.MakerClassBot, sell.MakerClass)
+}
+
+func TestClassifyBlockCountedOnce(t *testing.T) {
+	c, _ := newTestClassifier(nil)
+
+	c.Classify(1000, []*orm.Tx{trade(trader, "0xpool", types.Buy)}, nil)
+	makers := c.Classify(1000, []*orm.Tx{trade(trader, "0xpool", types.Buy)}, nil)
+	require.Equal(t, uint64(1), makers[0].Trades)
+	require.Equal(t, uint64(1000), makers[0].FirstBlock)
+}
+
+func TestClassifyWindow(t *testing.T) {
+	c, _ := newTestClassifier(nil)
+
+	c.Classify(1050, []*orm.Tx{trade(bot, "0xpool", types.Buy), trade(bot, "0xpool", types.Buy)}, nil)
+	// the previous window still counts
+	makers := c.Classify(1150, []*orm.Tx{trade(bot, "0xpool", types.Buy), trade(bot, "0xpool", types.Buy)}, nil)
+	require.Equal(t, types.MakerClassBot, makers[0].Class)
+	require.Equal(t, uint64(4), makers[0].RecentTrades)
+
+	// two windows later the activity is forgotten, but not the totals
+	makers = c.Classify(1350, []*orm.Tx{trade(bot, "0xpool", types.Buy)}, nil)
+	require.Equal(t, types.MakerClassTrader, makers[0].Class)
+	require.Equal(t, uint64(1), makers[0].RecentTrades)
+	require.Equal(t, uint64(5), makers[0].Trades)
+}
+
+func TestClassifyKind(t *testing.T) {
+	c, reader := newTestClassifier(map[common.Address][]byte{})
+
+	tx := trade(trader, "0xpool", types.Buy)
+	makers := c.Classify(1000, []*orm.Tx{tx}, nil)
+	require.Equal(t, types.MakerKindEOA, makers[0].Kind)
+	require.Equal(t, 1, reader.reads)
+
+	// the EOA delegates, which is noticed after KindRefreshBlocks
+	reader.codes[trader] = append([]byte{0xef, 0x01, 0x00}, delegate.Bytes()...)
+	c.Classify(1010, []*orm.Tx{trade(trader, "0xpool", types.Buy)}, nil)
+	require.Equal(t, 1, reader.reads)
+
+	tx = trade(trader, "0xpool", types.Buy)
+	makers = c.Classify(1050, []*orm.Tx{tx}, nil)
+	require.Equal(t, 2, reader.reads)
+	require.Equal(t, types.MakerKindDelegated, makers[0].Kind)
+	require.Equal(t, delegate.String(), makers[0].DelegateAddress)
+	require.Equal(t, types.MakerClassDelegated, tx.MakerClass)
+}
+
+func TestClassifyReadCodes(t *testing.T) {
+	c, reader := newTestClassifier(map[common.Address][]byte{bot: common.FromHex("0x6080604052")})
+
+	// read at the block of the trades when the block is parsed
+	codes := c.ReadCodes(1000, []common.Address{bot, trader, bot})
+	require.Len(t, codes, 2)
+	require.Equal(t, []uint64{1000, 1000}, reader.blocks)
+
+	makers := c.Classify(1000, []*orm.Tx{trade(bot, "0xpool", types.Buy), trade(trader, "0xpool", types.Buy)}, codes)
+	require.Equal(t, 2, reader.reads)
+	require.Equal(t, types.MakerKindContract, makers[0].Kind)
+	require.Equal(t, types.MakerKindEOA, makers[1].Kind)
+
+	// the kind of the contract is known, the one of the EOA is not expired yet
+	require.Empty(t, c.ReadCodes(1010, []common.Address{bot, trader}))
+	require.Equal(t, 2, reader.reads)
+}
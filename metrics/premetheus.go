@@ -17,9 +17,9 @@ var (
 )
 
 var (
-	CurrentHeight = prometheus.NewGauge(prometheus.GaugeOpts{Name: "current_height"})
-	NewestHeight  = prometheus.NewGauge(prometheus.GaugeOpts{Name: "newest_height"})
-	TxCntByBlock  = prometheus.NewGauge(prometheus.GaugeOpts{Name: "tx_cnt_by_block"})
+	CurrentHeight = prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "current_height"}, []string{"chain"})
+	NewestHeight  = prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "newest_height"}, []string{"chain"})
+	TxCntByBlock  = prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "tx_cnt_by_block"}, []string{"chain"})
 
 	GetBlockDurationMs = prometheus.NewSummary(prometheus.SummaryOpts{
 		Name:       "get_block_duration_ms",
@@ -45,7 +45,7 @@ var (
 		Objectives: defaultObjectives,
 	})
 
-	BlockQueueSize = prometheus.NewGauge(prometheus.GaugeOpts{Name: "block_queue_size"})
+	BlockQueueSize = prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "block_queue_size"}, []string{"chain"})
 
 	ParseBlockDurationMs = prometheus.NewSummary(prometheus.SummaryOpts{
 		Name:       "parse_block_duration_ms",
@@ -127,6 +127,20 @@ var (
 		},
 		[]string{"protocol"},
 	)
+
+	CacheFallbackToDB = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cache_fallback_to_db_total",
+		},
+		[]string{"type"},
+	)
+
+	FilterRecheckTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "filter_recheck_total",
+		},
+		[]string{"type", "result"},
+	)
 )
 
 func init() {
@@ -152,6 +166,8 @@ func init() {
 	prometheus.MustRegister(VerifyPairDurationMs)
 	prometheus.MustRegister(VerifyPairTotal)
 	prometheus.MustRegister(VerifyPairOkByProtocol)
+	prometheus.MustRegister(CacheFallbackToDB)
+	prometheus.MustRegister(FilterRecheckTotal)
 }
 
 func init() {
@@ -0,0 +1,254 @@
+package mev
+
+import (
+	"base_scan/repository/orm"
+	"base_scan/types"
+	"github.com/shopspring/decimal"
+	"sort"
+	"strings"
+)
+
+const (
+	KindSandwich = "sandwich"
+	KindBackrun  = "backrun"
+)
+
+// roles written to orm.Tx.MevRole
+const (
+	RoleSandwichFront  = "sandwich_front"
+	RoleSandwichBack   = "sandwich_back"
+	RoleSandwichVictim = "sandwich_victim"
+	RoleBackrun        = "backrun"
+	RoleBackrunTarget  = "backrun_target"
+)
+
+var (
+	wei18 = decimal.New(1, 18)
+)
+
+/*
+Detect finds sandwiches and back-runs among the trades of one block and labels the trades with their role.
+Trades are grouped by pool and ordered by position in block.
+
+A sandwich is a front-run and a back-run of the same maker on a pool in opposite directions,
+with at least one trade of another maker in the front-run direction between them.
+Its profit is the tokens bought and sold by the attacker times the price difference, minus the gas of both txs.
+The victim loss is the tokens of the victim times how much worse its price is than the front-run price,
+it also contains the price impact of the victim itself, so it is an upper bound.
+
+A back-run is a trade in the tx right after another maker's trade on the same pool, in the opposite direction,
+by a tx trading at least two pools, i.e. an arbitrage of the price moved by the target.
+Its profit is what the back-run tx sold minus what it bought in usd, minus its gas.
+
+A trade has one role, the first found is kept: sandwiches are found before back-runs,
+and pools are searched in order of their first trade, so a block always gives the same records.
+The records are of chainId, the chain of the block.
+*/
+func Detect(chainId uint64, txs []*orm.Tx, nativeTokenPrice decimal.Decimal) []*orm.Mev {
+	pools, txHash2Pools := groupSwaps(txs)
+
+	mevs := make([]*orm.Mev, 0)
+	for _, swaps := range pools {
+		mevs = append(mevs, detectSandwiches(swaps, nativeTokenPrice)...)
+	}
+	for _, swaps := range pools {
+		mevs = append(mevs, detectBackruns(swaps, txs, txHash2Pools, nativeTokenPrice)...)
+	}
+
+	sort.SliceStable(mevs, func(i, j int) bool {
+		if mevs[i].BackBlockIndex != mevs[j].BackBlockIndex {
+			return mevs[i].BackBlockIndex < mevs[j].BackBlockIndex
+		}
+		return mevs[i].PairAddress < mevs[j].PairAddress
+	})
+	for _, m := range mevs {
+		m.ChainId = int(chainId)
+	}
+	return mevs
+}
+
+func isSwap(tx *orm.Tx) bool {
+	return tx.Event == types.Buy || tx.Event == types.Sell
+}
+
+func opposite(tx1, tx2 *orm.Tx) bool {
+	return isSwap(tx1) && isSwap(tx2) && tx1.Event != tx2.Event
+}
+
+func before(tx1, tx2 *orm.Tx) bool {
+	if tx1.BlockIndex != tx2.BlockIndex {
+		return tx1.BlockIndex < tx2.BlockIndex
+	}
+	return tx1.TxIndex < tx2.TxIndex
+}
+
+/*
+groupSwaps returns the swaps of every pool ordered by position, the pools ordered by the position of their first swap,
+and the pools every tx swapped on.
+*/
+func groupSwaps(txs []*orm.Tx) ([][]*orm.Tx, map[string]map[string]struct{}) {
+	pool2Swaps := make(map[string][]*orm.Tx)
+	txHash2Pools := make(map[string]map[string]struct{})
+	for _, tx := range txs {
+		if !isSwap(tx) {
+			continue
+		}
+
+		pool2Swaps[tx.PairAddress] = append(pool2Swaps[tx.PairAddress], tx)
+
+		txPools, ok := txHash2Pools[tx.TxHash]
+		if !ok {
+			txPools = make(map[string]struct{})
+			txHash2Pools[tx.TxHash] = txPools
+		}
+		txPools[tx.PairAddress] = struct{}{}
+	}
+
+	pools := make([][]*orm.Tx, 0, len(pool2Swaps))
+	for _, swaps := range pool2Swaps {
+		sort.Slice(swaps, func(i, j int) bool {
+			return before(swaps[i], swaps[j])
+		})
+		pools = append(pools, swaps)
+	}
+	sort.Slice(pools, func(i, j int) bool {
+		return before(pools[i][0], pools[j][0])
+	})
+	return pools, txHash2Pools
+}
+
+// gasCostUsd is the l2 and l1 fee of a tx in usd.
+func gasCostUsd(tx *orm.Tx, nativeTokenPrice decimal.Decimal) decimal.Decimal {
+	fee := decimal.NewFromInt(int64(tx.GasUsed)).Mul(tx.EffectiveGasPrice).Add(tx.L1Fee)
+	return fee.Div(wei18).Mul(nativeTokenPrice)
+}
+
+func detectSandwiches(swaps []*orm.Tx, nativeTokenPrice decimal.Decimal) []*orm.Mev {
+	mevs := make([]*orm.Mev, 0)
+	for i, front := range swaps {
+		if front.MevRole != "" {
+			continue
+		}
+
+		for j := i + 1; j < len(swaps); j++ {
+			back := swaps[j]
+			if back.Maker != front.Maker || back.BlockIndex == front.BlockIndex || !opposite(front, back) || back.MevRole != "" {
+				continue
+			}
+
+			victims := make([]*orm.Tx, 0)
+			for _, victim := range swaps[i+1 : j] {
+				if victim.Maker != front.Maker && victim.Event == front.Event &&
+					victim.BlockIndex > front.BlockIndex && victim.BlockIndex < back.BlockIndex {
+					victims = append(victims, victim)
+				}
+			}
+			if len(victims) == 0 {
+				break
+			}
+
+			mevs = append(mevs, newSandwich(front, back, victims, nativeTokenPrice))
+			break
+		}
+	}
+	return mevs
+}
+
+func newSandwich(front, back *orm.Tx, victims []*orm.Tx, nativeTokenPrice decimal.Decimal) *orm.Mev {
+	front.MevRole = RoleSandwichFront
+	back.MevRole = RoleSandwichBack
+
+	matched := decimal.Min(front.Token0Amount, back.Token0Amount)
+	priceDiff := back.PriceUsd.Sub(front.PriceUsd)
+	if front.Event == types.Sell {
+		priceDiff = priceDiff.Neg()
+	}
+	gasCost := gasCostUsd(front, nativeTokenPrice).Add(gasCostUsd(back, nativeTokenPrice))
+
+	victimLoss := decimal.Zero
+	victimTxHashes := make([]string, 0, len(victims))
+	for _, victim := range victims {
+		victim.MevRole = RoleSandwichVictim
+		victimTxHashes = append(victimTxHashes, victim.TxHash)
+
+		loss := victim.PriceUsd.Sub(front.PriceUsd)
+		if front.Event == types.Sell {
+			loss = loss.Neg()
+		}
+		if loss.IsPositive() {
+			victimLoss = victimLoss.Add(loss.Mul(victim.Token0Amount))
+		}
+	}
+
+	return &orm.Mev{
+		Kind:            KindSandwich,
+		Block:           front.Block,
+		BlockAt:         front.BlockAt,
+		PairAddress:     front.PairAddress,
+		Token0Address:   front.Token0Address,
+		Attacker:        front.Maker,
+		FrontTxHash:     front.TxHash,
+		FrontBlockIndex: front.BlockIndex,
+		BackTxHash:      back.TxHash,
+		BackBlockIndex:  back.BlockIndex,
+		VictimTxHashes:  strings.Join(victimTxHashes, ","),
+		VictimCount:     len(victims),
+		ProfitUsd:       matched.Mul(priceDiff).Sub(gasCost),
+		GasCostUsd:      gasCost,
+		VictimLossUsd:   victimLoss,
+	}
+}
+
+func detectBackruns(swaps []*orm.Tx, txs []*orm.Tx, txHash2Pools map[string]map[string]struct{}, nativeTokenPrice decimal.Decimal) []*orm.Mev {
+	mevs := make([]*orm.Mev, 0)
+	for i := 1; i < len(swaps); i++ {
+		target, backrun := swaps[i-1], swaps[i]
+		if backrun.BlockIndex != target.BlockIndex+1 || backrun.Maker == target.Maker || !opposite(target, backrun) {
+			continue
+		}
+		if target.MevRole != "" || backrun.MevRole != "" || len(txHash2Pools[backrun.TxHash]) < 2 {
+			continue
+		}
+
+		mevs = append(mevs, newBackrun(target, backrun, txs, nativeTokenPrice))
+	}
+	return mevs
+}
+
+func newBackrun(target, backrun *orm.Tx, txs []*orm.Tx, nativeTokenPrice decimal.Decimal) *orm.Mev {
+	target.MevRole = RoleBackrunTarget
+
+	profit := decimal.Zero
+	for _, tx := range txs {
+		if tx.TxHash != backrun.TxHash || !isSwap(tx) {
+			continue
+		}
+
+		// the trades of the tx in a sandwich keep their role
+		if tx.MevRole == "" {
+			tx.MevRole = RoleBackrun
+		}
+		if tx.Event == types.Sell {
+			profit = profit.Add(tx.AmountUsd)
+		} else {
+			profit = profit.Sub(tx.AmountUsd)
+		}
+	}
+	gasCost := gasCostUsd(backrun, nativeTokenPrice)
+
+	return &orm.Mev{
+		Kind:           KindBackrun,
+		Block:          backrun.Block,
+		BlockAt:        backrun.BlockAt,
+		PairAddress:    backrun.PairAddress,
+		Token0Address:  backrun.Token0Address,
+		Attacker:       backrun.Maker,
+		BackTxHash:     backrun.TxHash,
+		BackBlockIndex: backrun.BlockIndex,
+		VictimTxHashes: target.TxHash,
+		VictimCount:    1,
+		ProfitUsd:      profit.Sub(gasCost),
+		GasCostUsd:     gasCost,
+		VictimLossUsd:  decimal.Zero,
+	}
+}
@@ -0,0 +1,156 @@
+package mev
+
+import (
+	"base_scan/repository/orm"
+	"base_scan/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+const (
+	pool      = "0xpool"
+	otherPool = "0xother"
+	attacker  = "0xattacker"
+
+	testChainId = 8453
+)
+
+func swap(txHash, maker, pairAddress, event string, blockIndex, logIndex uint, amount, price float64) *orm.Tx {
+	token0Amount := decimal.NewFromFloat(amount)
+	priceUsd := decimal.NewFromFloat(price)
+	return &orm.Tx{
+		TxHash:            txHash,
+		Maker:             maker,
+		PairAddress:       pairAddress,
+		Event:             event,
+		BlockIndex:        blockIndex,
+		TxIndex:           logIndex,
+		Token0Amount:      token0Amount,
+		PriceUsd:          priceUsd,
+		AmountUsd:         token0Amount.Mul(priceUsd),
+		GasUsed:           100000,
+		EffectiveGasPrice: decimal.NewFromInt(1_000_000_000),
+	}
+}
+
+func TestDetectSandwich(t *testing.T) {
+	front := swap("0x1", attacker, pool, types.Buy, 1, 1, 100, 1.00)
+	victim := swap("0x2", "0xvictim", pool, types.Buy, 2, 5, 50, 1.10)
+	unrelated := swap("0x3", "0xuser", pool, types.Sell, 3, 9, 10, 1.09)
+	back := swap("0x4", attacker, pool, types.Sell, 4, 12, 100, 1.05)
+
+	// unordered like BlockResult.GetKafkaMessage returns them
+	mevs := Detect(testChainId, []*orm.Tx{back, unrelated, victim, front}, decimal.NewFromInt(2000))
+	require.Len(t, mevs, 1)
+
+	m := mevs[0]
+	require.Equal(t, testChainId, m.ChainId)
+	require.Equal(t, KindSandwich, m.Kind)
+	require.Equal(t, attacker, m.Attacker)
+	require.Equal(t, "0x1", m.FrontTxHash)
+	require.Equal(t, "0x4", m.BackTxHash)
+	require.Equal(t, "0x2", m.VictimTxHashes)
+	require.Equal(t, 1, m.VictimCount)
+
+	// 1 gwei * 100000 gas * 2 txs = 0.0002 native = 0.4 usd
+	require.True(t, decimal.NewFromFloat(0.4).Equal(m.GasCostUsd), m.GasCostUsd.String())
+	// 100 * (1.05 - 1.00) - 0.4
+	require.True(t, decimal.NewFromFloat(4.6).Equal(m.ProfitUsd), m.ProfitUsd.String())
+	// 50 * (1.10 - 1.00)
+	require.True(t, decimal.NewFromFloat(5).Equal(m.VictimLossUsd), m.VictimLossUsd.String())
+
+	require.Equal(t, RoleSandwichFront, front.MevRole)
+	require.Equal(t, RoleSandwichVictim, victim.MevRole)
+	require.Equal(t, RoleSandwichBack, back.MevRole)
+	require.Empty(t, unrelated.MevRole)
+}
+
+func TestDetectNoSandwichWithoutVictim(t *testing.T) {
+	buy := swap("0x1", attacker, pool, types.Buy, 1, 1, 100, 1.00)
+	other := swap("0x2", "0xuser", pool, types.Sell, 2, 5, 50, 0.99)
+	sell := swap("0x3", attacker, pool, types.Sell, 5, 12, 100, 1.01)
+
+	mevs := Detect(testChainId, []*orm.Tx{buy, other, sell}, decimal.NewFromInt(2000))
+	require.Empty(t, mevs)
+	require.Empty(t, buy.MevRole)
+}
+
+func TestDetectBackrun(t *testing.T) {
+	target := swap("0x1", "0xwhale", pool, types.Buy, 7, 3, 1000, 1.00)
+	backrunSell := swap("0x2", "0xarb", pool, types.Sell, 8, 4, 200, 1.02)
+	backrunBuy := swap("0x2", "0xarb", otherPool, types.Buy, 8, 6, 200, 1.00)
+	// same pool but not the next tx
+	later := swap("0x3", "0xarb2", pool, types.Sell, 10, 9, 10, 1.01)
+
+	mevs := Detect(testChainId, []*orm.Tx{target, backrunSell, backrunBuy, later}, decimal.NewFromInt(2000))
+	require.Len(t, mevs, 1)
+
+	m := mevs[0]
+	require.Equal(t, KindBackrun, m.Kind)
+	require.Equal(t, "0xarb", m.Attacker)
+	require.Equal(t, "0x2", m.BackTxHash)
+	require.Equal(t, "0x1", m.VictimTxHashes)
+	// 204 - 200 - 0.2
+	require.True(t, decimal.NewFromFloat(3.8).Equal(m.ProfitUsd), m.ProfitUsd.String())
+
+	require.Equal(t, RoleBackrunTarget, target.MevRole)
+	require.Equal(t, RoleBackrun, backrunSell.MevRole)
+	require.Equal(t, RoleBackrun, backrunBuy.MevRole)
+	require.Empty(t, later.MevRole)
+}
+
+func TestDetectIgnoresLiquidity(t *testing.T) {
+	add := &orm.Tx{TxHash: "0x1", Maker: attacker, PairAddress: pool, Event: types.Add, BlockIndex: 1}
+	victim := swap("0x2", "0xvictim", pool, types.Buy, 2, 5, 50, 1.10)
+	remove := &orm.Tx{TxHash: "0x3", Maker: attacker, PairAddress: pool, Event: types.Remove, BlockIndex: 3}
+
+	require.Empty(t, Detect(testChainId, []*orm.Tx{add, victim, remove}, decimal.NewFromInt(2000)))
+}
+
+func TestDetectSandwichAndBackrunInOneTx(t *testing.T) {
+	// the back-run of the sandwich on pool also back-runs the whale on otherPool
+	for i := 0; i < 20; i++ {
+		front := swap("0x1", attacker, pool, types.Buy, 1, 1, 100, 1.00)
+		victim := swap("0x2", "0xvictim", pool, types.Buy, 2, 3, 50, 1.10)
+		target := swap("0x3", "0xwhale", otherPool, types.Buy, 2, 4, 1000, 2.00)
+		back := swap("0x4", attacker, pool, types.Sell, 3, 5, 100, 1.05)
+		backrun := swap("0x4", attacker, otherPool, types.Sell, 3, 6, 100, 2.02)
+
+		mevs := Detect(testChainId, []*orm.Tx{backrun, back, target, victim, front}, decimal.NewFromInt(2000))
+		require.Len(t, mevs, 2)
+
+		require.Equal(t, KindBackrun, mevs[0].Kind)
+		require.Equal(t, otherPool, mevs[0].PairAddress)
+		require.Equal(t, "0x4", mevs[0].BackTxHash)
+		require.Equal(t, "0x3", mevs[0].VictimTxHashes)
+
+		require.Equal(t, KindSandwich, mevs[1].Kind)
+		require.Equal(t, pool, mevs[1].PairAddress)
+		require.Equal(t, "0x1", mevs[1].FrontTxHash)
+		require.Equal(t, "0x4", mevs[1].BackTxHash)
+
+		require.Equal(t, RoleSandwichFront, front.MevRole)
+		require.Equal(t, RoleSandwichVictim, victim.MevRole)
+		require.Equal(t, RoleSandwichBack, back.MevRole)
+		require.Equal(t, RoleBackrunTarget, target.MevRole)
+		require.Equal(t, RoleBackrun, backrun.MevRole)
+	}
+}
+
+func TestDetectBackrunOfTwoPools(t *testing.T) {
+	// one record, on the pool traded first
+	for i := 0; i < 20; i++ {
+		target := swap("0x1", "0xwhale", otherPool, types.Buy, 4, 2, 1000, 2.00)
+		otherTarget := swap("0x2", "0xwhale2", pool, types.Sell, 4, 3, 500, 1.00)
+		backrunSell := swap("0x3", "0xarb", otherPool, types.Sell, 5, 4, 100, 2.02)
+		backrunBuy := swap("0x3", "0xarb", pool, types.Buy, 5, 6, 200, 1.00)
+
+		mevs := Detect(testChainId, []*orm.Tx{backrunBuy, otherTarget, backrunSell, target}, decimal.NewFromInt(2000))
+		require.Len(t, mevs, 1)
+		require.Equal(t, otherPool, mevs[0].PairAddress)
+		require.Equal(t, "0x1", mevs[0].VictimTxHashes)
+		require.Equal(t, RoleBackrunTarget, target.MevRole)
+		require.Empty(t, otherTarget.MevRole)
+	}
+}
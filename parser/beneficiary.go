@@ -0,0 +1,64 @@
+package parser
+
+import (
+	"base_scan/abi/aerodrome"
+	pancakev3 "base_scan/abi/pancake/v3"
+	uniswapv2 "base_scan/abi/uniswap/v2"
+	uniswapv3 "base_scan/abi/uniswap/v3"
+	"base_scan/types"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// swap topics with the sender in topic 1 and the recipient in topic 2, pancake v2 shares the uniswap v2 topic
+var swapTopics = map[common.Hash]struct{}{
+	uniswapv2.SwapTopic0: {},
+	uniswapv3.SwapTopic0: {},
+	pancakev3.SwapTopic0: {},
+	aerodrome.SwapTopic0: {},
+}
+
+type swapLeg struct {
+	logIndex uint
+	pool     common.Address
+	types.SwapParties
+}
+
+/*
+ResolveBeneficiaries follows the recipient of every swap of a tx through the later swaps it pays into,
+and returns the final receiver by log index.
+Every swap log counts, also of pools that are filtered, so that a route through them is not cut.
+A route ending at the router that called the last pool gets no beneficiary, the router forwards the output.
+*/
+func ResolveBeneficiaries(logs []*ethtypes.Log) map[uint]common.Address {
+	legs := make([]*swapLeg, 0)
+	for _, ethLog := range logs {
+		if len(ethLog.Topics) < 3 {
+			continue
+		}
+		if _, ok := swapTopics[ethLog.Topics[0]]; !ok {
+			continue
+		}
+
+		legs = append(legs, &swapLeg{
+			logIndex:    ethLog.Index,
+			pool:        ethLog.Address,
+			SwapParties: types.SwapPartiesFromTopics(ethLog.Topics),
+		})
+	}
+
+	beneficiaries := make(map[uint]common.Address, len(legs))
+	for i, leg := range legs {
+		last := leg
+		for _, next := range legs[i+1:] {
+			if next.pool == last.Recipient {
+				last = next
+			}
+		}
+
+		if last.Recipient != last.Sender {
+			beneficiaries[leg.logIndex] = last.Recipient
+		}
+	}
+	return beneficiaries
+}
@@ -0,0 +1,57 @@
+package parser
+
+import (
+	"base_scan/abi/aerodrome"
+	uniswapv2 "base_scan/abi/uniswap/v2"
+	uniswapv3 "base_scan/abi/uniswap/v3"
+	"base_scan/repository/orm"
+	"base_scan/types"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+var (
+	routerAddress = common.HexToAddress("0x00000000000000000000000000000000000000e1")
+	user          = common.HexToAddress("0x00000000000000000000000000000000000000e2")
+	poolA         = common.HexToAddress("0x00000000000000000000000000000000000000f2")
+	poolB         = common.HexToAddress("0x00000000000000000000000000000000000000f3")
+	poolC         = common.HexToAddress("0x00000000000000000000000000000000000000f4")
+)
+
+func swapPartiesLog(topic common.Hash, index uint, pool, sender, recipient common.Address) *ethtypes.Log {
+	return &ethtypes.Log{
+		Address: pool,
+		Topics:  []common.Hash{topic, common.BytesToHash(sender.Bytes()), common.BytesToHash(recipient.Bytes())},
+		Index:   index,
+	}
+}
+
+func TestResolveBeneficiaries(t *testing.T) {
+	beneficiaries := ResolveBeneficiaries([]*ethtypes.Log{
+		// poolA pays into poolB, which pays the user
+		swapPartiesLog(uniswapv2.SwapTopic0, 1, poolA, routerAddress, poolB),
+		{Address: poolB, Topics: []common.Hash{{}}, Index: 2},
+		swapPartiesLog(uniswapv3.SwapTopic0, 3, poolB, routerAddress, user),
+		// the router keeps the output, e.g. to unwrap weth
+		swapPartiesLog(aerodrome.SwapTopic0, 4, poolC, routerAddress, routerAddress),
+	})
+
+	require.Equal(t, map[uint]common.Address{1: user, 3: user}, beneficiaries)
+}
+
+func TestSwapPartiesFillOrmTx(t *testing.T) {
+	parties := types.SwapPartiesFromTopics(swapPartiesLog(uniswapv2.SwapTopic0, 0, poolA, routerAddress, routerAddress).Topics)
+	require.Equal(t, routerAddress, parties.Sender)
+
+	tx := &orm.Tx{Maker: user.String()}
+	parties.FillOrmTx(tx)
+	require.Equal(t, routerAddress.String(), tx.Sender)
+	require.Equal(t, routerAddress.String(), tx.Recipient)
+	require.Equal(t, user.String(), tx.Beneficiary)
+
+	parties.SetBeneficiary(poolB)
+	parties.FillOrmTx(tx)
+	require.Equal(t, poolB.String(), tx.Beneficiary)
+}
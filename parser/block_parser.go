@@ -1,10 +1,19 @@
 package parser
 
 import (
+	"base_scan/alert"
 	"base_scan/cache"
+	"base_scan/chain"
 	"base_scan/config"
+	"base_scan/grpcapi"
 	"base_scan/log"
+	"base_scan/maker"
 	"base_scan/metrics"
+	"base_scan/mev"
+	"base_scan/push"
+	"base_scan/quote"
+	"base_scan/repository/orm"
+	"base_scan/router"
 	"base_scan/sequencer"
 	"base_scan/service"
 	"base_scan/types"
@@ -34,6 +43,15 @@ type blockParser struct {
 	topicRouter  TopicRouter
 	kafkaSender  service.KafkaSender
 	dbService    service.DBService
+	classifier   *maker.Classifier
+	routers      *router.Registry
+	quotes       *quote.Engine
+	launchAlerts *alert.Detector
+	webhook      *alert.Webhook
+	pushServer   *push.Server
+	grpcServer   *grpcapi.Server
+	profile      *chain.Profile
+	baseTokens   *types.BaseTokens
 }
 
 func NewBlockParser(
@@ -44,23 +62,41 @@ func NewBlockParser(
 	topicRouter TopicRouter,
 	kafkaSender service.KafkaSender,
 	dbService service.DBService,
+	classifier *maker.Classifier,
+	routers *router.Registry,
+	quotes *quote.Engine,
+	launchAlerts *alert.Detector,
+	webhook *alert.Webhook,
+	pushServer *push.Server,
+	grpcServer *grpcapi.Server,
+	conf *config.BlockHandlerConf,
+	profile *chain.Profile,
 ) BlockParser {
-	workPool, err := ants.NewPool(config.G.BlockHandler.PoolSize)
+	workPool, err := ants.NewPool(conf.PoolSize)
 	if err != nil {
 		log.Logger.Fatal("ants pool(BlockParser) init err", zap.Error(err))
 	}
 
 	return &blockParser{
-		inputQueue:   make(chan *types.ParseBlockContext, config.G.BlockHandler.QueueSize),
+		inputQueue:   make(chan *types.ParseBlockContext, conf.QueueSize),
 		workPool:     workPool,
 		cache:        cache,
 		sequencer:    sequencer,
-		outputQueue:  make(chan *types.ParseBlockContext, config.G.BlockHandler.QueueSize),
+		outputQueue:  make(chan *types.ParseBlockContext, conf.QueueSize),
 		priceService: priceService,
 		pairService:  pairService,
 		topicRouter:  topicRouter,
 		kafkaSender:  kafkaSender,
 		dbService:    dbService,
+		classifier:   classifier,
+		routers:      routers,
+		quotes:       quotes,
+		launchAlerts: launchAlerts,
+		webhook:      webhook,
+		pushServer:   pushServer,
+		grpcServer:   grpcServer,
+		profile:      profile,
+		baseTokens:   profile.BaseTokens(),
 	}
 }
 
@@ -132,7 +168,7 @@ func (p *blockParser) parseBlock(pbc *types.ParseBlockContext) {
 	pbc.NativeTokenPrice = p.waitForNativeTokenPrice(pbc.HeightTime.HeightBigInt)
 
 	now := time.Now()
-	br := types.NewBlockResult(pbc.HeightTime.Height, pbc.HeightTime.Timestamp, pbc.NativeTokenPrice)
+	br := types.NewBlockResult(p.profile.Id, p.baseTokens, pbc.HeightTime.Height, pbc.HeightTime.Timestamp, pbc.NativeTokenPrice)
 	for _, txReceipt := range pbc.BlockReceipts {
 		if txReceipt.Status != 1 {
 			continue
@@ -144,7 +180,15 @@ func (p *blockParser) parseBlock(pbc *types.ParseBlockContext) {
 			continue
 		}
 
-		tr := types.NewTxResult(txSender)
+		tx := pbc.Block.Transactions()[txReceipt.TransactionIndex]
+		br.AddDeploymentOrTransfer(txSender, tx, txReceipt)
+		txMeta := pbc.GetTxMeta(txReceipt)
+		txMeta.Router = p.routers.Identify(txMeta.To, tx.Data())
+		tr := types.NewTxResult(txSender, txMeta, ParseUserOperations(txSender, txReceipt.Logs))
+		beneficiaries := ResolveBeneficiaries(txReceipt.Logs)
+		positionChanges := ParsePositionChanges(txReceipt.Logs, p.profile.PositionManagers)
+		positionIds := positionIdsByPoolLog(positionChanges)
+		br.AddPositionChanges(positionChanges)
 		for _, ethLog := range txReceipt.Logs {
 			if len(ethLog.Topics) == 0 {
 				continue
@@ -155,18 +199,37 @@ func (p *blockParser) parseBlock(pbc *types.ParseBlockContext) {
 				continue
 			}
 
+			if setter, ok := event.(types.BeneficiarySetter); ok {
+				setter.SetBeneficiary(beneficiaries[ethLog.Index])
+			}
+			if setter, ok := event.(types.PositionIdSetter); ok {
+				setter.SetPositionId(positionIds[ethLog.Index])
+			}
+
 			pairWrap := p.getPairByEvent(event) // TODO parallel
+			if pairWrap.NewPool {
+				br.AddNewPool(pairWrap.Pool)
+			}
 			if pairWrap.Pair.Filtered {
 				continue
 			}
+			if feeEvent, ok := event.(types.PairFeeEvent); ok {
+				pairWrap.Pair = p.pairService.UpdatePairFee(pairWrap.Pair, feeEvent.GetPairFeeUpdate())
+			}
 
 			collectNewPairAndTokens(br, pairWrap)
 			event.SetPair(pairWrap.Pair)
 			event.SetBlockTime(pbc.HeightTime.Time)
-			tr.AddEvent(event)
+			tr.AddEvent(event, ethLog.Index)
+			if event.CanGetPoolStateUpdate() {
+				br.PoolStateUpdates = append(br.PoolStateUpdates, event.GetPoolStateUpdate())
+			}
 		}
 		br.AddTxResult(tr)
 	}
+	if p.classifier != nil {
+		br.MakerCodes = p.classifier.ReadCodes(br.Height, br.Makers())
+	}
 
 	duration := time.Since(now)
 	metrics.ParseBlockDurationMs.Observe(float64(duration.Milliseconds()))
@@ -177,6 +240,10 @@ func (p *blockParser) parseBlock(pbc *types.ParseBlockContext) {
 }
 
 func (p *blockParser) getPairByEvent(event types.Event) *types.PairWrap {
+	if poolSwap, ok := event.(types.PoolSwapEvent); ok {
+		return p.pairService.GetPoolPair(poolSwap)
+	}
+
 	if event.CanGetPair() {
 		pair := event.GetPair()
 		if pair.Filtered {
@@ -197,6 +264,15 @@ func (p *blockParser) getPairByEvent(event types.Event) *types.PairWrap {
 
 func (p *blockParser) commitBlockResult(blockResult *types.BlockResult) {
 	blockInfo := blockResult.GetKafkaMessage()
+	mevs := mev.Detect(blockResult.ChainId, blockInfo.Txs, blockResult.NativeTokenPrice)
+	var makers []*orm.Maker
+	if p.classifier != nil {
+		makers = p.classifier.Classify(blockResult.Height, blockInfo.Txs, blockResult.MakerCodes)
+	}
+	launchAlertInfo := &types.LaunchAlertInfo{ChainId: blockInfo.ChainId, Height: blockInfo.Height, Timestamp: blockInfo.Timestamp}
+	if p.launchAlerts != nil {
+		launchAlertInfo.Alerts = p.launchAlerts.Detect(blockResult, blockInfo.Txs)
+	}
 
 	now := time.Now()
 	err := p.dbService.AddTokens(blockInfo.NewTokens)
@@ -209,11 +285,46 @@ func (p *blockParser) commitBlockResult(blockResult *types.BlockResult) {
 		log.Logger.Fatal("add pairs err", zap.Any("height", blockInfo.Height), zap.Error(err))
 	}
 
+	err = p.dbService.UpdatePairFees(blockInfo.PairFeeUpdates)
+	if err != nil {
+		log.Logger.Fatal("update pair fees err", zap.Any("height", blockInfo.Height), zap.Error(err))
+	}
+
+	err = p.dbService.AddPools(blockInfo.NewPools)
+	if err != nil {
+		log.Logger.Fatal("add pools err", zap.Any("height", blockInfo.Height), zap.Error(err))
+	}
+
+	err = p.dbService.UpsertLaunches(blockInfo.Launches)
+	if err != nil {
+		log.Logger.Fatal("upsert launches err", zap.Any("height", blockInfo.Height), zap.Error(err))
+	}
+
 	err = p.dbService.AddTxs(blockInfo.Txs)
 	if err != nil {
 		log.Logger.Fatal("add txs err", zap.Any("height", blockInfo.Height), zap.Error(err))
 	}
 
+	err = p.dbService.AddPoolFees(blockInfo.PoolFees)
+	if err != nil {
+		log.Logger.Fatal("add pool fees err", zap.Any("height", blockInfo.Height), zap.Error(err))
+	}
+
+	err = p.dbService.AddMevs(mevs)
+	if err != nil {
+		log.Logger.Fatal("add mevs err", zap.Any("height", blockInfo.Height), zap.Error(err))
+	}
+
+	err = p.dbService.UpsertMakers(makers)
+	if err != nil {
+		log.Logger.Fatal("upsert makers err", zap.Any("height", blockInfo.Height), zap.Error(err))
+	}
+
+	err = p.dbService.UpsertPositions(types.MergePositionChanges(blockResult.ChainId, blockResult.PositionChanges))
+	if err != nil {
+		log.Logger.Fatal("upsert positions err", zap.Any("height", blockInfo.Height), zap.Error(err))
+	}
+
 	duration := time.Since(now)
 	metrics.DbOperationDurationMs.Observe(float64(duration.Milliseconds()))
 	log.Logger.Info("db operation duration",
@@ -222,16 +333,51 @@ func (p *blockParser) commitBlockResult(blockResult *types.BlockResult) {
 		zap.String("price", blockInfo.NativeTokenPrice),
 		zap.Int("new tokens", len(blockInfo.NewTokens)),
 		zap.Int("new pairs", len(blockInfo.NewPairs)),
-		zap.Int("txs", len(blockInfo.Txs)))
+		zap.Int("new pools", len(blockInfo.NewPools)),
+		zap.Int("launches", len(blockInfo.Launches)),
+		zap.Int("pair fee updates", len(blockInfo.PairFeeUpdates)),
+		zap.Int("txs", len(blockInfo.Txs)),
+		zap.Int("pool fees", len(blockInfo.PoolFees)),
+		zap.Int("mevs", len(mevs)),
+		zap.Int("makers", len(makers)),
+		zap.Int("launch alerts", len(launchAlertInfo.Alerts)),
+		zap.Int("position changes", len(blockResult.PositionChanges)))
 
 	err = p.kafkaSender.Send(blockInfo)
 	if err != nil {
 		log.Logger.Fatal("kafka send msg err", zap.Error(err), zap.Any("block", blockResult.Height))
 	}
 
+	err = p.kafkaSender.SendMev(&types.MevInfo{ChainId: blockInfo.ChainId, Height: blockInfo.Height, Timestamp: blockInfo.Timestamp, Mevs: mevs})
+	if err != nil {
+		log.Logger.Fatal("kafka send mev msg err", zap.Error(err), zap.Any("block", blockResult.Height))
+	}
+
+	err = p.kafkaSender.SendPositions(&types.PositionInfo{ChainId: blockInfo.ChainId, Height: blockInfo.Height, Timestamp: blockInfo.Timestamp, Changes: blockResult.PositionChanges})
+	if err != nil {
+		log.Logger.Fatal("kafka send position msg err", zap.Error(err), zap.Any("block", blockResult.Height))
+	}
+
+	err = p.kafkaSender.SendLaunchAlerts(launchAlertInfo)
+	if err != nil {
+		log.Logger.Fatal("kafka send launch alert msg err", zap.Error(err), zap.Any("block", blockResult.Height))
+	}
+	if p.webhook != nil {
+		p.webhook.Send(launchAlertInfo)
+	}
+
 	p.cache.SetFinishedBlock(blockResult.Height)
-	metrics.CurrentHeight.Set(float64(blockResult.Height))
-	metrics.TxCntByBlock.Set(float64(len(blockInfo.Txs)))
+	if p.quotes != nil {
+		p.quotes.Apply(blockResult.Height, blockResult.PoolStateUpdates)
+	}
+	if p.pushServer != nil {
+		p.pushServer.Publish(blockInfo)
+	}
+	if p.grpcServer != nil {
+		p.grpcServer.Publish(blockInfo)
+	}
+	metrics.CurrentHeight.WithLabelValues(p.profile.Name).Set(float64(blockResult.Height))
+	metrics.TxCntByBlock.WithLabelValues(p.profile.Name).Set(float64(len(blockInfo.Txs)))
 }
 
 func (p *blockParser) startHandleBlockResult(wg *sync.WaitGroup) {
@@ -0,0 +1,111 @@
+package event_parser
+
+import (
+	"base_scan/abi/aerodrome"
+	"base_scan/parser/event_parser/event"
+	"base_scan/types"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+)
+
+// PoolCreatedEventParserAerodrome is the PairCreatedEventParser keeping the stable flag of the pool.
+type PoolCreatedEventParserAerodrome struct {
+	PairCreatedEventParser
+}
+
+func (o *PoolCreatedEventParserAerodrome) Parse(ethLog *ethtypes.Log) (types.Event, error) {
+	e, err := o.PairCreatedEventParser.Parse(ethLog)
+	if err != nil {
+		return nil, err
+	}
+
+	e.(*event.PairCreatedEvent).Pair.Stable = ethLog.Topics[3] != common.Hash{}
+	return e, nil
+}
+
+/*
+SetCustomFeeEventParser parses the fee changes of aerodrome pools by the factory, fees are in bips.
+A custom fee of 0 resets the pool to the default fee, which the pair service reads from the factory.
+*/
+type SetCustomFeeEventParser struct {
+	FactoryEventParser
+}
+
+func (o *SetCustomFeeEventParser) Parse(ethLog *ethtypes.Log) (types.Event, error) {
+	protocolId, ok := o.FactoryProtocolIds[ethLog.Address]
+	if !ok {
+		return nil, ErrWrongFactoryAddress
+	}
+
+	input, err := o.LogUnpacker.Unpack(ethLog)
+	if err != nil {
+		return nil, err
+	}
+
+	poolAddress := common.BytesToAddress(ethLog.Topics[1].Bytes()[12:])
+	e := &event.FeeChangeEvent{
+		EventCommon: types.EventCommonFromEthLog(ethLog),
+		Update: &types.PairFeeUpdate{
+			PairAddress: poolAddress,
+			Block:       ethLog.BlockNumber,
+			LogIndex:    ethLog.Index,
+		},
+	}
+
+	fee := input[0].(*big.Int)
+	switch {
+	case fee.Sign() == 0:
+		e.Update.Default = true
+	case fee.Cmp(big.NewInt(aerodrome.ZeroFeeIndicator)) == 0:
+		e.Update.Fee = 0
+	default:
+		e.Update.Fee = aerodrome.FeePips(fee)
+	}
+
+	e.Pair = &types.Pair{
+		Address: poolAddress,
+	}
+
+	e.PossibleProtocolIds = []int{protocolId}
+
+	return e, nil
+}
+
+/*
+PoolFeeEventParser parses the Fees and Claim events of aerodrome pools, Claim has the recipient as second topic.
+*/
+type PoolFeeEventParser struct {
+	PoolEventParser
+	Kind string
+}
+
+func (o *PoolFeeEventParser) Parse(ethLog *ethtypes.Log) (types.Event, error) {
+	input, err := o.ethLogUnpacker.Unpack(ethLog)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &event.PoolFeeEvent{
+		EventCommon: types.EventCommonFromEthLog(ethLog),
+		Kind:        o.Kind,
+		Sender:      common.BytesToAddress(ethLog.Topics[1].Bytes()[12:]),
+		Amount0Wei:  input[0].(*big.Int),
+		Amount1Wei:  input[1].(*big.Int),
+	}
+	if len(ethLog.Topics) > 2 {
+		e.Recipient = common.BytesToAddress(ethLog.Topics[2].Bytes()[12:])
+	}
+
+	if e.Amount0Wei.Sign() == 0 && e.Amount1Wei.Sign() == 0 {
+		return nil, errAmountInZero
+	}
+
+	e.Pair = &types.Pair{
+		Address: ethLog.Address,
+	}
+
+	e.PossibleProtocolIds = o.PossibleProtocolIds
+
+	return e, nil
+}
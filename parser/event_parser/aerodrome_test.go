@@ -0,0 +1,118 @@
+package event_parser
+
+import (
+	"base_scan/abi/aerodrome"
+	"base_scan/types"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+)
+
+func TestPoolCreated_AerodromeStable(t *testing.T) {
+	data, err := aerodrome.PoolCreatedEvent.Inputs.NonIndexed().Pack(testPool, big.NewInt(1))
+	require.NoError(t, err)
+	ethLog := &ethtypes.Log{
+		Address: aerodrome.FactoryAddress,
+		Topics: []common.Hash{
+			aerodrome.PoolCreatedTopic0,
+			common.BytesToHash(types.USDCAddress.Bytes()),
+			common.BytesToHash(testToken.Bytes()),
+			common.BigToHash(big.NewInt(1)),
+		},
+		Data: data,
+	}
+
+	e, err := Topic2EventParser[aerodrome.PoolCreatedTopic0].Parse(ethLog)
+	require.NoError(t, err)
+	require.True(t, e.IsCreatePair())
+	require.True(t, e.GetPair().Stable)
+	require.Equal(t, testPool, e.GetPair().Address)
+
+	ethLog.Topics[3] = common.Hash{}
+	e, err = Topic2EventParser[aerodrome.PoolCreatedTopic0].Parse(ethLog)
+	require.NoError(t, err)
+	require.False(t, e.GetPair().Stable)
+}
+
+func TestSetCustomFee_Aerodrome(t *testing.T) {
+	cases := []struct {
+		fee        int64
+		expectFee  uint32
+		expectDflt bool
+	}{
+		{fee: 25, expectFee: 2500},
+		{fee: 0, expectDflt: true},
+		{fee: aerodrome.ZeroFeeIndicator, expectFee: 0},
+	}
+
+	for _, c := range cases {
+		data, err := aerodrome.SetCustomFeeEvent.Inputs.NonIndexed().Pack(big.NewInt(c.fee))
+		require.NoError(t, err)
+		ethLog := &ethtypes.Log{
+			Address:     aerodrome.FactoryAddress,
+			Topics:      []common.Hash{aerodrome.SetCustomFeeTopic0, common.BytesToHash(testPool.Bytes())},
+			Data:        data,
+			BlockNumber: 100,
+			Index:       3,
+		}
+
+		e, err := Topic2EventParser[aerodrome.SetCustomFeeTopic0].Parse(ethLog)
+		require.NoError(t, err)
+		require.Equal(t, testPool, e.GetPairAddress())
+		require.Equal(t, []int{types.ProtocolIdAerodrome}, e.GetPossibleProtocolIds())
+		require.Equal(t, &types.PairFeeUpdate{
+			PairAddress: testPool,
+			Fee:         c.expectFee,
+			Default:     c.expectDflt,
+			Block:       100,
+			LogIndex:    3,
+		}, e.(types.PairFeeEvent).GetPairFeeUpdate())
+	}
+
+	_, err := Topic2EventParser[aerodrome.SetCustomFeeTopic0].Parse(&ethtypes.Log{
+		Address: testPool,
+		Topics:  []common.Hash{aerodrome.SetCustomFeeTopic0, common.BytesToHash(testPool.Bytes())},
+	})
+	require.ErrorIs(t, err, ErrWrongFactoryAddress)
+}
+
+func TestPoolFee_Aerodrome(t *testing.T) {
+	data, err := aerodrome.ClaimEvent.Inputs.NonIndexed().Pack(big.NewInt(3e6), big.NewInt(1e18))
+	require.NoError(t, err)
+	ethLog := &ethtypes.Log{
+		Address: testPool,
+		Topics:  []common.Hash{aerodrome.ClaimTopic0, common.BytesToHash(testBuyer.Bytes()), common.BytesToHash(testToken.Bytes())},
+		Data:    data,
+	}
+
+	e, err := Topic2EventParser[aerodrome.ClaimTopic0].Parse(ethLog)
+	require.NoError(t, err)
+
+	// token1 of the pool is the token0 of the pair
+	e.SetPair(&types.Pair{
+		Address:        testPool,
+		ProtocolId:     types.ProtocolIdAerodrome,
+		Token0Core:     &types.TokenCore{Address: testToken, Decimals: 18},
+		Token1Core:     &types.TokenCore{Address: types.USDCAddress, Decimals: 6},
+		TokensReversed: true,
+	})
+	poolFee := e.(types.PoolFeeEvent).GetPoolFee(8453)
+	require.Equal(t, 8453, poolFee.ChainId)
+	require.Equal(t, types.PoolFeeClaim, poolFee.Kind)
+	require.Equal(t, testBuyer.String(), poolFee.Sender)
+	require.Equal(t, testToken.String(), poolFee.Recipient)
+	require.True(t, decimal.NewFromInt(1).Equal(poolFee.Token0Amount))
+	require.True(t, decimal.NewFromInt(3).Equal(poolFee.Token1Amount))
+
+	data, err = aerodrome.FeesEvent.Inputs.NonIndexed().Pack(big.NewInt(0), big.NewInt(0))
+	require.NoError(t, err)
+	_, err = Topic2EventParser[aerodrome.FeesTopic0].Parse(&ethtypes.Log{
+		Address: testPool,
+		Topics:  []common.Hash{aerodrome.FeesTopic0, common.BytesToHash(testBuyer.Bytes())},
+		Data:    data,
+	})
+	require.ErrorIs(t, err, errAmountInZero)
+}
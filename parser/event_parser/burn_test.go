@@ -21,7 +21,7 @@ func TestBurn_Aerodrome(t *testing.T) {
 	pairWrap := tc.PairService.GetPair(event.GetPairAddress(), event.GetPossibleProtocolIds())
 	event.SetPair(pairWrap.Pair)
 
-	tx := event.GetTx(service.MockNativeTokenPrice)
+	tx := event.GetTx(types.DefaultBaseTokens, service.MockNativeTokenPrice)
 	expectAmt0, _ := decimal.NewFromString("4047.640731408680145311")
 	expectAmt1, _ := decimal.NewFromString("9.88229999999999995")
 	expectTx := &orm.Tx{
@@ -62,7 +62,7 @@ func TestBurn_UniswapV2(t *testing.T) {
 	pairWrap := tc.PairService.GetPair(event.GetPairAddress(), event.GetPossibleProtocolIds())
 	event.SetPair(pairWrap.Pair)
 
-	tx := event.GetTx(service.MockNativeTokenPrice)
+	tx := event.GetTx(types.DefaultBaseTokens, service.MockNativeTokenPrice)
 
 	token0Wei := decimal.NewFromBigInt(big.NewInt(1), int32(pairWrap.Pair.Token0Core.Decimals))
 	expectAmt0 := expectAmt0Wei.Div(token0Wei)
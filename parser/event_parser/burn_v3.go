@@ -21,6 +21,7 @@ func (o *BurnEventParserV3) Parse(ethLog *ethtypes.Log) (types.Event, error) {
 		EventCommon: types.EventCommonFromEthLog(ethLog),
 		Amount0Wei:  input[1].(*big.Int),
 		Amount1Wei:  input[2].(*big.Int),
+		Range:       types.NewLiquidityRange(ethLog.Topics, input[0].(*big.Int)),
 	}
 
 	e.Pair = &types.Pair{
@@ -11,13 +11,15 @@ type BurnEvent struct {
 	*types.EventCommon
 	Amount0Wei *big.Int
 	Amount1Wei *big.Int
+	// v3 only
+	Range *types.LiquidityRange
 }
 
 func (e *BurnEvent) CanGetTx() bool {
 	return true
 }
 
-func (e *BurnEvent) GetTx(bnbPrice decimal.Decimal) *orm.Tx {
+func (e *BurnEvent) GetTx(baseTokens *types.BaseTokens, bnbPrice decimal.Decimal) *orm.Tx {
 	tx := &orm.Tx{
 		TxHash:        e.TxHash.String(),
 		Event:         types.Remove,
@@ -33,8 +35,28 @@ func (e *BurnEvent) GetTx(bnbPrice decimal.Decimal) *orm.Tx {
 	}
 
 	tx.Token0Amount, tx.Token1Amount = ParseAmountsByPair(e.Amount0Wei, e.Amount1Wei, e.Pair)
-	tx.AmountUsd, tx.PriceUsd = CalcAmountAndPrice(bnbPrice, tx.Token0Amount, tx.Token1Amount, e.Pair.Token1Core.Address)
+	tx.AmountUsd, tx.PriceUsd = CalcAmountAndPrice(baseTokens, bnbPrice, tx.Token0Amount, tx.Token1Amount, e.Pair.Token1Core.Address)
+	e.TxMeta.FillOrmTx(tx)
+	e.UserOperation.FillOrmTx(tx)
+	e.Range.FillOrmTx(tx)
 	return tx
 }
 
+func (e *BurnEvent) SetPositionId(positionId *big.Int) {
+	if e.Range != nil {
+		e.Range.PositionId = positionId
+	}
+}
+
+func (e *BurnEvent) CanGetPoolStateUpdate() bool {
+	return e.Range != nil
+}
+
+func (e *BurnEvent) GetPoolStateUpdate() *types.PoolStateUpdate {
+	u := types.NewPoolStateUpdate(types.PoolStateBurn, e.Pair, e.LogIndex)
+	u.Liquidity, u.TickLower, u.TickUpper = e.Range.Liquidity, e.Range.TickLower, e.Range.TickUpper
+	return u
+}
+
 var _ types.Event = (*BurnEvent)(nil)
+var _ types.PositionIdSetter = (*BurnEvent)(nil)
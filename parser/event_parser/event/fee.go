@@ -0,0 +1,61 @@
+package event
+
+import (
+	"base_scan/repository/orm"
+	"base_scan/types"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+)
+
+// FeeChangeEvent is a change of the fee of its pair by the factory.
+type FeeChangeEvent struct {
+	*types.EventCommon
+	Update *types.PairFeeUpdate
+}
+
+func (e *FeeChangeEvent) GetPairFeeUpdate() *types.PairFeeUpdate {
+	return e.Update
+}
+
+/*
+PoolFeeEvent is a fee accrual or a fee claim of a pool, see orm.PoolFee.
+The amounts are in the order of the tokens of the pool, like the amounts of swaps.
+*/
+type PoolFeeEvent struct {
+	*types.EventCommon
+	Kind       string
+	Sender     common.Address
+	Recipient  common.Address
+	Amount0Wei *big.Int
+	Amount1Wei *big.Int
+}
+
+func (e *PoolFeeEvent) GetPoolFee(chainId uint64) *orm.PoolFee {
+	poolFee := &orm.PoolFee{
+		ChainId:       int(chainId),
+		TxHash:        e.TxHash.String(),
+		Kind:          e.Kind,
+		PairAddress:   e.Pair.Address.String(),
+		Program:       types.GetProtocolName(e.Pair.ProtocolId),
+		Token0Address: e.Pair.Token0Core.Address.String(),
+		Token1Address: e.Pair.Token1Core.Address.String(),
+		Sender:        e.Sender.String(),
+		Block:         e.BlockNumber,
+		BlockAt:       e.BlockTime,
+		BlockIndex:    e.TxIndex,
+		TxIndex:       e.LogIndex,
+	}
+	if e.Recipient != types.ZeroAddress {
+		poolFee.Recipient = e.Recipient.String()
+	}
+
+	poolFee.Token0Amount, poolFee.Token1Amount = ParseAmountsByPair(e.Amount0Wei, e.Amount1Wei, e.Pair)
+	return poolFee
+}
+
+var (
+	_ types.Event        = (*FeeChangeEvent)(nil)
+	_ types.PairFeeEvent = (*FeeChangeEvent)(nil)
+	_ types.Event        = (*PoolFeeEvent)(nil)
+	_ types.PoolFeeEvent = (*PoolFeeEvent)(nil)
+)
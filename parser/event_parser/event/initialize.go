@@ -0,0 +1,25 @@
+package event
+
+import (
+	"base_scan/types"
+	"math/big"
+)
+
+// InitializeEvent sets the first price of a v3 pool, it is no trade.
+type InitializeEvent struct {
+	*types.EventCommon
+	SqrtPriceX96 *big.Int
+	Tick         int32
+}
+
+func (e *InitializeEvent) CanGetPoolStateUpdate() bool {
+	return true
+}
+
+func (e *InitializeEvent) GetPoolStateUpdate() *types.PoolStateUpdate {
+	u := types.NewPoolStateUpdate(types.PoolStateInitialize, e.Pair, e.LogIndex)
+	u.SqrtPriceX96, u.Tick = e.SqrtPriceX96, e.Tick
+	return u
+}
+
+var _ types.Event = (*InitializeEvent)(nil)
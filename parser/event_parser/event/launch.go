@@ -0,0 +1,98 @@
+package event
+
+import (
+	"base_scan/repository/orm"
+	"base_scan/types"
+	"github.com/shopspring/decimal"
+	"math/big"
+)
+
+// LaunchEvent is the launch of a token on a launchpad, the pair created is the curve of the token.
+type LaunchEvent struct {
+	*types.EventCommon
+	Launch *types.Launch
+}
+
+func (e *LaunchEvent) CanGetPair() bool {
+	return true
+}
+
+func (e *LaunchEvent) GetPair() *types.Pair {
+	e.Pair.BlockAt = e.BlockTime
+	return e.Pair
+}
+
+func (e *LaunchEvent) IsCreatePair() bool {
+	return true
+}
+
+func (e *LaunchEvent) GetLaunch() *types.Launch {
+	e.Launch.ProtocolId = e.Pair.ProtocolId
+	e.Launch.BlockAt = e.BlockTime
+	return e.Launch
+}
+
+// GraduateEvent is the graduation of a launched token, its liquidity moved from the curve to Launch.Pool.
+type GraduateEvent struct {
+	*types.EventCommon
+	Launch *types.Launch
+}
+
+func (e *GraduateEvent) GetLaunch() *types.Launch {
+	e.Launch.ProtocolId = e.Pair.ProtocolId
+	e.Launch.GraduatedAt = e.BlockTime
+	return e.Launch
+}
+
+/*
+CurveTradeEvent is a buy or sell on the curve of a launched token, against the native token.
+QuoteAmountWei is what the trader paid or got before the fee of the launchpad was taken.
+*/
+type CurveTradeEvent struct {
+	*types.EventCommon
+	types.SwapParties
+	IsBuy          bool
+	TokenAmountWei *big.Int
+	QuoteAmountWei *big.Int
+}
+
+func (e *CurveTradeEvent) CanGetTx() bool {
+	return true
+}
+
+func (e *CurveTradeEvent) GetTx(baseTokens *types.BaseTokens, bnbPrice decimal.Decimal) *orm.Tx {
+	tx := &orm.Tx{
+		TxHash:        e.TxHash.String(),
+		Maker:         e.Maker.String(),
+		Token0Address: e.Pair.Token0Core.Address.String(),
+		Token1Address: e.Pair.Token1Core.Address.String(),
+		Block:         e.BlockNumber,
+		BlockAt:       e.BlockTime,
+		BlockIndex:    e.TxIndex,
+		TxIndex:       e.LogIndex,
+		PairAddress:   e.Pair.Address.String(),
+		Program:       types.GetProtocolName(e.Pair.ProtocolId),
+	}
+
+	tx.Token0Amount = decimal.NewFromBigInt(e.TokenAmountWei, -int32(e.Pair.Token0Core.Decimals))
+	tx.Token1Amount = decimal.NewFromBigInt(e.QuoteAmountWei, -int32(e.Pair.Token1Core.Decimals))
+	if e.IsBuy {
+		tx.Event = types.Buy
+	} else {
+		tx.Event = types.Sell
+	}
+
+	tx.AmountUsd, tx.PriceUsd = CalcAmountAndPrice(baseTokens, bnbPrice, tx.Token0Amount, tx.Token1Amount, e.Pair.Token1Core.Address)
+	e.TxMeta.FillOrmTx(tx)
+	e.UserOperation.FillOrmTx(tx)
+	e.SwapParties.FillOrmTx(tx)
+	return tx
+}
+
+var (
+	_ types.Event       = (*LaunchEvent)(nil)
+	_ types.LaunchEvent = (*LaunchEvent)(nil)
+	_ types.Event       = (*GraduateEvent)(nil)
+	_ types.LaunchEvent = (*GraduateEvent)(nil)
+	_ types.Event       = (*CurveTradeEvent)(nil)
+)
@@ -11,6 +11,8 @@ type MintEvent struct {
 	*types.EventCommon
 	Amount0Wei *big.Int
 	Amount1Wei *big.Int
+	// v3 only
+	Range *types.LiquidityRange
 }
 
 func (e *MintEvent) GetMintAmount() (decimal.Decimal, decimal.Decimal) {
@@ -21,7 +23,7 @@ func (e *MintEvent) CanGetTx() bool {
 	return true
 }
 
-func (e *MintEvent) GetTx(bnbPrice decimal.Decimal) *orm.Tx {
+func (e *MintEvent) GetTx(baseTokens *types.BaseTokens, bnbPrice decimal.Decimal) *orm.Tx {
 	tx := &orm.Tx{
 		TxHash:        e.TxHash.String(),
 		Event:         types.Add,
@@ -37,7 +39,10 @@ func (e *MintEvent) GetTx(bnbPrice decimal.Decimal) *orm.Tx {
 	}
 
 	tx.Token0Amount, tx.Token1Amount = ParseAmountsByPair(e.Amount0Wei, e.Amount1Wei, e.Pair)
-	tx.AmountUsd, tx.PriceUsd = CalcAmountAndPrice(bnbPrice, tx.Token0Amount, tx.Token1Amount, e.Pair.Token1Core.Address)
+	tx.AmountUsd, tx.PriceUsd = CalcAmountAndPrice(baseTokens, bnbPrice, tx.Token0Amount, tx.Token1Amount, e.Pair.Token1Core.Address)
+	e.TxMeta.FillOrmTx(tx)
+	e.UserOperation.FillOrmTx(tx)
+	e.Range.FillOrmTx(tx)
 	return tx
 }
 
@@ -45,4 +50,21 @@ func (e *MintEvent) IsMint() bool {
 	return true
 }
 
+func (e *MintEvent) SetPositionId(positionId *big.Int) {
+	if e.Range != nil {
+		e.Range.PositionId = positionId
+	}
+}
+
+func (e *MintEvent) CanGetPoolStateUpdate() bool {
+	return e.Range != nil
+}
+
+func (e *MintEvent) GetPoolStateUpdate() *types.PoolStateUpdate {
+	u := types.NewPoolStateUpdate(types.PoolStateMint, e.Pair, e.LogIndex)
+	u.Liquidity, u.TickLower, u.TickUpper = e.Range.Liquidity, e.Range.TickLower, e.Range.TickUpper
+	return u
+}
+
 var _ types.Event = (*MintEvent)(nil)
+var _ types.PositionIdSetter = (*MintEvent)(nil)
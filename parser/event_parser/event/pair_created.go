@@ -5,6 +5,9 @@ import "base_scan/types"
 type PairCreatedEvent struct {
 	*types.EventCommon
 	MintEvent types.Event
+	// v3 only
+	Fee         uint32
+	TickSpacing int32
 }
 
 func (e *PairCreatedEvent) CanGetPair() bool {
@@ -27,4 +30,28 @@ func (e *PairCreatedEvent) LinkEvent(event types.Event) { // for pair initial to
 	e.MintEvent = event
 }
 
+func (e *PairCreatedEvent) GetFirstLiquidity() *types.FirstLiquidity {
+	mint, ok := e.MintEvent.(types.TxMakerGetter)
+	if !ok {
+		return nil
+	}
+
+	return &types.FirstLiquidity{
+		Pair:    e.GetPair(),
+		Creator: mint.GetMaker(),
+		TxHash:  mint.GetTxHash(),
+	}
+}
+
+func (e *PairCreatedEvent) CanGetPoolStateUpdate() bool {
+	return e.TickSpacing != 0
+}
+
+func (e *PairCreatedEvent) GetPoolStateUpdate() *types.PoolStateUpdate {
+	u := types.NewPoolStateUpdate(types.PoolStateCreate, e.Pair, e.LogIndex)
+	u.Fee, u.TickSpacing = e.Fee, e.TickSpacing
+	return u
+}
+
 var _ types.Event = (*PairCreatedEvent)(nil)
+var _ types.FirstLiquidityEvent = (*PairCreatedEvent)(nil)
@@ -0,0 +1,87 @@
+package event
+
+import (
+	"base_scan/repository/orm"
+	"base_scan/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"math/big"
+)
+
+/*
+PoolSwapEvent is a swap of a curve or balancer pool, a trade of the pair of the two tokens it exchanges.
+The tokens of curve swaps are known by their coin index in the pool, SoldId and BoughtId, until they are resolved,
+the ones of balancer swaps are in the event.
+*/
+type PoolSwapEvent struct {
+	*types.EventCommon
+	types.SwapParties
+	Pool         *types.Pool
+	SoldId       int
+	BoughtId     int
+	TokenIn      common.Address
+	TokenOut     common.Address
+	AmountInWei  *big.Int
+	AmountOutWei *big.Int
+}
+
+func (e *PoolSwapEvent) GetPool() *types.Pool {
+	return e.Pool
+}
+
+func (e *PoolSwapEvent) ResolveTokens(pool *types.Pool) (common.Address, common.Address, bool) {
+	if e.TokenIn == types.ZeroAddress {
+		tokenIn, okIn := pool.TokenAt(e.SoldId)
+		tokenOut, okOut := pool.TokenAt(e.BoughtId)
+		if !okIn || !okOut {
+			return types.ZeroAddress, types.ZeroAddress, false
+		}
+		e.TokenIn, e.TokenOut = tokenIn, tokenOut
+	}
+
+	if e.TokenIn == e.TokenOut || !pool.HasToken(e.TokenIn) || !pool.HasToken(e.TokenOut) {
+		return types.ZeroAddress, types.ZeroAddress, false
+	}
+	return e.TokenIn, e.TokenOut, true
+}
+
+func (e *PoolSwapEvent) CanGetTx() bool {
+	return true
+}
+
+func (e *PoolSwapEvent) GetTx(baseTokens *types.BaseTokens, bnbPrice decimal.Decimal) *orm.Tx {
+	tx := &orm.Tx{
+		TxHash:        e.TxHash.String(),
+		Maker:         e.Maker.String(),
+		Token0Address: e.Pair.Token0Core.Address.String(),
+		Token1Address: e.Pair.Token1Core.Address.String(),
+		Block:         e.BlockNumber,
+		BlockAt:       e.BlockTime,
+		BlockIndex:    e.TxIndex,
+		TxIndex:       e.LogIndex,
+		PairAddress:   e.Pair.Address.String(),
+		Program:       types.GetProtocolName(e.Pair.ProtocolId),
+	}
+
+	token0Decimals, token1Decimals := -int32(e.Pair.Token0Core.Decimals), -int32(e.Pair.Token1Core.Decimals)
+	if e.TokenIn == e.Pair.Token0Core.Address {
+		tx.Token0Amount = decimal.NewFromBigInt(e.AmountInWei, token0Decimals)
+		tx.Token1Amount = decimal.NewFromBigInt(e.AmountOutWei, token1Decimals)
+		tx.Event = types.Sell
+	} else {
+		tx.Token0Amount = decimal.NewFromBigInt(e.AmountOutWei, token0Decimals)
+		tx.Token1Amount = decimal.NewFromBigInt(e.AmountInWei, token1Decimals)
+		tx.Event = types.Buy
+	}
+
+	tx.AmountUsd, tx.PriceUsd = CalcAmountAndPrice(baseTokens, bnbPrice, tx.Token0Amount, tx.Token1Amount, e.Pair.Token1Core.Address)
+	e.TxMeta.FillOrmTx(tx)
+	e.UserOperation.FillOrmTx(tx)
+	e.SwapParties.FillOrmTx(tx)
+	return tx
+}
+
+var (
+	_ types.Event         = (*PoolSwapEvent)(nil)
+	_ types.PoolSwapEvent = (*PoolSwapEvent)(nil)
+)
@@ -9,6 +9,7 @@ import (
 
 type SwapEvent struct {
 	*types.EventCommon
+	types.SwapParties
 	Amount0InWei  *big.Int
 	Amount1InWei  *big.Int
 	Amount0OutWei *big.Int
@@ -19,7 +20,7 @@ func (e *SwapEvent) CanGetTx() bool {
 	return true
 }
 
-func (e *SwapEvent) GetTx(bnbPrice decimal.Decimal) *orm.Tx {
+func (e *SwapEvent) GetTx(baseTokens *types.BaseTokens, bnbPrice decimal.Decimal) *orm.Tx {
 	tx := &orm.Tx{
 		TxHash:        e.TxHash.String(),
 		Maker:         e.Maker.String(),
@@ -50,7 +51,10 @@ func (e *SwapEvent) GetTx(bnbPrice decimal.Decimal) *orm.Tx {
 	} else {
 	}
 
-	tx.AmountUsd, tx.PriceUsd = CalcAmountAndPrice(bnbPrice, tx.Token0Amount, tx.Token1Amount, e.Pair.Token1Core.Address)
+	tx.AmountUsd, tx.PriceUsd = CalcAmountAndPrice(baseTokens, bnbPrice, tx.Token0Amount, tx.Token1Amount, e.Pair.Token1Core.Address)
+	e.TxMeta.FillOrmTx(tx)
+	e.UserOperation.FillOrmTx(tx)
+	e.SwapParties.FillOrmTx(tx)
 	return tx
 }
 
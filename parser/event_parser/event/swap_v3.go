@@ -9,15 +9,20 @@ import (
 
 type SwapEventV3 struct {
 	*types.EventCommon
+	types.SwapParties
 	Amount0Wei *big.Int
 	Amount1Wei *big.Int
+	// state of the pool after the swap
+	SqrtPriceX96 *big.Int
+	Liquidity    *big.Int
+	Tick         int32
 }
 
 func (e *SwapEventV3) CanGetTx() bool {
 	return true
 }
 
-func (e *SwapEventV3) GetTx(bnbPrice decimal.Decimal) *orm.Tx {
+func (e *SwapEventV3) GetTx(baseTokens *types.BaseTokens, bnbPrice decimal.Decimal) *orm.Tx {
 	tx := &orm.Tx{
 		TxHash:        e.TxHash.String(),
 		Maker:         e.Maker.String(),
@@ -41,7 +46,10 @@ func (e *SwapEventV3) GetTx(bnbPrice decimal.Decimal) *orm.Tx {
 	} else {
 	}
 
-	tx.AmountUsd, tx.PriceUsd = CalcAmountAndPrice(bnbPrice, tx.Token0Amount, tx.Token1Amount, e.Pair.Token1Core.Address)
+	tx.AmountUsd, tx.PriceUsd = CalcAmountAndPrice(baseTokens, bnbPrice, tx.Token0Amount, tx.Token1Amount, e.Pair.Token1Core.Address)
+	e.TxMeta.FillOrmTx(tx)
+	e.UserOperation.FillOrmTx(tx)
+	e.SwapParties.FillOrmTx(tx)
 	return tx
 }
 
@@ -58,4 +66,14 @@ func (e *SwapEventV3) GetPoolUpdateParameter() *types.PoolUpdateParameter {
 	}
 }
 
+func (e *SwapEventV3) CanGetPoolStateUpdate() bool {
+	return true
+}
+
+func (e *SwapEventV3) GetPoolStateUpdate() *types.PoolStateUpdate {
+	u := types.NewPoolStateUpdate(types.PoolStateSwap, e.Pair, e.LogIndex)
+	u.SqrtPriceX96, u.Liquidity, u.Tick = e.SqrtPriceX96, e.Liquidity, e.Tick
+	return u
+}
+
 var _ types.Event = (*SwapEventV3)(nil)
@@ -29,4 +29,14 @@ func (e *SyncEvent) GetPoolUpdate() *types.PoolUpdate {
 	return pu
 }
 
+func (e *SyncEvent) CanGetPoolStateUpdate() bool {
+	return true
+}
+
+func (e *SyncEvent) GetPoolStateUpdate() *types.PoolStateUpdate {
+	u := types.NewPoolStateUpdate(types.PoolStateSync, e.Pair, e.LogIndex)
+	u.Reserve0, u.Reserve1 = e.Amount0Wei, e.Amount1Wei
+	return u
+}
+
 var _ types.Event = (*SyncEvent)(nil)
@@ -19,16 +19,17 @@ func ParseAmountsByPair(token0AmountWei, token1AmountWei *big.Int, pair *types.P
 }
 
 func CalcAmountAndPrice(
+	baseTokens *types.BaseTokens,
 	bnbPrice decimal.Decimal,
 	token0Amount, token1Amount decimal.Decimal,
 	token1Address common.Address,
 ) (amountUSD, priceUSD decimal.Decimal) {
-	if types.IsWETH(token1Address) {
+	if baseTokens.IsNative(token1Address) {
 		amountUSD = token1Amount.Mul(bnbPrice)
 		if !token0Amount.IsZero() {
 			priceUSD = amountUSD.Div(token0Amount)
 		}
-	} else if types.IsUSDC(token1Address) {
+	} else if baseTokens.IsStable(token1Address) {
 		amountUSD = token1Amount
 		if !token0Amount.IsZero() {
 			priceUSD = amountUSD.Div(token0Amount)
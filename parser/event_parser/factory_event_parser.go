@@ -1,11 +1,13 @@
 package event_parser
 
 import (
+	"base_scan/types"
 	"github.com/ethereum/go-ethereum/common"
 )
 
 type FactoryEventParser struct {
-	Topic                    common.Hash
-	PossibleFactoryAddresses map[common.Address]struct{}
-	LogUnpacker              EthLogUnpacker
+	Topic              common.Hash
+	FactoryProtocolIds map[common.Address]int
+	BaseTokens         *types.BaseTokens
+	LogUnpacker        EthLogUnpacker
 }
@@ -3,17 +3,22 @@ package event_parser
 import (
 	"base_scan/abi"
 	"base_scan/abi/aerodrome"
+	"base_scan/abi/balancer"
+	"base_scan/abi/curve"
 	pancakev3 "base_scan/abi/pancake/v3"
 	uniswapv2 "base_scan/abi/uniswap/v2"
 	uniswapv3 "base_scan/abi/uniswap/v3"
+	"base_scan/abi/wow"
+	"base_scan/types"
 	"github.com/ethereum/go-ethereum/common"
 )
 
 var (
 	pairCreatedEventParser = &PairCreatedEventParser{
 		FactoryEventParser: FactoryEventParser{
-			Topic:                    uniswapv2.PairCreatedTopic0,
-			PossibleFactoryAddresses: abi.Topic2FactoryAddresses[uniswapv2.PairCreatedTopic0],
+			Topic:              uniswapv2.PairCreatedTopic0,
+			FactoryProtocolIds: abi.FactoryProtocolIdsOfTopic(uniswapv2.PairCreatedTopic0, abi.BaseFactoryProtocolIds),
+			BaseTokens:         types.DefaultBaseTokens,
 			LogUnpacker: EthLogUnpacker{
 				AbiEvent:      uniswapv2.PairCreatedEvent,
 				TopicLen:      3,
@@ -22,16 +27,58 @@ var (
 		},
 	}
 
-	poolCreatedEventParserAerodrome = &PairCreatedEventParser{
+	poolCreatedEventParserAerodrome = &PoolCreatedEventParserAerodrome{
+		PairCreatedEventParser: PairCreatedEventParser{
+			FactoryEventParser: FactoryEventParser{
+				Topic:              aerodrome.PoolCreatedTopic0,
+				FactoryProtocolIds: abi.FactoryProtocolIdsOfTopic(aerodrome.PoolCreatedTopic0, abi.BaseFactoryProtocolIds),
+				BaseTokens:         types.DefaultBaseTokens,
+				LogUnpacker: EthLogUnpacker{
+					AbiEvent:      aerodrome.PoolCreatedEvent,
+					TopicLen:      4,
+					DataUnpackLen: 2,
+				},
+			},
+		},
+	}
+
+	setCustomFeeEventParserAerodrome = &SetCustomFeeEventParser{
 		FactoryEventParser: FactoryEventParser{
-			Topic:                    aerodrome.PoolCreatedTopic0,
-			PossibleFactoryAddresses: abi.Topic2FactoryAddresses[aerodrome.PoolCreatedTopic0],
+			Topic:              aerodrome.SetCustomFeeTopic0,
+			FactoryProtocolIds: abi.FactoryProtocolIdsOfTopic(aerodrome.SetCustomFeeTopic0, abi.BaseFactoryProtocolIds),
+			BaseTokens:         types.DefaultBaseTokens,
 			LogUnpacker: EthLogUnpacker{
-				AbiEvent:      aerodrome.PoolCreatedEvent,
-				TopicLen:      4,
+				AbiEvent:      aerodrome.SetCustomFeeEvent,
+				TopicLen:      2,
+				DataUnpackLen: 1,
+			},
+		},
+	}
+
+	feesEventParserAerodrome = &PoolFeeEventParser{
+		PoolEventParser: PoolEventParser{
+			Topic:               aerodrome.FeesTopic0,
+			PossibleProtocolIds: abi.Topic2ProtocolIds[aerodrome.FeesTopic0],
+			ethLogUnpacker: EthLogUnpacker{
+				AbiEvent:      aerodrome.FeesEvent,
+				TopicLen:      2,
 				DataUnpackLen: 2,
 			},
 		},
+		Kind: types.PoolFeeAccrue,
+	}
+
+	claimEventParserAerodrome = &PoolFeeEventParser{
+		PoolEventParser: PoolEventParser{
+			Topic:               aerodrome.ClaimTopic0,
+			PossibleProtocolIds: abi.Topic2ProtocolIds[aerodrome.ClaimTopic0],
+			ethLogUnpacker: EthLogUnpacker{
+				AbiEvent:      aerodrome.ClaimEvent,
+				TopicLen:      3,
+				DataUnpackLen: 2,
+			},
+		},
+		Kind: types.PoolFeeClaim,
 	}
 
 	burnEventParser = &BurnEventParser{
@@ -118,6 +165,7 @@ var (
 		},
 	}
 
+	// Topic2EventParser parses the events of the factories deployed on base
 	Topic2EventParser = map[common.Hash]EventParser{
 		uniswapv2.PairCreatedTopic0: pairCreatedEventParser,
 		uniswapv2.MintTopic0:        mintEventParser,
@@ -127,8 +175,9 @@ var (
 
 		uniswapv3.PoolCreatedTopic0: &PoolCreatedEventParser{
 			FactoryEventParser: FactoryEventParser{
-				Topic:                    uniswapv3.PoolCreatedTopic0,
-				PossibleFactoryAddresses: abi.Topic2FactoryAddresses[uniswapv3.PoolCreatedTopic0],
+				Topic:              uniswapv3.PoolCreatedTopic0,
+				FactoryProtocolIds: abi.FactoryProtocolIdsOfTopic(uniswapv3.PoolCreatedTopic0, abi.BaseFactoryProtocolIds),
+				BaseTokens:         types.DefaultBaseTokens,
 				LogUnpacker: EthLogUnpacker{
 					AbiEvent:      uniswapv3.PoolCreatedEvent,
 					TopicLen:      4,
@@ -158,6 +207,17 @@ var (
 				},
 			},
 		},
+		uniswapv3.InitializeTopic0: &InitializeEventParserV3{
+			PoolEventParser: PoolEventParser{
+				Topic:               uniswapv3.InitializeTopic0,
+				PossibleProtocolIds: abi.Topic2ProtocolIds[uniswapv3.InitializeTopic0],
+				ethLogUnpacker: EthLogUnpacker{
+					AbiEvent:      uniswapv3.InitializeEvent,
+					TopicLen:      1,
+					DataUnpackLen: 2,
+				},
+			},
+		},
 		uniswapv3.SwapTopic0: &SwapEventParserV3{
 			PoolEventParser: PoolEventParser{
 				Topic:               uniswapv3.SwapTopic0,
@@ -182,9 +242,145 @@ var (
 			},
 		},
 
-		aerodrome.PoolCreatedTopic0: poolCreatedEventParserAerodrome,
-		aerodrome.BurnTopic0:        burnEventParserAerodrome,
-		aerodrome.SwapTopic0:        swapEventParserAerodrome,
-		aerodrome.SyncTopic0:        syncEventParserAerodrome,
+		aerodrome.PoolCreatedTopic0:  poolCreatedEventParserAerodrome,
+		aerodrome.BurnTopic0:         burnEventParserAerodrome,
+		aerodrome.SwapTopic0:         swapEventParserAerodrome,
+		aerodrome.SyncTopic0:         syncEventParserAerodrome,
+		aerodrome.SetCustomFeeTopic0: setCustomFeeEventParserAerodrome,
+		aerodrome.FeesTopic0:         feesEventParserAerodrome,
+		aerodrome.ClaimTopic0:        claimEventParserAerodrome,
+
+		curve.TokenExchangeTopic0: &TokenExchangeEventParser{
+			PoolEventParser: PoolEventParser{
+				Topic:               curve.TokenExchangeTopic0,
+				PossibleProtocolIds: abi.Topic2ProtocolIds[curve.TokenExchangeTopic0],
+				ethLogUnpacker: EthLogUnpacker{
+					AbiEvent:      curve.TokenExchangeEvent,
+					TopicLen:      2,
+					DataUnpackLen: 4,
+				},
+			},
+		},
+		curve.TokenExchangeV2Topic0: &TokenExchangeEventParser{
+			PoolEventParser: PoolEventParser{
+				Topic:               curve.TokenExchangeV2Topic0,
+				PossibleProtocolIds: abi.Topic2ProtocolIds[curve.TokenExchangeV2Topic0],
+				ethLogUnpacker: EthLogUnpacker{
+					AbiEvent:      curve.TokenExchangeV2Event,
+					TopicLen:      2,
+					DataUnpackLen: 4,
+				},
+			},
+		},
+		curve.TokenExchangeNGTopic0: &TokenExchangeEventParser{
+			PoolEventParser: PoolEventParser{
+				Topic:               curve.TokenExchangeNGTopic0,
+				PossibleProtocolIds: abi.Topic2ProtocolIds[curve.TokenExchangeNGTopic0],
+				ethLogUnpacker: EthLogUnpacker{
+					AbiEvent:      curve.TokenExchangeNGEvent,
+					TopicLen:      2,
+					DataUnpackLen: 6,
+				},
+			},
+		},
+
+		balancer.SwapTopic0: &BalancerSwapEventParser{
+			PoolEventParser: PoolEventParser{
+				Topic:               balancer.SwapTopic0,
+				PossibleProtocolIds: abi.Topic2ProtocolIds[balancer.SwapTopic0],
+				ethLogUnpacker: EthLogUnpacker{
+					AbiEvent:      balancer.SwapEvent,
+					TopicLen:      4,
+					DataUnpackLen: 2,
+				},
+			},
+		},
+
+		wow.WowTokenCreatedTopic0: &WowTokenCreatedEventParser{
+			FactoryEventParser: FactoryEventParser{
+				Topic:              wow.WowTokenCreatedTopic0,
+				FactoryProtocolIds: abi.FactoryProtocolIdsOfTopic(wow.WowTokenCreatedTopic0, abi.BaseFactoryProtocolIds),
+				BaseTokens:         types.DefaultBaseTokens,
+				LogUnpacker: EthLogUnpacker{
+					AbiEvent:      wow.WowTokenCreatedEvent,
+					TopicLen:      3,
+					DataUnpackLen: 8,
+				},
+			},
+		},
+		wow.WowTokenBuyTopic0: &WowTradeEventParser{
+			PoolEventParser: PoolEventParser{
+				Topic:               wow.WowTokenBuyTopic0,
+				PossibleProtocolIds: abi.Topic2ProtocolIds[wow.WowTokenBuyTopic0],
+				ethLogUnpacker: EthLogUnpacker{
+					AbiEvent:      wow.WowTokenBuyEvent,
+					TopicLen:      4,
+					DataUnpackLen: 8,
+				},
+			},
+			IsBuy: true,
+		},
+		wow.WowTokenSellTopic0: &WowTradeEventParser{
+			PoolEventParser: PoolEventParser{
+				Topic:               wow.WowTokenSellTopic0,
+				PossibleProtocolIds: abi.Topic2ProtocolIds[wow.WowTokenSellTopic0],
+				ethLogUnpacker: EthLogUnpacker{
+					AbiEvent:      wow.WowTokenSellEvent,
+					TopicLen:      4,
+					DataUnpackLen: 8,
+				},
+			},
+		},
+		wow.WowMarketGraduatedTopic0: &WowMarketGraduatedEventParser{
+			PoolEventParser: PoolEventParser{
+				Topic:               wow.WowMarketGraduatedTopic0,
+				PossibleProtocolIds: abi.Topic2ProtocolIds[wow.WowMarketGraduatedTopic0],
+				ethLogUnpacker: EthLogUnpacker{
+					AbiEvent:      wow.WowMarketGraduatedEvent,
+					TopicLen:      3,
+					DataUnpackLen: 4,
+				},
+			},
+		},
 	}
 )
+
+/*
+NewTopic2EventParser returns Topic2EventParser with the factory event parsers
+accepting the given factories and filtering by the given base tokens instead of the ones of base.
+*/
+func NewTopic2EventParser(factoryProtocolIds map[common.Address]int, baseTokens *types.BaseTokens) map[common.Hash]EventParser {
+	topic2EventParser := make(map[common.Hash]EventParser, len(Topic2EventParser))
+	for topic, eventParser := range Topic2EventParser {
+		switch p := eventParser.(type) {
+		case *PairCreatedEventParser:
+			c := *p
+			c.FactoryProtocolIds = abi.FactoryProtocolIdsOfTopic(topic, factoryProtocolIds)
+			c.BaseTokens = baseTokens
+			topic2EventParser[topic] = &c
+		case *PoolCreatedEventParser:
+			c := *p
+			c.FactoryProtocolIds = abi.FactoryProtocolIdsOfTopic(topic, factoryProtocolIds)
+			c.BaseTokens = baseTokens
+			topic2EventParser[topic] = &c
+		case *PoolCreatedEventParserAerodrome:
+			c := *p
+			c.FactoryProtocolIds = abi.FactoryProtocolIdsOfTopic(topic, factoryProtocolIds)
+			c.BaseTokens = baseTokens
+			topic2EventParser[topic] = &c
+		case *SetCustomFeeEventParser:
+			c := *p
+			c.FactoryProtocolIds = abi.FactoryProtocolIdsOfTopic(topic, factoryProtocolIds)
+			c.BaseTokens = baseTokens
+			topic2EventParser[topic] = &c
+		case *WowTokenCreatedEventParser:
+			c := *p
+			c.FactoryProtocolIds = abi.FactoryProtocolIdsOfTopic(topic, factoryProtocolIds)
+			c.BaseTokens = baseTokens
+			topic2EventParser[topic] = &c
+		default:
+			topic2EventParser[topic] = eventParser
+		}
+	}
+	return topic2EventParser
+}
@@ -0,0 +1,33 @@
+package event_parser
+
+import (
+	"base_scan/parser/event_parser/event"
+	"base_scan/types"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+)
+
+type InitializeEventParserV3 struct {
+	PoolEventParser
+}
+
+func (o *InitializeEventParserV3) Parse(ethLog *ethtypes.Log) (types.Event, error) {
+	input, err := o.ethLogUnpacker.Unpack(ethLog)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &event.InitializeEvent{
+		EventCommon:  types.EventCommonFromEthLog(ethLog),
+		SqrtPriceX96: input[0].(*big.Int),
+		Tick:         int32(input[1].(*big.Int).Int64()),
+	}
+
+	e.Pair = &types.Pair{
+		Address: ethLog.Address,
+	}
+
+	e.PossibleProtocolIds = o.PossibleProtocolIds
+
+	return e, nil
+}
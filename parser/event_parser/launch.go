@@ -0,0 +1,144 @@
+package event_parser
+
+import (
+	"base_scan/abi/wow"
+	"base_scan/parser/event_parser/event"
+	"base_scan/types"
+	"errors"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+)
+
+var (
+	errNotBondingCurve = errors.New("not a trade of the bonding curve")
+	errNotOwnToken     = errors.New("not emitted by the token")
+)
+
+// newCurvePair is the pair of the curve of a launched token, the curve address is the token itself.
+func newCurvePair(token, quoteToken common.Address, block uint64, protocolId int) *types.Pair {
+	token0, token1 := token, quoteToken
+	if token0.Cmp(token1) > 0 {
+		token0, token1 = token1, token0
+	}
+	return &types.Pair{
+		Address:    token,
+		Token0Core: &types.TokenCore{Address: token0},
+		Token1Core: &types.TokenCore{Address: token1},
+		Block:      block,
+		ProtocolId: protocolId,
+	}
+}
+
+/*
+WowTokenCreatedEventParser parses the launches of the wow factory, the curve is quoted in the native token of the chain,
+the uniswap v3 pool of the token is created with it and indexed as any pool.
+*/
+type WowTokenCreatedEventParser struct {
+	FactoryEventParser
+}
+
+func (o *WowTokenCreatedEventParser) Parse(ethLog *ethtypes.Log) (types.Event, error) {
+	protocolId, ok := o.FactoryProtocolIds[ethLog.Address]
+	if !ok {
+		return nil, ErrWrongFactoryAddress
+	}
+
+	input, err := o.LogUnpacker.Unpack(ethLog)
+	if err != nil {
+		return nil, err
+	}
+
+	token := input[6].(common.Address)
+	e := &event.LaunchEvent{
+		EventCommon: types.EventCommonFromEthLog(ethLog),
+		Launch: &types.Launch{
+			Token:   token,
+			Curve:   token,
+			Creator: common.BytesToAddress(ethLog.Topics[2].Bytes()[12:]),
+			Pool:    input[7].(common.Address),
+			Block:   ethLog.BlockNumber,
+		},
+	}
+	e.Pair = newCurvePair(token, o.BaseTokens.Native, ethLog.BlockNumber, protocolId)
+
+	return e, nil
+}
+
+/*
+WowTradeEventParser parses the WowTokenBuy and WowTokenSell of the wow tokens,
+only the trades of the bonding curve, the ones of the uniswap market are the swaps of its pool.
+*/
+type WowTradeEventParser struct {
+	PoolEventParser
+	IsBuy bool
+}
+
+func (o *WowTradeEventParser) Parse(ethLog *ethtypes.Log) (types.Event, error) {
+	input, err := o.ethLogUnpacker.Unpack(ethLog)
+	if err != nil {
+		return nil, err
+	}
+
+	if input[7].(uint8) != wow.MarketTypeBondingCurve {
+		return nil, errNotBondingCurve
+	}
+
+	e := &event.CurveTradeEvent{
+		EventCommon:    types.EventCommonFromEthLog(ethLog),
+		SwapParties:    types.SwapPartiesFromTopics(ethLog.Topics),
+		IsBuy:          o.IsBuy,
+		QuoteAmountWei: input[0].(*big.Int),
+		TokenAmountWei: input[3].(*big.Int),
+	}
+
+	if e.QuoteAmountWei.Sign() == 0 {
+		return nil, errAmountInZero
+	}
+
+	if e.TokenAmountWei.Sign() == 0 {
+		return nil, errAmountOutZero
+	}
+
+	e.Pair = &types.Pair{
+		Address: ethLog.Address,
+	}
+
+	e.PossibleProtocolIds = o.PossibleProtocolIds
+
+	return e, nil
+}
+
+// WowMarketGraduatedEventParser parses the graduation of a wow token to its uniswap v3 pool.
+type WowMarketGraduatedEventParser struct {
+	PoolEventParser
+}
+
+func (o *WowMarketGraduatedEventParser) Parse(ethLog *ethtypes.Log) (types.Event, error) {
+	if _, err := o.ethLogUnpacker.Unpack(ethLog); err != nil {
+		return nil, err
+	}
+
+	token := common.BytesToAddress(ethLog.Topics[1].Bytes()[12:])
+	if token != ethLog.Address {
+		return nil, errNotOwnToken
+	}
+
+	e := &event.GraduateEvent{
+		EventCommon: types.EventCommonFromEthLog(ethLog),
+		Launch: &types.Launch{
+			Token:          token,
+			Pool:           common.BytesToAddress(ethLog.Topics[2].Bytes()[12:]),
+			Graduated:      true,
+			GraduatedBlock: ethLog.BlockNumber,
+		},
+	}
+
+	e.Pair = &types.Pair{
+		Address: ethLog.Address,
+	}
+
+	e.PossibleProtocolIds = o.PossibleProtocolIds
+
+	return e, nil
+}
@@ -0,0 +1,133 @@
+package event_parser
+
+import (
+	"base_scan/abi/wow"
+	"base_scan/parser/event_parser/event"
+	"base_scan/types"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+)
+
+var testLaunchPool = common.HexToAddress("0x00000000000000000000000000000000000000f2")
+
+func wowTradeLog(t *testing.T, topic common.Hash, marketType uint8) *ethtypes.Log {
+	abiEvent := wow.WowTokenBuyEvent
+	if topic == wow.WowTokenSellTopic0 {
+		abiEvent = wow.WowTokenSellEvent
+	}
+	data, err := abiEvent.Inputs.NonIndexed().Pack(
+		big.NewInt(2e18), big.NewInt(2e16), big.NewInt(198e16), big.NewInt(0).Mul(big.NewInt(1e18), big.NewInt(1000)),
+		big.NewInt(0), "gm", big.NewInt(0), marketType,
+	)
+	require.NoError(t, err)
+	return &ethtypes.Log{
+		Address: testToken,
+		Topics:  []common.Hash{topic, common.BytesToHash(testBuyer.Bytes()), common.BytesToHash(testBuyer.Bytes()), {}},
+		Data:    data,
+	}
+}
+
+func TestWowTokenCreated(t *testing.T) {
+	data, err := wow.WowTokenCreatedEvent.Inputs.NonIndexed().Pack(
+		common.Address{}, common.Address{}, common.Address{}, "ipfs://", "Test", "T", testToken, testLaunchPool,
+	)
+	require.NoError(t, err)
+	ethLog := &ethtypes.Log{
+		Address:     wow.FactoryAddress,
+		Topics:      []common.Hash{wow.WowTokenCreatedTopic0, common.BytesToHash(wow.FactoryAddress.Bytes()), common.BytesToHash(testBuyer.Bytes())},
+		Data:        data,
+		BlockNumber: 100,
+	}
+
+	e, err := Topic2EventParser[wow.WowTokenCreatedTopic0].Parse(ethLog)
+	require.NoError(t, err)
+	require.True(t, e.IsCreatePair())
+	pair := e.GetPair()
+	require.Equal(t, testToken, pair.Address)
+	require.Equal(t, testToken, pair.Token0Core.Address)
+	require.Equal(t, types.WETHAddress, pair.Token1Core.Address)
+	require.Equal(t, types.ProtocolIdWow, pair.ProtocolId)
+
+	launch := e.(types.LaunchEvent).GetLaunch()
+	require.Equal(t, &types.Launch{
+		Token:      testToken,
+		ProtocolId: types.ProtocolIdWow,
+		Curve:      testToken,
+		Creator:    testBuyer,
+		Pool:       testLaunchPool,
+		Block:      100,
+	}, launch)
+
+	ethLog.Address = testPool
+	_, err = Topic2EventParser[wow.WowTokenCreatedTopic0].Parse(ethLog)
+	require.ErrorIs(t, err, ErrWrongFactoryAddress)
+
+	// the curve is quoted in the native token of the chain of the parser
+	native := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	parsers := NewTopic2EventParser(map[common.Address]int{wow.FactoryAddress: types.ProtocolIdWow}, &types.BaseTokens{Native: native})
+	ethLog.Address = wow.FactoryAddress
+	e, err = parsers[wow.WowTokenCreatedTopic0].Parse(ethLog)
+	require.NoError(t, err)
+	require.Equal(t, native, e.GetPair().Token0Core.Address)
+	require.Equal(t, testToken, e.GetPair().Token1Core.Address)
+}
+
+func TestWowTrade(t *testing.T) {
+	curvePair := &types.Pair{
+		Address:    testToken,
+		Token0Core: &types.TokenCore{Address: testToken, Symbol: "T", Decimals: 18},
+		Token1Core: &types.TokenCore{Address: types.WETHAddress, Symbol: "WETH", Decimals: 18},
+		ProtocolId: types.ProtocolIdWow,
+	}
+
+	e, err := Topic2EventParser[wow.WowTokenBuyTopic0].Parse(wowTradeLog(t, wow.WowTokenBuyTopic0, wow.MarketTypeBondingCurve))
+	require.NoError(t, err)
+	require.Equal(t, testToken, e.GetPairAddress())
+	require.Equal(t, []int{types.ProtocolIdWow}, e.GetPossibleProtocolIds())
+
+	e.SetPair(curvePair)
+	tx := e.GetTx(types.DefaultBaseTokens, decimal.NewFromInt(3000))
+	require.Equal(t, types.Buy, tx.Event)
+	require.True(t, decimal.NewFromInt(1000).Equal(tx.Token0Amount))
+	require.True(t, decimal.NewFromInt(2).Equal(tx.Token1Amount))
+	require.True(t, decimal.NewFromInt(6000).Equal(tx.AmountUsd))
+	require.True(t, decimal.NewFromInt(6).Equal(tx.PriceUsd))
+	require.Equal(t, types.ProtocolNameWow, tx.Program)
+	require.Equal(t, testBuyer.String(), tx.Sender)
+
+	e, err = Topic2EventParser[wow.WowTokenSellTopic0].Parse(wowTradeLog(t, wow.WowTokenSellTopic0, wow.MarketTypeBondingCurve))
+	require.NoError(t, err)
+	e.SetPair(curvePair)
+	require.Equal(t, types.Sell, e.GetTx(types.DefaultBaseTokens, decimal.NewFromInt(3000)).Event)
+
+	_, err = Topic2EventParser[wow.WowTokenBuyTopic0].Parse(wowTradeLog(t, wow.WowTokenBuyTopic0, wow.MarketTypeUniswapPool))
+	require.ErrorIs(t, err, errNotBondingCurve)
+}
+
+func TestWowMarketGraduated(t *testing.T) {
+	data, err := wow.WowMarketGraduatedEvent.Inputs.NonIndexed().Pack(big.NewInt(4e18), big.NewInt(1e18), big.NewInt(7), wow.MarketTypeUniswapPool)
+	require.NoError(t, err)
+	ethLog := &ethtypes.Log{
+		Address:     testToken,
+		Topics:      []common.Hash{wow.WowMarketGraduatedTopic0, common.BytesToHash(testToken.Bytes()), common.BytesToHash(testLaunchPool.Bytes())},
+		Data:        data,
+		BlockNumber: 200,
+	}
+
+	e, err := Topic2EventParser[wow.WowMarketGraduatedTopic0].Parse(ethLog)
+	require.NoError(t, err)
+	require.False(t, e.CanGetTx())
+	e.SetPair(&types.Pair{Address: testToken, ProtocolId: types.ProtocolIdWow})
+	launch := e.(*event.GraduateEvent).GetLaunch()
+	require.True(t, launch.Graduated)
+	require.Equal(t, testLaunchPool, launch.Pool)
+	require.Equal(t, uint64(200), launch.GraduatedBlock)
+
+	ethLog.Address = testPool
+	_, err = Topic2EventParser[wow.WowMarketGraduatedTopic0].Parse(ethLog)
+	require.ErrorIs(t, err, errNotOwnToken)
+}
@@ -32,7 +32,7 @@ func TestMint_Aerodrome(t *testing.T) {
 	pairWrap := tc.PairService.GetPair(event.GetPairAddress(), event.GetPossibleProtocolIds())
 	event.SetPair(pairWrap.Pair)
 
-	tx := event.GetTx(service.MockNativeTokenPrice)
+	tx := event.GetTx(types.DefaultBaseTokens, service.MockNativeTokenPrice)
 
 	token0Wei := decimal.NewFromBigInt(big.NewInt(1), int32(pairWrap.Pair.Token0Core.Decimals))
 	expectAmt0 := expectAmt0Wei.Div(token0Wei)
@@ -75,7 +75,7 @@ func TestMint_UniswapV2(t *testing.T) {
 	pairWrap := tc.PairService.GetPair(event.GetPairAddress(), event.GetPossibleProtocolIds())
 	event.SetPair(pairWrap.Pair)
 
-	tx := event.GetTx(service.MockNativeTokenPrice)
+	tx := event.GetTx(types.DefaultBaseTokens, service.MockNativeTokenPrice)
 
 	token0Wei := decimal.NewFromBigInt(big.NewInt(1), int32(pairWrap.Pair.Token0Core.Decimals))
 	expectAmt0 := expectAmt0Wei.Div(token0Wei)
@@ -118,7 +118,7 @@ func TestMint_PancakeV2(t *testing.T) {
 	pairWrap := tc.PairService.GetPair(event.GetPairAddress(), event.GetPossibleProtocolIds())
 	event.SetPair(pairWrap.Pair)
 
-	tx := event.GetTx(service.MockNativeTokenPrice)
+	tx := event.GetTx(types.DefaultBaseTokens, service.MockNativeTokenPrice)
 
 	token0Wei := decimal.NewFromBigInt(big.NewInt(1), int32(pairWrap.Pair.Token0Core.Decimals))
 	expectAmt0 := expectAmt0Wei.Div(token0Wei)
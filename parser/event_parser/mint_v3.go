@@ -21,6 +21,7 @@ func (o *MintEventParserV3) Parse(ethLog *ethtypes.Log) (types.Event, error) {
 		EventCommon: types.EventCommonFromEthLog(ethLog),
 		Amount0Wei:  input[2].(*big.Int),
 		Amount1Wei:  input[3].(*big.Int),
+		Range:       types.NewLiquidityRange(ethLog.Topics, input[1].(*big.Int)),
 	}
 
 	e.Pair = &types.Pair{
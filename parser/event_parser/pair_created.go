@@ -1,7 +1,6 @@
 package event_parser
 
 import (
-	"base_scan/abi"
 	"base_scan/parser/event_parser/event"
 	"base_scan/types"
 	"github.com/ethereum/go-ethereum/common"
@@ -15,17 +14,15 @@ type PairCreatedEventParser struct {
 func (o *PairCreatedEventParser) Parse(ethLog *ethtypes.Log) (types.Event, error) {
 	pair := &types.Pair{}
 
-	_, ok := o.PossibleFactoryAddresses[ethLog.Address]
+	protocolId, ok := o.FactoryProtocolIds[ethLog.Address]
 	if !ok {
-		pair.Filtered = true
-		pair.FilterCode = types.FilterCodeWrongFactory
+		pair.Filter(types.FilterCodeWrongFactory, ErrWrongFactoryAddress.Error())
 		return nil, ErrWrongFactoryAddress
 	}
 
 	input, err := o.LogUnpacker.Unpack(ethLog)
 	if err != nil {
-		pair.Filtered = true
-		pair.FilterCode = types.FilterCodeUnpackDataErr
+		pair.Filter(types.FilterCodeUnpackDataErr, err.Error())
 		return nil, err
 	}
 
@@ -41,9 +38,9 @@ func (o *PairCreatedEventParser) Parse(ethLog *ethtypes.Log) (types.Event, error
 		Address: common.BytesToAddress(ethLog.Topics[2].Bytes()[12:]),
 	}
 	pair.Block = ethLog.BlockNumber
-	pair.ProtocolId = abi.FactoryAddress2ProtocolId[ethLog.Address]
+	pair.ProtocolId = protocolId
 
-	pair.FilterByToken0AndToken1()
+	pair.FilterByToken0AndToken1(o.BaseTokens)
 
 	e.EventCommon.Pair = pair
 
@@ -40,9 +40,13 @@ func TestPairCreated_Aerodrome(t *testing.T) {
 		Block:      30217994,
 		BlockAt:    time.Unix(int64(blockTimestamp), 0),
 		ProtocolId: types.ProtocolIdAerodrome,
+		// the fee of aerodrome pools is read from the factory at the latest block, it may have changed since
+		Fee:        pairWrap.Pair.Fee,
+		Version:    types.ProtocolVersionV2,
 		Filtered:   false,
 		FilterCode: 0,
 	}
+	require.NotZero(t, pairWrap.Pair.Fee)
 
 	require.True(t, pairWrap.Pair.Equal(expectPair), "expect: %v, actual: %v", expectPair, pairWrap.Pair)
 }
@@ -78,6 +82,8 @@ func TestPairCreated_UniswapV2(t *testing.T) {
 		Block:      30251018,
 		BlockAt:    time.Unix(int64(blockTimestamp), 0),
 		ProtocolId: types.ProtocolIdUniswapV2,
+		Fee:        3000,
+		Version:    types.ProtocolVersionV2,
 		Filtered:   false,
 		FilterCode: 0,
 	}
@@ -116,6 +122,8 @@ func TestPairCreated_PancakeV2(t *testing.T) {
 		Block:      30230565,
 		BlockAt:    time.Unix(int64(blockTimestamp), 0),
 		ProtocolId: types.ProtocolIdPancakeV2,
+		Fee:        2500,
+		Version:    types.ProtocolVersionV2,
 		Filtered:   false,
 		FilterCode: 0,
 	}
@@ -1,11 +1,11 @@
 package event_parser
 
 import (
-	"base_scan/abi"
 	"base_scan/parser/event_parser/event"
 	"base_scan/types"
 	"github.com/ethereum/go-ethereum/common"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"math/big"
 )
 
 type PoolCreatedEventParser struct {
@@ -15,22 +15,22 @@ type PoolCreatedEventParser struct {
 func (o *PoolCreatedEventParser) Parse(ethLog *ethtypes.Log) (types.Event, error) {
 	pair := &types.Pair{}
 
-	_, ok := o.PossibleFactoryAddresses[ethLog.Address]
+	protocolId, ok := o.FactoryProtocolIds[ethLog.Address]
 	if !ok {
-		pair.Filtered = true
-		pair.FilterCode = types.FilterCodeWrongFactory
+		pair.Filter(types.FilterCodeWrongFactory, ErrWrongFactoryAddress.Error())
 		return nil, ErrWrongFactoryAddress
 	}
 
 	input, err := o.LogUnpacker.Unpack(ethLog)
 	if err != nil {
-		pair.Filtered = true
-		pair.FilterCode = types.FilterCodeUnpackDataErr
+		pair.Filter(types.FilterCodeUnpackDataErr, err.Error())
 		return nil, err
 	}
 
 	e := &event.PairCreatedEvent{
 		EventCommon: types.EventCommonFromEthLog(ethLog),
+		Fee:         uint32(ethLog.Topics[3].Big().Uint64()),
+		TickSpacing: int32(input[0].(*big.Int).Int64()),
 	}
 
 	pair.Address = input[1].(common.Address)
@@ -41,9 +41,11 @@ func (o *PoolCreatedEventParser) Parse(ethLog *ethtypes.Log) (types.Event, error
 		Address: common.BytesToAddress(ethLog.Topics[2].Bytes()[12:]),
 	}
 	pair.Block = ethLog.BlockNumber
-	pair.ProtocolId = abi.FactoryAddress2ProtocolId[ethLog.Address]
+	pair.ProtocolId = protocolId
+	pair.Fee = e.Fee
+	pair.TickSpacing = e.TickSpacing
 
-	pair.FilterByToken0AndToken1()
+	pair.FilterByToken0AndToken1(o.BaseTokens)
 
 	e.Pair = pair
 
@@ -24,6 +24,11 @@ func TestPoolCreated_UniswapV3(t *testing.T) {
 	pairWrap := tc.PairService.GetPairTokens(pair)
 	event.SetPair(pairWrap.Pair)
 
+	// fee and tick spacing of the pool are in the created event
+	update := event.GetPoolStateUpdate()
+	require.NotZero(t, update.Fee)
+	require.NotZero(t, update.TickSpacing)
+
 	expectPair := &types.Pair{
 		Address:        common.HexToAddress("0x2c93555C0150DA726957782e36A12D76D6851064"),
 		TokensReversed: true,
@@ -37,11 +42,14 @@ func TestPoolCreated_UniswapV3(t *testing.T) {
 			Symbol:   "WETH",
 			Decimals: 18,
 		},
-		Block:      30253895,
-		BlockAt:    time.Unix(int64(blockTimestamp), 0),
-		ProtocolId: types.ProtocolIdUniswapV3,
-		Filtered:   false,
-		FilterCode: 0,
+		Block:       30253895,
+		BlockAt:     time.Unix(int64(blockTimestamp), 0),
+		ProtocolId:  types.ProtocolIdUniswapV3,
+		Fee:         update.Fee,
+		TickSpacing: update.TickSpacing,
+		Version:     types.ProtocolVersionV3,
+		Filtered:    false,
+		FilterCode:  0,
 	}
 
 	require.True(t, pairWrap.Pair.Equal(expectPair), "expect: %v, actual: %v", expectPair, pairWrap.Pair)
@@ -62,6 +70,11 @@ func TestPoolCreated_PancakeV3(t *testing.T) {
 	pairWrap := tc.PairService.GetPairTokens(pair)
 	event.SetPair(pairWrap.Pair)
 
+	// fee and tick spacing of the pool are in the created event
+	update := event.GetPoolStateUpdate()
+	require.NotZero(t, update.Fee)
+	require.NotZero(t, update.TickSpacing)
+
 	expectPair := &types.Pair{
 		Address:        common.HexToAddress("0x5845A51630AFab7C68556CF57a7b6827Bd94d434"),
 		TokensReversed: true,
@@ -75,11 +88,14 @@ func TestPoolCreated_PancakeV3(t *testing.T) {
 			Symbol:   "WETH",
 			Decimals: 18,
 		},
-		Block:      30250940,
-		BlockAt:    time.Unix(int64(blockTimestamp), 0),
-		ProtocolId: types.ProtocolIdPancakeV3,
-		Filtered:   false,
-		FilterCode: 0,
+		Block:       30250940,
+		BlockAt:     time.Unix(int64(blockTimestamp), 0),
+		ProtocolId:  types.ProtocolIdPancakeV3,
+		Fee:         update.Fee,
+		TickSpacing: update.TickSpacing,
+		Version:     types.ProtocolVersionV3,
+		Filtered:    false,
+		FilterCode:  0,
 	}
 
 	require.True(t, pairWrap.Pair.Equal(expectPair), "expect: %v, actual: %v", expectPair, pairWrap.Pair)
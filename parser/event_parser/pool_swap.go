@@ -0,0 +1,121 @@
+package event_parser
+
+import (
+	"base_scan/abi/balancer"
+	"base_scan/abi/curve"
+	"base_scan/parser/event_parser/event"
+	"base_scan/types"
+	"errors"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+)
+
+var (
+	errWrongCoinIndex = errors.New("wrong coin index")
+	errNotVault       = errors.New("not emitted by the vault")
+)
+
+func coinIndex(v interface{}) (int, error) {
+	index := v.(*big.Int)
+	if index.Sign() < 0 || index.Cmp(big.NewInt(curve.MaxCoins)) >= 0 {
+		return 0, errWrongCoinIndex
+	}
+	return int(index.Int64()), nil
+}
+
+/*
+TokenExchangeEventParser parses the TokenExchange of curve pools, the buyer is both the sender and the recipient,
+ng pools may send the output to another receiver, which the event doesn't tell.
+*/
+type TokenExchangeEventParser struct {
+	PoolEventParser
+}
+
+func (o *TokenExchangeEventParser) Parse(ethLog *ethtypes.Log) (types.Event, error) {
+	input, err := o.ethLogUnpacker.Unpack(ethLog)
+	if err != nil {
+		return nil, err
+	}
+
+	soldId, err := coinIndex(input[0])
+	if err != nil {
+		return nil, err
+	}
+	boughtId, err := coinIndex(input[2])
+	if err != nil {
+		return nil, err
+	}
+
+	buyer := common.BytesToAddress(ethLog.Topics[1].Bytes()[12:])
+	e := &event.PoolSwapEvent{
+		EventCommon:  types.EventCommonFromEthLog(ethLog),
+		SwapParties:  types.SwapParties{Sender: buyer, Recipient: buyer},
+		Pool:         &types.Pool{Address: ethLog.Address, ProtocolId: types.ProtocolIdCurve},
+		SoldId:       soldId,
+		BoughtId:     boughtId,
+		AmountInWei:  input[1].(*big.Int),
+		AmountOutWei: input[3].(*big.Int),
+	}
+
+	if e.AmountInWei.Sign() == 0 {
+		return nil, errAmountInZero
+	}
+
+	if e.AmountOutWei.Sign() == 0 {
+		return nil, errAmountOutZero
+	}
+
+	e.Pair = &types.Pair{
+		Address: ethLog.Address,
+	}
+
+	e.PossibleProtocolIds = o.PossibleProtocolIds
+
+	return e, nil
+}
+
+/*
+BalancerSwapEventParser parses the Swap of the balancer v2 vault, the pool is the first 20 bytes of the pool id.
+The vault doesn't log the sender and the recipient of swaps.
+*/
+type BalancerSwapEventParser struct {
+	PoolEventParser
+}
+
+func (o *BalancerSwapEventParser) Parse(ethLog *ethtypes.Log) (types.Event, error) {
+	if ethLog.Address != balancer.VaultAddress {
+		return nil, errNotVault
+	}
+
+	input, err := o.ethLogUnpacker.Unpack(ethLog)
+	if err != nil {
+		return nil, err
+	}
+
+	poolId := ethLog.Topics[1]
+	e := &event.PoolSwapEvent{
+		EventCommon:  types.EventCommonFromEthLog(ethLog),
+		Pool:         &types.Pool{Address: balancer.PoolAddress(poolId), PoolId: poolId, ProtocolId: types.ProtocolIdBalancerV2},
+		TokenIn:      common.BytesToAddress(ethLog.Topics[2].Bytes()[12:]),
+		TokenOut:     common.BytesToAddress(ethLog.Topics[3].Bytes()[12:]),
+		AmountInWei:  input[0].(*big.Int),
+		AmountOutWei: input[1].(*big.Int),
+	}
+
+	if e.AmountInWei.Sign() == 0 {
+		return nil, errAmountInZero
+	}
+
+	if e.AmountOutWei.Sign() == 0 {
+		return nil, errAmountOutZero
+	}
+
+	e.Pair = &types.Pair{
+		Address: e.Pool.Address,
+	}
+
+	e.PossibleProtocolIds = o.PossibleProtocolIds
+
+	return e, nil
+}
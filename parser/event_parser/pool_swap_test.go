@@ -0,0 +1,129 @@
+package event_parser
+
+import (
+	"base_scan/abi/balancer"
+	"base_scan/abi/curve"
+	"base_scan/parser/event_parser/event"
+	"base_scan/types"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+)
+
+var (
+	testPool  = common.HexToAddress("0x00000000000000000000000000000000000000f1")
+	testToken = common.HexToAddress("0x00000000000000000000000000000000000000a0")
+	testBuyer = common.HexToAddress("0x00000000000000000000000000000000000000b0")
+)
+
+// poolPair is the pair the pair service makes of the traded tokens, USDC ordered as token1
+func poolPair(protocolId int) *types.Pair {
+	return &types.Pair{
+		Address:    testPool,
+		Token0Core: &types.TokenCore{Address: testToken, Symbol: "T", Decimals: 18},
+		Token1Core: &types.TokenCore{Address: types.USDCAddress, Symbol: "USDC", Decimals: 6},
+		ProtocolId: protocolId,
+	}
+}
+
+func TestTokenExchange_Curve(t *testing.T) {
+	data, err := curve.TokenExchangeEvent.Inputs.NonIndexed().Pack(big.NewInt(2), big.NewInt(1e6), big.NewInt(1), big.NewInt(5e17))
+	require.NoError(t, err)
+	ethLog := &ethtypes.Log{
+		Address: testPool,
+		Topics:  []common.Hash{curve.TokenExchangeTopic0, common.BytesToHash(testBuyer.Bytes())},
+		Data:    data,
+	}
+
+	e, err := Topic2EventParser[curve.TokenExchangeTopic0].Parse(ethLog)
+	require.NoError(t, err)
+	poolSwap := e.(*event.PoolSwapEvent)
+	require.Equal(t, &types.Pool{Address: testPool, ProtocolId: types.ProtocolIdCurve}, poolSwap.GetPool())
+	require.Equal(t, testPool, e.GetPairAddress())
+
+	pool := &types.Pool{Address: testPool, ProtocolId: types.ProtocolIdCurve, Tokens: []common.Address{types.WETHAddress, testToken, types.USDCAddress}}
+	tokenIn, tokenOut, ok := poolSwap.ResolveTokens(pool)
+	require.True(t, ok)
+	require.Equal(t, types.USDCAddress, tokenIn)
+	require.Equal(t, testToken, tokenOut)
+	_, _, ok = (&event.PoolSwapEvent{SoldId: 0, BoughtId: 3}).ResolveTokens(pool)
+	require.False(t, ok)
+
+	e.SetPair(poolPair(types.ProtocolIdCurve))
+	tx := e.GetTx(types.DefaultBaseTokens, decimal.Zero)
+	require.Equal(t, types.Buy, tx.Event)
+	require.True(t, decimal.RequireFromString("0.5").Equal(tx.Token0Amount))
+	require.True(t, decimal.NewFromInt(1).Equal(tx.Token1Amount))
+	require.True(t, decimal.NewFromInt(1).Equal(tx.AmountUsd))
+	require.True(t, decimal.NewFromInt(2).Equal(tx.PriceUsd))
+	require.Equal(t, types.ProtocolNameCurve, tx.Program)
+	require.Equal(t, testBuyer.String(), tx.Sender)
+
+	ethLog.Data, err = curve.TokenExchangeEvent.Inputs.NonIndexed().Pack(big.NewInt(9), big.NewInt(1e6), big.NewInt(0), big.NewInt(5e17))
+	require.NoError(t, err)
+	_, err = Topic2EventParser[curve.TokenExchangeTopic0].Parse(ethLog)
+	require.ErrorIs(t, err, errWrongCoinIndex)
+}
+
+func TestTokenExchange_CurveNG(t *testing.T) {
+	data, err := curve.TokenExchangeNGEvent.Inputs.NonIndexed().Pack(big.NewInt(0), big.NewInt(1e18), big.NewInt(1), big.NewInt(2e6), big.NewInt(1), big.NewInt(1))
+	require.NoError(t, err)
+	ethLog := &ethtypes.Log{
+		Address: testPool,
+		Topics:  []common.Hash{curve.TokenExchangeNGTopic0, common.BytesToHash(testBuyer.Bytes())},
+		Data:    data,
+	}
+
+	e, err := Topic2EventParser[curve.TokenExchangeNGTopic0].Parse(ethLog)
+	require.NoError(t, err)
+	_, _, ok := e.(types.PoolSwapEvent).ResolveTokens(&types.Pool{Tokens: []common.Address{testToken, types.USDCAddress}})
+	require.True(t, ok)
+
+	e.SetPair(poolPair(types.ProtocolIdCurve))
+	tx := e.GetTx(types.DefaultBaseTokens, decimal.Zero)
+	require.Equal(t, types.Sell, tx.Event)
+	require.True(t, decimal.NewFromInt(1).Equal(tx.Token0Amount))
+	require.True(t, decimal.NewFromInt(2).Equal(tx.Token1Amount))
+}
+
+func TestSwap_Balancer(t *testing.T) {
+	poolId := common.HexToHash("0x00000000000000000000000000000000000000f1000200000000000000000001")
+	data, err := balancer.SwapEvent.Inputs.NonIndexed().Pack(big.NewInt(3e18), big.NewInt(6e6))
+	require.NoError(t, err)
+	ethLog := &ethtypes.Log{
+		Address: balancer.VaultAddress,
+		Topics: []common.Hash{
+			balancer.SwapTopic0,
+			poolId,
+			common.BytesToHash(testToken.Bytes()),
+			common.BytesToHash(types.USDCAddress.Bytes()),
+		},
+		Data: data,
+	}
+
+	e, err := Topic2EventParser[balancer.SwapTopic0].Parse(ethLog)
+	require.NoError(t, err)
+	pool := e.(types.PoolSwapEvent).GetPool()
+	require.Equal(t, testPool, pool.Address)
+	require.Equal(t, poolId, pool.PoolId)
+	require.Equal(t, types.ProtocolIdBalancerV2, pool.ProtocolId)
+
+	_, _, ok := e.(types.PoolSwapEvent).ResolveTokens(&types.Pool{Tokens: []common.Address{types.WETHAddress, types.USDCAddress}})
+	require.False(t, ok)
+	_, _, ok = e.(types.PoolSwapEvent).ResolveTokens(&types.Pool{Tokens: []common.Address{types.WETHAddress, testToken, types.USDCAddress}})
+	require.True(t, ok)
+
+	e.SetPair(poolPair(types.ProtocolIdBalancerV2))
+	tx := e.GetTx(types.DefaultBaseTokens, decimal.Zero)
+	require.Equal(t, types.Sell, tx.Event)
+	require.True(t, decimal.NewFromInt(3).Equal(tx.Token0Amount))
+	require.True(t, decimal.NewFromInt(6).Equal(tx.Token1Amount))
+	require.Equal(t, types.ProtocolNameBalancerV2, tx.Program)
+
+	ethLog.Address = testPool
+	_, err = Topic2EventParser[balancer.SwapTopic0].Parse(ethLog)
+	require.ErrorIs(t, err, errNotVault)
+}
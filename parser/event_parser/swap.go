@@ -25,6 +25,7 @@ func (o *SwapEventParser) Parse(ethLog *ethtypes.Log) (types.Event, error) {
 
 	e := &event.SwapEvent{
 		EventCommon:   types.EventCommonFromEthLog(ethLog),
+		SwapParties:   types.SwapPartiesFromTopics(ethLog.Topics),
 		Amount0InWei:  eventInput[0].(*big.Int),
 		Amount1InWei:  eventInput[1].(*big.Int),
 		Amount0OutWei: eventInput[2].(*big.Int),
@@ -25,8 +25,13 @@ func (o *SwapEventParserV3) Parse(ethLog *ethtypes.Log) (types.Event, error) {
 
 	e := &event.SwapEventV3{
 		EventCommon: types.EventCommonFromEthLog(ethLog),
+		SwapParties: types.SwapPartiesFromTopics(ethLog.Topics),
 		Amount0Wei:  input[0].(*big.Int),
 		Amount1Wei:  input[1].(*big.Int),
+
+		SqrtPriceX96: input[2].(*big.Int),
+		Liquidity:    input[3].(*big.Int),
+		Tick:         int32(input[4].(*big.Int).Int64()),
 	}
 
 	if e.Amount0Wei.Sign() == 0 {
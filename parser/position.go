@@ -0,0 +1,162 @@
+package parser
+
+import (
+	uniswapv3 "base_scan/abi/uniswap/v3"
+	"base_scan/log"
+	"base_scan/parser/event_parser"
+	"base_scan/types"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+	"math/big"
+)
+
+var (
+	increaseLiquidityUnpacker = &event_parser.EthLogUnpacker{
+		AbiEvent:      uniswapv3.IncreaseLiquidityEvent,
+		TopicLen:      2,
+		DataUnpackLen: 3,
+	}
+	decreaseLiquidityUnpacker = &event_parser.EthLogUnpacker{
+		AbiEvent:      uniswapv3.DecreaseLiquidityEvent,
+		TopicLen:      2,
+		DataUnpackLen: 3,
+	}
+	positionCollectUnpacker = &event_parser.EthLogUnpacker{
+		AbiEvent:      uniswapv3.PositionCollectEvent,
+		TopicLen:      2,
+		DataUnpackLen: 3,
+	}
+
+	// the pool log each position manager event follows
+	positionKind2PoolTopic = map[string]common.Hash{
+		types.PositionIncrease: uniswapv3.MintTopic0,
+		types.PositionDecrease: uniswapv3.BurnTopic0,
+		types.PositionCollect:  uniswapv3.CollectTopic0,
+	}
+)
+
+/*
+ParsePositionChanges decodes the events of the position managers in the logs of a tx.
+A position manager first calls the pool, which logs Mint, Burn or Collect with the manager as owner,
+then logs its own IncreaseLiquidity, DecreaseLiquidity or Collect, which is linked to that pool log.
+*/
+func ParsePositionChanges(logs []*ethtypes.Log, managers map[common.Address]int) []*types.PositionChange {
+	if len(managers) == 0 {
+		return nil
+	}
+
+	changes := make([]*types.PositionChange, 0)
+	// the last pool log of every kind not yet linked, by owner
+	pending := make(map[common.Address]map[common.Hash]*ethtypes.Log)
+	for _, ethLog := range logs {
+		if len(ethLog.Topics) == 0 {
+			continue
+		}
+
+		switch ethLog.Topics[0] {
+		case uniswapv3.MintTopic0, uniswapv3.BurnTopic0, uniswapv3.CollectTopic0:
+			if len(ethLog.Topics) != 4 {
+				continue
+			}
+			owner := common.BytesToAddress(ethLog.Topics[1].Bytes()[12:])
+			if _, ok := managers[owner]; !ok {
+				continue
+			}
+			if _, ok := pending[owner]; !ok {
+				pending[owner] = make(map[common.Hash]*ethtypes.Log)
+			}
+			pending[owner][ethLog.Topics[0]] = ethLog
+			continue
+		}
+
+		if _, ok := managers[ethLog.Address]; !ok {
+			continue
+		}
+
+		change, err := parsePositionChange(ethLog)
+		if err != nil {
+			log.Logger.Info("Err: unpack position manager event err", zap.Error(err), zap.Any("txHash", ethLog.TxHash))
+			continue
+		}
+		if change == nil {
+			continue
+		}
+
+		if poolTopic, ok := positionKind2PoolTopic[change.Kind]; ok {
+			poolLog, ok := pending[ethLog.Address][poolTopic]
+			if !ok {
+				log.Logger.Info("Warning: position change without pool log", zap.Any("txHash", ethLog.TxHash), zap.Uint("logIndex", ethLog.Index))
+				continue
+			}
+			delete(pending[ethLog.Address], poolTopic)
+
+			change.PoolAddress = poolLog.Address
+			change.TickLower = types.TickFromTopic(poolLog.Topics[2])
+			change.TickUpper = types.TickFromTopic(poolLog.Topics[3])
+			change.PoolLogIndex = poolLog.Index
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// parsePositionChange returns nil for logs of the manager that are not position changes, like approvals.
+func parsePositionChange(ethLog *ethtypes.Log) (*types.PositionChange, error) {
+	change := &types.PositionChange{
+		Manager:  ethLog.Address,
+		Block:    ethLog.BlockNumber,
+		TxHash:   ethLog.TxHash,
+		LogIndex: ethLog.Index,
+	}
+
+	var (
+		unpacker *event_parser.EthLogUnpacker
+		input    []interface{}
+		err      error
+	)
+	switch ethLog.Topics[0] {
+	case uniswapv3.IncreaseLiquidityTopic0:
+		change.Kind, unpacker = types.PositionIncrease, increaseLiquidityUnpacker
+	case uniswapv3.DecreaseLiquidityTopic0:
+		change.Kind, unpacker = types.PositionDecrease, decreaseLiquidityUnpacker
+	case uniswapv3.PositionCollectTopic0:
+		change.Kind, unpacker = types.PositionCollect, positionCollectUnpacker
+	case uniswapv3.TransferTopic0:
+		if len(ethLog.Topics) != 4 {
+			return nil, nil
+		}
+		change.Kind = types.PositionTransfer
+		change.From = common.BytesToAddress(ethLog.Topics[1].Bytes()[12:])
+		change.To = common.BytesToAddress(ethLog.Topics[2].Bytes()[12:])
+		change.TokenId = ethLog.Topics[3].Big()
+		return change, nil
+	default:
+		return nil, nil
+	}
+
+	if input, err = unpacker.Unpack(ethLog); err != nil {
+		return nil, err
+	}
+
+	change.TokenId = ethLog.Topics[1].Big()
+	if change.Kind == types.PositionCollect {
+		change.To = input[0].(common.Address)
+	} else {
+		change.Liquidity = input[0].(*big.Int)
+	}
+	change.Amount0 = input[1].(*big.Int)
+	change.Amount1 = input[2].(*big.Int)
+	return change, nil
+}
+
+// positionIdsByPoolLog maps the index of the pool log of every change to its token id.
+func positionIdsByPoolLog(changes []*types.PositionChange) map[uint]*big.Int {
+	positionIds := make(map[uint]*big.Int, len(changes))
+	for _, change := range changes {
+		if change.Kind == types.PositionIncrease || change.Kind == types.PositionDecrease {
+			positionIds[change.PoolLogIndex] = change.TokenId
+		}
+	}
+	return positionIds
+}
@@ -0,0 +1,99 @@
+package parser
+
+import (
+	uniswapv3 "base_scan/abi/uniswap/v3"
+	"base_scan/types"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+)
+
+var positionManagers = map[common.Address]int{uniswapv3.PositionManagerAddress: types.ProtocolIdUniswapV3}
+
+func tickTopic(tick int64) common.Hash {
+	return common.BytesToHash(math.U256Bytes(big.NewInt(tick)))
+}
+
+func poolLog(topic0 common.Hash, owner common.Address, index uint) *ethtypes.Log {
+	return &ethtypes.Log{
+		Address: pool,
+		Topics:  []common.Hash{topic0, common.BytesToHash(owner.Bytes()), tickTopic(-600), tickTopic(600)},
+		Index:   index,
+	}
+}
+
+func positionManagerLog(t *testing.T, event *abi.Event, tokenId int64, index uint, args ...interface{}) *ethtypes.Log {
+	data, err := event.Inputs.NonIndexed().Pack(args...)
+	require.NoError(t, err)
+
+	return &ethtypes.Log{
+		Address: uniswapv3.PositionManagerAddress,
+		Topics:  []common.Hash{event.ID, common.BigToHash(big.NewInt(tokenId))},
+		Data:    data,
+		Index:   index,
+	}
+}
+
+func transferLog(from, to common.Address, tokenId int64, index uint) *ethtypes.Log {
+	return &ethtypes.Log{
+		Address: uniswapv3.PositionManagerAddress,
+		Topics:  []common.Hash{uniswapv3.TransferTopic0, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes()), common.BigToHash(big.NewInt(tokenId))},
+		Index:   index,
+	}
+}
+
+func TestParsePositionChanges(t *testing.T) {
+	manager := uniswapv3.PositionManagerAddress
+	logs := []*ethtypes.Log{
+		// mint
+		poolLog(uniswapv3.MintTopic0, manager, 0),
+		transferLog(common.Address{}, account1, 7, 1),
+		positionManagerLog(t, uniswapv3.IncreaseLiquidityEvent, 7, 2, big.NewInt(1000), big.NewInt(10), big.NewInt(20)),
+		// liquidity owned directly is not a position
+		poolLog(uniswapv3.BurnTopic0, account2, 3),
+		// decrease and collect
+		poolLog(uniswapv3.BurnTopic0, manager, 4),
+		positionManagerLog(t, uniswapv3.DecreaseLiquidityEvent, 7, 5, big.NewInt(400), big.NewInt(4), big.NewInt(8)),
+		poolLog(uniswapv3.CollectTopic0, manager, 6),
+		positionManagerLog(t, uniswapv3.PositionCollectEvent, 7, 7, account2, big.NewInt(5), big.NewInt(9)),
+	}
+
+	changes := ParsePositionChanges(logs, positionManagers)
+	require.Len(t, changes, 4)
+
+	require.Equal(t, types.PositionTransfer, changes[0].Kind)
+	require.Equal(t, account1, changes[0].To)
+	require.Equal(t, common.Address{}, changes[0].PoolAddress)
+
+	increase := changes[1]
+	require.Equal(t, types.PositionIncrease, increase.Kind)
+	require.Equal(t, big.NewInt(7), increase.TokenId)
+	require.Equal(t, pool, increase.PoolAddress)
+	require.Equal(t, int32(-600), increase.TickLower)
+	require.Equal(t, int32(600), increase.TickUpper)
+	require.Equal(t, uint(0), increase.PoolLogIndex)
+	require.Equal(t, big.NewInt(1000), increase.Liquidity)
+
+	require.Equal(t, types.PositionDecrease, changes[2].Kind)
+	require.Equal(t, uint(4), changes[2].PoolLogIndex)
+	require.Equal(t, types.PositionCollect, changes[3].Kind)
+	require.Equal(t, account2, changes[3].To)
+	require.Equal(t, big.NewInt(9), changes[3].Amount1)
+
+	positionIds := positionIdsByPoolLog(changes)
+	require.Len(t, positionIds, 2)
+	require.Equal(t, big.NewInt(7), positionIds[0])
+	require.Equal(t, big.NewInt(7), positionIds[4])
+}
+
+func TestParsePositionChangesWithoutPoolLog(t *testing.T) {
+	logs := []*ethtypes.Log{
+		positionManagerLog(t, uniswapv3.IncreaseLiquidityEvent, 7, 0, big.NewInt(1000), big.NewInt(10), big.NewInt(20)),
+	}
+	require.Empty(t, ParsePositionChanges(logs, positionManagers))
+	require.Nil(t, ParsePositionChanges(logs, nil))
+}
@@ -20,9 +20,9 @@ type topicRouter struct {
 	topic2EventParser map[common.Hash]event_parser.EventParser
 }
 
-func NewTopicRouter() TopicRouter {
+func NewTopicRouter(factoryProtocolIds map[common.Address]int, baseTokens *types.BaseTokens) TopicRouter {
 	return &topicRouter{
-		topic2EventParser: event_parser.Topic2EventParser,
+		topic2EventParser: event_parser.NewTopic2EventParser(factoryProtocolIds, baseTokens),
 	}
 }
 
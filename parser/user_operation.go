@@ -0,0 +1,67 @@
+package parser
+
+import (
+	"base_scan/abi/entrypoint"
+	"base_scan/log"
+	"base_scan/parser/event_parser"
+	"base_scan/types"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+	"math/big"
+)
+
+var userOperationEventUnpacker = &event_parser.EthLogUnpacker{
+	AbiEvent:      entrypoint.UserOperationEvent,
+	TopicLen:      4,
+	DataUnpackLen: 4,
+}
+
+/*
+ParseUserOperations decodes the UserOperationEvents of known EntryPoints in the logs of a bundle tx.
+An operation starts after the BeforeExecution event or the previous operation of the same EntryPoint,
+logs of the validation phase before BeforeExecution are not attributed to any operation.
+*/
+func ParseUserOperations(bundler common.Address, logs []*ethtypes.Log) types.UserOperations {
+	var userOperations types.UserOperations
+	entryPoint2NextLogIndex := make(map[common.Address]uint)
+	for _, ethLog := range logs {
+		if len(ethLog.Topics) == 0 {
+			continue
+		}
+		if _, ok := entrypoint.EntryPointAddresses[ethLog.Address]; !ok {
+			continue
+		}
+
+		switch ethLog.Topics[0] {
+		case entrypoint.BeforeExecutionTopic0:
+			entryPoint2NextLogIndex[ethLog.Address] = ethLog.Index + 1
+		case entrypoint.UserOperationEventTopic0:
+			input, err := userOperationEventUnpacker.Unpack(ethLog)
+			if err != nil {
+				log.Logger.Info("Err: unpack user operation event err", zap.Error(err), zap.Any("txHash", ethLog.TxHash))
+				continue
+			}
+
+			firstLogIndex, ok := entryPoint2NextLogIndex[ethLog.Address]
+			if !ok {
+				firstLogIndex = ethLog.Index
+			}
+			userOperations = append(userOperations, &types.UserOperation{
+				Hash:          ethLog.Topics[1],
+				EntryPoint:    ethLog.Address,
+				Sender:        common.BytesToAddress(ethLog.Topics[2].Bytes()),
+				Paymaster:     common.BytesToAddress(ethLog.Topics[3].Bytes()),
+				Bundler:       bundler,
+				Nonce:         input[0].(*big.Int),
+				Success:       input[1].(bool),
+				ActualGasCost: input[2].(*big.Int),
+				ActualGasUsed: input[3].(*big.Int),
+				FirstLogIndex: firstLogIndex,
+				LogIndex:      ethLog.Index,
+			})
+			entryPoint2NextLogIndex[ethLog.Address] = ethLog.Index + 1
+		}
+	}
+	return userOperations
+}
@@ -0,0 +1,84 @@
+package parser
+
+import (
+	"base_scan/abi/entrypoint"
+	"base_scan/types"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+)
+
+var (
+	bundler  = common.HexToAddress("0x00000000000000000000000000000000000000b1")
+	account1 = common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	account2 = common.HexToAddress("0x00000000000000000000000000000000000000a2")
+	pool     = common.HexToAddress("0x00000000000000000000000000000000000000f1")
+)
+
+func userOperationEventLog(t *testing.T, index uint, sender common.Address) *ethtypes.Log {
+	data, err := entrypoint.UserOperationEvent.Inputs.NonIndexed().Pack(big.NewInt(1), true, big.NewInt(2), big.NewInt(3))
+	require.NoError(t, err)
+
+	return &ethtypes.Log{
+		Address: entrypoint.EntryPointV07Address,
+		Topics: []common.Hash{
+			entrypoint.UserOperationEventTopic0,
+			common.BigToHash(big.NewInt(int64(index))),
+			common.BytesToHash(sender.Bytes()),
+			{},
+		},
+		Data:  data,
+		Index: index,
+	}
+}
+
+func swapLog(index uint) *ethtypes.Log {
+	return &ethtypes.Log{Address: pool, Topics: []common.Hash{{}}, Index: index}
+}
+
+func TestParseUserOperations(t *testing.T) {
+	logs := []*ethtypes.Log{
+		// validation phase
+		swapLog(0),
+		{Address: entrypoint.EntryPointV07Address, Topics: []common.Hash{entrypoint.BeforeExecutionTopic0}, Index: 1},
+		swapLog(2),
+		userOperationEventLog(t, 3, account1),
+		swapLog(4),
+		swapLog(5),
+		userOperationEventLog(t, 6, account2),
+		// after handleOps, done by the bundler itself
+		swapLog(7),
+	}
+
+	userOperations := ParseUserOperations(bundler, logs)
+	require.Len(t, userOperations, 2)
+	require.Equal(t, account1, userOperations[0].Sender)
+	require.Equal(t, bundler, userOperations[0].Bundler)
+	require.True(t, userOperations[0].Success)
+	require.Equal(t, big.NewInt(2), userOperations[0].ActualGasCost)
+
+	require.Nil(t, userOperations.Of(0))
+	require.Equal(t, account1, userOperations.Of(2).Sender)
+	require.Equal(t, account2, userOperations.Of(4).Sender)
+	require.Equal(t, account2, userOperations.Of(5).Sender)
+	require.Nil(t, userOperations.Of(7))
+
+	tr := types.NewTxResult(bundler, nil, userOperations)
+	inOperation := &types.EventCommon{Pair: &types.Pair{Address: pool}}
+	tr.AddEvent(inOperation, 4)
+	require.Equal(t, account2, inOperation.Maker)
+	require.Equal(t, userOperations[1], inOperation.UserOperation)
+
+	byBundler := &types.EventCommon{Pair: &types.Pair{Address: pool}}
+	tr.AddEvent(byBundler, 7)
+	require.Equal(t, bundler, byBundler.Maker)
+	require.Nil(t, byBundler.UserOperation)
+}
+
+func TestParseUserOperationsUnknownEntryPoint(t *testing.T) {
+	ethLog := userOperationEventLog(t, 3, account1)
+	ethLog.Address = pool
+	require.Empty(t, ParseUserOperations(bundler, []*ethtypes.Log{ethLog}))
+}
@@ -0,0 +1,347 @@
+package main
+
+import (
+	"base_scan/alert"
+	"base_scan/archive"
+	"base_scan/block_getter"
+	"base_scan/cache"
+	"base_scan/chain"
+	"base_scan/config"
+	"base_scan/grpcapi"
+	"base_scan/grpcapi/pb"
+	"base_scan/log"
+	"base_scan/maker"
+	"base_scan/parser"
+	"base_scan/push"
+	"base_scan/quote"
+	"base_scan/repository"
+	"base_scan/router"
+	"base_scan/sequencer"
+	"base_scan/service"
+	"base_scan/types"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"io"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrTargetShared = errors.New("pipelines share the same target")
+)
+
+/*
+pipeline indexes one chain, a process runs one pipeline per config file.
+Pipelines share nothing but the process, each has its own clients, cache, services and kafka sender.
+*/
+type pipeline struct {
+	conf        *config.Config
+	profile     *chain.Profile
+	logger      *zap.Logger
+	cacheCloser io.Closer
+	blockGetter block_getter.BlockGetter
+	blockParser parser.BlockParser
+	sequencers  []sequencer.BlockSequencer
+	priceSvc    service.PriceService
+}
+
+func createDBService(conf *config.Config, chainId uint64) service.DBService {
+	var (
+		txDb               *gorm.DB
+		txDbErr            error
+		tokenPairDb        *gorm.DB
+		tokenPairDbErr     error
+		tokenRepository    *repository.TokenRepository
+		pairRepository     *repository.PairRepository
+		poolRepository     *repository.PoolRepository
+		launchRepository   *repository.LaunchRepository
+		txRepository       *repository.TxRepository
+		mevRepository      *repository.MevRepository
+		makerRepository    *repository.MakerRepository
+		positionRepository *repository.PositionRepository
+		poolFeeRepository  *repository.PoolFeeRepository
+	)
+
+	if conf.TxDatabase.Enabled {
+		txDb, txDbErr = gorm.Open(postgres.Open(conf.TxDatabase.DBDatasource.GetPostgresDsn()))
+		if txDbErr != nil {
+			log.Logger.Fatal("failed to connect to tx db", zap.Error(txDbErr))
+		}
+
+		txRepository = repository.NewTxRepository(txDb, chainId)
+		mevRepository = repository.NewMevRepository(txDb)
+		makerRepository = repository.NewMakerRepository(txDb)
+		positionRepository = repository.NewPositionRepository(txDb)
+		poolFeeRepository = repository.NewPoolFeeRepository(txDb)
+	}
+
+	if conf.TokenPairDatabase.Enabled {
+		tokenPairDb, tokenPairDbErr = gorm.Open(postgres.Open(conf.TokenPairDatabase.DBDatasource.GetPostgresDsn()))
+		if tokenPairDbErr != nil {
+			log.Logger.Fatal("failed to connect to token_pair db", zap.Error(tokenPairDbErr))
+		}
+
+		tokenRepository = repository.NewTokenRepository(tokenPairDb, chainId)
+		pairRepository = repository.NewPairRepository(tokenPairDb, chainId)
+		poolRepository = repository.NewPoolRepository(tokenPairDb, chainId)
+		launchRepository = repository.NewLaunchRepository(tokenPairDb, chainId)
+	}
+
+	return service.NewDBService(tokenRepository, pairRepository, poolRepository, launchRepository, txRepository, mevRepository, makerRepository, positionRepository, poolFeeRepository)
+}
+
+// cacheTarget identifies where a cache config writes, two pipelines must not write to the same place.
+func cacheTarget(conf *config.Config) string {
+	if conf.Cache.Backend == config.CacheBackendPebble {
+		dir, err := filepath.Abs(conf.Cache.PebbleDir)
+		if err != nil {
+			dir = conf.Cache.PebbleDir
+		}
+		return fmt.Sprintf("pebble:%s", dir)
+	}
+	return fmt.Sprintf("redis:%s/%d", strings.ToLower(conf.Redis.Addr), conf.Redis.DB)
+}
+
+// fileTarget identifies a file written by a pipeline.
+func fileTarget(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		abs = path
+	}
+	return fmt.Sprintf("file:%s", abs)
+}
+
+// listenTarget identifies a port served by a pipeline, a port is served by one api of one pipeline whatever the host.
+func listenTarget(listen string) string {
+	if _, port, err := net.SplitHostPort(listen); err == nil {
+		return fmt.Sprintf("port:%s", port)
+	}
+	return fmt.Sprintf("listen:%s", listen)
+}
+
+// targets are the places a pipeline writes to or serves on, they must not be shared with another pipeline.
+func targets(conf *config.Config) []string {
+	targets := []string{cacheTarget(conf)}
+	if conf.Quote.Enabled {
+		if conf.Quote.SnapshotPath != "" {
+			targets = append(targets, fileTarget(conf.Quote.SnapshotPath))
+		}
+		if conf.Quote.Listen != "" {
+			targets = append(targets, listenTarget(conf.Quote.Listen))
+		}
+	}
+	if conf.Push.Enabled {
+		targets = append(targets, listenTarget(conf.Push.Listen))
+	}
+	if conf.Grpc.Enabled {
+		targets = append(targets, listenTarget(conf.Grpc.Listen))
+	}
+	return targets
+}
+
+func checkTargets(confs []*config.Config) error {
+	owners := make(map[string]int, len(confs))
+	for i, conf := range confs {
+		for _, target := range targets(conf) {
+			if j, ok := owners[target]; ok {
+				return fmt.Errorf("%w: config %d and %d both use %s", ErrTargetShared, j, i, target)
+			}
+			owners[target] = i
+		}
+	}
+	return nil
+}
+
+func newPipeline(conf *config.Config, profile *chain.Profile) *pipeline {
+	logger := log.Logger.With(zap.String("chain", profile.Name))
+
+	ethClient, dialEthErr := ethclient.Dial(conf.Chain.Endpoint)
+	if dialEthErr != nil {
+		logger.Fatal("Failed to connect to the chain(http): %v", zap.Error(dialEthErr))
+	}
+
+	ethClientArchive, dialEthErrArchive := ethclient.Dial(conf.Chain.EndpointArchive)
+	if dialEthErrArchive != nil {
+		logger.Fatal("Failed to connect to the chain archive(http): %v", zap.Error(dialEthErrArchive))
+	}
+
+	wsEthClient, dialEthWsErr := ethclient.Dial(conf.Chain.WsEndpoint)
+	if dialEthWsErr != nil {
+		logger.Fatal("Failed to connect to the chain(ws): %v", zap.Error(dialEthWsErr))
+	}
+
+	c, cacheCloser, newCacheErr := cache.NewCacheByConf(conf.Cache, conf.Redis)
+	if newCacheErr != nil {
+		logger.Fatal("create cache err", zap.String("backend", conf.Cache.Backend), zap.Error(newCacheErr))
+	}
+
+	contractCaller := service.NewContractCaller(ethClient, conf.ContractCaller.Retry.GetRetryParams())
+
+	dbService := createDBService(conf, profile.Id)
+	pairService := service.NewPairService(c, contractCaller, dbService, conf.FilterTTL, profile.BaseTokens(), profile.Factories, profile.InitCodeHashes, profile.DiscoverForks)
+	contractCallerArchive := service.NewContractCaller(ethClientArchive, conf.ContractCaller.Retry.GetRetryParams())
+	priceService := service.NewPriceService(c, contractCallerArchive, ethClient, conf.PriceService.PoolSize, profile)
+
+	blockSequencerForBlockHandler := sequencer.NewBlockSequencer(conf.EnableSequencer)
+
+	topicRouter := parser.NewTopicRouter(profile.FactoryProtocolIds(), profile.BaseTokens())
+	routers, routersErr := router.NewRegistry(profile, conf.Routers)
+	if routersErr != nil {
+		logger.Fatal("create router registry err", zap.Error(routersErr))
+	}
+	kafkaSender := service.NewKafkaSender(conf.Kafka)
+
+	var classifier *maker.Classifier
+	if conf.Maker.Enabled {
+		classifier = maker.NewClassifier(c, contractCaller, conf.Maker, profile.Id)
+	}
+
+	var quotes *quote.Engine
+	if conf.Quote.Enabled {
+		var quotesErr error
+		quotes, quotesErr = quote.NewEngine(conf.Quote)
+		if quotesErr != nil {
+			logger.Fatal("create quote engine err", zap.Error(quotesErr))
+		}
+		if conf.Quote.Listen != "" {
+			listener, listenErr := net.Listen("tcp", conf.Quote.Listen)
+			if listenErr != nil {
+				logger.Fatal("quote api listen err", zap.String("listen", conf.Quote.Listen), zap.Error(listenErr))
+			}
+			go func() {
+				err := http.Serve(listener, quote.NewHandler(quotes))
+				logger.Error("quote api stopped", zap.String("listen", conf.Quote.Listen), zap.Error(err))
+			}()
+		}
+	}
+
+	var (
+		launchAlerts *alert.Detector
+		webhook      *alert.Webhook
+	)
+	if conf.LaunchAlert.Enabled {
+		launchAlerts = alert.NewDetector(conf.LaunchAlert)
+		if conf.LaunchAlert.WebhookUrl != "" {
+			webhook = alert.NewWebhook(conf.LaunchAlert)
+		}
+	}
+
+	var pushServer *push.Server
+	if conf.Push.Enabled {
+		pushServer = push.NewServer(conf.Push)
+		listener, listenErr := net.Listen("tcp", conf.Push.Listen)
+		if listenErr != nil {
+			logger.Fatal("push listen err", zap.String("listen", conf.Push.Listen), zap.Error(listenErr))
+		}
+		go func() {
+			err := http.Serve(listener, push.NewHandler(pushServer))
+			logger.Error("push server stopped", zap.String("listen", conf.Push.Listen), zap.Error(err))
+		}()
+	}
+
+	var grpcServer *grpcapi.Server
+	if conf.Grpc.Enabled {
+		grpcServer = grpcapi.NewServer(conf.Grpc, c, dbService)
+		listener, listenErr := net.Listen("tcp", conf.Grpc.Listen)
+		if listenErr != nil {
+			logger.Fatal("grpc listen err", zap.String("listen", conf.Grpc.Listen), zap.Error(listenErr))
+		}
+		server := grpc.NewServer()
+		pb.RegisterIndexerServer(server, grpcServer)
+		go func() {
+			err := server.Serve(listener)
+			logger.Error("grpc server stopped", zap.String("listen", conf.Grpc.Listen), zap.Error(err))
+		}()
+	}
+
+	blockParser := parser.NewBlockParser(
+		c,
+		blockSequencerForBlockHandler,
+		priceService,
+		pairService,
+		topicRouter,
+		kafkaSender,
+		dbService,
+		classifier,
+		routers,
+		quotes,
+		launchAlerts,
+		webhook,
+		pushServer,
+		grpcServer,
+		conf.BlockHandler,
+		profile,
+	)
+
+	var blockArchive *archive.Archive
+	if conf.Archive.Write || conf.Archive.Reparse {
+		store, storeErr := archive.NewStoreByConf(conf.Archive)
+		if storeErr != nil {
+			logger.Fatal("create archive store err", zap.String("backend", conf.Archive.Backend), zap.Error(storeErr))
+		}
+		blockArchive = archive.NewArchive(store, profile.Id)
+	}
+
+	blockSequencerForBlockGetter := sequencer.NewBlockSequencer(conf.EnableSequencer)
+	blockGetter := block_getter.NewBlockGetter(ethClient, wsEthClient, c, blockSequencerForBlockGetter, conf.BlockGetter, conf.Archive, blockArchive, profile)
+
+	return &pipeline{
+		conf:        conf,
+		profile:     profile,
+		logger:      logger,
+		cacheCloser: cacheCloser,
+		blockGetter: blockGetter,
+		blockParser: blockParser,
+		sequencers:  []sequencer.BlockSequencer{blockSequencerForBlockGetter, blockSequencerForBlockHandler},
+		priceSvc:    priceService,
+	}
+}
+
+// run indexes blocks until stop is called and every parsed block is committed.
+func (p *pipeline) run() {
+	defer p.cacheCloser.Close()
+
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	p.blockParser.Start(wg)
+
+	startBlockNumber := p.blockGetter.GetStartBlockNumber(p.conf.BlockGetter.StartBlockNumber)
+	if startBlockNumber == 0 {
+		p.logger.Fatal("start block number is zero")
+	}
+
+	for _, s := range p.sequencers {
+		s.Init(startBlockNumber)
+	}
+
+	p.priceSvc.Start(startBlockNumber)
+	p.blockGetter.Start()
+	p.blockGetter.StartDispatch(startBlockNumber)
+
+	var blockCtx *types.ParseBlockContext
+	for {
+		blockCtx = p.blockGetter.Next()
+		if blockCtx == nil {
+			p.logger.Info("no more block to parse")
+			p.blockParser.Stop()
+			break
+		}
+		p.blockParser.ParseBlockAsync(blockCtx)
+	}
+
+	p.logger.Info("wait all block commited")
+	wg.Wait()
+	p.logger.Info("all block commited")
+}
+
+func (p *pipeline) stop() {
+	p.blockGetter.Stop()
+}
@@ -0,0 +1,278 @@
+package push
+
+import (
+	"base_scan/config"
+	"base_scan/fanout"
+	"base_scan/repository/orm"
+	"base_scan/types"
+	"fmt"
+	"github.com/gorilla/websocket"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+/*
+Frame is what a client receives for a committed block, the trades and pool updates of the block it subscribed to.
+Replayed frames are of blocks committed before the client connected.
+*/
+type Frame struct {
+	Height      uint64              `json:"height"`
+	Timestamp   uint64              `json:"timestamp"`
+	Replay      bool                `json:"replay,omitempty"`
+	Trades      []*orm.Tx           `json:"trades"`
+	PoolUpdates []*types.PoolUpdate `json:"pool_updates"`
+}
+
+// Error is sent to a client for a request it can't be served, e.g. an invalid subscription.
+type Error struct {
+	Error string `json:"error"`
+}
+
+/*
+Server pushes the trades and pool updates of committed blocks to websocket clients, see NewHandler.
+The last config.PushConf ReplayBlocks blocks are kept in memory to replay them to clients connecting with a height.
+Each client is a subscriber of a fanout.Fanout of SendQueueSize frames, a client falling that far behind is
+disconnected.
+*/
+type Server struct {
+	conf     *config.PushConf
+	upgrader websocket.Upgrader
+	clients  *fanout.Fanout
+	// updated and read under the lock of clients
+	recent []*types.BlockInfo
+}
+
+func NewServer(conf *config.PushConf) *Server {
+	return &Server{
+		conf: conf,
+		upgrader: websocket.Upgrader{
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+		clients: fanout.New("push", conf.SendQueueSize),
+		recent:  make([]*types.BlockInfo, 0, conf.ReplayBlocks),
+	}
+}
+
+// Publish keeps a committed block for the replays and sends it to the clients, see fanout.Fanout Publish.
+func (s *Server) Publish(blockInfo *types.BlockInfo) {
+	s.clients.Publish(blockInfo, func() {
+		if s.conf.ReplayBlocks == 0 {
+			return
+		}
+		if len(s.recent) == s.conf.ReplayBlocks {
+			copy(s.recent, s.recent[1:])
+			s.recent = s.recent[:len(s.recent)-1]
+		}
+		s.recent = append(s.recent, blockInfo)
+	})
+}
+
+// Clients is the number of connected clients.
+func (s *Server) Clients() int {
+	return s.clients.Len()
+}
+
+/*
+add subscribes a client and returns the blocks to replay to it, from height from on.
+from 0 replays nothing, an error is returned when the blocks from height from on are not all kept.
+*/
+func (s *Server) add(c *client, from uint64) ([]*types.BlockInfo, error) {
+	var replay []*types.BlockInfo
+	subscriber, err := s.clients.Subscribe(c.conn.RemoteAddr().String(), func() error {
+		if from == 0 {
+			return nil
+		}
+		if len(s.recent) == 0 || s.recent[0].Height > from {
+			oldest := uint64(0)
+			if len(s.recent) > 0 {
+				oldest = s.recent[0].Height
+			}
+			return fmt.Errorf("height %d can't be replayed, the oldest kept is %d", from, oldest)
+		}
+		for _, blockInfo := range s.recent {
+			if blockInfo.Height >= from {
+				replay = append(replay, blockInfo)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.subscriber = subscriber
+	return replay, nil
+}
+
+func (s *Server) remove(c *client) {
+	s.clients.Unsubscribe(c.subscriber)
+}
+
+/*
+NewHandler serves the push feed of a server at GET /ws, upgraded to a websocket:
+- from: height to replay the kept blocks from, optional
+- pairs, tokens, makers, protocols: comma separated subscriptions, optional
+Clients change their subscriptions by sending a Request, e.g. {"op":"subscribe","pairs":["0x.."]}.
+*/
+func NewHandler(server *Server) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		var from uint64
+		if query.Get("from") != "" {
+			var err error
+			from, err = strconv.ParseUint(query.Get("from"), 10, 64)
+			if err != nil {
+				http.Error(w, "from must be a block height", http.StatusBadRequest)
+				return
+			}
+		}
+		subscription := NewSubscription()
+		if err := subscription.Apply(requestFromQuery(query)); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		conn, err := server.upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		server.serve(conn, subscription, from)
+	})
+	return mux
+}
+
+func (s *Server) serve(conn *websocket.Conn, subscription *Subscription, from uint64) {
+	c := newClient(conn, subscription, s.conf)
+	replay, err := s.add(c, from)
+	if err != nil {
+		_ = c.writeJSON(&Error{Error: err.Error()})
+		_ = conn.Close()
+		return
+	}
+
+	go c.readLoop(s)
+	c.writeLoop(replay)
+	s.remove(c)
+	_ = conn.Close()
+}
+
+// client is a connection, its frames are written by writeLoop and its requests read by readLoop.
+type client struct {
+	conn         *websocket.Conn
+	subscriber   *fanout.Subscriber
+	writeTimeout time.Duration
+	pingInterval time.Duration
+
+	mu           sync.Mutex
+	subscription *Subscription
+}
+
+func newClient(conn *websocket.Conn, subscription *Subscription, conf *config.PushConf) *client {
+	return &client{
+		conn:         conn,
+		writeTimeout: time.Duration(conf.WriteTimeoutMs) * time.Millisecond,
+		pingInterval: time.Duration(conf.PingIntervalMs) * time.Millisecond,
+		subscription: subscription,
+	}
+}
+
+// frameOf returns the frame of a block for the subscription of the client, nil when nothing matches.
+func (c *client) frameOf(blockInfo *types.BlockInfo, replay bool) *Frame {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.subscription.Empty() {
+		return nil
+	}
+
+	frame := &Frame{
+		Height:      blockInfo.Height,
+		Timestamp:   blockInfo.Timestamp,
+		Replay:      replay,
+		Trades:      make([]*orm.Tx, 0),
+		PoolUpdates: make([]*types.PoolUpdate, 0),
+	}
+	for _, tx := range blockInfo.Txs {
+		if c.subscription.MatchTrade(tx) {
+			frame.Trades = append(frame.Trades, tx)
+		}
+	}
+	for _, update := range blockInfo.PoolUpdates {
+		if c.subscription.MatchPoolUpdate(update) {
+			frame.PoolUpdates = append(frame.PoolUpdates, update)
+		}
+	}
+	if len(frame.Trades) == 0 && len(frame.PoolUpdates) == 0 {
+		return nil
+	}
+	return frame
+}
+
+func (c *client) writeJSON(v interface{}) error {
+	_ = c.conn.SetWriteDeadline(time.Now().Add(c.writeTimeout))
+	return c.conn.WriteJSON(v)
+}
+
+func (c *client) writeFrame(blockInfo *types.BlockInfo, replay bool) error {
+	frame := c.frameOf(blockInfo, replay)
+	if frame == nil {
+		return nil
+	}
+	return c.writeJSON(frame)
+}
+
+// writeLoop writes the replayed blocks, then the published ones until the client is removed or a write fails.
+func (c *client) writeLoop(replay []*types.BlockInfo) {
+	for _, blockInfo := range replay {
+		if err := c.writeFrame(blockInfo, true); err != nil {
+			return
+		}
+	}
+
+	ticker := time.NewTicker(c.pingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case blockInfo, ok := <-c.subscriber.Queue():
+			if !ok {
+				return
+			}
+			if err := c.writeFrame(blockInfo, false); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(c.writeTimeout)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// readLoop applies the requests of the client until the connection is closed or the client misses its pongs.
+func (c *client) readLoop(s *Server) {
+	defer s.remove(c)
+
+	_ = c.conn.SetReadDeadline(time.Now().Add(2 * c.pingInterval))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(2 * c.pingInterval))
+	})
+
+	for {
+		request := &Request{}
+		if err := c.conn.ReadJSON(request); err != nil {
+			return
+		}
+
+		c.mu.Lock()
+		err := c.subscription.Apply(request)
+		c.mu.Unlock()
+		if err != nil {
+			// WriteControl may be called concurrently with the write loop
+			_ = c.conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseUnsupportedData, err.Error()), time.Now().Add(c.writeTimeout))
+			return
+		}
+	}
+}
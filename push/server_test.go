@@ -0,0 +1,136 @@
+package push
+
+import (
+	"base_scan/config"
+	"base_scan/repository/orm"
+	"base_scan/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	testPair  = common.HexToAddress("0x00000000000000000000000000000000000000b1")
+	testOther = common.HexToAddress("0x00000000000000000000000000000000000000b2")
+	testMaker = common.HexToAddress("0x00000000000000000000000000000000000000c1")
+)
+
+func testConf() *config.PushConf {
+	return &config.PushConf{
+		Enabled:        true,
+		ReplayBlocks:   3,
+		SendQueueSize:  2,
+		WriteTimeoutMs: 1000,
+		PingIntervalMs: 1000,
+	}
+}
+
+func testBlock(height uint64) *types.BlockInfo {
+	return &types.BlockInfo{
+		Height: height,
+		Txs: []*orm.Tx{
+			{TxHash: "0x1", Event: types.Buy, PairAddress: testPair.String(), Maker: testMaker.String(), Program: "UniswapV2"},
+			{TxHash: "0x2", Event: types.Sell, PairAddress: testOther.String(), Program: "UniswapV3"},
+		},
+		PoolUpdates: []*types.PoolUpdate{{Program: "UniswapV2", Address: testPair}},
+	}
+}
+
+func TestSubscriptionApply(t *testing.T) {
+	s := NewSubscription()
+	require.True(t, s.Empty())
+
+	require.NoError(t, s.Apply(&Request{Op: OpSubscribe, Pairs: []string{strings.ToLower(testPair.String())}, Protocols: []string{"UniswapV3"}}))
+	tx := testBlock(1).Txs
+	require.True(t, s.MatchTrade(tx[0]))
+	require.True(t, s.MatchTrade(tx[1]))
+
+	require.NoError(t, s.Apply(&Request{Op: OpUnsubscribe, Protocols: []string{"UniswapV3"}}))
+	require.False(t, s.MatchTrade(tx[1]))
+
+	require.ErrorIs(t, s.Apply(&Request{Op: "watch"}), ErrUnknownOp)
+	require.Error(t, s.Apply(&Request{Op: OpSubscribe, Makers: []string{"bob"}}))
+	require.Error(t, s.Apply(&Request{Op: OpSubscribe, Protocols: []string{"NoSwap"}}))
+}
+
+func dial(t *testing.T, server *httptest.Server, query string) *websocket.Conn {
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws?" + query
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func readFrame(t *testing.T, conn *websocket.Conn) *Frame {
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	frame := &Frame{}
+	require.NoError(t, conn.ReadJSON(frame))
+	return frame
+}
+
+func waitClients(t *testing.T, s *Server, n int) {
+	require.Eventually(t, func() bool { return s.Clients() == n }, time.Second, 10*time.Millisecond)
+}
+
+func TestServerReplayAndLive(t *testing.T) {
+	s := NewServer(testConf())
+	server := httptest.NewServer(NewHandler(s))
+	defer server.Close()
+
+	for height := uint64(1); height <= 4; height++ {
+		s.Publish(testBlock(height))
+	}
+
+	conn := dial(t, server, "from=3&pairs="+testPair.String())
+	for _, height := range []uint64{3, 4} {
+		frame := readFrame(t, conn)
+		require.Equal(t, height, frame.Height)
+		require.True(t, frame.Replay)
+		require.Len(t, frame.Trades, 1)
+		require.Equal(t, "0x1", frame.Trades[0].TxHash)
+		require.Len(t, frame.PoolUpdates, 1)
+	}
+
+	waitClients(t, s, 1)
+	require.NoError(t, conn.WriteJSON(&Request{Op: OpSubscribe, Protocols: []string{"UniswapV3"}}))
+	require.Eventually(t, func() bool {
+		s.Publish(testBlock(5))
+		frame := readFrame(t, conn)
+		return len(frame.Trades) == 2
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestServerReplayTooOld(t *testing.T) {
+	s := NewServer(testConf())
+	server := httptest.NewServer(NewHandler(s))
+	defer server.Close()
+
+	for height := uint64(1); height <= 4; height++ {
+		s.Publish(testBlock(height))
+	}
+
+	conn := dial(t, server, "from=1")
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	e := &Error{}
+	require.NoError(t, conn.ReadJSON(e))
+	require.Contains(t, e.Error, "oldest kept is 2")
+}
+
+func TestServerDisconnectsSlowClient(t *testing.T) {
+	s := NewServer(testConf())
+	server := httptest.NewServer(NewHandler(s))
+	defer server.Close()
+
+	dial(t, server, "pairs="+testPair.String())
+	waitClients(t, s, 1)
+
+	// the client never reads, the queue fills up once the socket buffers are full
+	require.Eventually(t, func() bool {
+		s.Publish(testBlock(1))
+		return s.Clients() == 0
+	}, 5*time.Second, time.Millisecond)
+}
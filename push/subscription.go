@@ -0,0 +1,150 @@
+package push
+
+import (
+	"base_scan/repository/orm"
+	"base_scan/types"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"net/url"
+	"strings"
+)
+
+const (
+	OpSubscribe   = "subscribe"
+	OpUnsubscribe = "unsubscribe"
+)
+
+var (
+	ErrUnknownOp = errors.New("unknown op")
+)
+
+/*
+Request is a message of a client, it adds (OpSubscribe) or removes (OpUnsubscribe) subscriptions.
+Protocols are protocol names like UniswapV2, see types.GetProtocolName.
+*/
+type Request struct {
+	Op        string   `json:"op"`
+	Pairs     []string `json:"pairs"`
+	Tokens    []string `json:"tokens"`
+	Makers    []string `json:"makers"`
+	Protocols []string `json:"protocols"`
+}
+
+// Subscription is what a client subscribed to, a trade or pool update matching any of it is sent.
+type Subscription struct {
+	Pairs     map[string]struct{}
+	Tokens    map[string]struct{}
+	Makers    map[string]struct{}
+	Protocols map[string]struct{}
+}
+
+func NewSubscription() *Subscription {
+	return &Subscription{
+		Pairs:     make(map[string]struct{}),
+		Tokens:    make(map[string]struct{}),
+		Makers:    make(map[string]struct{}),
+		Protocols: make(map[string]struct{}),
+	}
+}
+
+// requestFromQuery reads a subscribe request from the comma separated query params pairs, tokens, makers and protocols.
+func requestFromQuery(query url.Values) *Request {
+	split := func(key string) []string {
+		value := query.Get(key)
+		if value == "" {
+			return nil
+		}
+		return strings.Split(value, ",")
+	}
+
+	return &Request{
+		Op:        OpSubscribe,
+		Pairs:     split("pairs"),
+		Tokens:    split("tokens"),
+		Makers:    split("makers"),
+		Protocols: split("protocols"),
+	}
+}
+
+// normalizeAddresses returns the checksummed addresses orm.Tx and types.PoolUpdate are written with.
+func normalizeAddresses(addresses []string) ([]string, error) {
+	normalized := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		address = strings.TrimSpace(address)
+		if !common.IsHexAddress(address) {
+			return nil, fmt.Errorf("%q is not an address", address)
+		}
+		normalized = append(normalized, common.HexToAddress(address).String())
+	}
+	return normalized, nil
+}
+
+// Apply adds or removes the subscriptions of a request, nothing is changed when the request is invalid.
+func (s *Subscription) Apply(request *Request) error {
+	var apply func(set map[string]struct{}, keys []string)
+	switch request.Op {
+	case OpSubscribe:
+		apply = func(set map[string]struct{}, keys []string) {
+			for _, key := range keys {
+				set[key] = struct{}{}
+			}
+		}
+	case OpUnsubscribe:
+		apply = func(set map[string]struct{}, keys []string) {
+			for _, key := range keys {
+				delete(set, key)
+			}
+		}
+	default:
+		return fmt.Errorf("%w %q", ErrUnknownOp, request.Op)
+	}
+
+	pairs, err := normalizeAddresses(request.Pairs)
+	if err != nil {
+		return err
+	}
+	tokens, err := normalizeAddresses(request.Tokens)
+	if err != nil {
+		return err
+	}
+	makers, err := normalizeAddresses(request.Makers)
+	if err != nil {
+		return err
+	}
+	for _, protocol := range request.Protocols {
+		if types.GetProtocolId(protocol) == 0 {
+			return fmt.Errorf("unknown protocol %q", protocol)
+		}
+	}
+
+	apply(s.Pairs, pairs)
+	apply(s.Tokens, tokens)
+	apply(s.Makers, makers)
+	apply(s.Protocols, request.Protocols)
+	return nil
+}
+
+func (s *Subscription) Empty() bool {
+	return len(s.Pairs) == 0 && len(s.Tokens) == 0 && len(s.Makers) == 0 && len(s.Protocols) == 0
+}
+
+func has(set map[string]struct{}, key string) bool {
+	_, ok := set[key]
+	return ok
+}
+
+func (s *Subscription) MatchTrade(tx *orm.Tx) bool {
+	return has(s.Pairs, tx.PairAddress) ||
+		has(s.Tokens, tx.Token0Address) ||
+		has(s.Tokens, tx.Token1Address) ||
+		has(s.Makers, tx.Maker) ||
+		has(s.Protocols, tx.Program)
+}
+
+func (s *Subscription) MatchPoolUpdate(update *types.PoolUpdate) bool {
+	return has(s.Pairs, update.Address.String()) ||
+		has(s.Tokens, update.Token0Address.String()) ||
+		has(s.Tokens, update.Token1Address.String()) ||
+		has(s.Protocols, update.Program)
+}
@@ -0,0 +1,176 @@
+package quote
+
+import (
+	"base_scan/config"
+	"base_scan/log"
+	"base_scan/types"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+/*
+Engine keeps the swap state of the v2 and v3 pools in memory, built block by block from the pool logs,
+and quotes swaps on it with the math of the pools.
+A v3 pool is tracked from its creation, as its ticks can't be known otherwise, a v2 pool from its first sync.
+Blocks must be applied in order, when blocks are missed, e.g. the indexer restarted from an older snapshot,
+the state is dropped and tracking starts again.
+*/
+type Engine struct {
+	mu             sync.RWMutex
+	height         uint64
+	pools          map[common.Address]*Pool
+	snapshotPath   string
+	snapshotBlocks uint64
+}
+
+// snapshot is the saved state of an engine.
+type snapshot struct {
+	Height uint64
+	Pools  []*Pool
+}
+
+// NewEngine returns an engine with the state of the snapshot of the config, or an empty one when there is none.
+func NewEngine(conf *config.QuoteConf) (*Engine, error) {
+	e := &Engine{
+		pools:          make(map[common.Address]*Pool),
+		snapshotPath:   conf.SnapshotPath,
+		snapshotBlocks: conf.SnapshotBlocks,
+	}
+	if e.snapshotPath == "" {
+		return e, nil
+	}
+
+	data, err := os.ReadFile(e.snapshotPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return e, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read quote snapshot err: %w", err)
+	}
+
+	var s snapshot
+	if err = json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("unmarshal quote snapshot err: %w", err)
+	}
+	e.height = s.Height
+	for _, pool := range s.Pools {
+		if pool.Ticks == nil {
+			pool.Ticks = make(map[int32]*Tick)
+		}
+		pool.sortTicks()
+		e.pools[pool.Address] = pool
+	}
+	log.Logger.Info("quote snapshot loaded", zap.Uint64("height", e.height), zap.Int("pools", len(e.pools)))
+	return e, nil
+}
+
+// Height is the last block applied.
+func (e *Engine) Height() uint64 {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.height
+}
+
+// Apply changes the state by the updates of a block, in log order, and saves a snapshot when one is due.
+func (e *Engine) Apply(height uint64, updates []*types.PoolStateUpdate) {
+	e.mu.Lock()
+	if e.height != 0 && height <= e.height {
+		e.mu.Unlock()
+		return
+	}
+	if e.height != 0 && height > e.height+1 {
+		log.Logger.Warn("quote state dropped, blocks missed", zap.Uint64("height", e.height), zap.Uint64("block", height))
+		e.pools = make(map[common.Address]*Pool)
+	}
+
+	for _, u := range updates {
+		pool := e.poolOf(u)
+		if pool == nil {
+			continue
+		}
+		pool.apply(u)
+		pool.UpdatedBlock = height
+	}
+	e.height = height
+	e.mu.Unlock()
+
+	if e.snapshotPath != "" && height%e.snapshotBlocks == 0 {
+		if err := e.Save(); err != nil {
+			log.Logger.Error("save quote snapshot err", zap.Uint64("height", height), zap.Error(err))
+		}
+	}
+}
+
+// poolOf returns the pool of an update, creating it when the update starts the tracking, or nil.
+func (e *Engine) poolOf(u *types.PoolStateUpdate) *Pool {
+	if pool, ok := e.pools[u.Address]; ok {
+		return pool
+	}
+
+	var pool *Pool
+	switch {
+	case u.Kind == types.PoolStateCreate && isV3(u.ProtocolId):
+		pool = &Pool{FeePips: u.Fee, TickSpacing: u.TickSpacing, Ticks: make(map[int32]*Tick)}
+	case u.Kind == types.PoolStateSync && types.V2FeePips(u.ProtocolId) != 0:
+		pool = &Pool{FeePips: types.V2FeePips(u.ProtocolId)}
+	default:
+		return nil
+	}
+	pool.Address, pool.ProtocolId, pool.Token0, pool.Token1 = u.Address, u.ProtocolId, u.Token0, u.Token1
+	e.pools[u.Address] = pool
+	return pool
+}
+
+// Quote simulates swapping amountIn of tokenIn on a pool, without changing the state.
+func (e *Engine) Quote(poolAddress, tokenIn common.Address, amountIn *big.Int) (*Quote, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	pool, ok := e.pools[poolAddress]
+	if !ok {
+		return nil, ErrUnknownPool
+	}
+	return pool.quote(tokenIn, amountIn)
+}
+
+// Pool returns a copy of the state of a pool, or nil.
+func (e *Engine) Pool(poolAddress common.Address) *Pool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	pool, ok := e.pools[poolAddress]
+	if !ok {
+		return nil
+	}
+	return pool.copy()
+}
+
+// Save writes the state to the snapshot path, through a temporary file so that a crash keeps the previous snapshot.
+func (e *Engine) Save() error {
+	e.mu.RLock()
+	s := snapshot{Height: e.height, Pools: make([]*Pool, 0, len(e.pools))}
+	for _, pool := range e.pools {
+		s.Pools = append(s.Pools, pool.copy())
+	}
+	e.mu.RUnlock()
+
+	data, err := json.Marshal(&s)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(e.snapshotPath), 0755); err != nil {
+		return err
+	}
+	tmp := e.snapshotPath + ".tmp"
+	if err = os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, e.snapshotPath)
+}
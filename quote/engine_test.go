@@ -0,0 +1,220 @@
+package quote
+
+import (
+	"base_scan/config"
+	"base_scan/types"
+	"encoding/json"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+var (
+	pool   = common.HexToAddress("0x00000000000000000000000000000000000000f1")
+	token0 = common.HexToAddress("0x00000000000000000000000000000000000000a0")
+	token1 = common.HexToAddress("0x00000000000000000000000000000000000000a1")
+)
+
+func e18(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), big.NewInt(1e18))
+}
+
+func update(kind string, protocolId int) *types.PoolStateUpdate {
+	return &types.PoolStateUpdate{Kind: kind, ProtocolId: protocolId, Address: pool, Token0: token0, Token1: token1}
+}
+
+func liquidityUpdate(kind string, tickLower, tickUpper int32, liquidity *big.Int) *types.PoolStateUpdate {
+	u := update(kind, types.ProtocolIdUniswapV3)
+	u.TickLower, u.TickUpper, u.Liquidity = tickLower, tickUpper, liquidity
+	return u
+}
+
+// v3Updates create a 0.3% pool at price 1 with the liquidity of the ranges, by pairs of ticks.
+func v3Updates(liquidity *big.Int, ranges ...int32) []*types.PoolStateUpdate {
+	create := update(types.PoolStateCreate, types.ProtocolIdUniswapV3)
+	create.Fee, create.TickSpacing = 3000, 60
+	initialize := update(types.PoolStateInitialize, types.ProtocolIdUniswapV3)
+	initialize.SqrtPriceX96 = q96
+
+	updates := []*types.PoolStateUpdate{create, initialize}
+	for i := 0; i < len(ranges); i += 2 {
+		updates = append(updates, liquidityUpdate(types.PoolStateMint, ranges[i], ranges[i+1], liquidity))
+	}
+	return updates
+}
+
+func newTestEngine(t *testing.T) *Engine {
+	e, err := NewEngine(&config.QuoteConf{})
+	require.NoError(t, err)
+	return e
+}
+
+func TestQuoteV2(t *testing.T) {
+	e := newTestEngine(t)
+	sync := update(types.PoolStateSync, types.ProtocolIdUniswapV2)
+	sync.Reserve0, sync.Reserve1 = e18(100), big.NewInt(300000e6)
+	e.Apply(100, []*types.PoolStateUpdate{sync})
+
+	amountIn := e18(1)
+	q, err := e.Quote(pool, token0, amountIn)
+	require.NoError(t, err)
+	// UniswapV2Library.getAmountOut
+	amountInWithFee := new(big.Int).Mul(amountIn, big.NewInt(997))
+	numerator := new(big.Int).Mul(amountInWithFee, sync.Reserve1)
+	denominator := new(big.Int).Add(new(big.Int).Mul(sync.Reserve0, big.NewInt(1000)), amountInWithFee)
+	require.Equal(t, numerator.Quo(numerator, denominator), q.AmountOut)
+	require.Equal(t, token1, q.TokenOut)
+	require.Equal(t, uint64(100), q.Block)
+
+	_, err = e.Quote(pool, common.Address{}, amountIn)
+	require.ErrorIs(t, err, ErrUnknownToken)
+	_, err = e.Quote(token0, token0, amountIn)
+	require.ErrorIs(t, err, ErrUnknownPool)
+
+	// aerodrome pools have other curves and fees
+	aerodrome := update(types.PoolStateSync, types.ProtocolIdAerodrome)
+	aerodrome.Address = token0
+	e.Apply(101, []*types.PoolStateUpdate{aerodrome})
+	require.Nil(t, e.Pool(token0))
+}
+
+func TestQuoteV2Fork(t *testing.T) {
+	fork, err := types.RegisterFork("QuoteSwap", types.ProtocolIdUniswapV2, 2500)
+	require.NoError(t, err)
+
+	e := newTestEngine(t)
+	sync := update(types.PoolStateSync, fork.Id)
+	sync.Reserve0, sync.Reserve1 = e18(100), e18(100)
+	e.Apply(100, []*types.PoolStateUpdate{sync})
+	require.Equal(t, uint32(2500), e.Pool(pool).FeePips)
+
+	// the fee of discovered forks is unknown
+	discovered := update(types.PoolStateSync, types.ProtocolIdUniswapV2Fork)
+	discovered.Address = token0
+	e.Apply(101, []*types.PoolStateUpdate{discovered})
+	require.Nil(t, e.Pool(token0))
+}
+
+func TestQuoteV3InRange(t *testing.T) {
+	e := newTestEngine(t)
+	liquidity := e18(1000)
+	e.Apply(100, v3Updates(liquidity, -887220, 887220))
+	require.Equal(t, liquidity, e.Pool(pool).Liquidity)
+
+	amountIn := e18(1)
+	q, err := e.Quote(pool, token0, amountIn)
+	require.NoError(t, err)
+
+	// one step within the liquidity, see SqrtPriceMath.getNextSqrtPriceFromAmount0RoundingUp
+	amountLessFee := mulDiv(amountIn, big.NewInt(997000), big.NewInt(feePipsDenominator))
+	numerator := new(big.Int).Lsh(liquidity, 96)
+	sqrtPriceNext := mulDivRoundingUp(numerator, q96, new(big.Int).Add(numerator, new(big.Int).Mul(amountLessFee, q96)))
+	require.Equal(t, sqrtPriceNext, q.SqrtPriceX96)
+	require.Equal(t, mulDiv(liquidity, new(big.Int).Sub(q96, sqrtPriceNext), q96), q.AmountOut)
+	require.Equal(t, amountIn, q.AmountIn)
+	require.Equal(t, GetTickAtSqrtRatio(sqrtPriceNext), q.Tick)
+
+	// quoting doesn't change the state
+	require.Equal(t, q96, e.Pool(pool).SqrtPriceX96)
+}
+
+func TestQuoteV3CrossTicks(t *testing.T) {
+	fullRange := newTestEngine(t)
+	fullRange.Apply(100, v3Updates(e18(1000), -887220, 887220))
+	twice := newTestEngine(t)
+	twice.Apply(100, v3Updates(e18(2000), -887220, 887220))
+	e := newTestEngine(t)
+	e.Apply(100, v3Updates(e18(1000), -887220, 887220, -60, 60))
+	require.Equal(t, e18(2000), e.Pool(pool).Liquidity)
+
+	amountIn := e18(50)
+	q, err := e.Quote(pool, token0, amountIn)
+	require.NoError(t, err)
+	require.Less(t, q.Tick, int32(-60))
+	low, _ := fullRange.Quote(pool, token0, amountIn)
+	high, _ := twice.Quote(pool, token0, amountIn)
+	require.Equal(t, 1, q.AmountOut.Cmp(low.AmountOut))
+	require.Equal(t, -1, q.AmountOut.Cmp(high.AmountOut))
+
+	// removing the range leaves the full range only
+	e.Apply(101, []*types.PoolStateUpdate{liquidityUpdate(types.PoolStateBurn, -60, 60, e18(1000))})
+	require.Len(t, e.Pool(pool).Ticks, 2)
+	q, err = e.Quote(pool, token0, amountIn)
+	require.NoError(t, err)
+	require.Equal(t, low.AmountOut, q.AmountOut)
+}
+
+func TestQuoteV3OutOfLiquidity(t *testing.T) {
+	e := newTestEngine(t)
+	e.Apply(100, v3Updates(e18(1), -60, 60))
+
+	q, err := e.Quote(pool, token1, e18(1000))
+	require.NoError(t, err)
+	require.Equal(t, -1, q.AmountIn.Cmp(e18(1000)))
+	require.Equal(t, MaxTick-1, q.Tick)
+}
+
+func TestApplyBlocks(t *testing.T) {
+	e := newTestEngine(t)
+	e.Apply(100, v3Updates(e18(1000), -887220, 887220))
+
+	// a block applied again is skipped
+	e.Apply(100, []*types.PoolStateUpdate{liquidityUpdate(types.PoolStateMint, -60, 60, e18(1))})
+	require.Len(t, e.Pool(pool).Ticks, 2)
+
+	// the ticks can't be known for pools not seen created
+	swap := update(types.PoolStateSwap, types.ProtocolIdUniswapV3)
+	swap.Address = token0
+	e.Apply(101, []*types.PoolStateUpdate{swap})
+	require.Nil(t, e.Pool(token0))
+
+	// a gap drops the state
+	e.Apply(103, nil)
+	require.Nil(t, e.Pool(pool))
+	require.Equal(t, uint64(103), e.Height())
+}
+
+func TestSnapshot(t *testing.T) {
+	conf := &config.QuoteConf{SnapshotPath: filepath.Join(t.TempDir(), "quote", "snapshot.json"), SnapshotBlocks: 10}
+	e, err := NewEngine(conf)
+	require.NoError(t, err)
+	e.Apply(99, v3Updates(e18(1000), -887220, 887220, -60, 60))
+	before, err := e.Quote(pool, token0, e18(50))
+	require.NoError(t, err)
+
+	// saved at the block of the snapshot interval
+	e.Apply(100, nil)
+	loaded, err := NewEngine(conf)
+	require.NoError(t, err)
+	require.Equal(t, uint64(100), loaded.Height())
+	after, err := loaded.Quote(pool, token0, e18(50))
+	require.NoError(t, err)
+	require.Equal(t, before, after)
+}
+
+func TestHandler(t *testing.T) {
+	e := newTestEngine(t)
+	e.Apply(100, v3Updates(e18(1000), -887220, 887220))
+	server := httptest.NewServer(NewHandler(e))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/quote?pool=" + pool.String() + "&token_in=" + token1.String() + "&amount_in=1000000000000000000")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body quoteResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	q, _ := e.Quote(pool, token1, e18(1))
+	require.Equal(t, q.AmountOut.String(), body.AmountOut)
+	require.Equal(t, token0.String(), body.TokenOut)
+
+	resp, err = http.Get(server.URL + "/quote?pool=" + token0.String() + "&token_in=" + token1.String() + "&amount_in=1")
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
@@ -0,0 +1,96 @@
+package quote
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+	"net/http"
+)
+
+// quoteResponse is a Quote with the big numbers as decimal strings, which json clients can't lose precision of.
+type quoteResponse struct {
+	Pool         string `json:"pool"`
+	TokenIn      string `json:"token_in"`
+	TokenOut     string `json:"token_out"`
+	AmountIn     string `json:"amount_in"`
+	AmountOut    string `json:"amount_out"`
+	SqrtPriceX96 string `json:"sqrt_price_x96,omitempty"`
+	Tick         int32  `json:"tick,omitempty"`
+	Block        uint64 `json:"block"`
+	Height       uint64 `json:"height"`
+}
+
+/*
+NewHandler serves the quote api of an engine:
+GET /quote?pool=0x..&token_in=0x..&amount_in=1000000000000000000 quotes a swap of amount_in wei,
+GET /pool?address=0x.. returns the state of a pool.
+*/
+func NewHandler(engine *Engine) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/quote", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		pool, tokenIn := query.Get("pool"), query.Get("token_in")
+		if !common.IsHexAddress(pool) || !common.IsHexAddress(tokenIn) {
+			http.Error(w, "pool and token_in must be addresses", http.StatusBadRequest)
+			return
+		}
+		amountIn, ok := new(big.Int).SetString(query.Get("amount_in"), 10)
+		if !ok {
+			http.Error(w, "amount_in must be an integer in wei", http.StatusBadRequest)
+			return
+		}
+
+		q, err := engine.Quote(common.HexToAddress(pool), common.HexToAddress(tokenIn), amountIn)
+		if err != nil {
+			http.Error(w, err.Error(), statusOf(err))
+			return
+		}
+
+		resp := &quoteResponse{
+			Pool:      q.Pool.String(),
+			TokenIn:   q.TokenIn.String(),
+			TokenOut:  q.TokenOut.String(),
+			AmountIn:  q.AmountIn.String(),
+			AmountOut: q.AmountOut.String(),
+			Tick:      q.Tick,
+			Block:     q.Block,
+			Height:    engine.Height(),
+		}
+		if q.SqrtPriceX96 != nil {
+			resp.SqrtPriceX96 = q.SqrtPriceX96.String()
+		}
+		writeJSON(w, resp)
+	})
+	mux.HandleFunc("/pool", func(w http.ResponseWriter, r *http.Request) {
+		address := r.URL.Query().Get("address")
+		if !common.IsHexAddress(address) {
+			http.Error(w, "address must be an address", http.StatusBadRequest)
+			return
+		}
+
+		pool := engine.Pool(common.HexToAddress(address))
+		if pool == nil {
+			http.Error(w, ErrUnknownPool.Error(), http.StatusNotFound)
+			return
+		}
+		writeJSON(w, pool)
+	})
+	return mux
+}
+
+func statusOf(err error) int {
+	switch {
+	case errors.Is(err, ErrUnknownPool):
+		return http.StatusNotFound
+	case errors.Is(err, ErrUnknownToken), errors.Is(err, ErrInvalidAmount):
+		return http.StatusBadRequest
+	default:
+		return http.StatusUnprocessableEntity
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,291 @@
+package quote
+
+import (
+	"base_scan/types"
+	"errors"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+	"sort"
+)
+
+var (
+	ErrUnknownPool       = errors.New("pool is not tracked")
+	ErrUnknownToken      = errors.New("token is not in the pool")
+	ErrInvalidAmount     = errors.New("amount in must be > 0")
+	ErrNoLiquidity       = errors.New("pool has no liquidity")
+	ErrNotInitialized    = errors.New("pool is not initialized")
+	ErrInconsistentState = errors.New("pool state is inconsistent")
+)
+
+// v3ProtocolIds are the protocols with the concentrated liquidity math of uniswap v3
+var v3ProtocolIds = map[int]bool{
+	types.ProtocolIdUniswapV3: true,
+	types.ProtocolIdPancakeV3: true,
+}
+
+func isV3(protocolId int) bool {
+	return v3ProtocolIds[types.BaseProtocolId(protocolId)]
+}
+
+// Tick is an initialized tick of a v3 pool, LiquidityNet is added to the liquidity when the price crosses it upwards.
+type Tick struct {
+	LiquidityGross *big.Int
+	LiquidityNet   *big.Int
+}
+
+/*
+Pool is the swap state of a v2 or v3 pool. A v2 pool has reserves, a v3 pool the price,
+the liquidity in range and the initialized ticks. FeePips is in hundredths of a bip, 3000 is 0.3%.
+*/
+type Pool struct {
+	Address      common.Address
+	ProtocolId   int
+	Token0       common.Address
+	Token1       common.Address
+	FeePips      uint32
+	UpdatedBlock uint64
+
+	Reserve0 *big.Int `json:",omitempty"`
+	Reserve1 *big.Int `json:",omitempty"`
+
+	TickSpacing  int32           `json:",omitempty"`
+	SqrtPriceX96 *big.Int        `json:",omitempty"`
+	Liquidity    *big.Int        `json:",omitempty"`
+	Tick         int32           `json:",omitempty"`
+	Ticks        map[int32]*Tick `json:",omitempty"`
+	sortedTicks  []int32
+}
+
+func (p *Pool) IsV3() bool {
+	return p.TickSpacing != 0
+}
+
+func (p *Pool) initialized() bool {
+	return p.SqrtPriceX96 != nil
+}
+
+// sortTicks rebuilds the sorted initialized ticks searched by nextInitializedTick.
+func (p *Pool) sortTicks() {
+	p.sortedTicks = make([]int32, 0, len(p.Ticks))
+	for tick := range p.Ticks {
+		p.sortedTicks = append(p.sortedTicks, tick)
+	}
+	sort.Slice(p.sortedTicks, func(i, j int) bool { return p.sortedTicks[i] < p.sortedTicks[j] })
+}
+
+// apply changes the state by an update of the pool.
+func (p *Pool) apply(u *types.PoolStateUpdate) {
+	switch u.Kind {
+	case types.PoolStateSync:
+		p.Reserve0, p.Reserve1 = u.Reserve0, u.Reserve1
+	case types.PoolStateInitialize:
+		p.SqrtPriceX96, p.Tick = u.SqrtPriceX96, u.Tick
+		p.Liquidity = new(big.Int)
+	case types.PoolStateSwap:
+		p.SqrtPriceX96, p.Liquidity, p.Tick = u.SqrtPriceX96, u.Liquidity, u.Tick
+	case types.PoolStateMint, types.PoolStateBurn:
+		delta := new(big.Int).Set(u.Liquidity)
+		if u.Kind == types.PoolStateBurn {
+			delta.Neg(delta)
+		}
+		p.updateTick(u.TickLower, delta, false)
+		p.updateTick(u.TickUpper, delta, true)
+		if p.initialized() && p.Tick >= u.TickLower && p.Tick < u.TickUpper {
+			p.Liquidity = new(big.Int).Add(p.Liquidity, delta)
+		}
+	}
+}
+
+func (p *Pool) updateTick(tick int32, delta *big.Int, upper bool) {
+	if delta.Sign() == 0 {
+		return
+	}
+
+	t, ok := p.Ticks[tick]
+	if !ok {
+		t = &Tick{LiquidityGross: new(big.Int), LiquidityNet: new(big.Int)}
+	}
+	t.LiquidityGross = new(big.Int).Add(t.LiquidityGross, delta)
+	if upper {
+		t.LiquidityNet = new(big.Int).Sub(t.LiquidityNet, delta)
+	} else {
+		t.LiquidityNet = new(big.Int).Add(t.LiquidityNet, delta)
+	}
+
+	if t.LiquidityGross.Sign() <= 0 {
+		delete(p.Ticks, tick)
+	} else {
+		p.Ticks[tick] = t
+	}
+	if ok != (t.LiquidityGross.Sign() > 0) {
+		p.sortTicks()
+	}
+}
+
+/*
+nextInitializedTick is TickBitmap.nextInitializedTickWithinOneWord, it returns the next initialized tick
+at or below tick when lte, else above tick, but not beyond the word of 256 tick spacings the search starts in.
+When there is none the word boundary is returned, which the swap stops at like the pool does.
+*/
+func (p *Pool) nextInitializedTick(tick int32, lte bool) (int32, bool) {
+	compressed := tick / p.TickSpacing
+	if tick < 0 && tick%p.TickSpacing != 0 {
+		compressed--
+	}
+
+	if lte {
+		lowest := (compressed - compressed&0xff) * p.TickSpacing
+		i := sort.Search(len(p.sortedTicks), func(i int) bool { return p.sortedTicks[i] > compressed*p.TickSpacing }) - 1
+		if i >= 0 && p.sortedTicks[i] >= lowest {
+			return p.sortedTicks[i], true
+		}
+		return lowest, false
+	}
+
+	next := compressed + 1
+	highest := (next + 255 - next&0xff) * p.TickSpacing
+	i := sort.Search(len(p.sortedTicks), func(i int) bool { return p.sortedTicks[i] >= next*p.TickSpacing })
+	if i < len(p.sortedTicks) && p.sortedTicks[i] <= highest {
+		return p.sortedTicks[i], true
+	}
+	return highest, false
+}
+
+/*
+Quote is the result of swapping AmountIn of TokenIn on a pool at Block.
+AmountIn is less than asked when the price reached the bound of the pool before, with nothing left to swap against.
+SqrtPriceX96 and Tick are the state of a v3 pool after the swap.
+*/
+type Quote struct {
+	Pool         common.Address
+	TokenIn      common.Address
+	TokenOut     common.Address
+	AmountIn     *big.Int
+	AmountOut    *big.Int
+	SqrtPriceX96 *big.Int
+	Tick         int32
+	Block        uint64
+}
+
+func (p *Pool) quote(tokenIn common.Address, amountIn *big.Int) (*Quote, error) {
+	if amountIn == nil || amountIn.Sign() <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	var zeroForOne bool
+	switch tokenIn {
+	case p.Token0:
+		zeroForOne = true
+	case p.Token1:
+		zeroForOne = false
+	default:
+		return nil, ErrUnknownToken
+	}
+
+	q := &Quote{Pool: p.Address, TokenIn: tokenIn, TokenOut: p.Token1, Block: p.UpdatedBlock}
+	if !zeroForOne {
+		q.TokenOut = p.Token0
+	}
+
+	var err error
+	if p.IsV3() {
+		q.AmountIn, q.AmountOut, q.SqrtPriceX96, q.Tick, err = p.swapV3(zeroForOne, amountIn)
+	} else {
+		q.AmountIn, q.AmountOut, err = p.swapV2(zeroForOne, amountIn)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// swapV2 is UniswapV2Library.getAmountOut with the fee of the protocol.
+func (p *Pool) swapV2(zeroForOne bool, amountIn *big.Int) (*big.Int, *big.Int, error) {
+	reserveIn, reserveOut := p.Reserve0, p.Reserve1
+	if !zeroForOne {
+		reserveIn, reserveOut = reserveOut, reserveIn
+	}
+	if reserveIn == nil || reserveIn.Sign() == 0 || reserveOut.Sign() == 0 {
+		return nil, nil, ErrNoLiquidity
+	}
+
+	amountInWithFee := new(big.Int).Mul(amountIn, big.NewInt(feePipsDenominator-int64(p.FeePips)))
+	numerator := new(big.Int).Mul(amountInWithFee, reserveOut)
+	denominator := new(big.Int).Mul(reserveIn, big.NewInt(feePipsDenominator))
+	denominator.Add(denominator, amountInWithFee)
+	return new(big.Int).Set(amountIn), numerator.Quo(numerator, denominator), nil
+}
+
+// swapV3 is the loop of UniswapV3Pool.swap for an exact input without price limit.
+func (p *Pool) swapV3(zeroForOne bool, amountIn *big.Int) (*big.Int, *big.Int, *big.Int, int32, error) {
+	if !p.initialized() {
+		return nil, nil, nil, 0, ErrNotInitialized
+	}
+
+	sqrtPriceLimit := new(big.Int).Add(MinSqrtRatio, big.NewInt(1))
+	if !zeroForOne {
+		sqrtPriceLimit.Sub(MaxSqrtRatio, big.NewInt(1))
+	}
+
+	amountRemaining := new(big.Int).Set(amountIn)
+	amountOut := new(big.Int)
+	sqrtPrice := new(big.Int).Set(p.SqrtPriceX96)
+	liquidity := new(big.Int).Set(p.Liquidity)
+	tick := p.Tick
+	for amountRemaining.Sign() != 0 && sqrtPrice.Cmp(sqrtPriceLimit) != 0 {
+		sqrtPriceStart := sqrtPrice
+		tickNext, initialized := p.nextInitializedTick(tick, zeroForOne)
+		if tickNext < MinTick {
+			tickNext = MinTick
+		} else if tickNext > MaxTick {
+			tickNext = MaxTick
+		}
+		sqrtPriceNext := GetSqrtRatioAtTick(tickNext)
+
+		sqrtPriceTarget := sqrtPriceNext
+		if zeroForOne && sqrtPriceNext.Cmp(sqrtPriceLimit) < 0 || !zeroForOne && sqrtPriceNext.Cmp(sqrtPriceLimit) > 0 {
+			sqrtPriceTarget = sqrtPriceLimit
+		}
+
+		var stepIn, stepOut, stepFee *big.Int
+		sqrtPrice, stepIn, stepOut, stepFee = computeSwapStep(sqrtPrice, sqrtPriceTarget, liquidity, amountRemaining, p.FeePips)
+		amountRemaining.Sub(amountRemaining, stepIn).Sub(amountRemaining, stepFee)
+		amountOut.Add(amountOut, stepOut)
+
+		if sqrtPrice.Cmp(sqrtPriceNext) == 0 {
+			if initialized {
+				liquidityNet := p.Ticks[tickNext].LiquidityNet
+				if zeroForOne {
+					liquidity.Sub(liquidity, liquidityNet)
+				} else {
+					liquidity.Add(liquidity, liquidityNet)
+				}
+				if liquidity.Sign() < 0 {
+					return nil, nil, nil, 0, ErrInconsistentState
+				}
+			}
+			tick = tickNext
+			if zeroForOne {
+				tick--
+			}
+		} else if sqrtPrice.Cmp(sqrtPriceStart) != 0 {
+			tick = GetTickAtSqrtRatio(sqrtPrice)
+		}
+	}
+
+	if amountOut.Sign() == 0 {
+		return nil, nil, nil, 0, ErrNoLiquidity
+	}
+	return new(big.Int).Sub(amountIn, amountRemaining), amountOut, sqrtPrice, tick, nil
+}
+
+// copy returns a deep copy of the pool, for snapshots and the api.
+func (p *Pool) copy() *Pool {
+	c := *p
+	c.Ticks = make(map[int32]*Tick, len(p.Ticks))
+	for tick, t := range p.Ticks {
+		c.Ticks[tick] = &Tick{LiquidityGross: t.LiquidityGross, LiquidityNet: t.LiquidityNet}
+	}
+	c.sortedTicks = nil
+	return &c
+}
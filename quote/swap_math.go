@@ -0,0 +1,120 @@
+package quote
+
+import "math/big"
+
+// feePipsDenominator is the denominator of fees in hundredths of a bip, 3000 is 0.3%
+const feePipsDenominator = 1000000
+
+func mulDiv(a, b, denominator *big.Int) *big.Int {
+	n := new(big.Int).Mul(a, b)
+	return n.Quo(n, denominator)
+}
+
+func mulDivRoundingUp(a, b, denominator *big.Int) *big.Int {
+	n := new(big.Int).Mul(a, b)
+	return divRoundingUp(n, denominator)
+}
+
+func divRoundingUp(a, b *big.Int) *big.Int {
+	q, r := new(big.Int).QuoRem(a, b, new(big.Int))
+	if r.Sign() != 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	return q
+}
+
+// getAmount0Delta is SqrtPriceMath.getAmount0Delta, the token0 between two prices for a liquidity.
+func getAmount0Delta(sqrtRatioA, sqrtRatioB, liquidity *big.Int, roundUp bool) *big.Int {
+	if sqrtRatioA.Cmp(sqrtRatioB) > 0 {
+		sqrtRatioA, sqrtRatioB = sqrtRatioB, sqrtRatioA
+	}
+
+	numerator1 := new(big.Int).Lsh(liquidity, 96)
+	numerator2 := new(big.Int).Sub(sqrtRatioB, sqrtRatioA)
+	if roundUp {
+		return divRoundingUp(mulDivRoundingUp(numerator1, numerator2, sqrtRatioB), sqrtRatioA)
+	}
+	amount := mulDiv(numerator1, numerator2, sqrtRatioB)
+	return amount.Quo(amount, sqrtRatioA)
+}
+
+// getAmount1Delta is SqrtPriceMath.getAmount1Delta, the token1 between two prices for a liquidity.
+func getAmount1Delta(sqrtRatioA, sqrtRatioB, liquidity *big.Int, roundUp bool) *big.Int {
+	if sqrtRatioA.Cmp(sqrtRatioB) > 0 {
+		sqrtRatioA, sqrtRatioB = sqrtRatioB, sqrtRatioA
+	}
+
+	diff := new(big.Int).Sub(sqrtRatioB, sqrtRatioA)
+	if roundUp {
+		return mulDivRoundingUp(liquidity, diff, q96)
+	}
+	return mulDiv(liquidity, diff, q96)
+}
+
+/*
+getNextSqrtPriceFromInput is SqrtPriceMath.getNextSqrtPriceFromInput.
+The token0 branch keeps the overflow checks of the 256-bit version, they select a different rounding.
+*/
+func getNextSqrtPriceFromInput(sqrtPriceX96, liquidity, amountIn *big.Int, zeroForOne bool) *big.Int {
+	if !zeroForOne {
+		quotient := mulDiv(amountIn, q96, liquidity)
+		return quotient.Add(quotient, sqrtPriceX96)
+	}
+
+	if amountIn.Sign() == 0 {
+		return new(big.Int).Set(sqrtPriceX96)
+	}
+	numerator1 := new(big.Int).Lsh(liquidity, 96)
+	product := new(big.Int).Mul(amountIn, sqrtPriceX96)
+	if product.Cmp(maxUint256) <= 0 {
+		denominator := new(big.Int).Add(numerator1, product)
+		if denominator.Cmp(maxUint256) <= 0 {
+			return mulDivRoundingUp(numerator1, sqrtPriceX96, denominator)
+		}
+	}
+	denominator := new(big.Int).Quo(numerator1, sqrtPriceX96)
+	return divRoundingUp(numerator1, denominator.Add(denominator, amountIn))
+}
+
+/*
+computeSwapStep is SwapMath.computeSwapStep for an exact input, it swaps amountRemaining, fees included,
+from sqrtRatioCurrent towards sqrtRatioTarget within one liquidity.
+*/
+func computeSwapStep(sqrtRatioCurrent, sqrtRatioTarget, liquidity, amountRemaining *big.Int, feePips uint32) (sqrtRatioNext, amountIn, amountOut, feeAmount *big.Int) {
+	zeroForOne := sqrtRatioCurrent.Cmp(sqrtRatioTarget) >= 0
+	fee := big.NewInt(int64(feePips))
+	feeComplement := big.NewInt(feePipsDenominator - int64(feePips))
+
+	amountRemainingLessFee := mulDiv(amountRemaining, feeComplement, big.NewInt(feePipsDenominator))
+	if zeroForOne {
+		amountIn = getAmount0Delta(sqrtRatioTarget, sqrtRatioCurrent, liquidity, true)
+	} else {
+		amountIn = getAmount1Delta(sqrtRatioCurrent, sqrtRatioTarget, liquidity, true)
+	}
+	if amountRemainingLessFee.Cmp(amountIn) >= 0 {
+		sqrtRatioNext = new(big.Int).Set(sqrtRatioTarget)
+	} else {
+		sqrtRatioNext = getNextSqrtPriceFromInput(sqrtRatioCurrent, liquidity, amountRemainingLessFee, zeroForOne)
+	}
+
+	reached := sqrtRatioNext.Cmp(sqrtRatioTarget) == 0
+	if zeroForOne {
+		if !reached {
+			amountIn = getAmount0Delta(sqrtRatioNext, sqrtRatioCurrent, liquidity, true)
+		}
+		amountOut = getAmount1Delta(sqrtRatioNext, sqrtRatioCurrent, liquidity, false)
+	} else {
+		if !reached {
+			amountIn = getAmount1Delta(sqrtRatioCurrent, sqrtRatioNext, liquidity, true)
+		}
+		amountOut = getAmount0Delta(sqrtRatioCurrent, sqrtRatioNext, liquidity, false)
+	}
+
+	if !reached {
+		// the remainder of an exact input is the fee
+		feeAmount = new(big.Int).Sub(amountRemaining, amountIn)
+	} else {
+		feeAmount = mulDivRoundingUp(amountIn, fee, feeComplement)
+	}
+	return
+}
@@ -0,0 +1,102 @@
+package quote
+
+import (
+	"math"
+	"math/big"
+)
+
+// bounds of the ticks and prices of v3 pools, see TickMath.sol
+const (
+	MinTick int32 = -887272
+	MaxTick int32 = 887272
+)
+
+var (
+	MinSqrtRatio = big.NewInt(4295128739)
+	MaxSqrtRatio = hexBig("fffd8963efd1fc6a506488495d951d5263988d26")
+
+	q96        = new(big.Int).Lsh(big.NewInt(1), 96)
+	q128       = new(big.Int).Lsh(big.NewInt(1), 128)
+	maxUint160 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 160), big.NewInt(1))
+	maxUint256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+	// 1/sqrt(1.0001)^(2^i) in Q128.128 for the bits of a tick, the first one is for bit 0
+	sqrtRatioFactors = []*big.Int{
+		hexBig("fffcb933bd6fad37aa2d162d1a594001"),
+		hexBig("fff97272373d413259a46990580e213a"),
+		hexBig("fff2e50f5f656932ef12357cf3c7fdcc"),
+		hexBig("ffe5caca7e10e4e61c3624eaa0941cd0"),
+		hexBig("ffcb9843d60f6159c9db58835c926644"),
+		hexBig("ff973b41fa98c081472e6896dfb254c0"),
+		hexBig("ff2ea16466c96a3843ec78b326b52861"),
+		hexBig("fe5dee046a99a2a811c461f1969c3053"),
+		hexBig("fcbe86c7900a88aedcffc83b479aa3a4"),
+		hexBig("f987a7253ac413176f2b074cf7815e54"),
+		hexBig("f3392b0822b70005940c7a398e4b70f3"),
+		hexBig("e7159475a2c29b7443b29c7fa6e889d9"),
+		hexBig("d097f3bdfd2022b8845ad8f792aa5825"),
+		hexBig("a9f746462d870fdf8a65dc1f90e061e5"),
+		hexBig("70d869a156d2a1b890bb3df62baf32f7"),
+		hexBig("31be135f97d08fd981231505542fcfa6"),
+		hexBig("9aa508b5b7a84e1c677de54f3e99bc9"),
+		hexBig("5d6af8dedb81196699c329225ee604"),
+		hexBig("2216e584f5fa1ea926041bedfe98"),
+		hexBig("48a170391f7dc42444e8fa2"),
+	}
+)
+
+func hexBig(s string) *big.Int {
+	n, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		panic("invalid hex " + s)
+	}
+	return n
+}
+
+// GetSqrtRatioAtTick returns sqrt(1.0001^tick) in Q64.96, rounded like TickMath.getSqrtRatioAtTick.
+func GetSqrtRatioAtTick(tick int32) *big.Int {
+	absTick := tick
+	if tick < 0 {
+		absTick = -tick
+	}
+
+	ratio := new(big.Int).Set(q128)
+	for i, factor := range sqrtRatioFactors {
+		if absTick&(1<<i) == 0 {
+			continue
+		}
+		if i == 0 {
+			ratio.Set(factor)
+			continue
+		}
+		ratio.Rsh(ratio.Mul(ratio, factor), 128)
+	}
+	if tick > 0 {
+		ratio.Quo(maxUint256, ratio)
+	}
+
+	// Q128.128 to Q64.96, rounding up
+	sqrtPriceX96 := new(big.Int).Rsh(ratio, 32)
+	if new(big.Int).And(ratio, big.NewInt(math.MaxUint32)).Sign() != 0 {
+		sqrtPriceX96.Add(sqrtPriceX96, big.NewInt(1))
+	}
+	return sqrtPriceX96
+}
+
+/*
+GetTickAtSqrtRatio returns the greatest tick whose sqrt ratio is at most sqrtPriceX96, like TickMath.getTickAtSqrtRatio.
+The tick is estimated in floating point and then corrected with GetSqrtRatioAtTick, which gives the same exact result.
+*/
+func GetTickAtSqrtRatio(sqrtPriceX96 *big.Int) int32 {
+	f, _ := new(big.Float).SetInt(sqrtPriceX96).Float64()
+	estimate := math.Floor(2 * (math.Log2(f) - 96) / math.Log2(1.0001))
+	tick := int32(math.Max(math.Min(estimate, float64(MaxTick)), float64(MinTick)))
+
+	for tick > MinTick && GetSqrtRatioAtTick(tick).Cmp(sqrtPriceX96) > 0 {
+		tick--
+	}
+	for tick < MaxTick && GetSqrtRatioAtTick(tick+1).Cmp(sqrtPriceX96) <= 0 {
+		tick++
+	}
+	return tick
+}
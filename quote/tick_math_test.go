@@ -0,0 +1,32 @@
+package quote
+
+import (
+	"github.com/stretchr/testify/require"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestGetSqrtRatioAtTick(t *testing.T) {
+	require.Equal(t, MinSqrtRatio, GetSqrtRatioAtTick(MinTick))
+	require.Equal(t, "1461446703485210103287273052203988822378723970342", GetSqrtRatioAtTick(MaxTick).String())
+	require.Equal(t, q96, GetSqrtRatioAtTick(0))
+
+	for _, tick := range []int32{-600000, -50000, -1, 1, 12345, 200000} {
+		expected := math.Pow(1.0001, float64(tick)/2)
+		actual, _ := new(big.Float).Quo(new(big.Float).SetInt(GetSqrtRatioAtTick(tick)), new(big.Float).SetInt(q96)).Float64()
+		require.InEpsilon(t, expected, actual, 1e-9, "tick %d", tick)
+	}
+}
+
+func TestGetTickAtSqrtRatio(t *testing.T) {
+	require.Equal(t, MinTick, GetTickAtSqrtRatio(MinSqrtRatio))
+	require.Equal(t, MaxTick-1, GetTickAtSqrtRatio(new(big.Int).Sub(MaxSqrtRatio, big.NewInt(1))))
+
+	for _, tick := range []int32{-887000, -600000, -50000, -1, 0, 1, 12345, 200000, 887000} {
+		sqrtRatio := GetSqrtRatioAtTick(tick)
+		require.Equal(t, tick, GetTickAtSqrtRatio(sqrtRatio))
+		require.Equal(t, tick-1, GetTickAtSqrtRatio(new(big.Int).Sub(sqrtRatio, big.NewInt(1))))
+		require.Equal(t, tick, GetTickAtSqrtRatio(new(big.Int).Add(sqrtRatio, big.NewInt(1))))
+	}
+}
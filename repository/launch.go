@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"base_scan/repository/orm"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LaunchRepository struct {
+	*BaseRepository[orm.Launch]
+	chainId int
+}
+
+func NewLaunchRepository(db *gorm.DB, chainId uint64) *LaunchRepository {
+	baseRepo := NewBaseRepository[orm.Launch](db)
+	return &LaunchRepository{BaseRepository: baseRepo, chainId: int(chainId)}
+}
+
+/*
+UpsertBatch stores the launches and graduations of one block, see types.MergeLaunches.
+A graduation only sets the pool and the graduation of the launch, the launch fields are kept.
+*/
+func (r *LaunchRepository) UpsertBatch(launches []*orm.Launch) error {
+	if len(launches) == 0 {
+		return nil
+	}
+
+	ifGraduation := func(column string) clause.Expr {
+		return gorm.Expr("CASE WHEN EXCLUDED.graduated_block > 0 THEN EXCLUDED." + column + " ELSE launch." + column + " END")
+	}
+
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "token"}, {Name: "chain_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"pool":            gorm.Expr("CASE WHEN EXCLUDED.pool <> '' THEN EXCLUDED.pool ELSE launch.pool END"),
+			"graduated_block": ifGraduation("graduated_block"),
+			"graduated_at":    ifGraduation("graduated_at"),
+		}),
+	}).CreateInBatches(launches, 200).Error
+}
+
+func (r *LaunchRepository) GetByTokenAndChainId(token string) (*orm.Launch, error) {
+	var launch orm.Launch
+	err := r.db.Where("token = ? AND chain_id = ?", token, r.chainId).First(&launch).Error
+	if err != nil {
+		return nil, err
+	}
+	return &launch, nil
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"base_scan/repository/orm"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MakerRepository struct {
+	*BaseRepository[orm.Maker]
+}
+
+func NewMakerRepository(db *gorm.DB) *MakerRepository {
+	baseRepo := NewBaseRepository[orm.Maker](db)
+	return &MakerRepository{BaseRepository: baseRepo}
+}
+
+// UpsertBatch overwrites the profiles of makers that already exist, except when they were created.
+func (r *MakerRepository) UpsertBatch(makers []*orm.Maker) error {
+	if len(makers) == 0 {
+		return nil
+	}
+
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "chain_id"}, {Name: "address"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"kind", "delegate_address", "class", "first_block", "last_block",
+			"trades", "buys", "sells", "volume_usd", "active_blocks", "busy_blocks", "round_trips",
+			"recent_trades", "recent_busy_blocks", "recent_round_trips", "updated_at",
+		}),
+	}).CreateInBatches(makers, 200).Error
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"base_scan/repository/orm"
+	"gorm.io/gorm"
+)
+
+type MevRepository struct {
+	*BaseRepository[orm.Mev]
+}
+
+func NewMevRepository(db *gorm.DB) *MevRepository {
+	baseRepo := NewBaseRepository[orm.Mev](db)
+	return &MevRepository{BaseRepository: baseRepo}
+}
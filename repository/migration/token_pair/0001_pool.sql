@@ -0,0 +1,15 @@
+-- multi-asset pools, see orm.Pool
+CREATE TABLE IF NOT EXISTS pool
+(
+    address    varchar(42) NOT NULL,
+    pool_id    varchar(66) NOT NULL DEFAULT '',
+    tokens     text        NOT NULL,
+    chain_id   integer     NOT NULL,
+    block      bigint      NOT NULL,
+    block_at   timestamp   NOT NULL,
+    program    varchar(64) NOT NULL,
+    created_at timestamp   NOT NULL DEFAULT now(),
+    UNIQUE (address, chain_id)
+);
+
+CREATE INDEX IF NOT EXISTS pool_chain_block_idx ON pool (chain_id, block);
@@ -0,0 +1,18 @@
+-- tokens launched on bonding curve launchpads, see orm.Launch
+CREATE TABLE IF NOT EXISTS launch
+(
+    token           varchar(42) NOT NULL,
+    chain_id        integer     NOT NULL,
+    program         varchar(64) NOT NULL,
+    curve           varchar(42) NOT NULL,
+    creator         varchar(42) NOT NULL,
+    pool            varchar(42) NOT NULL DEFAULT '',
+    block           bigint      NOT NULL,
+    block_at        timestamp   NOT NULL,
+    graduated_block bigint      NOT NULL DEFAULT 0,
+    graduated_at    timestamp   NOT NULL DEFAULT '0001-01-01 00:00:00',
+    created_at      timestamp   NOT NULL DEFAULT now(),
+    UNIQUE (token, chain_id)
+);
+
+CREATE INDEX IF NOT EXISTS launch_chain_creator_idx ON launch (chain_id, creator);
@@ -0,0 +1,4 @@
+-- stable flag and fee of aerodrome pairs, see orm.Pair
+ALTER TABLE pair
+    ADD COLUMN IF NOT EXISTS stable boolean NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS fee    bigint  NOT NULL DEFAULT 0;
@@ -0,0 +1,4 @@
+-- tick spacing and protocol version of pairs, see orm.Pair
+ALTER TABLE pair
+    ADD COLUMN IF NOT EXISTS tick_spacing integer NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS version      integer NOT NULL DEFAULT 0;
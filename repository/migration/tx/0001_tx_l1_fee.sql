@@ -0,0 +1,9 @@
+-- op stack tx type and l1 fee of trades, see orm.Tx
+ALTER TABLE tx
+    ADD COLUMN IF NOT EXISTS tx_type                 smallint       NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS l1_fee                  numeric(78)    NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS l1_gas_used             numeric(78)    NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS l1_gas_price            numeric(78)    NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS l1_blob_base_fee        numeric(78)    NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS l1_base_fee_scalar      bigint         NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS l1_blob_base_fee_scalar bigint         NOT NULL DEFAULT 0;
@@ -0,0 +1,7 @@
+-- execution of the tx of trades, see orm.Tx
+ALTER TABLE tx
+    ADD COLUMN IF NOT EXISTS to_address          varchar(42) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS nonce               bigint      NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS gas_used            bigint      NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS effective_gas_price numeric(78) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS priority_fee        numeric(78) NOT NULL DEFAULT 0;
@@ -0,0 +1,28 @@
+-- sandwiches and back-runs, see orm.Mev, and the role of trades in them
+CREATE TABLE IF NOT EXISTS mev
+(
+    id                uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    chain_id          integer        NOT NULL,
+    kind              varchar(16)    NOT NULL,
+    block             bigint         NOT NULL,
+    block_at          timestamp      NOT NULL,
+    pair_address      varchar(42)    NOT NULL,
+    token0_address    varchar(42)    NOT NULL,
+    attacker          varchar(42)    NOT NULL,
+    front_tx_hash     varchar(66)    NOT NULL DEFAULT '',
+    front_block_index integer        NOT NULL DEFAULT 0,
+    back_tx_hash      varchar(66)    NOT NULL,
+    back_block_index  integer        NOT NULL,
+    victim_tx_hashes  text           NOT NULL,
+    victim_count      integer        NOT NULL,
+    profit_usd        numeric        NOT NULL,
+    gas_cost_usd      numeric        NOT NULL,
+    victim_loss_usd   numeric        NOT NULL,
+    created_at        timestamp      NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS mev_block_idx ON mev (chain_id, block);
+CREATE INDEX IF NOT EXISTS mev_attacker_idx ON mev (attacker);
+
+ALTER TABLE tx
+    ADD COLUMN IF NOT EXISTS mev_role varchar(16) NOT NULL DEFAULT '';
@@ -0,0 +1,29 @@
+-- maker profiles, see orm.Maker, and the class of the maker of trades
+CREATE TABLE IF NOT EXISTS maker
+(
+    chain_id           integer     NOT NULL,
+    address            varchar(42) NOT NULL,
+    kind               varchar(16) NOT NULL,
+    delegate_address   varchar(42) NOT NULL DEFAULT '',
+    class              varchar(16) NOT NULL,
+    first_block        bigint      NOT NULL,
+    last_block         bigint      NOT NULL,
+    trades             bigint      NOT NULL DEFAULT 0,
+    buys               bigint      NOT NULL DEFAULT 0,
+    sells              bigint      NOT NULL DEFAULT 0,
+    volume_usd         numeric     NOT NULL DEFAULT 0,
+    active_blocks      bigint      NOT NULL DEFAULT 0,
+    busy_blocks        bigint      NOT NULL DEFAULT 0,
+    round_trips        bigint      NOT NULL DEFAULT 0,
+    recent_trades      bigint      NOT NULL DEFAULT 0,
+    recent_busy_blocks bigint      NOT NULL DEFAULT 0,
+    recent_round_trips bigint      NOT NULL DEFAULT 0,
+    created_at         timestamp   NOT NULL DEFAULT now(),
+    updated_at         timestamp   NOT NULL DEFAULT now(),
+    PRIMARY KEY (chain_id, address)
+);
+
+CREATE INDEX IF NOT EXISTS maker_class_idx ON maker (chain_id, class);
+
+ALTER TABLE tx
+    ADD COLUMN IF NOT EXISTS maker_class varchar(16) NOT NULL DEFAULT '';
@@ -0,0 +1,5 @@
+-- erc-4337 user operation of trades, see orm.Tx
+ALTER TABLE tx
+    ADD COLUMN IF NOT EXISTS bundler      varchar(42) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS user_op_hash varchar(66) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS paymaster    varchar(42) NOT NULL DEFAULT '';
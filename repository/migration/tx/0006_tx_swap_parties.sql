@@ -0,0 +1,5 @@
+-- sender, recipient and beneficiary of swaps, see orm.Tx
+ALTER TABLE tx
+    ADD COLUMN IF NOT EXISTS sender      varchar(42) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS recipient   varchar(42) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS beneficiary varchar(42) NOT NULL DEFAULT '';
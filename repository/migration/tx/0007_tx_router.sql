@@ -0,0 +1,3 @@
+-- router or aggregator of trades, see orm.Tx
+ALTER TABLE tx
+    ADD COLUMN IF NOT EXISTS router text NOT NULL DEFAULT '';
@@ -0,0 +1,33 @@
+-- v3 positions of the position managers, see orm.Position, and the range of v3 liquidity trades
+CREATE TABLE IF NOT EXISTS v3_position
+(
+    chain_id      integer     NOT NULL,
+    manager       varchar(42) NOT NULL,
+    token_id      varchar(78) NOT NULL,
+    pool_address  varchar(42) NOT NULL DEFAULT '',
+    owner         varchar(42) NOT NULL DEFAULT '',
+    tick_lower    integer     NOT NULL DEFAULT 0,
+    tick_upper    integer     NOT NULL DEFAULT 0,
+    liquidity     numeric(78) NOT NULL DEFAULT 0,
+    deposited0    numeric(78) NOT NULL DEFAULT 0,
+    deposited1    numeric(78) NOT NULL DEFAULT 0,
+    withdrawn0    numeric(78) NOT NULL DEFAULT 0,
+    withdrawn1    numeric(78) NOT NULL DEFAULT 0,
+    collected0    numeric(78) NOT NULL DEFAULT 0,
+    collected1    numeric(78) NOT NULL DEFAULT 0,
+    burned        boolean     NOT NULL DEFAULT false,
+    created_block bigint      NOT NULL DEFAULT 0,
+    updated_block bigint      NOT NULL DEFAULT 0,
+    created_at    timestamp   NOT NULL DEFAULT now(),
+    updated_at    timestamp   NOT NULL DEFAULT now(),
+    PRIMARY KEY (chain_id, manager, token_id)
+);
+
+CREATE INDEX IF NOT EXISTS v3_position_owner_idx ON v3_position (chain_id, owner);
+CREATE INDEX IF NOT EXISTS v3_position_pool_idx ON v3_position (chain_id, pool_address);
+
+ALTER TABLE tx
+    ADD COLUMN IF NOT EXISTS tick_lower  integer     NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS tick_upper  integer     NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS liquidity   numeric(78) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS position_id varchar(78) NOT NULL DEFAULT '';
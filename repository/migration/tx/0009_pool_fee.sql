@@ -0,0 +1,23 @@
+-- fee accruals and claims of aerodrome pools, see orm.PoolFee
+CREATE TABLE IF NOT EXISTS pool_fee
+(
+    chain_id       integer     NOT NULL,
+    tx_hash        varchar(66) NOT NULL,
+    kind           varchar(16) NOT NULL,
+    pair_address   varchar(42) NOT NULL,
+    program        varchar(64) NOT NULL,
+    token0_address varchar(42) NOT NULL,
+    token1_address varchar(42) NOT NULL,
+    token0_amount  numeric     NOT NULL,
+    token1_amount  numeric     NOT NULL,
+    sender         varchar(42) NOT NULL,
+    recipient      varchar(42) NOT NULL DEFAULT '',
+    block          bigint      NOT NULL,
+    block_at       timestamp   NOT NULL,
+    block_index    integer     NOT NULL,
+    tx_index       integer     NOT NULL,
+    created_at     timestamp   NOT NULL DEFAULT now(),
+    UNIQUE (chain_id, tx_hash, tx_index)
+);
+
+CREATE INDEX IF NOT EXISTS pool_fee_pair_block_idx ON pool_fee (chain_id, pair_address, block);
@@ -0,0 +1,35 @@
+-- chain of trades, see orm.Tx, existing trades are of base
+ALTER TABLE tx
+    ADD COLUMN IF NOT EXISTS chain_id integer NOT NULL DEFAULT 8453;
+
+CREATE UNIQUE INDEX IF NOT EXISTS tx_chain_uniq_idx ON tx (chain_id, token0_address, block, block_index, tx_index);
+
+-- the unique key of trades without chain_id would reject the same position of a token on another chain
+DO
+$$
+    DECLARE
+        old record;
+    BEGIN
+        FOR old IN
+            SELECT i.indexrelid::regclass AS index_name, c.conname AS constraint_name
+            FROM pg_index i
+                     LEFT JOIN pg_constraint c ON c.conindid = i.indexrelid AND c.conrelid = i.indrelid
+            WHERE i.indrelid = 'tx'::regclass
+              AND i.indisunique
+              AND NOT i.indisprimary
+              AND i.indexrelid <> 'tx_chain_uniq_idx'::regclass
+              AND (SELECT array_agg(a.attname::text ORDER BY a.attname)
+                   FROM pg_attribute a
+                   WHERE a.attrelid = i.indrelid
+                     AND a.attnum = ANY (i.indkey)) = ARRAY ['block', 'block_index', 'token0_address', 'tx_index']
+            LOOP
+                IF old.constraint_name IS NOT NULL THEN
+                    EXECUTE format('ALTER TABLE tx DROP CONSTRAINT %I', old.constraint_name);
+                ELSE
+                    EXECUTE format('DROP INDEX %s', old.index_name);
+                END IF;
+            END LOOP;
+    END
+$$;
+
+CREATE INDEX IF NOT EXISTS tx_chain_block_idx ON tx (chain_id, block);
@@ -0,0 +1,29 @@
+package orm
+
+import (
+	"time"
+)
+
+/*
+Launch is a token launched on a bonding curve launchpad.
+Curve is the pair of its trades on the curve, Pool the dex pool its liquidity graduates to,
+some launchpads create the pool at the launch, the others at the graduation.
+GraduatedBlock is 0 until the token graduates.
+*/
+type Launch struct {
+	Token          string
+	ChainId        int
+	Program        string
+	Curve          string
+	Creator        string
+	Pool           string
+	Block          uint64
+	BlockAt        time.Time
+	GraduatedBlock uint64
+	GraduatedAt    time.Time
+	CreatedAt      time.Time `gorm:"autoCreateTime"`
+}
+
+func (l *Launch) TableName() string {
+	return "launch"
+}
@@ -0,0 +1,36 @@
+package orm
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+/*
+Maker is the profile of a trading address, see package maker.
+It is upserted by chain and address after every block the maker traded in, Recent* cover the last two stats windows.
+*/
+type Maker struct {
+	ChainId          int    `gorm:"primaryKey"`
+	Address          string `gorm:"primaryKey"`
+	Kind             string
+	DelegateAddress  string
+	Class            string
+	FirstBlock       uint64
+	LastBlock        uint64
+	Trades           uint64
+	Buys             uint64
+	Sells            uint64
+	VolumeUsd        decimal.Decimal
+	ActiveBlocks     uint64
+	BusyBlocks       uint64
+	RoundTrips       uint64
+	RecentTrades     uint64
+	RecentBusyBlocks uint64
+	RecentRoundTrips uint64
+	CreatedAt        time.Time `gorm:"autoCreateTime"`
+	UpdatedAt        time.Time `gorm:"autoUpdateTime"`
+}
+
+func (m *Maker) TableName() string {
+	return "maker"
+}
@@ -0,0 +1,37 @@
+package orm
+
+import (
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+/*
+Mev is a sandwich or a back-run found in a block, see package mev.
+For a back-run FrontTxHash is empty and the victim is the tx that was back-run.
+VictimTxHashes is comma separated.
+*/
+type Mev struct {
+	Id              uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey;readonly"`
+	ChainId         int
+	Kind            string
+	Block           uint64
+	BlockAt         time.Time
+	PairAddress     string
+	Token0Address   string
+	Attacker        string
+	FrontTxHash     string
+	FrontBlockIndex uint
+	BackTxHash      string
+	BackBlockIndex  uint
+	VictimTxHashes  string
+	VictimCount     int
+	ProfitUsd       decimal.Decimal
+	GasCostUsd      decimal.Decimal
+	VictimLossUsd   decimal.Decimal
+	CreatedAt       time.Time `gorm:"autoCreateTime"`
+}
+
+func (m *Mev) TableName() string {
+	return "mev"
+}
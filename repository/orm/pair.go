@@ -6,17 +6,21 @@ import (
 )
 
 type Pair struct {
-	Name      string
-	Address   string
-	Token0    string
-	Token1    string
-	ChainId   int
-	Reserve0  decimal.Decimal
-	Reserve1  decimal.Decimal
-	Block     uint64
-	BlockAt   time.Time
-	Program   string
-	CreatedAt time.Time `gorm:"autoCreateTime"`
+	Name        string
+	Address     string
+	Token0      string
+	Token1      string
+	ChainId     int
+	Reserve0    decimal.Decimal
+	Reserve1    decimal.Decimal
+	Block       uint64
+	BlockAt     time.Time
+	Program     string
+	Stable      bool
+	Fee         uint32
+	TickSpacing int32
+	Version     int
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
 }
 
 func (p *Pair) TableName() string {
@@ -45,5 +49,17 @@ func (p *Pair) Equal(p2 *Pair) bool {
 	if !p.Reserve1.Equal(p2.Reserve1) {
 		return false
 	}
+	if p.Stable != p2.Stable {
+		return false
+	}
+	if p.Fee != p2.Fee {
+		return false
+	}
+	if p.TickSpacing != p2.TickSpacing {
+		return false
+	}
+	if p.Version != p2.Version {
+		return false
+	}
 	return true
 }
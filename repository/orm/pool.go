@@ -0,0 +1,24 @@
+package orm
+
+import (
+	"time"
+)
+
+/*
+Pool is a multi-asset pool, Tokens are the token addresses in pool order separated by commas.
+PoolId is the id of a balancer pool in the vault, empty for curve.
+*/
+type Pool struct {
+	Address   string
+	PoolId    string
+	Tokens    string
+	ChainId   int
+	Block     uint64
+	BlockAt   time.Time
+	Program   string
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (p *Pool) TableName() string {
+	return "pool"
+}
@@ -0,0 +1,34 @@
+package orm
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+/*
+PoolFee is a fee event of an aerodrome pool, amounts are in the tokens of the pair, ordered as the pair.
+- accrue: the fee taken from the input of a swap, kept apart from the reserves for the LPs, Sender is the swap caller
+- claim: fees claimed by an LP, Sender is the LP and Recipient receives the fees
+*/
+type PoolFee struct {
+	ChainId       int
+	TxHash        string
+	Kind          string
+	PairAddress   string
+	Program       string
+	Token0Address string
+	Token1Address string
+	Token0Amount  decimal.Decimal
+	Token1Amount  decimal.Decimal
+	Sender        string
+	Recipient     string
+	Block         uint64
+	BlockAt       time.Time
+	BlockIndex    uint
+	TxIndex       uint
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+}
+
+func (f *PoolFee) TableName() string {
+	return "pool_fee"
+}
@@ -0,0 +1,38 @@
+package orm
+
+import (
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+/*
+Position is a v3 position of a NonfungiblePositionManager, amounts are in wei of the pool tokens.
+Withdrawn is the liquidity removed, Collected what was collected, which is the withdrawn tokens plus the fees,
+so the fees collected are Collected - Withdrawn.
+The counters start at the first block indexed, for positions minted before it they are deltas since that block.
+*/
+type Position struct {
+	ChainId      int    `gorm:"primaryKey"`
+	Manager      string `gorm:"primaryKey"`
+	TokenId      string `gorm:"primaryKey"`
+	PoolAddress  string
+	Owner        string
+	TickLower    int32
+	TickUpper    int32
+	Liquidity    decimal.Decimal
+	Deposited0   decimal.Decimal
+	Deposited1   decimal.Decimal
+	Withdrawn0   decimal.Decimal
+	Withdrawn1   decimal.Decimal
+	Collected0   decimal.Decimal
+	Collected1   decimal.Decimal
+	Burned       bool
+	CreatedBlock uint64
+	UpdatedBlock uint64
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime"`
+}
+
+func (p *Position) TableName() string {
+	return "v3_position"
+}
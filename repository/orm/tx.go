@@ -10,6 +10,7 @@ import (
 type Tx struct {
 	Id            uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey;readonly"`
 	TxHash        string
+	ChainId       int
 	Event         string
 	Token0Amount  decimal.Decimal
 	Token1Amount  decimal.Decimal
@@ -24,13 +25,46 @@ type Tx struct {
 	TxIndex       uint
 	PairAddress   string
 	Program       string
-	CreatedAt     time.Time `gorm:"autoCreateTime"`
+	MevRole       string
+	MakerClass    string
+	// swaps only, see types.SwapParties
+	Sender      string
+	Recipient   string
+	Beneficiary string
+	// v3 add and remove only, see types.LiquidityRange
+	TickLower  int32
+	TickUpper  int32
+	Liquidity  decimal.Decimal
+	PositionId string
+	// erc-4337 only, Maker is the smart account and Bundler the tx sender
+	Bundler    string
+	UserOpHash string
+	Paymaster  string
+	// execution of the tx, see types.TxMeta
+	ToAddress         string
+	Router            string
+	Nonce             uint64
+	GasUsed           uint64
+	EffectiveGasPrice decimal.Decimal
+	PriorityFee       decimal.Decimal
+	// op stack only, see types.TxMeta
+	TxType              uint8
+	L1Fee               decimal.Decimal
+	L1GasUsed           decimal.Decimal
+	L1GasPrice          decimal.Decimal
+	L1BlobBaseFee       decimal.Decimal
+	L1BaseFeeScalar     uint64
+	L1BlobBaseFeeScalar uint64
+	CreatedAt           time.Time `gorm:"autoCreateTime"`
 }
 
 func (t *Tx) Equal(tx *Tx) bool {
 	if t.TxHash != tx.TxHash {
 		return false
 	}
+	if t.ChainId != tx.ChainId {
+		return false
+	}
 	if t.Event != tx.Event {
 		return false
 	}
@@ -1,29 +1,59 @@
 package repository
 
 import (
-	"base_scan/chain"
 	"base_scan/repository/orm"
 	"gorm.io/gorm"
 )
 
 type PairRepository struct {
 	*BaseRepository[orm.Pair]
+	chainId int
 }
 
-func NewPairRepository(db *gorm.DB) *PairRepository {
+func NewPairRepository(db *gorm.DB, chainId uint64) *PairRepository {
 	baseRepo := NewBaseRepository[orm.Pair](db)
-	return &PairRepository{BaseRepository: baseRepo}
+	return &PairRepository{BaseRepository: baseRepo, chainId: int(chainId)}
 }
 
 func (r *PairRepository) GetByAddressAndChainId(address string) (*orm.Pair, error) {
 	var pair orm.Pair
-	err := r.db.Where("address = ? AND chain_id = ?", address, chain.Id).First(&pair).Error
+	err := r.db.Where("address = ? AND chain_id = ?", address, r.chainId).First(&pair).Error
 	if err != nil {
 		return nil, err
 	}
 	return &pair, nil
 }
 
+func (r *PairRepository) GetBatchAfterAddress(address string, limit int) ([]*orm.Pair, error) {
+	var pairs []*orm.Pair
+	err := r.db.Where("address > ? AND chain_id = ?", address, r.chainId).
+		Order("address").
+		Limit(limit).
+		Find(&pairs).Error
+	if err != nil {
+		return nil, err
+	}
+	return pairs, nil
+}
+
+func (r *PairRepository) GetByBlockRange(from, to uint64) ([]*orm.Pair, error) {
+	var pairs []*orm.Pair
+	err := r.db.Where("block >= ? AND block <= ? AND chain_id = ?", from, to, r.chainId).
+		Order("block").
+		Find(&pairs).Error
+	if err != nil {
+		return nil, err
+	}
+	return pairs, nil
+}
+
 func (r *PairRepository) DeleteByAddressAndChainId(address string) error {
-	return r.db.Where("address = ? AND chain_id = ?", address, chain.Id).Delete(&orm.Pair{}).Error
+	return r.db.Where("address = ? AND chain_id = ?", address, r.chainId).Delete(&orm.Pair{}).Error
+}
+
+// UpdateFee sets the fee of a pair, fee is in hundredths of a bip.
+func (r *PairRepository) UpdateFee(address string, fee uint32) error {
+	return r.db.Model(&orm.Pair{}).
+		Where("address = ? AND chain_id = ?", address, r.chainId).
+		Update("fee", fee).Error
 }
@@ -1,7 +1,6 @@
 package repository
 
 import (
-	"base_scan/chain"
 	"base_scan/repository/orm"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/require"
@@ -10,13 +9,15 @@ import (
 	"testing"
 )
 
+const testChainId = 8453
+
 func preparePairTest() *PairRepository {
 	dsn := "host=localhost user=postgres password=12345678 dbname=test port=5432 sslmode=disable"
 	db, err := gorm.Open(postgres.Open(dsn))
 	if err != nil {
 		panic(err)
 	}
-	return NewPairRepository(db)
+	return NewPairRepository(db, testChainId)
 }
 
 func cleanupPairTest(pairRepository *PairRepository, addresses ...string) {
@@ -32,7 +33,7 @@ func TestPairRepository_Create(t *testing.T) {
 		Address:  "0x01",
 		Token0:   "0x0a",
 		Token1:   "0x0b",
-		ChainId:  chain.Id,
+		ChainId:  testChainId,
 		Reserve0: decimal.NewFromInt(1),
 		Reserve1: decimal.NewFromInt(2),
 	}
@@ -53,7 +54,7 @@ func TestPairRepository_CreateDup(t *testing.T) {
 			Address:  "0xa1",
 			Token0:   "0xa1",
 			Token1:   "0xa1",
-			ChainId:  chain.Id,
+			ChainId:  testChainId,
 			Reserve0: decimal.NewFromInt(1),
 			Reserve1: decimal.NewFromInt(2),
 		},
@@ -62,7 +63,7 @@ func TestPairRepository_CreateDup(t *testing.T) {
 			Address:  "0xa2",
 			Token0:   "0xa2",
 			Token1:   "0xa2",
-			ChainId:  chain.Id,
+			ChainId:  testChainId,
 			Reserve0: decimal.NewFromInt(1),
 			Reserve1: decimal.NewFromInt(2),
 		},
@@ -71,7 +72,7 @@ func TestPairRepository_CreateDup(t *testing.T) {
 			Address:  "0xa3",
 			Token0:   "0xa3",
 			Token1:   "0xa3",
-			ChainId:  chain.Id,
+			ChainId:  testChainId,
 			Reserve0: decimal.NewFromInt(1),
 			Reserve1: decimal.NewFromInt(2),
 		},
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"base_scan/repository/orm"
+	"gorm.io/gorm"
+)
+
+type PoolRepository struct {
+	*BaseRepository[orm.Pool]
+	chainId int
+}
+
+func NewPoolRepository(db *gorm.DB, chainId uint64) *PoolRepository {
+	baseRepo := NewBaseRepository[orm.Pool](db)
+	return &PoolRepository{BaseRepository: baseRepo, chainId: int(chainId)}
+}
+
+func (r *PoolRepository) GetByAddressAndChainId(address string) (*orm.Pool, error) {
+	var pool orm.Pool
+	err := r.db.Where("address = ? AND chain_id = ?", address, r.chainId).First(&pool).Error
+	if err != nil {
+		return nil, err
+	}
+	return &pool, nil
+}
+
+func (r *PoolRepository) GetByBlockRange(from, to uint64) ([]*orm.Pool, error) {
+	var pools []*orm.Pool
+	err := r.db.Where("block >= ? AND block <= ? AND chain_id = ?", from, to, r.chainId).
+		Order("block").
+		Find(&pools).Error
+	if err != nil {
+		return nil, err
+	}
+	return pools, nil
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"base_scan/repository/orm"
+	"gorm.io/gorm"
+)
+
+type PoolFeeRepository struct {
+	*BaseRepository[orm.PoolFee]
+}
+
+func NewPoolFeeRepository(db *gorm.DB) *PoolFeeRepository {
+	baseRepo := NewBaseRepository[orm.PoolFee](db)
+	return &PoolFeeRepository{BaseRepository: baseRepo}
+}
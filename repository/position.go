@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"base_scan/repository/orm"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PositionRepository struct {
+	*BaseRepository[orm.Position]
+}
+
+func NewPositionRepository(db *gorm.DB) *PositionRepository {
+	baseRepo := NewBaseRepository[orm.Position](db)
+	return &PositionRepository{BaseRepository: baseRepo}
+}
+
+/*
+UpsertBatch applies the deltas of one block, see types.MergePositionChanges.
+Counters are added, the pool and range only set when known, and a block already applied is skipped,
+so that parsing a block again after a restart does not count it twice.
+*/
+func (r *PositionRepository) UpsertBatch(positions []*orm.Position) error {
+	if len(positions) == 0 {
+		return nil
+	}
+
+	sum := func(column string) clause.Expr {
+		return gorm.Expr("v3_position." + column + " + EXCLUDED." + column)
+	}
+	ifPoolKnown := func(column string) clause.Expr {
+		return gorm.Expr("CASE WHEN EXCLUDED.pool_address <> '' THEN EXCLUDED." + column + " ELSE v3_position." + column + " END")
+	}
+
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "chain_id"}, {Name: "manager"}, {Name: "token_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"pool_address":  ifPoolKnown("pool_address"),
+			"tick_lower":    ifPoolKnown("tick_lower"),
+			"tick_upper":    ifPoolKnown("tick_upper"),
+			"owner":         gorm.Expr("CASE WHEN EXCLUDED.owner <> '' OR EXCLUDED.burned THEN EXCLUDED.owner ELSE v3_position.owner END"),
+			"liquidity":     sum("liquidity"),
+			"deposited0":    sum("deposited0"),
+			"deposited1":    sum("deposited1"),
+			"withdrawn0":    sum("withdrawn0"),
+			"withdrawn1":    sum("withdrawn1"),
+			"collected0":    sum("collected0"),
+			"collected1":    sum("collected1"),
+			"burned":        gorm.Expr("v3_position.burned OR EXCLUDED.burned"),
+			"created_block": gorm.Expr("GREATEST(v3_position.created_block, EXCLUDED.created_block)"),
+			"updated_block": gorm.Expr("EXCLUDED.updated_block"),
+			"updated_at":    gorm.Expr("EXCLUDED.updated_at"),
+		}),
+		Where: clause.Where{Exprs: []clause.Expression{gorm.Expr("v3_position.updated_block < EXCLUDED.updated_block")}},
+	}).CreateInBatches(positions, 200).Error
+}
@@ -1,7 +1,6 @@
 package repository
 
 import (
-	"base_scan/chain"
 	"base_scan/repository/orm"
 	_ "gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -9,16 +8,17 @@ import (
 
 type TokenRepository struct {
 	*BaseRepository[orm.Token]
+	chainId int
 }
 
-func NewTokenRepository(db *gorm.DB) *TokenRepository {
+func NewTokenRepository(db *gorm.DB, chainId uint64) *TokenRepository {
 	baseRepo := NewBaseRepository[orm.Token](db)
-	return &TokenRepository{BaseRepository: baseRepo}
+	return &TokenRepository{BaseRepository: baseRepo, chainId: int(chainId)}
 }
 
 func (r *TokenRepository) GetByAddressAndChainId(address string) (*orm.Token, error) {
 	var token orm.Token
-	err := r.db.Where("address = ? AND chain_id = ?", address, chain.Id).First(&token).Error
+	err := r.db.Where("address = ? AND chain_id = ?", address, r.chainId).First(&token).Error
 	if err != nil {
 		return nil, err
 	}
@@ -27,10 +27,22 @@ func (r *TokenRepository) GetByAddressAndChainId(address string) (*orm.Token, er
 
 func (r *TokenRepository) UpdateMainPair(address string, mainPair string) error {
 	return r.db.Model(&orm.Token{}).
-		Where("address = ? AND chain_id = ?", address, chain.Id).
+		Where("address = ? AND chain_id = ?", address, r.chainId).
 		Update("main_pair", mainPair).Error
 }
 
+func (r *TokenRepository) GetBatchAfterAddress(address string, limit int) ([]*orm.Token, error) {
+	var tokens []*orm.Token
+	err := r.db.Where("address > ? AND chain_id = ?", address, r.chainId).
+		Order("address").
+		Limit(limit).
+		Find(&tokens).Error
+	if err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
 func (r *TokenRepository) DeleteByAddressAndChainId(address string) error {
-	return r.db.Where("address = ? AND chain_id = ?", address, chain.Id).Delete(&orm.Token{}).Error
+	return r.db.Where("address = ? AND chain_id = ?", address, r.chainId).Delete(&orm.Token{}).Error
 }
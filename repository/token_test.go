@@ -1,7 +1,6 @@
 package repository
 
 import (
-	"base_scan/chain"
 	"base_scan/repository/orm"
 	"github.com/stretchr/testify/require"
 	"gorm.io/driver/postgres"
@@ -15,7 +14,7 @@ func prepareTokenTest() *TokenRepository {
 	if err != nil {
 		panic(err)
 	}
-	return NewTokenRepository(db)
+	return NewTokenRepository(db, testChainId)
 }
 
 func cleanupTokenTest(tokenRepository *TokenRepository, addresses ...string) {
@@ -33,7 +32,7 @@ func TestTokenRepository_Create(t *testing.T) {
 		Symbol:      "DUEL",
 		Decimal:     18,
 		TotalSupply: "2659527283.779538",
-		ChainId:     chain.Id,
+		ChainId:     testChainId,
 	}
 
 	tokenRepository.Create(token)
@@ -52,7 +51,7 @@ func TestTokenRepository_CreateBatch_NoConflict(t *testing.T) {
 			Symbol:      "s1",
 			Decimal:     18,
 			TotalSupply: "1",
-			ChainId:     chain.Id,
+			ChainId:     testChainId,
 		},
 		{
 			Address:     "0x02",
@@ -60,7 +59,7 @@ func TestTokenRepository_CreateBatch_NoConflict(t *testing.T) {
 			Symbol:      "s2",
 			Decimal:     18,
 			TotalSupply: "2",
-			ChainId:     chain.Id,
+			ChainId:     testChainId,
 		},
 		{
 			Address:     "0x03",
@@ -68,7 +67,7 @@ func TestTokenRepository_CreateBatch_NoConflict(t *testing.T) {
 			Symbol:      "s3",
 			Decimal:     18,
 			TotalSupply: "3",
-			ChainId:     chain.Id,
+			ChainId:     testChainId,
 		},
 	}
 
@@ -91,7 +90,7 @@ func TestTokenRepository_CreateBatch_ConflictWithDb(t *testing.T) {
 			Symbol:      "s1",
 			Decimal:     18,
 			TotalSupply: "1",
-			ChainId:     chain.Id,
+			ChainId:     testChainId,
 		},
 		{
 			Address:     "0x02",
@@ -99,7 +98,7 @@ func TestTokenRepository_CreateBatch_ConflictWithDb(t *testing.T) {
 			Symbol:      "s2",
 			Decimal:     18,
 			TotalSupply: "2",
-			ChainId:     chain.Id,
+			ChainId:     testChainId,
 		},
 		{
 			Address:     "0x03",
@@ -107,7 +106,7 @@ func TestTokenRepository_CreateBatch_ConflictWithDb(t *testing.T) {
 			Symbol:      "s3",
 			Decimal:     18,
 			TotalSupply: "3",
-			ChainId:     chain.Id,
+			ChainId:     testChainId,
 		},
 	}
 
@@ -131,7 +130,7 @@ func TestTokenRepository_UpdateMainPair(t *testing.T) {
 		Symbol:      "s1",
 		Decimal:     18,
 		TotalSupply: "1",
-		ChainId:     chain.Id,
+		ChainId:     testChainId,
 	}
 
 	tokenRepository.Create(token)
@@ -143,3 +142,25 @@ func TestTokenRepository_UpdateMainPair(t *testing.T) {
 	require.Equal(t, "0x06", tokenQueried.MainPair)
 	cleanupTokenTest(tokenRepository, token.Address)
 }
+
+func TestTokenRepository_GetBatchAfterAddress(t *testing.T) {
+	tokenRepository := prepareTokenTest()
+	tokens := []*orm.Token{
+		{Address: "0x01", Name: "n1", Symbol: "s1", Decimal: 18, TotalSupply: "1", ChainId: testChainId},
+		{Address: "0x02", Name: "n2", Symbol: "s2", Decimal: 18, TotalSupply: "2", ChainId: testChainId},
+		{Address: "0x03", Name: "n3", Symbol: "s3", Decimal: 18, TotalSupply: "3", ChainId: testChainId},
+	}
+	defer cleanupTokenTest(tokenRepository, tokens[0].Address, tokens[1].Address, tokens[2].Address)
+
+	createErr := tokenRepository.CreateBatch(tokens, "address", "chain_id")
+	require.NoError(t, createErr)
+
+	batch, err := tokenRepository.GetBatchAfterAddress(tokens[0].Address, 1)
+	require.NoError(t, err)
+	require.Len(t, batch, 1)
+	require.True(t, tokens[1].Equal(batch[0]))
+
+	batch, err = tokenRepository.GetBatchAfterAddress(tokens[2].Address, 1)
+	require.NoError(t, err)
+	require.Len(t, batch, 0)
+}
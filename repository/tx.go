@@ -7,20 +7,55 @@ import (
 
 type TxRepository struct {
 	*BaseRepository[orm.Tx]
+	chainId int
 }
 
-func NewTxRepository(db *gorm.DB) *TxRepository {
+func NewTxRepository(db *gorm.DB, chainId uint64) *TxRepository {
 	baseRepo := NewBaseRepository[orm.Tx](db)
-	return &TxRepository{BaseRepository: baseRepo}
+	return &TxRepository{BaseRepository: baseRepo, chainId: int(chainId)}
 }
 
 func (r *TxRepository) GetByUniqIndex(token0Address string, block uint64, blockIndex, txIndex uint) (*orm.Tx, error) {
 	tx := &orm.Tx{}
-	err := r.db.Where("token0_address = ? AND block = ? AND block_index = ? AND tx_index = ?",
+	err := r.db.Where("token0_address = ? AND block = ? AND block_index = ? AND tx_index = ? AND chain_id = ?",
 		token0Address,
 		block,
 		blockIndex,
-		txIndex).First(tx).Error
+		txIndex,
+		r.chainId).First(tx).Error
+	if err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+func (r *TxRepository) GetMaxBlock() (uint64, error) {
+	var maxBlock uint64
+	err := r.db.Model(&orm.Tx{}).Select("COALESCE(MAX(block), 0)").Where("chain_id = ?", r.chainId).Scan(&maxBlock).Error
+	if err != nil {
+		return 0, err
+	}
+	return maxBlock, nil
+}
+
+// GetByBlockRange returns the txs of the blocks from..to, ordered by position in block.
+func (r *TxRepository) GetByBlockRange(from, to uint64) ([]*orm.Tx, error) {
+	var txs []*orm.Tx
+	err := r.db.Where("block >= ? AND block <= ? AND chain_id = ?", from, to, r.chainId).
+		Order("block, block_index, tx_index").
+		Find(&txs).Error
+	if err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
+
+// GetLastByPair returns the last tx of a pair at or before a block.
+func (r *TxRepository) GetLastByPair(pairAddress string, block uint64) (*orm.Tx, error) {
+	tx := &orm.Tx{}
+	err := r.db.Where("pair_address = ? AND block <= ? AND chain_id = ?", pairAddress, block, r.chainId).
+		Order("block DESC, block_index DESC, tx_index DESC").
+		First(tx).Error
 	if err != nil {
 		return nil, err
 	}
@@ -29,7 +64,7 @@ func (r *TxRepository) GetByUniqIndex(token0Address string, block uint64, blockI
 
 func (r *TxRepository) DeleteById(id string) error {
 	tx := &orm.Tx{}
-	err := r.db.Where("id = ?", id).Delete(tx).Error
+	err := r.db.Where("id = ? AND chain_id = ?", id, r.chainId).Delete(tx).Error
 	if err != nil {
 		return err
 	}
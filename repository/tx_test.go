@@ -17,7 +17,7 @@ func prepareTxTest() *TxRepository {
 	if err != nil {
 		panic(err)
 	}
-	return NewTxRepository(db)
+	return NewTxRepository(db, testChainId)
 }
 
 func cleanupTxTest(txRepository *TxRepository, Ids ...string) {
@@ -30,6 +30,7 @@ func TestTxRepository_Create(t *testing.T) {
 	txRepository := prepareTxTest()
 	tx := &orm.Tx{
 		TxHash:        "0xa1",
+		ChainId:       testChainId,
 		Event:         "buy",
 		Token0Amount:  decimal.NewFromFloat(0.001),
 		Token1Amount:  decimal.NewFromFloat(0.002),
@@ -59,6 +60,7 @@ func TestTxRepository_CreateDup(t *testing.T) {
 	txes := []*orm.Tx{
 		{
 			TxHash:        "0xa1",
+			ChainId:       testChainId,
 			Event:         "buy",
 			Token0Amount:  decimal.NewFromFloat(0.001),
 			Token1Amount:  decimal.NewFromFloat(0.002),
@@ -76,6 +78,7 @@ func TestTxRepository_CreateDup(t *testing.T) {
 		},
 		{
 			TxHash:        "0xa2",
+			ChainId:       testChainId,
 			Event:         "buy",
 			Token0Amount:  decimal.NewFromFloat(0.001),
 			Token1Amount:  decimal.NewFromFloat(0.002),
@@ -93,6 +96,7 @@ func TestTxRepository_CreateDup(t *testing.T) {
 		},
 		{
 			TxHash:        "0xa3",
+			ChainId:       testChainId,
 			Event:         "buy",
 			Token0Amount:  decimal.NewFromFloat(0.001),
 			Token1Amount:  decimal.NewFromFloat(0.002),
@@ -126,3 +130,30 @@ func TestTxRepository_CreateDup(t *testing.T) {
 
 	defer cleanupTxTest(txRepository, txIds...)
 }
+
+func TestTxRepository_GetMaxBlock(t *testing.T) {
+	txRepository := prepareTxTest()
+	tx := &orm.Tx{
+		TxHash:        "0xa1",
+		ChainId:       testChainId,
+		Event:         "buy",
+		Token0Address: "0xa1",
+		Token1Address: "0xa1",
+		Block:         1<<40 + 1,
+		BlockAt:       time.Now(),
+		BlockIndex:    1,
+		TxIndex:       1,
+		PairAddress:   "0xa1",
+		Program:       types.ProtocolNameUniswapV2,
+	}
+	createErr := txRepository.Create(tx)
+	require.NoError(t, createErr)
+
+	txQueried, getErr := txRepository.GetByUniqIndex(tx.Token0Address, tx.Block, tx.BlockIndex, tx.TxIndex)
+	require.NoError(t, getErr)
+	defer cleanupTxTest(txRepository, txQueried.Id.String())
+
+	maxBlock, err := txRepository.GetMaxBlock()
+	require.NoError(t, err)
+	require.Equal(t, tx.Block, maxBlock)
+}
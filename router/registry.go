@@ -0,0 +1,110 @@
+package router
+
+import (
+	"base_scan/chain"
+	"base_scan/config"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+var (
+	ErrInvalidAddress  = errors.New("invalid router address")
+	ErrInvalidSelector = errors.New("invalid router selector")
+)
+
+type selector [4]byte
+
+/*
+Registry names the router or aggregator a tx was sent to, by the tx to address or,
+for routers deployed at many addresses like telegram bot routers, by the function selector of the calldata.
+An address matches before a selector.
+*/
+type Registry struct {
+	address2Name  map[common.Address]string
+	selector2Name map[selector]string
+	// routers of the config restricted to some selectors
+	address2Selectors map[common.Address]map[selector]struct{}
+}
+
+func parseSelector(name, s string) (selector, error) {
+	b, err := hexutil.Decode(s)
+	if err != nil || len(b) != len(selector{}) {
+		return selector{}, fmt.Errorf("%w: routers %s %q", ErrInvalidSelector, name, s)
+	}
+	return selector(b), nil
+}
+
+/*
+NewRegistry merges the routers of the profile and of the config.
+A config router with both addresses and selectors only matches calls of those selectors to those addresses.
+*/
+func NewRegistry(profile *chain.Profile, routers []*config.RouterConf) (*Registry, error) {
+	r := &Registry{
+		address2Name:      make(map[common.Address]string, len(profile.Routers)),
+		selector2Name:     make(map[selector]string),
+		address2Selectors: make(map[common.Address]map[selector]struct{}),
+	}
+	for address, name := range profile.Routers {
+		r.address2Name[address] = name
+	}
+
+	for _, router := range routers {
+		selectors := make(map[selector]struct{}, len(router.Selectors))
+		for _, s := range router.Selectors {
+			sel, err := parseSelector(router.Name, s)
+			if err != nil {
+				return nil, err
+			}
+			selectors[sel] = struct{}{}
+		}
+
+		if len(router.Addresses) == 0 {
+			for sel := range selectors {
+				r.selector2Name[sel] = router.Name
+			}
+			continue
+		}
+
+		for _, hex := range router.Addresses {
+			if !common.IsHexAddress(hex) {
+				return nil, fmt.Errorf("%w: routers %s %q", ErrInvalidAddress, router.Name, hex)
+			}
+			address := common.HexToAddress(hex)
+			r.address2Name[address] = router.Name
+			if len(selectors) > 0 {
+				r.address2Selectors[address] = selectors
+			} else {
+				delete(r.address2Selectors, address)
+			}
+		}
+	}
+	return r, nil
+}
+
+// Identify returns the name of the router called by a tx, or "" when it is unknown.
+func (r *Registry) Identify(to *common.Address, input []byte) string {
+	var sel selector
+	hasSelector := len(input) >= len(sel)
+	if hasSelector {
+		copy(sel[:], input)
+	}
+
+	if to != nil {
+		if name, ok := r.address2Name[*to]; ok {
+			selectors, restricted := r.address2Selectors[*to]
+			if !restricted {
+				return name
+			}
+			if _, ok = selectors[sel]; hasSelector && ok {
+				return name
+			}
+		}
+	}
+
+	if hasSelector {
+		return r.selector2Name[sel]
+	}
+	return ""
+}
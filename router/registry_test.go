@@ -0,0 +1,62 @@
+package router
+
+import (
+	"base_scan/chain"
+	"base_scan/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+var (
+	universalRouter = common.HexToAddress("0x3fC91A3afd70395Cd496C647d5a6CC9D4B2b7FAD")
+	botRouter       = common.HexToAddress("0x00000000000000000000000000000000000000b0")
+	unknown         = common.HexToAddress("0x00000000000000000000000000000000000000c0")
+	execute         = common.FromHex("0x3593564c0000")
+	buy             = common.FromHex("0x12345678")
+)
+
+func newTestRegistry(t *testing.T, routers []*config.RouterConf) *Registry {
+	profile, err := chain.GetProfile(chain.ProfileBase)
+	require.NoError(t, err)
+
+	r, err := NewRegistry(profile, routers)
+	require.NoError(t, err)
+	return r
+}
+
+func TestIdentifyByAddress(t *testing.T) {
+	r := newTestRegistry(t, nil)
+	require.Equal(t, "uniswap_universal_router", r.Identify(&universalRouter, execute))
+	require.Equal(t, "uniswap_universal_router", r.Identify(&universalRouter, nil))
+	require.Empty(t, r.Identify(&unknown, execute))
+	// contract creation
+	require.Empty(t, r.Identify(nil, execute))
+}
+
+func TestIdentifyBySelector(t *testing.T) {
+	r := newTestRegistry(t, []*config.RouterConf{
+		{Name: "bot", Selectors: []string{"0x12345678"}},
+		{Name: "restricted", Addresses: []string{botRouter.String()}, Selectors: []string{"0x3593564c"}},
+		// config overrides the profile
+		{Name: "uniswap", Addresses: []string{universalRouter.String()}},
+	})
+
+	require.Equal(t, "bot", r.Identify(&unknown, buy))
+	require.Equal(t, "restricted", r.Identify(&botRouter, execute))
+	// not restricted to this selector, falls back to the selector match
+	require.Equal(t, "bot", r.Identify(&botRouter, buy))
+	require.Empty(t, r.Identify(&botRouter, common.FromHex("0x01")))
+	require.Equal(t, "uniswap", r.Identify(&universalRouter, execute))
+}
+
+func TestNewRegistryInvalid(t *testing.T) {
+	profile, err := chain.GetProfile(chain.ProfileBase)
+	require.NoError(t, err)
+
+	_, err = NewRegistry(profile, []*config.RouterConf{{Name: "bad", Selectors: []string{"0x1234"}}})
+	require.ErrorIs(t, err, ErrInvalidSelector)
+
+	_, err = NewRegistry(profile, []*config.RouterConf{{Name: "bad", Addresses: []string{"0x1234"}}})
+	require.ErrorIs(t, err, ErrInvalidAddress)
+}
@@ -1,7 +1,6 @@
 package sequencer
 
 import (
-	"base_scan/config"
 	"base_scan/log"
 	"base_scan/types"
 	"go.uber.org/zap"
@@ -24,9 +23,9 @@ type blockSequencer struct {
 	sequence uint64
 }
 
-func NewBlockSequencer() BlockSequencer {
+func NewBlockSequencer(active bool) BlockSequencer {
 	s := &blockSequencer{
-		active: config.G.EnableSequencer,
+		active: active,
 	}
 	s.cond = sync.NewCond(&s.mu)
 	return s
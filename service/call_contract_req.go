@@ -1,6 +1,7 @@
 package service
 
 import (
+	"base_scan/abi/aerodrome"
 	"base_scan/abi/bep20"
 	uniswapv2 "base_scan/abi/uniswap/v2"
 	uniswapv3 "base_scan/abi/uniswap/v3"
@@ -48,11 +49,15 @@ var (
 		},
 		{
 			Abi:   uniswapv2.PairAbi,
-			Names: []string{"token0", "token1"},
+			Names: []string{"token0", "token1", "factory"},
 		},
 		{
 			Abi:   uniswapv3.PoolAbi,
-			Names: []string{"fee"},
+			Names: []string{"fee", "tickSpacing"},
+		},
+		{
+			Abi:   aerodrome.PairAbi,
+			Names: []string{"stable"},
 		},
 	}
 
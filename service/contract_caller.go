@@ -2,6 +2,8 @@ package service
 
 import (
 	"base_scan/abi/aerodrome"
+	"base_scan/abi/balancer"
+	"base_scan/abi/curve"
 	uniswapv2 "base_scan/abi/uniswap/v2"
 	uniswapv3 "base_scan/abi/uniswap/v3"
 	"base_scan/config"
@@ -169,6 +171,14 @@ func (c *ContractCaller) CallToken1(address *common.Address) (common.Address, er
 	return c.queryAddress(address, "token1")
 }
 
+/*
+CallFactory
+for uniswap/pancake v2 and v3 and their forks, the factory that created the pool
+*/
+func (c *ContractCaller) CallFactory(address *common.Address) (common.Address, error) {
+	return c.queryAddress(address, "factory")
+}
+
 /*
 CallGetPair
 for uniswap/pancake v2
@@ -205,6 +215,14 @@ func (c *ContractCaller) CallFee(address *common.Address) (*big.Int, error) {
 	return c.queryBigInt(address, "fee")
 }
 
+/*
+CallTickSpacing
+for uniswap/pancake v3
+*/
+func (c *ContractCaller) CallTickSpacing(address *common.Address) (int, error) {
+	return c.queryInt(address, "tickSpacing")
+}
+
 /*
 CallGetPool
 for uniswap/pancake v3
@@ -237,8 +255,8 @@ func (c *ContractCaller) CallGetPool(factoryAddress, token0Address, token1Addres
 CallIsPool
 for aerodrome
 */
-func (c *ContractCaller) CallIsPool(poolAddress *common.Address) (bool, error) {
-	req := BuildCallContractReqDynamic(nil, &aerodrome.FactoryAddress, aerodrome.FactoryAbi, "isPool", poolAddress)
+func (c *ContractCaller) CallIsPool(factoryAddress, poolAddress *common.Address) (bool, error) {
+	req := BuildCallContractReqDynamic(nil, factoryAddress, aerodrome.FactoryAbi, "isPool", poolAddress)
 
 	bytes, err := c.CallContract(req)
 	if err != nil {
@@ -261,12 +279,104 @@ func (c *ContractCaller) CallIsPool(poolAddress *common.Address) (bool, error) {
 	return ParseBool(values[0])
 }
 
+/*
+CallStable
+for aerodrome, whether the pool is a stable pool
+*/
+func (c *ContractCaller) CallStable(poolAddress *common.Address) (bool, error) {
+	values, err := c.queryValues(poolAddress, "stable", 1)
+	if err != nil {
+		return false, err
+	}
+	return ParseBool(values[0])
+}
+
+/*
+CallGetFee
+for aerodrome, the fee of a pool in bips, the custom fee or the default fee of stable or volatile pools
+*/
+func (c *ContractCaller) CallGetFee(factoryAddress, poolAddress *common.Address, stable bool) (*big.Int, error) {
+	req := BuildCallContractReqDynamic(nil, factoryAddress, aerodrome.FactoryAbi, "getFee", poolAddress, stable)
+
+	bytes, err := c.CallContract(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(bytes) == 0 {
+		return nil, ErrOutputEmpty
+	}
+
+	values, unpackErr := AerodromeFactoryUnpacker.Unpack("getFee", bytes, 1)
+	if unpackErr != nil {
+		return nil, unpackErr
+	}
+
+	if len(values) != 1 {
+		return nil, ErrWrongOutputLength
+	}
+
+	return ParseBigInt(values[0])
+}
+
+/*
+CallCoins
+for curve, the token of a coin index, it fails for an index out of the pool
+*/
+func (c *ContractCaller) CallCoins(poolAddress *common.Address, index int) (common.Address, error) {
+	req := BuildCallContractReqDynamic(nil, poolAddress, curve.PoolAbi, "coins", big.NewInt(int64(index)))
+
+	bytes, err := c.CallContract(req)
+	if err != nil {
+		return types.ZeroAddress, err
+	}
+
+	if len(bytes) == 0 {
+		return types.ZeroAddress, ErrOutputEmpty
+	}
+
+	values, unpackErr := CurvePoolUnpacker.Unpack("coins", bytes, 1)
+	if unpackErr != nil {
+		return types.ZeroAddress, unpackErr
+	}
+
+	return ParseAddress(values[0])
+}
+
+/*
+CallGetPoolTokens
+for balancer v2, the tokens of a pool in the vault
+*/
+func (c *ContractCaller) CallGetPoolTokens(vaultAddress *common.Address, poolId common.Hash) ([]common.Address, error) {
+	req := BuildCallContractReqDynamic(nil, vaultAddress, balancer.VaultAbi, "getPoolTokens", poolId)
+
+	bytes, err := c.CallContract(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(bytes) == 0 {
+		return nil, ErrOutputEmpty
+	}
+
+	values, unpackErr := BalancerVaultUnpacker.Unpack("getPoolTokens", bytes, 3)
+	if unpackErr != nil {
+		return nil, unpackErr
+	}
+
+	tokens, ok := values[0].([]common.Address)
+	if !ok {
+		return nil, ErrWrongAddressType
+	}
+	return tokens, nil
+}
+
 /*
 callGetReserves
 for uniswap/pancake v2
 */
-func (c *ContractCaller) callGetReserves(blockNumber *big.Int) ([]interface{}, error) {
-	req := BuildCallContractReqDynamic(blockNumber, &types.WETHUSDCPairAddressUniswapV2, uniswapv2.PairAbi, "getReserves")
+func (c *ContractCaller) callGetReserves(pairAddress *common.Address, blockNumber *big.Int) ([]interface{}, error) {
+	req := BuildCallContractReqDynamic(blockNumber, pairAddress, uniswapv2.PairAbi, "getReserves")
 
 	bytes, err := c.CallContract(req)
 	if err != nil {
@@ -289,8 +399,8 @@ func (c *ContractCaller) callGetReserves(blockNumber *big.Int) ([]interface{}, e
 	return values, nil
 }
 
-func (c *ContractCaller) GetReservesByBlockNumber(blockNumber *big.Int) (*big.Int, *big.Int, error) {
-	values, err := c.callGetReserves(blockNumber)
+func (c *ContractCaller) GetReservesByBlockNumber(pairAddress *common.Address, blockNumber *big.Int) (*big.Int, *big.Int, error) {
+	values, err := c.callGetReserves(pairAddress, blockNumber)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -307,3 +417,12 @@ func (c *ContractCaller) GetReservesByBlockNumber(blockNumber *big.Int) (*big.In
 
 	return reserve0, reserve1, nil
 }
+
+// CodeAt returns the code at address at the end of block blockNumber, empty for an EOA.
+func (c *ContractCaller) CodeAt(address common.Address, blockNumber *big.Int) ([]byte, error) {
+	ctxWithTimeout, cancel := context.WithTimeout(c.ctx, c.retryParams.Timeout)
+	defer cancel()
+	return retry.DoWithData(func() ([]byte, error) {
+		return c.ethClient.CodeAt(ctxWithTimeout, address, blockNumber)
+	}, c.retryParams.Attempts, c.retryParams.Delay, retry.Context(ctxWithTimeout))
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"base_scan/abi/aerodrome"
 	pancakev2 "base_scan/abi/pancake/v2"
 	pancakev3 "base_scan/abi/pancake/v3"
 	uniswapv2 "base_scan/abi/uniswap/v2"
@@ -254,7 +255,7 @@ func TestContractCaller_CallIsPool(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		isPool, err := cc.CallIsPool(&test.pairAddress)
+		isPool, err := cc.CallIsPool(&aerodrome.FactoryAddress, &test.pairAddress)
 		require.Nil(t, err)
 		require.Equal(t, test.isPool, isPool)
 	}
@@ -350,13 +351,15 @@ func TestContractCaller_CallFee(t *testing.T) {
 
 func TestContractCaller_GetReservesByBlockNumber(t *testing.T) {
 	t.Skip()
-	ethClient, err := ethclient.Dial(config.G.Chain.Endpoint)
+	conf := config.Default()
+	ethClient, err := ethclient.Dial(conf.Chain.Endpoint)
 	if err != nil {
 		t.Fatal(err)
 	}
-	cc := NewContractCaller(ethClient, config.G.ContractCaller.Retry.GetRetryParams())
+	cc := NewContractCaller(ethClient, conf.ContractCaller.Retry.GetRetryParams())
 
-	r0, r1, err := cc.GetReservesByBlockNumber(big.NewInt(30423400))
+	pricePair := common.HexToAddress("0x88A43bbDF9D098eEC7bCEda4e2494615dfD9bB9C")
+	r0, r1, err := cc.GetReservesByBlockNumber(&pricePair, big.NewInt(30423400))
 	if err != nil {
 		t.Fatal(err)
 	}
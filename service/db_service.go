@@ -3,20 +3,48 @@ package service
 import (
 	"base_scan/repository"
 	"base_scan/repository/orm"
+	"base_scan/types"
+	"errors"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	ErrTokenPairDBDisabled = errors.New("token pair db disabled")
+	ErrTxDBDisabled        = errors.New("tx db disabled")
 )
 
 type DBService interface {
 	AddTokens(tokens []*orm.Token) error
 	AddPairs(pairs []*orm.Pair) error
+	AddPools(pools []*orm.Pool) error
 	AddTxs(txs []*orm.Tx) error
+	AddMevs(mevs []*orm.Mev) error
+	UpsertMakers(makers []*orm.Maker) error
+	UpsertPositions(positions []*orm.Position) error
+	UpsertLaunches(launches []*orm.Launch) error
+	UpdatePairFees(updates []*types.PairFeeUpdate) error
+	AddPoolFees(poolFees []*orm.PoolFee) error
+	GetToken(address common.Address) (*orm.Token, error)
+	GetPair(address common.Address) (*orm.Pair, error)
+	GetPool(address common.Address) (*orm.Pool, error)
+	GetTxsByBlockRange(from, to uint64) ([]*orm.Tx, error)
+	GetPairsByBlockRange(from, to uint64) ([]*orm.Pair, error)
+	GetPoolsByBlockRange(from, to uint64) ([]*orm.Pool, error)
+	GetLastTx(pairAddress common.Address, block uint64) (*orm.Tx, error)
 }
 
 type dbService struct {
-	tokenRepository *repository.TokenRepository
-	pairRepository  *repository.PairRepository
-	txRepository    *repository.TxRepository
-	enableTokenPair bool
-	enableTx        bool
+	tokenRepository    *repository.TokenRepository
+	pairRepository     *repository.PairRepository
+	poolRepository     *repository.PoolRepository
+	launchRepository   *repository.LaunchRepository
+	txRepository       *repository.TxRepository
+	mevRepository      *repository.MevRepository
+	makerRepository    *repository.MakerRepository
+	positionRepository *repository.PositionRepository
+	poolFeeRepository  *repository.PoolFeeRepository
+	enableTokenPair    bool
+	enableTx           bool
 }
 
 func (s *dbService) AddTokens(tokens []*orm.Token) error {
@@ -35,24 +63,153 @@ func (s *dbService) AddPairs(pairs []*orm.Pair) error {
 	return s.pairRepository.CreateBatch(pairs, "address", "chain_id")
 }
 
+func (s *dbService) AddPools(pools []*orm.Pool) error {
+	if !s.enableTokenPair {
+		return nil
+	}
+
+	return s.poolRepository.CreateBatch(pools, "address", "chain_id")
+}
+
 func (s *dbService) AddTxs(txs []*orm.Tx) error {
 	if !s.enableTx {
 		return nil
 	}
-	
-	return s.txRepository.CreateBatch(txs, "token0_address", "block", "block_index", "tx_index")
+
+	return s.txRepository.CreateBatch(txs, "chain_id", "token0_address", "block", "block_index", "tx_index")
+}
+
+func (s *dbService) AddMevs(mevs []*orm.Mev) error {
+	if !s.enableTx {
+		return nil
+	}
+
+	return s.mevRepository.CreateBatch(mevs)
+}
+
+func (s *dbService) UpsertMakers(makers []*orm.Maker) error {
+	if !s.enableTx {
+		return nil
+	}
+
+	return s.makerRepository.UpsertBatch(makers)
+}
+
+func (s *dbService) UpsertPositions(positions []*orm.Position) error {
+	if !s.enableTx {
+		return nil
+	}
+
+	return s.positionRepository.UpsertBatch(positions)
+}
+
+func (s *dbService) UpsertLaunches(launches []*orm.Launch) error {
+	if !s.enableTokenPair {
+		return nil
+	}
+
+	return s.launchRepository.UpsertBatch(launches)
+}
+
+func (s *dbService) UpdatePairFees(updates []*types.PairFeeUpdate) error {
+	if !s.enableTokenPair {
+		return nil
+	}
+
+	for _, update := range updates {
+		if err := s.pairRepository.UpdateFee(update.PairAddress.String(), update.Fee); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *dbService) AddPoolFees(poolFees []*orm.PoolFee) error {
+	if !s.enableTx {
+		return nil
+	}
+
+	return s.poolFeeRepository.CreateBatch(poolFees, "chain_id", "tx_hash", "tx_index")
+}
+
+func (s *dbService) GetToken(address common.Address) (*orm.Token, error) {
+	if !s.enableTokenPair {
+		return nil, ErrTokenPairDBDisabled
+	}
+
+	return s.tokenRepository.GetByAddressAndChainId(address.String())
+}
+
+func (s *dbService) GetPair(address common.Address) (*orm.Pair, error) {
+	if !s.enableTokenPair {
+		return nil, ErrTokenPairDBDisabled
+	}
+
+	return s.pairRepository.GetByAddressAndChainId(address.String())
+}
+
+func (s *dbService) GetPool(address common.Address) (*orm.Pool, error) {
+	if !s.enableTokenPair {
+		return nil, ErrTokenPairDBDisabled
+	}
+
+	return s.poolRepository.GetByAddressAndChainId(address.String())
+}
+
+func (s *dbService) GetTxsByBlockRange(from, to uint64) ([]*orm.Tx, error) {
+	if !s.enableTx {
+		return nil, ErrTxDBDisabled
+	}
+
+	return s.txRepository.GetByBlockRange(from, to)
+}
+
+func (s *dbService) GetPairsByBlockRange(from, to uint64) ([]*orm.Pair, error) {
+	if !s.enableTokenPair {
+		return nil, ErrTokenPairDBDisabled
+	}
+
+	return s.pairRepository.GetByBlockRange(from, to)
+}
+
+func (s *dbService) GetPoolsByBlockRange(from, to uint64) ([]*orm.Pool, error) {
+	if !s.enableTokenPair {
+		return nil, ErrTokenPairDBDisabled
+	}
+
+	return s.poolRepository.GetByBlockRange(from, to)
+}
+
+func (s *dbService) GetLastTx(pairAddress common.Address, block uint64) (*orm.Tx, error) {
+	if !s.enableTx {
+		return nil, ErrTxDBDisabled
+	}
+
+	return s.txRepository.GetLastByPair(pairAddress.String(), block)
 }
 
 func NewDBService(
 	tokenRepository *repository.TokenRepository,
 	pairRepository *repository.PairRepository,
+	poolRepository *repository.PoolRepository,
+	launchRepository *repository.LaunchRepository,
 	txRepository *repository.TxRepository,
+	mevRepository *repository.MevRepository,
+	makerRepository *repository.MakerRepository,
+	positionRepository *repository.PositionRepository,
+	poolFeeRepository *repository.PoolFeeRepository,
 ) DBService {
 	return &dbService{
-		tokenRepository: tokenRepository,
-		pairRepository:  pairRepository,
-		txRepository:    txRepository,
-		enableTokenPair: tokenRepository != nil && pairRepository != nil,
-		enableTx:        txRepository != nil,
+		tokenRepository:    tokenRepository,
+		pairRepository:     pairRepository,
+		poolRepository:     poolRepository,
+		launchRepository:   launchRepository,
+		txRepository:       txRepository,
+		mevRepository:      mevRepository,
+		makerRepository:    makerRepository,
+		positionRepository: positionRepository,
+		poolFeeRepository:  poolFeeRepository,
+		enableTokenPair:    tokenRepository != nil && pairRepository != nil && poolRepository != nil && launchRepository != nil,
+		enableTx:           txRepository != nil && mevRepository != nil && makerRepository != nil && positionRepository != nil && poolFeeRepository != nil,
 	}
 }
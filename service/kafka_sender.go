@@ -14,6 +14,9 @@ import (
 
 type KafkaSender interface {
 	Send(block *types.BlockInfo) error
+	SendMev(mevInfo *types.MevInfo) error
+	SendPositions(positionInfo *types.PositionInfo) error
+	SendLaunchAlerts(launchAlertInfo *types.LaunchAlertInfo) error
 }
 
 type kafkaSender struct {
@@ -84,3 +87,57 @@ func (s *kafkaSender) Send(block *types.BlockInfo) error {
 
 	return nil
 }
+
+func (s *kafkaSender) SendMev(mevInfo *types.MevInfo) error {
+	if !s.conf.Enabled || s.conf.MevTopic == "" || len(mevInfo.Mevs) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(mevInfo)
+	if err != nil {
+		return fmt.Errorf("json.Marshal error: %v, %v", err, mevInfo)
+	}
+
+	s.asyncProducer.Input() <- &sarama.ProducerMessage{
+		Topic: s.conf.MevTopic,
+		Value: sarama.ByteEncoder(data),
+	}
+
+	return nil
+}
+
+func (s *kafkaSender) SendPositions(positionInfo *types.PositionInfo) error {
+	if !s.conf.Enabled || s.conf.PositionTopic == "" || len(positionInfo.Changes) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(positionInfo)
+	if err != nil {
+		return fmt.Errorf("json.Marshal error: %v, %v", err, positionInfo)
+	}
+
+	s.asyncProducer.Input() <- &sarama.ProducerMessage{
+		Topic: s.conf.PositionTopic,
+		Value: sarama.ByteEncoder(data),
+	}
+
+	return nil
+}
+
+func (s *kafkaSender) SendLaunchAlerts(launchAlertInfo *types.LaunchAlertInfo) error {
+	if !s.conf.Enabled || s.conf.LaunchTopic == "" || len(launchAlertInfo.Alerts) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(launchAlertInfo)
+	if err != nil {
+		return fmt.Errorf("json.Marshal error: %v, %v", err, launchAlertInfo)
+	}
+
+	s.asyncProducer.Input() <- &sarama.ProducerMessage{
+		Topic: s.conf.LaunchTopic,
+		Value: sarama.ByteEncoder(data),
+	}
+
+	return nil
+}
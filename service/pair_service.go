@@ -1,46 +1,126 @@
 package service
 
 import (
-	pancakev2 "base_scan/abi/pancake/v2"
-	pancakev3 "base_scan/abi/pancake/v3"
-	uniswapv2 "base_scan/abi/uniswap/v2"
-	uniswapv3 "base_scan/abi/uniswap/v3"
+	"base_scan/abi/aerodrome"
+	"base_scan/abi/balancer"
+	"base_scan/abi/curve"
 	"base_scan/cache"
+	"base_scan/config"
 	"base_scan/log"
 	"base_scan/metrics"
 	"base_scan/types"
 	"context"
+	"errors"
+	"fmt"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
 	"golang.org/x/sync/singleflight"
+	"gorm.io/gorm"
 	"math/big"
+	"sort"
 	"sync"
 	"time"
 )
 
+var (
+	ErrTokenFiltered       = errors.New("token filtered")
+	ErrTooFewPoolTokens    = errors.New("pool has less than 2 tokens")
+	ErrTokenNotInPool      = errors.New("traded token not in pool")
+	ErrUnknownPoolProtocol = errors.New("unknown pool protocol")
+)
+
 type PairService interface {
 	SetPair(pair *types.Pair)
 	GetPairTokens(pair *types.Pair) *types.PairWrap
 	GetPair(pairAddress common.Address, possibleProtocolIds []int) *types.PairWrap
+	GetPoolPair(event types.PoolSwapEvent) *types.PairWrap
+	UpdatePairFee(pair *types.Pair, update *types.PairFeeUpdate) *types.Pair
 }
 
 type pairService struct {
 	ctx            context.Context
 	cache          cache.Cache
 	contractCaller *ContractCaller
+	dbService      DBService
+	filterTTL      *config.FilterTTLConf
+	baseTokens     *types.BaseTokens
+	factories      map[int]common.Address
+	forkIds        []int
+	initCodeHashes map[int]common.Hash
+	discoverForks  bool
 	group          singleflight.Group
 }
 
+/*
+NewPairService
+factories may contain forks registered by types.RegisterFork, a pool of a fork with init code hash is verified by
+its create2 address, see chain.Profile.
+With discoverForks a pool no factory verified is verified against the factory it returns.
+*/
 func NewPairService(
 	cache cache.Cache,
 	contractCaller *ContractCaller,
+	dbService DBService,
+	filterTTL *config.FilterTTLConf,
+	baseTokens *types.BaseTokens,
+	factories map[int]common.Address,
+	initCodeHashes map[int]common.Hash,
+	discoverForks bool,
 ) PairService {
+	forkIds := make([]int, 0)
+	for protocolId := range factories {
+		if _, ok := types.GetFork(protocolId); ok {
+			forkIds = append(forkIds, protocolId)
+		}
+	}
+	sort.Ints(forkIds)
+
 	return &pairService{
 		ctx:            context.Background(),
 		cache:          cache,
 		contractCaller: contractCaller,
+		dbService:      dbService,
+		filterTTL:      filterTTL,
+		baseTokens:     baseTokens,
+		factories:      factories,
+		forkIds:        forkIds,
+		initCodeHashes: initCodeHashes,
+		discoverForks:  discoverForks,
+	}
+}
+
+func secondsToDuration(seconds int) time.Duration {
+	return time.Duration(seconds) * time.Second
+}
+
+func (s *pairService) tokenFilterTTL(filterCode int) time.Duration {
+	switch filterCode {
+	case types.TokenFilterCodeGetDecimals:
+		return secondsToDuration(s.filterTTL.TokenGetDecimalsSec)
+	default:
+		return 0
+	}
+}
+
+func (s *pairService) pairFilterTTL(filterCode int) time.Duration {
+	switch filterCode {
+	case types.FilterCodeGetToken0, types.FilterCodeGetToken1, types.FilterCodeGetPoolTokens:
+		return secondsToDuration(s.filterTTL.PairGetTokenSec)
+	case types.FilterCodeVerifyFailed:
+		return secondsToDuration(s.filterTTL.PairVerifyFailedSec)
+	default:
+		return 0
+	}
+}
+
+func observeFilterRecheck(typ string, filtered bool) {
+	result := "valid"
+	if filtered {
+		result = "filtered"
 	}
+	metrics.FilterRecheckTotal.WithLabelValues(typ, result).Inc()
 }
 
 func (s *pairService) SetPair(pair *types.Pair) {
@@ -103,7 +183,7 @@ func (s *pairService) doGetToken(tokenAddress common.Address) (*types.Token, err
 	}
 
 	if decimalsRes.err != nil {
-		token.Filtered = true
+		token.Filter(types.TokenFilterCodeGetDecimals, decimalsRes.err.Error())
 		return token, decimalsRes.err
 	}
 	token.Decimals = int8(decimalsRes.decimals)
@@ -115,10 +195,45 @@ func (s *pairService) doGetToken(tokenAddress common.Address) (*types.Token, err
 	return token, nil
 }
 
+func (s *pairService) getTokenFromDB(tokenAddress common.Address) (*types.Token, bool) {
+	ormToken, err := s.dbService.GetToken(tokenAddress)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) && !errors.Is(err, ErrTokenPairDBDisabled) {
+			log.Logger.Error("get token from db err", zap.Error(err), zap.String("address", tokenAddress.String()))
+		}
+		return nil, false
+	}
+
+	token := types.NewTokenFromOrm(ormToken)
+	s.cache.SetToken(token)
+	metrics.CacheFallbackToDB.WithLabelValues("token").Inc()
+	return token, true
+}
+
+/*
+getToken
+a filtered token in cache is returned as ErrTokenFiltered until its filter expires,
+then it is removed from cache and checked again as a new token
+*/
 func (s *pairService) getToken(tokenAddress common.Address) (*types.Token, error, bool) {
+	recheck := false
 	cacheToken, ok := s.cache.GetToken(tokenAddress)
 	if ok {
-		return cacheToken, nil, true
+		if !cacheToken.Filtered {
+			return cacheToken, nil, true
+		}
+
+		if !cacheToken.FilterExpired(s.tokenFilterTTL(cacheToken.FilterCode)) {
+			return nil, fmt.Errorf("%w: %s", ErrTokenFiltered, cacheToken.FilterReason), true
+		}
+
+		s.cache.DelToken(tokenAddress)
+		recheck = true
+	}
+
+	dbToken, ok := s.getTokenFromDB(tokenAddress)
+	if ok {
+		return dbToken, nil, true
 	}
 
 	now := time.Now()
@@ -127,6 +242,9 @@ func (s *pairService) getToken(tokenAddress common.Address) (*types.Token, error
 		s.cache.SetToken(token)
 		return token, err
 	})
+	if recheck {
+		observeFilterRecheck("token", err != nil)
+	}
 	if err != nil {
 		return nil, err, false
 	}
@@ -164,14 +282,12 @@ func (s *pairService) getPairTokens(pair *types.Pair) *types.PairWrap {
 	wg.Wait()
 
 	if token0Err != nil {
-		pair.Filtered = true
-		pair.FilterCode = types.FilterCodeGetToken0
+		pair.Filter(types.FilterCodeGetToken0, token0Err.Error())
 		return pairWrap
 	}
 
 	if token1Err != nil {
-		pair.Filtered = true
-		pair.FilterCode = types.FilterCodeGetToken1
+		pair.Filter(types.FilterCodeGetToken1, token1Err.Error())
 		return pairWrap
 	}
 
@@ -183,7 +299,7 @@ func (s *pairService) getPairTokens(pair *types.Pair) *types.PairWrap {
 	pair.Token1Core.Symbol = token1.Symbol
 	pair.Token1Core.Decimals = token1.Decimals
 
-	tokensReversed := pair.OrderToken0Token1()
+	tokensReversed := pair.OrderToken0Token1(s.baseTokens)
 	if tokensReversed {
 		pairWrap.NewToken0 = !token1FromCache
 		pairWrap.NewToken1 = !token0FromCache
@@ -192,9 +308,87 @@ func (s *pairService) getPairTokens(pair *types.Pair) *types.PairWrap {
 		pairWrap.NewToken1 = !token1FromCache
 	}
 
+	if pairWrap.NewPair {
+		s.setPairParams(pair)
+	}
+
 	return pairWrap
 }
 
+/*
+setPairParams sets the version and the fee of a new pair, and the tick spacing of a concentrated liquidity pool.
+The fee of a v3 pool comes from its created event or from verifyPairV3, the one of a v2 pool is the fee of its
+protocol. A failed call leaves the param unknown, it doesn't filter the pair.
+*/
+func (s *pairService) setPairParams(pair *types.Pair) {
+	pair.Version = types.ProtocolVersion(pair.ProtocolId)
+	switch {
+	case pair.ProtocolId == types.ProtocolIdAerodrome:
+		fee, err := s.getAerodromeFee(pair)
+		if err != nil {
+			log.Logger.Info("Err: CallGetFee err, the fee of this pair is unknown",
+				zap.Error(err),
+				zap.String("pair address", pair.Address.String()),
+			)
+		}
+		pair.Fee = fee
+
+	case pair.Version == types.ProtocolVersionV2:
+		pair.Fee = types.V2FeePips(pair.ProtocolId)
+
+	case pair.Version == types.ProtocolVersionV3 && pair.TickSpacing == 0:
+		tickSpacing, err := s.contractCaller.CallTickSpacing(&pair.Address)
+		if err != nil {
+			log.Logger.Info("Err: CallTickSpacing err, the tick spacing of this pair is unknown",
+				zap.Error(err),
+				zap.String("pair address", pair.Address.String()),
+			)
+		}
+		pair.TickSpacing = int32(tickSpacing)
+	}
+}
+
+/*
+getAerodromeFee
+the fee of an aerodrome pool in hundredths of a bip, getFee of the factory is in bips
+*/
+func (s *pairService) getAerodromeFee(pair *types.Pair) (uint32, error) {
+	factoryAddress, ok := s.factories[types.ProtocolIdAerodrome]
+	if !ok {
+		return 0, nil
+	}
+
+	fee, err := s.contractCaller.CallGetFee(&factoryAddress, &pair.Address, pair.Stable)
+	if err != nil {
+		return 0, err
+	}
+
+	return aerodrome.FeePips(fee), nil
+}
+
+/*
+UpdatePairFee
+the pair in cache is replaced by a copy with the new fee, as other goroutines may read the cached one.
+An update back to the default fee gets its fee from the factory.
+*/
+func (s *pairService) UpdatePairFee(pair *types.Pair, update *types.PairFeeUpdate) *types.Pair {
+	if update.Default {
+		fee, err := s.getAerodromeFee(pair)
+		if err != nil {
+			log.Logger.Info("Err: CallGetFee err, the default fee of this pair is unknown",
+				zap.Error(err),
+				zap.String("pair address", pair.Address.String()),
+			)
+		}
+		update.Fee = fee
+	}
+
+	updated := *pair
+	updated.Fee = update.Fee
+	s.SetPair(&updated)
+	return &updated
+}
+
 func (s *pairService) GetPairTokens(pair *types.Pair) *types.PairWrap {
 	doResult, _, _ := s.group.Do(pair.Address.String(), func() (interface{}, error) {
 		pairWrap := s.getPairTokens(pair)
@@ -205,8 +399,31 @@ func (s *pairService) GetPairTokens(pair *types.Pair) *types.PairWrap {
 	return doResult.(*types.PairWrap)
 }
 
+/*
+isLaunchpadOnly
+the curves of launchpads are only known by their launch, a curve of no launch in cache or db was launched
+before the indexer started and is filtered without calls
+*/
+func isLaunchpadOnly(possibleProtocolIds []int) bool {
+	for _, protocolId := range possibleProtocolIds {
+		if !types.IsLaunchpad(protocolId) {
+			return false
+		}
+	}
+	return len(possibleProtocolIds) > 0
+}
+
 func (s *pairService) getPair(pairAddress common.Address, possibleProtocolIds []int) *types.PairWrap {
 	doResult, _, _ := s.group.Do(pairAddress.String()+"gp", func() (interface{}, error) {
+		if isLaunchpadOnly(possibleProtocolIds) {
+			pair := &types.Pair{Address: pairAddress}
+			pair.Filter(types.FilterCodeVerifyFailed, "launch not indexed")
+			s.SetPair(pair)
+			return &types.PairWrap{
+				Pair: pair,
+			}, nil
+		}
+
 		pair := s.doGetPair(pairAddress)
 		if pair.Filtered {
 			s.SetPair(pair)
@@ -234,11 +451,59 @@ func (s *pairService) getPair(pairAddress common.Address, possibleProtocolIds []
 	return doResult.(*types.PairWrap)
 }
 
+/*
+getPairFromDB
+pairs are only persisted after verification, so a pair found in db is neither filtered nor new
+*/
+func (s *pairService) getPairFromDB(pairAddress common.Address) (*types.Pair, bool) {
+	ormPair, err := s.dbService.GetPair(pairAddress)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) && !errors.Is(err, ErrTokenPairDBDisabled) {
+			log.Logger.Error("get pair from db err", zap.Error(err), zap.String("address", pairAddress.String()))
+		}
+		return nil, false
+	}
+
+	token0, token0Err, _ := s.getToken(common.HexToAddress(ormPair.Token0))
+	if token0Err != nil {
+		return nil, false
+	}
+
+	token1, token1Err, _ := s.getToken(common.HexToAddress(ormPair.Token1))
+	if token1Err != nil {
+		return nil, false
+	}
+
+	pair := types.NewPairFromOrm(ormPair, token0, token1)
+	s.SetPair(pair)
+	metrics.CacheFallbackToDB.WithLabelValues("pair").Inc()
+	return pair, true
+}
+
+/*
+GetPair
+a filtered pair in cache is returned until its filter expires,
+then it is removed from cache and checked again as a new pair
+*/
 func (s *pairService) GetPair(pairAddress common.Address, possibleProtocolIds []int) *types.PairWrap {
 	cachePair, ok := s.cache.GetPair(pairAddress)
+	if ok {
+		if !cachePair.FilterExpired(s.pairFilterTTL(cachePair.FilterCode)) {
+			return &types.PairWrap{
+				Pair: cachePair,
+			}
+		}
+
+		s.cache.DelPair(pairAddress)
+		pairWrap := s.getPair(pairAddress, possibleProtocolIds)
+		observeFilterRecheck("pair", pairWrap.Pair.Filtered)
+		return pairWrap
+	}
+
+	dbPair, ok := s.getPairFromDB(pairAddress)
 	if ok {
 		return &types.PairWrap{
-			Pair: cachePair,
+			Pair: dbPair,
 		}
 	}
 
@@ -280,8 +545,7 @@ func (s *pairService) doGetPair(pairAddress common.Address) *types.Pair {
 			zap.Error(token0Res.err),
 			zap.String("pair address", pairAddress.String()),
 		)
-		pair.Filtered = true
-		pair.FilterCode = types.FilterCodeGetToken0
+		pair.Filter(types.FilterCodeGetToken0, token0Res.err.Error())
 		return pair
 	}
 	pair.Token0Core = &types.TokenCore{
@@ -293,8 +557,7 @@ func (s *pairService) doGetPair(pairAddress common.Address) *types.Pair {
 			zap.Error(token1Res.err),
 			zap.String("pair address", pairAddress.String()),
 		)
-		pair.Filtered = true
-		pair.FilterCode = types.FilterCodeGetToken1
+		pair.Filter(types.FilterCodeGetToken1, token1Res.err.Error())
 		return pair
 	}
 	pair.Token1Core = &types.TokenCore{
@@ -302,7 +565,7 @@ func (s *pairService) doGetPair(pairAddress common.Address) *types.Pair {
 	}
 
 	metrics.GetPairDurationMs.Observe(float64(time.Since(now).Milliseconds()))
-	pair.FilterByToken0AndToken1()
+	pair.FilterByToken0AndToken1(s.baseTokens)
 	return pair
 }
 
@@ -325,7 +588,12 @@ func (s *pairService) verifyPairV3(pairFactoryAddress common.Address, pair *type
 		return false
 	}
 
-	return types.IsSameAddress(pairAddressQueried, pair.Address)
+	if !types.IsSameAddress(pairAddressQueried, pair.Address) {
+		return false
+	}
+
+	pair.Fee = uint32(fee.Uint64())
+	return true
 }
 
 func (s *pairService) verifyPair(pair *types.Pair, possibleProtocolIds []int) bool {
@@ -336,9 +604,14 @@ func (s *pairService) verifyPair(pair *types.Pair, possibleProtocolIds []int) bo
 	}()
 
 	for _, protocolId := range possibleProtocolIds {
+		factoryAddress, ok := s.factories[protocolId]
+		if !ok {
+			continue
+		}
+
 		switch protocolId {
 		case types.ProtocolIdUniswapV2:
-			if s.verifyPairV2(uniswapv2.FactoryAddress, pair) {
+			if s.verifyPairV2(factoryAddress, pair) {
 				pair.ProtocolId = protocolId
 				metrics.VerifyPairTotal.WithLabelValues("success").Inc()
 				metrics.VerifyPairOkByProtocol.WithLabelValues("uniswap_v2").Inc()
@@ -346,7 +619,7 @@ func (s *pairService) verifyPair(pair *types.Pair, possibleProtocolIds []int) bo
 			}
 
 		case types.ProtocolIdPancakeV2:
-			if s.verifyPairV2(pancakev2.FactoryAddress, pair) {
+			if s.verifyPairV2(factoryAddress, pair) {
 				pair.ProtocolId = protocolId
 				metrics.VerifyPairTotal.WithLabelValues("success").Inc()
 				metrics.VerifyPairOkByProtocol.WithLabelValues("pancake_v2").Inc()
@@ -354,7 +627,7 @@ func (s *pairService) verifyPair(pair *types.Pair, possibleProtocolIds []int) bo
 			}
 
 		case types.ProtocolIdUniswapV3:
-			if s.verifyPairV3(uniswapv3.FactoryAddress, pair) {
+			if s.verifyPairV3(factoryAddress, pair) {
 				pair.ProtocolId = protocolId
 				metrics.VerifyPairTotal.WithLabelValues("success").Inc()
 				metrics.VerifyPairOkByProtocol.WithLabelValues("uniswap_v3").Inc()
@@ -362,7 +635,7 @@ func (s *pairService) verifyPair(pair *types.Pair, possibleProtocolIds []int) bo
 			}
 
 		case types.ProtocolIdPancakeV3:
-			if s.verifyPairV3(pancakev3.FactoryAddress, pair) {
+			if s.verifyPairV3(factoryAddress, pair) {
 				pair.ProtocolId = protocolId
 				metrics.VerifyPairTotal.WithLabelValues("success").Inc()
 				metrics.VerifyPairOkByProtocol.WithLabelValues("pancake_v3").Inc()
@@ -370,13 +643,24 @@ func (s *pairService) verifyPair(pair *types.Pair, possibleProtocolIds []int) bo
 			}
 
 		case types.ProtocolIdAerodrome:
-			isPool, isPoolErr := s.contractCaller.CallIsPool(&pair.Address)
+			isPool, isPoolErr := s.contractCaller.CallIsPool(&factoryAddress, &pair.Address)
 			if isPoolErr != nil {
 				continue
 			}
 
 			if isPool {
+				// a pair of unknown curve is not kept, it fails verification and is checked again after the filter ttl
+				stable, stableErr := s.contractCaller.CallStable(&pair.Address)
+				if stableErr != nil {
+					log.Logger.Info("Err: CallStable err, this pair will filtered",
+						zap.Error(stableErr),
+						zap.String("pair address", pair.Address.String()),
+					)
+					continue
+				}
+
 				pair.ProtocolId = protocolId
+				pair.Stable = stable
 				metrics.VerifyPairTotal.WithLabelValues("success").Inc()
 				metrics.VerifyPairOkByProtocol.WithLabelValues("aerodrome").Inc()
 				return true
@@ -384,9 +668,261 @@ func (s *pairService) verifyPair(pair *types.Pair, possibleProtocolIds []int) bo
 		}
 	}
 
-	pair.Filtered = true
-	pair.FilterCode = types.FilterCodeVerifyFailed
+	forkBases := possibleForkBases(possibleProtocolIds)
+	for _, forkId := range s.forkIds {
+		fork, _ := types.GetFork(forkId)
+		if !forkBases[fork.Base] || !s.verifyForkPair(fork, pair) {
+			continue
+		}
+
+		pair.ProtocolId = forkId
+		metrics.VerifyPairTotal.WithLabelValues("success").Inc()
+		metrics.VerifyPairOkByProtocol.WithLabelValues(forkMetricsLabel(fork.Base)).Inc()
+		return true
+	}
+
+	if s.discoverForks && len(forkBases) > 0 && s.discoverForkPair(pair, forkBases) {
+		metrics.VerifyPairTotal.WithLabelValues("success").Inc()
+		metrics.VerifyPairOkByProtocol.WithLabelValues("discovered_" + forkMetricsLabel(types.BaseProtocolId(pair.ProtocolId))).Inc()
+		return true
+	}
+
+	pair.Filter(types.FilterCodeVerifyFailed, "no factory verified the pair")
 	metrics.VerifyPairTotal.WithLabelValues("failed").Inc()
 
 	return false
 }
+
+// forkBaseOfProtocolId maps the protocols whose events a pool emits to the protocol forked by the pool
+var forkBaseOfProtocolId = map[int]int{
+	types.ProtocolIdUniswapV2: types.ProtocolIdUniswapV2,
+	types.ProtocolIdPancakeV2: types.ProtocolIdUniswapV2,
+	types.ProtocolIdUniswapV3: types.ProtocolIdUniswapV3,
+}
+
+func possibleForkBases(possibleProtocolIds []int) map[int]bool {
+	forkBases := make(map[int]bool, 2)
+	for _, protocolId := range possibleProtocolIds {
+		if base, ok := forkBaseOfProtocolId[protocolId]; ok {
+			forkBases[base] = true
+		}
+	}
+	return forkBases
+}
+
+func forkMetricsLabel(base int) string {
+	if base == types.ProtocolIdUniswapV3 {
+		return "fork_v3"
+	}
+	return "fork_v2"
+}
+
+/*
+poolAddressV2 is the create2 address of the uniswap v2 like pair of token0 and token1 (in pair order)
+created by factory, salt keccak256(abi.encodePacked(token0, token1))
+*/
+func poolAddressV2(factory, token0, token1 common.Address, initCodeHash common.Hash) common.Address {
+	salt := crypto.Keccak256Hash(token0.Bytes(), token1.Bytes())
+	return crypto.CreateAddress2(factory, salt, initCodeHash.Bytes())
+}
+
+/*
+poolAddressV3 is the create2 address of the uniswap v3 like pool of token0, token1 (in pool order) and fee
+created by factory, salt keccak256(abi.encode(token0, token1, fee))
+*/
+func poolAddressV3(factory, token0, token1 common.Address, fee *big.Int, initCodeHash common.Hash) common.Address {
+	salt := crypto.Keccak256Hash(
+		common.LeftPadBytes(token0.Bytes(), 32),
+		common.LeftPadBytes(token1.Bytes(), 32),
+		common.LeftPadBytes(fee.Bytes(), 32),
+	)
+	return crypto.CreateAddress2(factory, salt, initCodeHash.Bytes())
+}
+
+func (s *pairService) verifyForkPair(fork *types.Fork, pair *types.Pair) bool {
+	factoryAddress := s.factories[fork.Id]
+	initCodeHash, ok := s.initCodeHashes[fork.Id]
+	if !ok {
+		if fork.Base == types.ProtocolIdUniswapV3 {
+			return s.verifyPairV3(factoryAddress, pair)
+		}
+		return s.verifyPairV2(factoryAddress, pair)
+	}
+
+	if fork.Base == types.ProtocolIdUniswapV3 {
+		fee, err := s.contractCaller.CallFee(&pair.Address)
+		if err != nil {
+			return false
+		}
+		if poolAddressV3(factoryAddress, pair.Token0Core.Address, pair.Token1Core.Address, fee, initCodeHash) != pair.Address {
+			return false
+		}
+		pair.Fee = uint32(fee.Uint64())
+		return true
+	}
+	return poolAddressV2(factoryAddress, pair.Token0Core.Address, pair.Token1Core.Address, initCodeHash) == pair.Address
+}
+
+/*
+discoverForkPair verifies the pair against the factory returned by its factory(),
+a pair of a known factory is not discovered again since that factory did not verify it
+*/
+func (s *pairService) discoverForkPair(pair *types.Pair, forkBases map[int]bool) bool {
+	factoryAddress, err := s.contractCaller.CallFactory(&pair.Address)
+	if err != nil || factoryAddress == types.ZeroAddress {
+		return false
+	}
+	for _, knownFactory := range s.factories {
+		if knownFactory == factoryAddress {
+			return false
+		}
+	}
+
+	switch {
+	case forkBases[types.ProtocolIdUniswapV2] && s.verifyPairV2(factoryAddress, pair):
+		pair.ProtocolId = types.ProtocolIdUniswapV2Fork
+	case forkBases[types.ProtocolIdUniswapV3] && s.verifyPairV3(factoryAddress, pair):
+		pair.ProtocolId = types.ProtocolIdUniswapV3Fork
+	default:
+		return false
+	}
+
+	log.Logger.Info("discovered fork pair",
+		zap.String("pair address", pair.Address.String()),
+		zap.String("factory", factoryAddress.String()),
+		zap.String("protocol", types.GetProtocolName(pair.ProtocolId)),
+	)
+	return true
+}
+
+// getCurveCoins calls coins until the index is out of the pool
+func (s *pairService) getCurveCoins(poolAddress common.Address) ([]common.Address, error) {
+	coins := make([]common.Address, 0, 2)
+	for i := 0; i < curve.MaxCoins; i++ {
+		coin, err := s.contractCaller.CallCoins(&poolAddress, i)
+		if errors.Is(err, ErrOutputEmpty) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		coins = append(coins, coin)
+	}
+	return coins, nil
+}
+
+func (s *pairService) doGetPool(poolRef *types.Pool) *types.Pool {
+	pool := &types.Pool{
+		Address:    poolRef.Address,
+		PoolId:     poolRef.PoolId,
+		ProtocolId: poolRef.ProtocolId,
+	}
+
+	var (
+		tokens []common.Address
+		err    error
+	)
+	switch pool.ProtocolId {
+	case types.ProtocolIdCurve:
+		tokens, err = s.getCurveCoins(pool.Address)
+	case types.ProtocolIdBalancerV2:
+		tokens, err = s.contractCaller.CallGetPoolTokens(&balancer.VaultAddress, pool.PoolId)
+	default:
+		err = fmt.Errorf("%w: %d", ErrUnknownPoolProtocol, pool.ProtocolId)
+	}
+	if err == nil && len(tokens) < 2 {
+		err = ErrTooFewPoolTokens
+	}
+	if err != nil {
+		log.Logger.Info("Err: get pool tokens err, this pool will filtered",
+			zap.Error(err),
+			zap.String("pool address", pool.Address.String()),
+		)
+		pool.Filter(types.FilterCodeGetPoolTokens, err.Error())
+		return pool
+	}
+
+	pool.Tokens = tokens
+	return pool
+}
+
+func (s *pairService) getPoolFromDB(poolAddress common.Address) (*types.Pool, bool) {
+	ormPool, err := s.dbService.GetPool(poolAddress)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) && !errors.Is(err, ErrTokenPairDBDisabled) {
+			log.Logger.Error("get pool from db err", zap.Error(err), zap.String("address", poolAddress.String()))
+		}
+		return nil, false
+	}
+
+	pool := types.NewPoolFromOrm(ormPool)
+	s.cache.SetPool(pool)
+	metrics.CacheFallbackToDB.WithLabelValues("pool").Inc()
+	return pool, true
+}
+
+/*
+getPool returns the pool of poolRef with its tokens and whether it is new,
+a filtered pool in cache is returned until its filter expires like a pair
+*/
+func (s *pairService) getPool(poolRef *types.Pool) (*types.Pool, bool) {
+	recheck := false
+	cachePool, ok := s.cache.GetPool(poolRef.Address)
+	if ok {
+		if !cachePool.FilterExpired(s.pairFilterTTL(cachePool.FilterCode)) {
+			return cachePool, false
+		}
+
+		s.cache.DelPool(poolRef.Address)
+		recheck = true
+	}
+
+	dbPool, ok := s.getPoolFromDB(poolRef.Address)
+	if ok {
+		return dbPool, false
+	}
+
+	doResult, _, _ := s.group.Do(poolRef.Address.String()+"pl", func() (interface{}, error) {
+		pool := s.doGetPool(poolRef)
+		s.cache.SetPool(pool)
+		return pool, nil
+	})
+	pool := doResult.(*types.Pool)
+	if recheck {
+		observeFilterRecheck("pool", pool.Filtered)
+	}
+	return pool, !pool.Filtered
+}
+
+/*
+GetPoolPair returns the pair of the tokens traded by a swap of a pool with the pool.
+The pair has the address of the pool and is never cached or new, since all the pairs of a pool share the address,
+the pool is persisted instead.
+*/
+func (s *pairService) GetPoolPair(event types.PoolSwapEvent) *types.PairWrap {
+	pool, newPool := s.getPool(event.GetPool())
+	filteredPairWrap := func(filterCode int, filterReason string) *types.PairWrap {
+		pair := &types.Pair{Address: pool.Address, ProtocolId: pool.ProtocolId}
+		pair.Filter(filterCode, filterReason)
+		return &types.PairWrap{Pair: pair, Pool: pool, NewPool: newPool}
+	}
+
+	if pool.Filtered {
+		return filteredPairWrap(pool.FilterCode, pool.FilterReason)
+	}
+
+	tokenIn, tokenOut, ok := event.ResolveTokens(pool)
+	if !ok {
+		return filteredPairWrap(types.FilterCodeGetPoolTokens, ErrTokenNotInPool.Error())
+	}
+
+	pair := pool.PairOf(tokenIn, tokenOut)
+	if pair.FilterByToken0AndToken1(s.baseTokens) {
+		return &types.PairWrap{Pair: pair, Pool: pool, NewPool: newPool}
+	}
+
+	pairWrap := s.getPairTokens(pair)
+	pairWrap.NewPair = false
+	pairWrap.Pool, pairWrap.NewPool = pool, newPool
+	return pairWrap
+}
@@ -1,7 +1,10 @@
 package service
 
 import (
+	"base_scan/types"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/require"
+	"math/big"
 	"testing"
 )
 
@@ -233,3 +236,24 @@ func TestPairService_GetPair_TokensReversed(t *testing.T) {
 	t.Log(expectPair)
 	t.Log(pw.Pair)
 }
+
+func TestPoolAddress(t *testing.T) {
+	usdc := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	weth := common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2")
+
+	// uniswap v2 and v3 USDC/WETH on ethereum
+	require.Equal(t, common.HexToAddress("0xB4e16d0168e52d35CaCD2c6185b44281Ec28C9Dc"), poolAddressV2(
+		common.HexToAddress("0x5C69bEe701ef814a2B6a3EDD4B1652CB9cc5aA6f"), usdc, weth,
+		common.HexToHash("0x96e8ac4277198ff8b6f785478aa9a39f403cb768dd02cbee326c3e7da348845f"),
+	))
+	require.Equal(t, common.HexToAddress("0x88e6A0c2dDD26FEEb64F039a2c41296FcB3f5640"), poolAddressV3(
+		common.HexToAddress("0x1F98431c8aD98523631AE4a59f267346ea31F984"), usdc, weth, big.NewInt(500),
+		common.HexToHash("0xe34f199b19b2b4f47f68442619d555527d244f78a3297ea89325f843f87b8b54"),
+	))
+}
+
+func TestPossibleForkBases(t *testing.T) {
+	require.Equal(t, map[int]bool{types.ProtocolIdUniswapV2: true}, possibleForkBases([]int{types.ProtocolIdUniswapV2, types.ProtocolIdPancakeV2}))
+	require.Equal(t, map[int]bool{types.ProtocolIdUniswapV3: true}, possibleForkBases([]int{types.ProtocolIdUniswapV3, types.ProtocolIdPancakeV3}))
+	require.Empty(t, possibleForkBases([]int{types.ProtocolIdAerodrome}))
+}
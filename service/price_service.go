@@ -2,6 +2,7 @@ package service
 
 import (
 	"base_scan/cache"
+	"base_scan/chain"
 	"base_scan/log"
 	"base_scan/metrics"
 	"context"
@@ -24,6 +25,7 @@ type priceService struct {
 	workPoolSize   int
 	workPool       *ants.Pool
 	ethClient      *ethclient.Client
+	profile        *chain.Profile
 }
 
 func NewPriceService(
@@ -31,6 +33,7 @@ func NewPriceService(
 	contractCaller *ContractCaller,
 	ethClient *ethclient.Client,
 	poolSize int,
+	profile *chain.Profile,
 ) PriceService {
 	var workPool *ants.Pool
 	var err error
@@ -47,6 +50,7 @@ func NewPriceService(
 		workPoolSize:   poolSize,
 		workPool:       workPool,
 		ethClient:      ethClient,
+		profile:        profile,
 	}
 }
 
@@ -85,16 +89,21 @@ func (ps *priceService) GetNativeTokenPrice(blockNumber *big.Int) (decimal.Decim
 
 func (ps *priceService) getNativeTokenPrice(blockNumber *big.Int) (decimal.Decimal, error) {
 	now := time.Now()
-	reserve0, reserve1, err := ps.contractCaller.GetReservesByBlockNumber(blockNumber)
+	reserve0, reserve1, err := ps.contractCaller.GetReservesByBlockNumber(&ps.profile.PricePair, blockNumber)
 	if err != nil {
 		log.Logger.Error("GetReservesByBlockNumber err", zap.Error(err), zap.Uint64("blockNumber", blockNumber.Uint64()))
 		return decimal.Zero, err
 	}
 	metrics.CallContractArchiveDurationMs.Observe(float64(time.Since(now).Milliseconds()))
 
-	// TODO get pair info
-	USDCAmountDivWETHAmount := decimal.NewFromBigInt(reserve1, -6).Div(decimal.NewFromBigInt(reserve0, -18))
-	ps.cache.SetPrice(blockNumber, USDCAmountDivWETHAmount)
+	nativeReserve, stableReserve := reserve0, reserve1
+	if !ps.profile.PricePairNativeIsToken0 {
+		nativeReserve, stableReserve = reserve1, reserve0
+	}
+
+	stableAmountDivNativeAmount := decimal.NewFromBigInt(stableReserve, -int32(ps.profile.StableTokenDecimals)).
+		Div(decimal.NewFromBigInt(nativeReserve, -int32(ps.profile.NativeTokenDecimals())))
+	ps.cache.SetPrice(blockNumber, stableAmountDivNativeAmount)
 
-	return USDCAmountDivWETHAmount, nil
+	return stableAmountDivNativeAmount, nil
 }
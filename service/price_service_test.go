@@ -2,6 +2,7 @@ package service
 
 import (
 	"base_scan/cache"
+	"base_scan/chain"
 	"base_scan/config"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"math/big"
@@ -10,16 +11,22 @@ import (
 
 func TestPriceService_GetBNBPrice(t *testing.T) {
 	t.Skip()
-	c := cache.MockCache{}
+	c := cache.NewMockCache()
 
-	ethClient, err := ethclient.Dial(config.G.Chain.EndpointArchive)
+	conf := config.Default()
+	ethClient, err := ethclient.Dial(conf.Chain.EndpointArchive)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	cc := NewContractCaller(ethClient, config.G.ContractCaller.Retry.GetRetryParams())
+	profile, err := chain.GetProfile(chain.ProfileBase)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cc := NewContractCaller(ethClient, conf.ContractCaller.Retry.GetRetryParams())
 
-	ps := NewPriceService(&c, cc, ethClient, 0)
+	ps := NewPriceService(c, cc, ethClient, 0, profile)
 	price, err := ps.GetNativeTokenPrice(big.NewInt(22466005))
 	if err != nil {
 		t.Fatal(err)
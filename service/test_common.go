@@ -20,6 +20,8 @@ type TestPair struct {
 	token0        *TestToken
 	token1        *TestToken
 	fee           *big.Int
+	feePips       uint32
+	tickSpacing   int32
 }
 
 func (tp *TestPair) GetPairWithoutTokenInfo() *types.Pair {
@@ -34,6 +36,10 @@ func (tp *TestPair) GetPairWithoutTokenInfo() *types.Pair {
 		},
 		ProtocolId: tp.protocolId,
 	}
+	if tp.fee != nil {
+		// the fee of a v3 pool is in its created event
+		pair.Fee = uint32(tp.fee.Uint64())
+	}
 	return pair
 }
 
@@ -51,10 +57,13 @@ func (tp *TestPair) GetExpectedPair() *types.Pair {
 			Symbol:   tp.token1.symbol,
 			Decimals: tp.token1.decimals,
 		},
-		ProtocolId: tp.protocolId,
+		ProtocolId:  tp.protocolId,
+		Fee:         tp.feePips,
+		TickSpacing: tp.tickSpacing,
+		Version:     types.ProtocolVersion(tp.protocolId),
 	}
 
-	pair.OrderToken0Token1()
+	pair.OrderToken0Token1(types.DefaultBaseTokens)
 	return pair
 }
 
@@ -104,12 +113,15 @@ var (
 		tokenReversed: true,
 		token0:        tokenWETH,
 		token1:        tokenAERO,
+		// the default fee of volatile pools
+		feePips: 3000,
 	}
 	pairUniswapV2 = &TestPair{
 		protocolId: types.ProtocolIdUniswapV2,
 		address:    common.HexToAddress("0x88A43bbDF9D098eEC7bCEda4e2494615dfD9bB9C"),
 		token0:     tokenWETH,
 		token1:     tokenUSDC,
+		feePips:    3000,
 	}
 	pairUniswapV3 = &TestPair{
 		protocolId:    types.ProtocolIdUniswapV3,
@@ -118,12 +130,15 @@ var (
 		token0:        tokenWETH,
 		token1:        tokenPEPE,
 		fee:           big.NewInt(10000),
+		feePips:       10000,
+		tickSpacing:   200,
 	}
 	pairPancakeV2 = &TestPair{
 		protocolId: types.ProtocolIdPancakeV2,
 		address:    common.HexToAddress("0xc637ab6D3aB0c55a7812B0b23955bA6E40859447"),
 		token0:     tokenCAKE,
 		token1:     tokenWETH,
+		feePips:    2500,
 	}
 	pairPancakeV3 = &TestPair{
 		protocolId:    types.ProtocolIdPancakeV3,
@@ -132,6 +147,8 @@ var (
 		token0:        tokenWETH,
 		token1:        tokenDEGEN,
 		fee:           big.NewInt(500),
+		feePips:       500,
+		tickSpacing:   10,
 	}
 )
 
@@ -2,6 +2,7 @@ package service
 
 import (
 	"base_scan/cache"
+	"base_scan/chain"
 	"base_scan/config"
 	"context"
 	"github.com/ethereum/go-ethereum/common"
@@ -30,9 +31,15 @@ func GetTestContext() *TestContext {
 		panic(err)
 	}
 
-	contractCaller := NewContractCaller(ethClient, config.G.ContractCaller.Retry.GetRetryParams())
+	profile, err := chain.GetProfile(chain.ProfileBase)
+	if err != nil {
+		panic(err)
+	}
+
+	conf := config.Default()
+	contractCaller := NewContractCaller(ethClient, conf.ContractCaller.Retry.GetRetryParams())
 	cache := cache.NewMockCache()
-	pairService_ := NewPairService(cache, contractCaller)
+	pairService_ := NewPairService(cache, contractCaller, NewDBService(nil, nil, nil, nil, nil, nil, nil, nil, nil), conf.FilterTTL, profile.BaseTokens(), profile.Factories, profile.InitCodeHashes, profile.DiscoverForks)
 
 	return &TestContext{
 		ethClient:      ethClient,
@@ -2,7 +2,9 @@ package service
 
 import (
 	"base_scan/abi/aerodrome"
+	"base_scan/abi/balancer"
 	"base_scan/abi/bep20"
+	"base_scan/abi/curve"
 	"base_scan/abi/ds_token"
 	uniswapv2 "base_scan/abi/uniswap/v2"
 	uniswapv3 "base_scan/abi/uniswap/v3"
@@ -75,6 +77,18 @@ var (
 		aerodrome.FactoryAbi,
 	})
 
+	AerodromePoolUnpacker = NewUnpacker([]*abi.ABI{
+		aerodrome.PairAbi,
+	})
+
+	CurvePoolUnpacker = NewUnpacker([]*abi.ABI{
+		curve.PoolAbi,
+	})
+
+	BalancerVaultUnpacker = NewUnpacker([]*abi.ABI{
+		balancer.VaultAbi,
+	})
+
 	Name2Unpacker = map[string]Unpacker{
 		"name":        TokenUnpacker,
 		"symbol":      TokenUnpacker,
@@ -83,7 +97,10 @@ var (
 		"token0":      UniswapV2PairUnpacker,
 		"token1":      UniswapV2PairUnpacker,
 		"getReserves": UniswapV2PairUnpacker,
+		"factory":     UniswapV2PairUnpacker,
 		"fee":         UniswapV3PoolUnpacker,
+		"tickSpacing": UniswapV3PoolUnpacker,
+		"stable":      AerodromePoolUnpacker,
 	}
 )
 
@@ -0,0 +1,29 @@
+package types
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+)
+
+/*
+BaseTokens are the quote tokens of a chain, the wrapped native token and the stable token,
+pairs are priced in them and a pair without base token is filtered. See chain.Profile.
+*/
+type BaseTokens struct {
+	Native common.Address
+	Stable common.Address
+}
+
+// DefaultBaseTokens are the base tokens of base, the chain of the default factories, see abi.BaseFactoryProtocolIds.
+var DefaultBaseTokens = &BaseTokens{Native: WETHAddress, Stable: USDCAddress}
+
+func (b *BaseTokens) IsNative(address common.Address) bool {
+	return address == b.Native
+}
+
+func (b *BaseTokens) IsStable(address common.Address) bool {
+	return address == b.Stable
+}
+
+func (b *BaseTokens) IsBase(address common.Address) bool {
+	return b.IsNative(address) || b.IsStable(address)
+}
@@ -8,24 +8,40 @@ import (
 )
 
 type BlockResult struct {
+	ChainId          uint64
+	BaseTokens       *BaseTokens
 	Height           uint64
 	Timestamp        uint64
 	BlockTime        time.Time
 	NativeTokenPrice decimal.Decimal
 	NewPairs         map[common.Address]*Pair
+	NewPools         map[common.Address]*Pool
 	NewTokens        map[common.Address]*Token
 	TxResults        []*TxResult
+	PositionChanges  []*PositionChange
+	PoolStateUpdates []*PoolStateUpdate
+	Deployments      []*Deployment
+	NativeTransfers  []*NativeTransfer
+	// code of the makers read when the block is parsed, see maker.Classifier
+	MakerCodes map[common.Address][]byte
 }
 
-func NewBlockResult(height, Timestamp uint64, nativeTokenPrice decimal.Decimal) *BlockResult {
+func NewBlockResult(chainId uint64, baseTokens *BaseTokens, height, Timestamp uint64, nativeTokenPrice decimal.Decimal) *BlockResult {
 	return &BlockResult{
+		ChainId:          chainId,
+		BaseTokens:       baseTokens,
 		Height:           height,
 		Timestamp:        Timestamp,
 		BlockTime:        time.Unix(int64(Timestamp), 0),
 		NativeTokenPrice: nativeTokenPrice,
 		NewPairs:         make(map[common.Address]*Pair),
+		NewPools:         make(map[common.Address]*Pool),
 		NewTokens:        make(map[common.Address]*Token),
 		TxResults:        make([]*TxResult, 0, 200),
+		PositionChanges:  make([]*PositionChange, 0),
+		PoolStateUpdates: make([]*PoolStateUpdate, 0),
+		Deployments:      make([]*Deployment, 0),
+		NativeTransfers:  make([]*NativeTransfer, 0),
 	}
 }
 
@@ -33,6 +49,36 @@ func (br *BlockResult) AddTxResult(txResult *TxResult) {
 	br.TxResults = append(br.TxResults, txResult)
 }
 
+// AddNewPool keeps a copy of a pool first seen in the block, with the block set.
+func (br *BlockResult) AddNewPool(pool *Pool) {
+	newPool := *pool
+	newPool.Block = br.Height
+	newPool.BlockAt = br.BlockTime
+	br.NewPools[pool.Address] = &newPool
+}
+
+func (br *BlockResult) AddPositionChanges(changes []*PositionChange) {
+	for _, change := range changes {
+		change.BlockAt = br.BlockTime
+	}
+	br.PositionChanges = append(br.PositionChanges, changes...)
+}
+
+// Makers returns the senders of the txs with events and the smart accounts of their user operations, in block order.
+func (br *BlockResult) Makers() []common.Address {
+	makers := make([]common.Address, 0, len(br.TxResults))
+	for _, txResult := range br.TxResults {
+		if len(txResult.PairAddress2TxPairEvent) == 0 {
+			continue
+		}
+		makers = append(makers, txResult.Maker)
+		for _, userOperation := range txResult.UserOperations {
+			makers = append(makers, userOperation.Sender)
+		}
+	}
+	return makers
+}
+
 func (br *BlockResult) linkEvents() {
 	for _, txResult := range br.TxResults {
 		txResult.LinkEvents()
@@ -50,6 +96,9 @@ func (br *BlockResult) getAllEvents() []Event {
 			events = append(events, txPairEvent.PancakeV2...)
 			events = append(events, txPairEvent.PancakeV3...)
 			events = append(events, txPairEvent.Aerodrome...)
+			events = append(events, txPairEvent.Forks...)
+			events = append(events, txPairEvent.Pools...)
+			events = append(events, txPairEvent.Launchpads...)
 		}
 	}
 	return events
@@ -93,14 +142,29 @@ func (br *BlockResult) GetKafkaMessage() *BlockInfo {
 	newPairs := make([]*Pair, 0, 10)
 	poolUpdates := make([]*PoolUpdate, 0, 40)
 	poolUpdateParameters := make([]*PoolUpdateParameter, 0, 40)
+	launches := make([]*Launch, 0)
+	pairFeeUpdates := make([]*PairFeeUpdate, 0)
+	poolFees := make([]*orm.PoolFee, 0)
 	for _, event := range events {
+		if launchEvent, ok := event.(LaunchEvent); ok {
+			launches = append(launches, launchEvent.GetLaunch())
+		}
+
+		if pairFeeEvent, ok := event.(PairFeeEvent); ok {
+			pairFeeUpdates = append(pairFeeUpdates, pairFeeEvent.GetPairFeeUpdate())
+		}
+
+		if poolFeeEvent, ok := event.(PoolFeeEvent); ok {
+			poolFees = append(poolFees, poolFeeEvent.GetPoolFee(br.ChainId))
+		}
+
 		if event.IsCreatePair() {
 			newPairs = append(newPairs, event.GetPair())
 			continue
 		}
 
 		if event.CanGetTx() {
-			txs = append(txs, event.GetTx(br.NativeTokenPrice))
+			txs = append(txs, event.GetTx(br.BaseTokens, br.NativeTokenPrice))
 		}
 
 		if event.CanGetPoolUpdate() {
@@ -119,26 +183,42 @@ func (br *BlockResult) GetKafkaMessage() *BlockInfo {
 
 	ormTokens := make([]*orm.Token, 0, len(br.NewTokens))
 	for _, token := range br.NewTokens {
-		ormTokens = append(ormTokens, token.GetOrmToken())
+		ormTokens = append(ormTokens, token.GetOrmToken(br.ChainId))
 	}
 
 	ormPairs := make([]*orm.Pair, 0, len(newPairs))
 	for _, pair := range br.NewPairs {
-		ormPairs = append(ormPairs, pair.GetOrmPair())
+		ormPairs = append(ormPairs, pair.GetOrmPair(br.ChainId))
+	}
+
+	ormPools := make([]*orm.Pool, 0, len(br.NewPools))
+	for _, pool := range br.NewPools {
+		ormPools = append(ormPools, pool.GetOrmPool(br.ChainId))
+	}
+
+	ormLaunches := make([]*orm.Launch, 0, len(launches))
+	for _, launch := range MergeLaunches(launches) {
+		ormLaunches = append(ormLaunches, launch.GetOrmLaunch(br.ChainId))
 	}
 
 	poolUpdatesMerged := mergePoolUpdates(poolUpdates)
 	poolUpdateParametersMerged := mergePoolUpdateParameters(poolUpdateParameters)
 
 	block := &BlockInfo{
+		ChainId:              br.ChainId,
 		Height:               br.Height,
 		Timestamp:            br.Timestamp,
 		NativeTokenPrice:     br.NativeTokenPrice.String(),
 		Txs:                  txs,
 		NewTokens:            ormTokens,
 		NewPairs:             ormPairs,
+		NewPools:             ormPools,
+		Launches:             ormLaunches,
+		PairFeeUpdates:       mergePairFeeUpdates(pairFeeUpdates),
+		PoolFees:             poolFees,
 		PoolUpdates:          poolUpdatesMerged,
 		PoolUpdateParameters: poolUpdateParametersMerged,
+		Routes:               NewRoutes(txs),
 	}
 
 	return block
@@ -158,7 +238,7 @@ func (br *BlockResult) GetOldKafkaMessageAndNewTokensPairs() (*BlockInfoOld, []*
 		}
 
 		if event.CanGetTx() {
-			txs = append(txs, event.GetTx(br.NativeTokenPrice))
+			txs = append(txs, event.GetTx(br.BaseTokens, br.NativeTokenPrice))
 		}
 
 		if event.CanGetPoolUpdate() {
@@ -177,12 +257,12 @@ func (br *BlockResult) GetOldKafkaMessageAndNewTokensPairs() (*BlockInfoOld, []*
 
 	ormTokens := make([]*orm.Token, 0, len(br.NewTokens))
 	for _, token := range br.NewTokens {
-		ormTokens = append(ormTokens, token.GetOrmToken())
+		ormTokens = append(ormTokens, token.GetOrmToken(br.ChainId))
 	}
 
 	ormPairs := make([]*orm.Pair, 0, len(uniswapNewPairs))
 	for _, pair := range br.NewPairs {
-		ormPairs = append(ormPairs, pair.GetOrmPair())
+		ormPairs = append(ormPairs, pair.GetOrmPair(br.ChainId))
 	}
 
 	poolUpdatesV2Merged := mergePoolUpdates(poolUpdatesV2)
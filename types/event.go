@@ -15,10 +15,12 @@ type Event interface {
 	GetPairAddress() common.Address
 	SetPair(pair *Pair)
 	SetMaker(maker common.Address)
+	SetTxMeta(txMeta *TxMeta)
+	SetUserOperation(userOperation *UserOperation)
 	SetBlockTime(blockTime time.Time)
 
 	CanGetTx() bool
-	GetTx(bnbPrice decimal.Decimal) *orm.Tx
+	GetTx(baseTokens *BaseTokens, bnbPrice decimal.Decimal) *orm.Tx
 
 	CanGetPoolUpdate() bool
 	GetPoolUpdate() *PoolUpdate
@@ -26,6 +28,9 @@ type Event interface {
 	CanGetPoolUpdateParameter() bool
 	GetPoolUpdateParameter() *PoolUpdateParameter
 
+	CanGetPoolStateUpdate() bool
+	GetPoolStateUpdate() *PoolStateUpdate
+
 	LinkEvent(event Event)
 
 	IsCreatePair() bool
@@ -15,6 +15,8 @@ type EventCommon struct {
 	BlockTime           time.Time
 	TxHash              common.Hash
 	Maker               common.Address
+	TxMeta              *TxMeta
+	UserOperation       *UserOperation
 	TxIndex             uint
 	LogIndex            uint
 	PossibleProtocolIds []int
@@ -54,7 +56,7 @@ func (e *EventCommon) CanGetTx() bool {
 	return false
 }
 
-func (e *EventCommon) GetTx(bnbPrice decimal.Decimal) *orm.Tx {
+func (e *EventCommon) GetTx(baseTokens *BaseTokens, bnbPrice decimal.Decimal) *orm.Tx {
 	return nil
 }
 
@@ -74,6 +76,14 @@ func (e *EventCommon) GetPoolUpdateParameter() *PoolUpdateParameter {
 	return nil
 }
 
+func (e *EventCommon) CanGetPoolStateUpdate() bool {
+	return false
+}
+
+func (e *EventCommon) GetPoolStateUpdate() *PoolStateUpdate {
+	return nil
+}
+
 func (e *EventCommon) LinkEvent(event Event) {
 }
 
@@ -89,10 +99,26 @@ func (e *EventCommon) GetMintAmount() (decimal.Decimal, decimal.Decimal) {
 	return decimal.Zero, decimal.Zero
 }
 
+func (e *EventCommon) GetMaker() common.Address {
+	return e.Maker
+}
+
+func (e *EventCommon) GetTxHash() common.Hash {
+	return e.TxHash
+}
+
 func (e *EventCommon) SetMaker(maker common.Address) {
 	e.Maker = maker
 }
 
+func (e *EventCommon) SetTxMeta(txMeta *TxMeta) {
+	e.TxMeta = txMeta
+}
+
+func (e *EventCommon) SetUserOperation(userOperation *UserOperation) {
+	e.UserOperation = userOperation
+}
+
 func (e *EventCommon) SetBlockTime(blockTime time.Time) {
 	e.BlockTime = blockTime
 }
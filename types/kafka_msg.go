@@ -4,15 +4,46 @@ import (
 	"base_scan/repository/orm"
 )
 
+// BlockInfo is the message of the block topic, ChainId tells the chains of pipelines sharing a topic apart.
 type BlockInfo struct {
+	ChainId              uint64
 	Height               uint64
 	Timestamp            uint64
 	NativeTokenPrice     string
 	Txs                  []*orm.Tx
 	NewTokens            []*orm.Token
 	NewPairs             []*orm.Pair
+	NewPools             []*orm.Pool
+	Launches             []*orm.Launch
+	PairFeeUpdates       []*PairFeeUpdate
+	PoolFees             []*orm.PoolFee
 	PoolUpdates          []*PoolUpdate
 	PoolUpdateParameters []*PoolUpdateParameter
+	Routes               []*Route
+}
+
+// MevInfo is the message of the mev topic, one per block with mev.
+type MevInfo struct {
+	ChainId   uint64
+	Height    uint64
+	Timestamp uint64
+	Mevs      []*orm.Mev
+}
+
+// PositionInfo is the message of the position topic, one per block with position changes.
+type PositionInfo struct {
+	ChainId   uint64
+	Height    uint64
+	Timestamp uint64
+	Changes   []*PositionChange
+}
+
+// LaunchAlertInfo is the message of the launch topic, one per block with launches.
+type LaunchAlertInfo struct {
+	ChainId   uint64
+	Height    uint64
+	Timestamp uint64
+	Alerts    []*LaunchAlert
 }
 
 type BlockInfoOld struct {
@@ -0,0 +1,92 @@
+package types
+
+import (
+	"base_scan/repository/orm"
+	"github.com/ethereum/go-ethereum/common"
+	"time"
+)
+
+/*
+Launch is a token launched on a bonding curve launchpad, see IsLaunchpad.
+Curve is the address of the pair of the curve, the trades before the graduation are its txs,
+Pool the dex pool of the trades after, zero until known.
+A launch event carries either the launch or the graduation, Graduated tells which.
+*/
+type Launch struct {
+	Token          common.Address
+	ProtocolId     int
+	Curve          common.Address
+	Creator        common.Address
+	Pool           common.Address
+	Block          uint64
+	BlockAt        time.Time
+	Graduated      bool
+	GraduatedBlock uint64
+	GraduatedAt    time.Time
+}
+
+// LaunchEvent is implemented by the launch and graduation events of launchpads.
+type LaunchEvent interface {
+	GetLaunch() *Launch
+}
+
+// merge adds what l2 knows about the same token, e.g. the graduation of a launch in the same block.
+func (l *Launch) merge(l2 *Launch) {
+	if l2.Graduated {
+		l.Graduated = true
+		l.GraduatedBlock = l2.GraduatedBlock
+		l.GraduatedAt = l2.GraduatedAt
+	} else {
+		l.Curve = l2.Curve
+		l.Creator = l2.Creator
+		l.Block = l2.Block
+		l.BlockAt = l2.BlockAt
+	}
+	if l2.Pool != ZeroAddress {
+		l.Pool = l2.Pool
+	}
+}
+
+// MergeLaunches merges the launch events of one block into one launch by token.
+func MergeLaunches(launches []*Launch) []*Launch {
+	merged := make([]*Launch, 0, len(launches))
+	byToken := make(map[common.Address]*Launch, len(launches))
+	for _, launch := range launches {
+		if m, ok := byToken[launch.Token]; ok {
+			m.merge(launch)
+			continue
+		}
+
+		m := *launch
+		byToken[launch.Token] = &m
+		merged = append(merged, &m)
+	}
+	return merged
+}
+
+/*
+GetOrmLaunch
+a graduation has no launch fields, the launch is stored at its launch block and only updated by the graduation,
+see repository.LaunchRepository
+*/
+func (l *Launch) GetOrmLaunch(chainId uint64) *orm.Launch {
+	ormLaunch := &orm.Launch{
+		Token:          l.Token.String(),
+		ChainId:        int(chainId),
+		Program:        GetProtocolName(l.ProtocolId),
+		Block:          l.Block,
+		BlockAt:        l.BlockAt,
+		GraduatedBlock: l.GraduatedBlock,
+		GraduatedAt:    l.GraduatedAt,
+	}
+	if l.Curve != ZeroAddress {
+		ormLaunch.Curve = l.Curve.String()
+	}
+	if l.Creator != ZeroAddress {
+		ormLaunch.Creator = l.Creator.String()
+	}
+	if l.Pool != ZeroAddress {
+		ormLaunch.Pool = l.Pool.String()
+	}
+	return ormLaunch
+}
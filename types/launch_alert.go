@@ -0,0 +1,121 @@
+package types
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/shopspring/decimal"
+	"math/big"
+	"time"
+)
+
+// risk signals of a launch alert
+const (
+	// the deployment of the token was not seen in the window, it was deployed before it or before a restart
+	LaunchRiskUnknownDeployer = "unknown_deployer"
+	// the pool holds less of the supply than config.LaunchAlertConf MinSupplyInPoolPercent
+	LaunchRiskLowSupplyInPool = "low_supply_in_pool"
+	// other wallets bought in the block of the launch
+	LaunchRiskSniped = "sniped"
+	// wallets funded by the creator or the deployer bought in the block of the launch
+	LaunchRiskFundedWalletsBought = "funded_wallets_bought"
+	// the creator or the deployer bought in the block of the launch
+	LaunchRiskCreatorBought = "creator_bought"
+)
+
+/*
+LaunchAlert is the first liquidity of a new pair of a token against a base token, see package alert.
+Creator added the liquidity, Deployer deployed the token when its deployment was seen, FundedWallets received native
+tokens from either of them. Snipers are the other wallets that bought in the block of the launch.
+Amounts are in tokens, LiquidityUSD counts both sides at the value of the base token side.
+*/
+type LaunchAlert struct {
+	TokenAddress        string
+	TokenName           string
+	TokenSymbol         string
+	TokenDecimals       int8
+	TokenTotalSupply    decimal.Decimal
+	PairAddress         string
+	Program             string
+	QuoteTokenAddress   string
+	QuoteTokenSymbol    string
+	TokenAmount         decimal.Decimal
+	QuoteAmount         decimal.Decimal
+	LiquidityUSD        decimal.Decimal
+	SupplyInPoolPercent decimal.Decimal
+	Creator             string
+	Deployer            string
+	FundedWallets       []string
+	Snipers             []string
+	Risks               []string
+	TxHash              string
+	Block               uint64
+	BlockAt             time.Time
+}
+
+/*
+FirstLiquidity is the mint adding the first liquidity of a new pair, in the tx Creator sent,
+see LinkPairCreatedEventAndMintEvent.
+*/
+type FirstLiquidity struct {
+	Pair    *Pair
+	Creator common.Address
+	TxHash  common.Hash
+}
+
+// FirstLiquidityEvent is implemented by the pair created events, nil when no mint is linked.
+type FirstLiquidityEvent interface {
+	GetFirstLiquidity() *FirstLiquidity
+}
+
+// TxMakerGetter is an event knowing its tx and maker, see EventCommon.
+type TxMakerGetter interface {
+	GetMaker() common.Address
+	GetTxHash() common.Hash
+}
+
+// NativeTransfer is a tx only transferring native tokens, the launch alerts look for the wallets a deployer funded.
+type NativeTransfer struct {
+	From  common.Address
+	To    common.Address
+	Value *big.Int
+}
+
+// erc20TransferTopic is the topic of the erc20 Transfer, the erc721 one has the token id as a fourth topic.
+var erc20TransferTopic = common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+
+// Deployment is a contract deployed by a tx, the launch alerts look for the deployer of a token.
+type Deployment struct {
+	Contract common.Address
+	Deployer common.Address
+}
+
+/*
+AddDeploymentOrTransfer records the contracts deployed by a tx or the native tokens it transferred, if any.
+Contracts created by a contract have no receipt of their own, a token minting from the zero address in a tx calling
+another contract is taken as deployed by the sender of the tx through a factory. Later mints, of a liquidity pool
+e.g., are recorded too, the launch alerts keep the first deployment of a contract.
+*/
+func (br *BlockResult) AddDeploymentOrTransfer(sender common.Address, tx *ethtypes.Transaction, receipt *ethtypes.Receipt) {
+	switch {
+	case tx.To() == nil && receipt.ContractAddress != ZeroAddress:
+		br.Deployments = append(br.Deployments, &Deployment{Contract: receipt.ContractAddress, Deployer: sender})
+	case tx.To() != nil && len(tx.Data()) == 0 && tx.Value().Sign() > 0:
+		br.NativeTransfers = append(br.NativeTransfers, &NativeTransfer{From: sender, To: *tx.To(), Value: tx.Value()})
+	case tx.To() != nil:
+		minted := make(map[common.Address]struct{})
+		for _, ethLog := range receipt.Logs {
+			if !isMintFromZero(ethLog) || ethLog.Address == *tx.To() {
+				continue
+			}
+			if _, ok := minted[ethLog.Address]; ok {
+				continue
+			}
+			minted[ethLog.Address] = struct{}{}
+			br.Deployments = append(br.Deployments, &Deployment{Contract: ethLog.Address, Deployer: sender})
+		}
+	}
+}
+
+func isMintFromZero(ethLog *ethtypes.Log) bool {
+	return len(ethLog.Topics) == 3 && ethLog.Topics[0] == erc20TransferTopic && ethLog.Topics[1] == (common.Hash{})
+}
@@ -0,0 +1,43 @@
+package types
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+)
+
+func TestAddDeploymentOrTransfer(t *testing.T) {
+	sender := common.HexToAddress("0x00000000000000000000000000000000000000c1")
+	factory := common.HexToAddress("0x00000000000000000000000000000000000000f1")
+	token := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	nft := common.HexToAddress("0x00000000000000000000000000000000000000a2")
+	mint := func(address common.Address, topics ...common.Hash) *ethtypes.Log {
+		return &ethtypes.Log{Address: address, Topics: append([]common.Hash{erc20TransferTopic, {}}, topics...)}
+	}
+
+	br := NewBlockResult(8453, DefaultBaseTokens, 100, 1700000000, decimal.NewFromInt(2000))
+	deploy := ethtypes.NewTx(&ethtypes.LegacyTx{Data: []byte{0x60}})
+	br.AddDeploymentOrTransfer(sender, deploy, &ethtypes.Receipt{ContractAddress: token})
+	require.Equal(t, []*Deployment{{Contract: token, Deployer: sender}}, br.Deployments)
+
+	// a token minting in a factory call, once per tx, the erc721 mints and the mints of the called contract are not
+	br = NewBlockResult(8453, DefaultBaseTokens, 100, 1700000000, decimal.NewFromInt(2000))
+	call := ethtypes.NewTx(&ethtypes.LegacyTx{To: &factory, Data: []byte{0x01}})
+	br.AddDeploymentOrTransfer(sender, call, &ethtypes.Receipt{Logs: []*ethtypes.Log{
+		mint(token, common.BytesToHash(sender.Bytes())),
+		mint(token, common.BytesToHash(factory.Bytes())),
+		mint(nft, common.BytesToHash(sender.Bytes()), common.BigToHash(big.NewInt(1))),
+		mint(factory, common.BytesToHash(sender.Bytes())),
+	}})
+	require.Equal(t, []*Deployment{{Contract: token, Deployer: sender}}, br.Deployments)
+	require.Empty(t, br.NativeTransfers)
+
+	br = NewBlockResult(8453, DefaultBaseTokens, 100, 1700000000, decimal.NewFromInt(2000))
+	transfer := ethtypes.NewTx(&ethtypes.LegacyTx{To: &factory, Value: big.NewInt(1)})
+	br.AddDeploymentOrTransfer(sender, transfer, &ethtypes.Receipt{})
+	require.Empty(t, br.Deployments)
+	require.Equal(t, []*NativeTransfer{{From: sender, To: factory, Value: big.NewInt(1)}}, br.NativeTransfers)
+}
@@ -0,0 +1,40 @@
+package types
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestMergeLaunches(t *testing.T) {
+	token := common.HexToAddress("0x00000000000000000000000000000000000000a0")
+	other := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	pool := common.HexToAddress("0x00000000000000000000000000000000000000f2")
+	launchedAt := time.Unix(1700000000, 0)
+
+	launches := MergeLaunches([]*Launch{
+		{Token: token, ProtocolId: ProtocolIdWow, Curve: token, Block: 10, BlockAt: launchedAt},
+		{Token: other, ProtocolId: ProtocolIdWow, Graduated: true, GraduatedBlock: 10, GraduatedAt: launchedAt, Pool: pool},
+		{Token: token, ProtocolId: ProtocolIdWow, Graduated: true, GraduatedBlock: 10, GraduatedAt: launchedAt, Pool: pool},
+	})
+	require.Len(t, launches, 2)
+	require.Equal(t, &Launch{
+		Token:          token,
+		ProtocolId:     ProtocolIdWow,
+		Curve:          token,
+		Pool:           pool,
+		Block:          10,
+		BlockAt:        launchedAt,
+		Graduated:      true,
+		GraduatedBlock: 10,
+		GraduatedAt:    launchedAt,
+	}, launches[0])
+
+	ormLaunch := launches[1].GetOrmLaunch(8453)
+	require.Equal(t, other.String(), ormLaunch.Token)
+	require.Equal(t, ProtocolNameWow, ormLaunch.Program)
+	require.Equal(t, "", ormLaunch.Curve)
+	require.Equal(t, pool.String(), ormLaunch.Pool)
+	require.Equal(t, uint64(10), ormLaunch.GraduatedBlock)
+}
@@ -0,0 +1,136 @@
+package types
+
+import (
+	"base_scan/repository/orm"
+	"encoding/json"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+// what the code at a maker address is
+const (
+	MakerKindEOA         = "eoa"
+	MakerKindDelegated   = "delegated"
+	MakerKindSmartWallet = "smart_wallet"
+	MakerKindContract    = "contract"
+)
+
+// classes written to orm.Tx.MakerClass
+const (
+	MakerClassBot         = "bot"
+	MakerClassSmartWallet = "smart_wallet"
+	MakerClassDelegated   = "delegated"
+	MakerClassContract    = "contract"
+	MakerClassTrader      = "trader"
+)
+
+// MakerActivity counts the trades of a maker over some blocks.
+type MakerActivity struct {
+	Trades       uint64
+	ActiveBlocks uint64
+	BusyBlocks   uint64
+	RoundTrips   uint64
+}
+
+func (a *MakerActivity) Add(activity *MakerActivity) {
+	a.Trades += activity.Trades
+	a.ActiveBlocks += activity.ActiveBlocks
+	a.BusyBlocks += activity.BusyBlocks
+	a.RoundTrips += activity.RoundTrips
+}
+
+/*
+Maker is the profile of a trading address, cached by address and mirrored to the maker table.
+Total counts every block the maker traded in, Window and PrevWindow only the current and the previous window.
+*/
+type Maker struct {
+	Address          common.Address `json:"-"`
+	Kind             string
+	DelegateAddress  string
+	KindCheckedBlock uint64
+	Class            string
+	FirstBlock       uint64
+	LastBlock        uint64
+	Buys             uint64
+	Sells            uint64
+	VolumeUsd        decimal.Decimal
+	Total            MakerActivity
+	WindowStart      uint64
+	Window           MakerActivity
+	PrevWindow       MakerActivity
+	Timestamp        time.Time
+}
+
+func (m *Maker) MarshalBinary() ([]byte, error) {
+	type Alias Maker
+	return json.Marshal(&struct {
+		AddressString string `json:"Address"`
+		*Alias
+	}{
+		AddressString: m.Address.String(),
+		Alias:         (*Alias)(m),
+	})
+}
+
+func (m *Maker) UnmarshalBinary(data []byte) error {
+	type Alias Maker
+	aux := &struct {
+		AddressString string `json:"Address"`
+		*Alias
+	}{
+		Alias: (*Alias)(m),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	m.Address = common.HexToAddress(aux.AddressString)
+	return nil
+}
+
+func (m *Maker) Equal(maker *Maker) bool {
+	return IsSameAddress(m.Address, maker.Address) &&
+		m.Kind == maker.Kind &&
+		m.DelegateAddress == maker.DelegateAddress &&
+		m.KindCheckedBlock == maker.KindCheckedBlock &&
+		m.Class == maker.Class &&
+		m.FirstBlock == maker.FirstBlock &&
+		m.LastBlock == maker.LastBlock &&
+		m.Buys == maker.Buys &&
+		m.Sells == maker.Sells &&
+		m.VolumeUsd.Equal(maker.VolumeUsd) &&
+		m.Total == maker.Total &&
+		m.WindowStart == maker.WindowStart &&
+		m.Window == maker.Window &&
+		m.PrevWindow == maker.PrevWindow
+}
+
+// Recent is the activity of the current and the previous window.
+func (m *Maker) Recent() MakerActivity {
+	recent := m.Window
+	recent.Add(&m.PrevWindow)
+	return recent
+}
+
+func (m *Maker) GetOrmMaker(chainId uint64) *orm.Maker {
+	recent := m.Recent()
+	return &orm.Maker{
+		ChainId:          int(chainId),
+		Address:          m.Address.String(),
+		Kind:             m.Kind,
+		DelegateAddress:  m.DelegateAddress,
+		Class:            m.Class,
+		FirstBlock:       m.FirstBlock,
+		LastBlock:        m.LastBlock,
+		Trades:           m.Total.Trades,
+		Buys:             m.Buys,
+		Sells:            m.Sells,
+		VolumeUsd:        m.VolumeUsd,
+		ActiveBlocks:     m.Total.ActiveBlocks,
+		BusyBlocks:       m.Total.BusyBlocks,
+		RoundTrips:       m.Total.RoundTrips,
+		RecentTrades:     recent.Trades,
+		RecentBusyBlocks: recent.BusyBlocks,
+		RecentRoundTrips: recent.RoundTrips,
+	}
+}
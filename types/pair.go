@@ -1,7 +1,6 @@
 package types
 
 import (
-	"base_scan/chain"
 	"base_scan/repository/orm"
 	"base_scan/util"
 	"encoding/json"
@@ -17,6 +16,7 @@ const (
 	FilterCodeNoBaseToken
 	FilterCodeWrongFactory
 	FilterCodeUnpackDataErr
+	FilterCodeGetPoolTokens
 )
 
 type TokenCore struct {
@@ -67,6 +67,18 @@ func (t *TokenCore) Equal(token *TokenCore) bool {
 	return true
 }
 
+/*
+PairSchemaVersion is the version of the cached pair value written by MarshalBinary.
+Bump it together with a migration in cache/migration whenever the cached fields change.
+*/
+const PairSchemaVersion = 4
+
+/*
+Pair
+Fee is the current swap fee in hundredths of a bip, 0 when unknown, it changes for aerodrome pools, see PairFeeUpdate.
+TickSpacing is set for the concentrated liquidity pools, Stable tells the stable pools of aerodrome from the volatile
+ones, Version is the pool design of the protocol, see ProtocolVersion.
+*/
 type Pair struct {
 	Address          common.Address `json:"-"`
 	TokensReversed   bool
@@ -79,8 +91,14 @@ type Pair struct {
 	Block            uint64
 	BlockAt          time.Time
 	ProtocolId       int
+	Stable           bool
+	Fee              uint32
+	TickSpacing      int32
+	Version          int
 	Filtered         bool
 	FilterCode       int
+	FilteredAt       time.Time
+	FilterReason     string
 	Timestamp        time.Time
 }
 
@@ -93,9 +111,11 @@ func (p *Pair) MarshalBinary() ([]byte, error) {
 	type Alias Pair
 	return json.Marshal(&struct {
 		AddressString string `json:"Address"`
+		SchemaVersion int
 		*Alias
 	}{
 		AddressString: p.Address.String(),
+		SchemaVersion: PairSchemaVersion,
 		Alias:         (*Alias)(p),
 	})
 }
@@ -121,16 +141,16 @@ func (p *Pair) swapToken0Token1() {
 	p.TokensReversed = true
 }
 
-func (p *Pair) OrderToken0Token1() bool {
+func (p *Pair) OrderToken0Token1(baseTokens *BaseTokens) bool {
 	if p.Token0Core == nil || p.Token1Core == nil {
 		return false
 	}
 
-	token0IsBaseToken := IsBaseToken(p.Token0Core.Address)
-	token1IsBaseToken := IsBaseToken(p.Token1Core.Address)
+	token0IsBaseToken := baseTokens.IsBase(p.Token0Core.Address)
+	token1IsBaseToken := baseTokens.IsBase(p.Token1Core.Address)
 
 	if token0IsBaseToken && token1IsBaseToken {
-		if IsUSDC(p.Token0Core.Address) {
+		if baseTokens.IsStable(p.Token0Core.Address) {
 			p.swapToken0Token1()
 		}
 	} else if !token0IsBaseToken && !token1IsBaseToken {
@@ -171,6 +191,18 @@ func (p *Pair) Equal(pair *Pair) bool {
 	if p.ProtocolId != pair.ProtocolId {
 		return false
 	}
+	if p.Stable != pair.Stable {
+		return false
+	}
+	if p.Fee != pair.Fee {
+		return false
+	}
+	if p.TickSpacing != pair.TickSpacing {
+		return false
+	}
+	if p.Version != pair.Version {
+		return false
+	}
 	if p.Filtered != pair.Filtered {
 		return false
 	}
@@ -184,10 +216,24 @@ func (p *Pair) IsFiltered() bool {
 	return p.Filtered
 }
 
-func (p *Pair) FilterByToken0AndToken1() bool {
-	if !IsBaseToken(p.Token0Core.Address) && !IsBaseToken(p.Token1Core.Address) {
-		p.Filtered = true
-		p.FilterCode = FilterCodeNoBaseToken
+func (p *Pair) Filter(filterCode int, filterReason string) {
+	p.Filtered = true
+	p.FilterCode = filterCode
+	p.FilteredAt = time.Now()
+	p.FilterReason = filterReason
+}
+
+/*
+FilterExpired reports whether a filtered pair should be checked again.
+ttl 0 means the filter never expires.
+*/
+func (p *Pair) FilterExpired(ttl time.Duration) bool {
+	return p.Filtered && ttl > 0 && time.Since(p.FilteredAt) > ttl
+}
+
+func (p *Pair) FilterByToken0AndToken1(baseTokens *BaseTokens) bool {
+	if !baseTokens.IsBase(p.Token0Core.Address) && !baseTokens.IsBase(p.Token1Core.Address) {
+		p.Filter(FilterCodeNoBaseToken, "no base token")
 	}
 
 	return p.Filtered
@@ -207,24 +253,70 @@ func getPairName(token0Symbol, token1Symbol string) string {
 	return token0Symbol + "/" + token1Symbol
 }
 
-func (p *Pair) GetOrmPair() *orm.Pair {
+func (p *Pair) GetOrmPair(chainId uint64) *orm.Pair {
 	return &orm.Pair{
-		Name:     getPairName(p.Token0Core.Symbol, p.Token1Core.Symbol),
-		Address:  p.Address.String(),
-		Token0:   p.Token0Core.Address.String(),
-		Token1:   p.Token1Core.Address.String(),
-		Reserve0: p.Token0InitAmount.Mul(decimal.New(1, int32(p.Token0Core.Decimals))), // for db type is numeric(78)
-		Reserve1: p.Token1InitAmount.Mul(decimal.New(1, int32(p.Token1Core.Decimals))), // for db type is numeric(78)
-		ChainId:  chain.Id,
-		Block:    p.Block,
-		BlockAt:  p.BlockAt,
-		Program:  GetProtocolName(p.ProtocolId),
+		Name:        getPairName(p.Token0Core.Symbol, p.Token1Core.Symbol),
+		Address:     p.Address.String(),
+		Token0:      p.Token0Core.Address.String(),
+		Token1:      p.Token1Core.Address.String(),
+		Reserve0:    p.Token0InitAmount.Mul(decimal.New(1, int32(p.Token0Core.Decimals))), // for db type is numeric(78)
+		Reserve1:    p.Token1InitAmount.Mul(decimal.New(1, int32(p.Token1Core.Decimals))), // for db type is numeric(78)
+		ChainId:     int(chainId),
+		Block:       p.Block,
+		BlockAt:     p.BlockAt,
+		Program:     GetProtocolName(p.ProtocolId),
+		Stable:      p.Stable,
+		Fee:         p.Fee,
+		TickSpacing: p.TickSpacing,
+		Version:     p.Version,
+	}
+}
+
+/*
+NewPairFromOrm rebuilds a pair from its db record.
+token0 and token1 must match ormPair.Token0 and ormPair.Token1, which are stored already ordered.
+All supported factories sort the tokens by address, so the pair was reversed if the stored token0 is the greater one.
+*/
+func NewPairFromOrm(ormPair *orm.Pair, token0, token1 *Token) *Pair {
+	pair := &Pair{
+		Address: common.HexToAddress(ormPair.Address),
+		Token0Core: &TokenCore{
+			Address:  token0.Address,
+			Symbol:   token0.Symbol,
+			Decimals: token0.Decimals,
+		},
+		Token1Core: &TokenCore{
+			Address:  token1.Address,
+			Symbol:   token1.Symbol,
+			Decimals: token1.Decimals,
+		},
+		Token0:      token0,
+		Token1:      token1,
+		Block:       ormPair.Block,
+		BlockAt:     ormPair.BlockAt,
+		ProtocolId:  GetProtocolId(ormPair.Program),
+		Stable:      ormPair.Stable,
+		Fee:         ormPair.Fee,
+		TickSpacing: ormPair.TickSpacing,
+		Version:     ormPair.Version,
 	}
+
+	pair.TokensReversed = token0.Address.Cmp(token1.Address) > 0
+	pair.Token0InitAmount = ormPair.Reserve0.Div(decimal.New(1, int32(token0.Decimals)))
+	pair.Token1InitAmount = ormPair.Reserve1.Div(decimal.New(1, int32(token1.Decimals)))
+
+	return pair
 }
 
+/*
+PairWrap is the pair of an event and what is new about it.
+Pool is the pool of the pair for the swaps of pools, see PoolSwapEvent.
+*/
 type PairWrap struct {
 	Pair      *Pair
 	NewPair   bool
 	NewToken0 bool
 	NewToken1 bool
+	Pool      *Pool
+	NewPool   bool
 }
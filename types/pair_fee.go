@@ -0,0 +1,52 @@
+package types
+
+import (
+	"base_scan/repository/orm"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	PoolFeeAccrue = "accrue"
+	PoolFeeClaim  = "claim"
+)
+
+/*
+PairFeeUpdate is a change of the fee of a pair, Fee is in hundredths of a bip.
+Default is set when the pair went back to the default fee of its factory, which the event doesn't tell,
+the pair service fills Fee then, see service.PairService UpdatePairFee.
+*/
+type PairFeeUpdate struct {
+	PairAddress common.Address
+	Fee         uint32
+	Default     bool
+	Block       uint64
+	LogIndex    uint
+}
+
+// PairFeeEvent is implemented by the events changing the fee of their pair.
+type PairFeeEvent interface {
+	GetPairFeeUpdate() *PairFeeUpdate
+}
+
+// PoolFeeEvent is implemented by the fee accrual and fee claim events of pools.
+type PoolFeeEvent interface {
+	GetPoolFee(chainId uint64) *orm.PoolFee
+}
+
+// mergePairFeeUpdates keeps the last update of every pair.
+func mergePairFeeUpdates(updates []*PairFeeUpdate) []*PairFeeUpdate {
+	merged := make([]*PairFeeUpdate, 0, len(updates))
+	indexByPair := make(map[common.Address]int, len(updates))
+	for _, update := range updates {
+		i, ok := indexByPair[update.PairAddress]
+		if !ok {
+			indexByPair[update.PairAddress] = len(merged)
+			merged = append(merged, update)
+			continue
+		}
+		if update.LogIndex > merged[i].LogIndex {
+			merged[i] = update
+		}
+	}
+	return merged
+}
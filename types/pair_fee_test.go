@@ -0,0 +1,22 @@
+package types
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestMergePairFeeUpdates(t *testing.T) {
+	pair0 := common.HexToAddress("0x01")
+	pair1 := common.HexToAddress("0x02")
+	updates := []*PairFeeUpdate{
+		{PairAddress: pair0, Fee: 100, LogIndex: 1},
+		{PairAddress: pair1, Fee: 3000, LogIndex: 2},
+		{PairAddress: pair0, Default: true, Fee: 500, LogIndex: 3},
+	}
+
+	require.Equal(t, []*PairFeeUpdate{
+		{PairAddress: pair0, Default: true, Fee: 500, LogIndex: 3},
+		{PairAddress: pair1, Fee: 3000, LogIndex: 2},
+	}, mergePairFeeUpdates(updates))
+}
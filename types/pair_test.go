@@ -16,12 +16,27 @@ func TestPair_IsFiltered(t *testing.T) {
 		Token1Core: &TokenCore{Address: common.HexToAddress("0xfe0A4739139D5b64b9fA86DA767B464086A9d5B2")},
 	}
 
-	filtered := pair.FilterByToken0AndToken1()
+	filtered := pair.FilterByToken0AndToken1(DefaultBaseTokens)
 	assert.Equal(t, filtered, true)
 	assert.Equal(t, pair.Filtered, true)
 	assert.Equal(t, pair.FilterCode, FilterCodeNoBaseToken)
 }
 
+func TestPair_FilterExpired(t *testing.T) {
+	pair := &Pair{}
+	require.False(t, pair.FilterExpired(time.Hour))
+
+	pair.Filter(FilterCodeGetToken0, "execution reverted")
+	require.True(t, pair.Filtered)
+	require.Equal(t, "execution reverted", pair.FilterReason)
+	require.False(t, pair.FilterExpired(time.Hour))
+	require.False(t, pair.FilterExpired(0))
+
+	pair.FilteredAt = time.Now().Add(-2 * time.Hour)
+	require.True(t, pair.FilterExpired(time.Hour))
+	require.False(t, pair.FilterExpired(0))
+}
+
 func TestPair_OrderTokens(t *testing.T) {
 	TokenWETH := &TokenCore{
 		Address: WETHAddress,
@@ -91,7 +106,7 @@ func TestPair_OrderTokens(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			test.pair.OrderToken0Token1()
+			test.pair.OrderToken0Token1(DefaultBaseTokens)
 			assert.Equal(t, test.pair.Token0Core.Address, test.expected.Token0Core.Address)
 			assert.Equal(t, test.pair.Token1Core.Address, test.expected.Token1Core.Address)
 			assert.Equal(t, test.pair.TokensReversed, test.TokensReversed)
@@ -143,3 +158,61 @@ func TestPair_MarshalBinary(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, pair.Equal(pair2))
 }
+
+func TestNewPairFromOrm(t *testing.T) {
+	tokenWETH := &Token{
+		Address:  WETHAddress,
+		Symbol:   "WETH",
+		Decimals: 18,
+	}
+
+	tests := []struct {
+		name  string
+		token *Token
+	}{
+		{
+			name: "token0 is not base token",
+			token: &Token{
+				Address:  common.HexToAddress("0x1234567890123456789012345678901234567890"),
+				Symbol:   "LOW",
+				Decimals: 9,
+			},
+		},
+		{
+			name: "token0 is base token",
+			token: &Token{
+				Address:  common.HexToAddress("0xf234567890123456789012345678901234567890"),
+				Symbol:   "HIGH",
+				Decimals: 9,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// factories sort tokens by address
+			token0, token1 := tt.token, tokenWETH
+			if token0.Address.Cmp(token1.Address) > 0 {
+				token0, token1 = token1, token0
+			}
+
+			pair := &Pair{
+				Address:          common.HexToAddress("0xF6C8490Df6a5bFCc07484DC87254B4139C9CCCd3"),
+				Token0Core:       &TokenCore{Address: token0.Address, Symbol: token0.Symbol, Decimals: token0.Decimals},
+				Token1Core:       &TokenCore{Address: token1.Address, Symbol: token1.Symbol, Decimals: token1.Decimals},
+				Token0InitAmount: decimal.NewFromFloat(1.5),
+				Token1InitAmount: decimal.NewFromFloat(0.25),
+				Block:            100,
+				BlockAt:          time.Unix(1000, 0),
+				ProtocolId:       ProtocolIdUniswapV3,
+				Fee:              500,
+				TickSpacing:      10,
+				Version:          ProtocolVersionV3,
+			}
+			pair.OrderToken0Token1(DefaultBaseTokens)
+
+			pairFromOrm := NewPairFromOrm(pair.GetOrmPair(8453), tt.token, tokenWETH)
+			require.True(t, pair.Equal(pairFromOrm), "expect: %v, actual: %v", pair, pairFromOrm)
+		})
+	}
+}
@@ -1,11 +1,11 @@
 package types
 
 import (
-	chainparams "base_scan/chain"
 	"base_scan/log"
 	"errors"
 	"github.com/ethereum/go-ethereum/common"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
 	"math/big"
@@ -34,6 +34,7 @@ func GetBlockHeightTime(header *ethtypes.Header) *BlockHeightTime {
 
 type ParseBlockContext struct {
 	// input
+	ChainConfig      *params.ChainConfig
 	Block            *ethtypes.Block
 	BlockReceipts    []*ethtypes.Receipt
 	HeightTime       *BlockHeightTime
@@ -63,7 +64,12 @@ func (c *ParseBlockContext) GetTxSender(txIndex uint) (common.Address, error) {
 		return ZeroAddress, errx
 	}
 
-	signer := ethtypes.MakeSigner(chainparams.ChainConfig, c.HeightTime.HeightBigInt, c.HeightTime.Timestamp)
+	// deposit txs are not signed, the london signer takes their sender from the tx itself,
+	// chains without op forks in ChainConfig still have to decode them
+	signer := ethtypes.MakeSigner(c.ChainConfig, c.HeightTime.HeightBigInt, c.HeightTime.Timestamp)
+	if transactions[txIndex].IsDepositTx() {
+		signer = ethtypes.NewLondonSigner(c.ChainConfig.ChainID)
+	}
 	sender, err := ethtypes.Sender(signer, transactions[txIndex])
 	if err != nil {
 		return ZeroAddress, err
@@ -72,3 +78,8 @@ func (c *ParseBlockContext) GetTxSender(txIndex uint) (common.Address, error) {
 	c.TxIndex2TxSender[txIndex] = sender
 	return sender, nil
 }
+
+// GetTxMeta must be called after GetTxSender succeeded for the receipt, which checks the tx index.
+func (c *ParseBlockContext) GetTxMeta(receipt *ethtypes.Receipt) *TxMeta {
+	return NewTxMeta(c.ChainConfig.ChainID.Uint64(), c.Block.Transactions()[receipt.TransactionIndex], receipt, c.Block.BaseFee())
+}
@@ -0,0 +1,177 @@
+package types
+
+import (
+	"base_scan/repository/orm"
+	"encoding/json"
+	"github.com/ethereum/go-ethereum/common"
+	"strings"
+	"time"
+)
+
+/*
+PoolSchemaVersion is the version of the cached pool value written by MarshalBinary.
+Bump it together with a migration in cache/migration whenever the cached fields change.
+*/
+const PoolSchemaVersion = 1
+
+/*
+Pool is a curve or balancer pool of any number of tokens, Tokens are in pool order,
+so the index of a token is its index in the curve events.
+PoolId is the id of a balancer pool in the vault, zero for curve.
+The swaps of a pool are trades of the pair of the two tokens traded, see PairOf.
+*/
+type Pool struct {
+	Address      common.Address `json:"-"`
+	PoolId       common.Hash
+	ProtocolId   int
+	Tokens       []common.Address
+	Block        uint64
+	BlockAt      time.Time
+	Filtered     bool
+	FilterCode   int
+	FilteredAt   time.Time
+	FilterReason string
+	Timestamp    time.Time
+}
+
+/*
+PoolSwapEvent is implemented by the swaps of pools, their pair is resolved from the pool instead of the pair contract.
+GetPool returns the pool the event belongs to, without its tokens.
+ResolveTokens finds the tokens sold and bought in the pool and keeps them, false when the pool has no such tokens.
+*/
+type PoolSwapEvent interface {
+	GetPool() *Pool
+	ResolveTokens(pool *Pool) (tokenIn, tokenOut common.Address, ok bool)
+}
+
+func (p *Pool) MarshalBinary() ([]byte, error) {
+	type Alias Pool
+	return json.Marshal(&struct {
+		AddressString string `json:"Address"`
+		SchemaVersion int
+		*Alias
+	}{
+		AddressString: p.Address.String(),
+		SchemaVersion: PoolSchemaVersion,
+		Alias:         (*Alias)(p),
+	})
+}
+
+func (p *Pool) UnmarshalBinary(data []byte) error {
+	type Alias Pool
+	aux := &struct {
+		AddressString string `json:"Address"`
+		*Alias
+	}{
+		Alias: (*Alias)(p),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	p.Address = common.HexToAddress(aux.AddressString)
+	return nil
+}
+
+func (p *Pool) Equal(pool *Pool) bool {
+	if !IsSameAddress(p.Address, pool.Address) || p.PoolId != pool.PoolId || p.ProtocolId != pool.ProtocolId {
+		return false
+	}
+	if len(p.Tokens) != len(pool.Tokens) {
+		return false
+	}
+	for i, token := range p.Tokens {
+		if !IsSameAddress(token, pool.Tokens[i]) {
+			return false
+		}
+	}
+	return p.Block == pool.Block && p.Filtered == pool.Filtered && p.FilterCode == pool.FilterCode
+}
+
+func (p *Pool) Filter(filterCode int, filterReason string) {
+	p.Filtered = true
+	p.FilterCode = filterCode
+	p.FilteredAt = time.Now()
+	p.FilterReason = filterReason
+}
+
+/*
+FilterExpired reports whether a filtered pool should be checked again.
+ttl 0 means the filter never expires.
+*/
+func (p *Pool) FilterExpired(ttl time.Duration) bool {
+	return p.Filtered && ttl > 0 && time.Since(p.FilteredAt) > ttl
+}
+
+// TokenAt returns the token of a curve coin index.
+func (p *Pool) TokenAt(index int) (common.Address, bool) {
+	if index < 0 || index >= len(p.Tokens) {
+		return ZeroAddress, false
+	}
+	return p.Tokens[index], true
+}
+
+func (p *Pool) HasToken(token common.Address) bool {
+	for _, t := range p.Tokens {
+		if t == token {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+PairOf returns the pair of two tokens of the pool, with the address of the pool and the tokens sorted by address
+like the pairs of the factories, tokens and ordering are left to the pair service.
+*/
+func (p *Pool) PairOf(tokenA, tokenB common.Address) *Pair {
+	if tokenA.Cmp(tokenB) > 0 {
+		tokenA, tokenB = tokenB, tokenA
+	}
+	return &Pair{
+		Address:    p.Address,
+		Token0Core: &TokenCore{Address: tokenA},
+		Token1Core: &TokenCore{Address: tokenB},
+		Block:      p.Block,
+		BlockAt:    p.BlockAt,
+		ProtocolId: p.ProtocolId,
+	}
+}
+
+func (p *Pool) GetOrmPool(chainId uint64) *orm.Pool {
+	tokens := make([]string, 0, len(p.Tokens))
+	for _, token := range p.Tokens {
+		tokens = append(tokens, token.String())
+	}
+
+	ormPool := &orm.Pool{
+		Address: p.Address.String(),
+		Tokens:  strings.Join(tokens, ","),
+		ChainId: int(chainId),
+		Block:   p.Block,
+		BlockAt: p.BlockAt,
+		Program: GetProtocolName(p.ProtocolId),
+	}
+	if p.PoolId != (common.Hash{}) {
+		ormPool.PoolId = p.PoolId.String()
+	}
+	return ormPool
+}
+
+// NewPoolFromOrm rebuilds a pool from its db record.
+func NewPoolFromOrm(ormPool *orm.Pool) *Pool {
+	pool := &Pool{
+		Address:    common.HexToAddress(ormPool.Address),
+		ProtocolId: GetProtocolId(ormPool.Program),
+		Block:      ormPool.Block,
+		BlockAt:    ormPool.BlockAt,
+	}
+	if ormPool.PoolId != "" {
+		pool.PoolId = common.HexToHash(ormPool.PoolId)
+	}
+	for _, token := range strings.Split(ormPool.Tokens, ",") {
+		if token != "" {
+			pool.Tokens = append(pool.Tokens, common.HexToAddress(token))
+		}
+	}
+	return pool
+}
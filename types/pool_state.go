@@ -0,0 +1,59 @@
+package types
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+)
+
+// kinds of PoolStateUpdate
+const (
+	PoolStateCreate     = "create"
+	PoolStateInitialize = "initialize"
+	PoolStateSync       = "sync"
+	PoolStateSwap       = "swap"
+	PoolStateMint       = "mint"
+	PoolStateBurn       = "burn"
+)
+
+/*
+PoolStateUpdate is a pool log changing what a swap on the pool would return, see package quote.
+Token0 and Token1 are the tokens in the order of the pool, not of the pair.
+Which fields are set depends on the kind:
+create has Fee and TickSpacing of a v3 pool, sync the Reserves of a v2 pool,
+initialize and swap the SqrtPriceX96 and Tick, swap also the Liquidity in range,
+mint and burn the Liquidity added to or removed from TickLower..TickUpper.
+*/
+type PoolStateUpdate struct {
+	Kind         string
+	ProtocolId   int
+	Address      common.Address
+	Token0       common.Address
+	Token1       common.Address
+	LogIndex     uint
+	Reserve0     *big.Int
+	Reserve1     *big.Int
+	Fee          uint32
+	TickSpacing  int32
+	SqrtPriceX96 *big.Int
+	Liquidity    *big.Int
+	Tick         int32
+	TickLower    int32
+	TickUpper    int32
+}
+
+// NewPoolStateUpdate starts an update of the pair an event belongs to.
+func NewPoolStateUpdate(kind string, pair *Pair, logIndex uint) *PoolStateUpdate {
+	u := &PoolStateUpdate{
+		Kind:       kind,
+		ProtocolId: pair.ProtocolId,
+		Address:    pair.Address,
+		LogIndex:   logIndex,
+	}
+	if pair.Token0Core != nil && pair.Token1Core != nil {
+		u.Token0, u.Token1 = pair.Token0Core.Address, pair.Token1Core.Address
+		if pair.TokensReversed {
+			u.Token0, u.Token1 = u.Token1, u.Token0
+		}
+	}
+	return u
+}
@@ -0,0 +1,155 @@
+package types
+
+import (
+	"base_scan/repository/orm"
+	"encoding/binary"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+	"sort"
+	"time"
+)
+
+// kinds of PositionChange
+const (
+	PositionIncrease = "increase"
+	PositionDecrease = "decrease"
+	PositionCollect  = "collect"
+	PositionTransfer = "transfer"
+)
+
+/*
+LiquidityRange is the range of a v3 mint or burn, Owner is the one in the pool, usually a position manager.
+PositionId is the token id of the position manager, nil for liquidity owned directly.
+*/
+type LiquidityRange struct {
+	Owner      common.Address
+	TickLower  int32
+	TickUpper  int32
+	Liquidity  *big.Int
+	PositionId *big.Int
+}
+
+// NewLiquidityRange reads the owner and the ticks from the topics of a v3 Mint or Burn.
+func NewLiquidityRange(topics []common.Hash, liquidity *big.Int) *LiquidityRange {
+	return &LiquidityRange{
+		Owner:     common.BytesToAddress(topics[1].Bytes()[12:]),
+		TickLower: TickFromTopic(topics[2]),
+		TickUpper: TickFromTopic(topics[3]),
+		Liquidity: liquidity,
+	}
+}
+
+// TickFromTopic decodes an indexed int24.
+func TickFromTopic(topic common.Hash) int32 {
+	return int32(binary.BigEndian.Uint32(topic[28:]))
+}
+
+func (r *LiquidityRange) FillOrmTx(tx *orm.Tx) {
+	if r == nil {
+		return
+	}
+
+	tx.TickLower = r.TickLower
+	tx.TickUpper = r.TickUpper
+	tx.Liquidity = bigIntToDecimal(r.Liquidity)
+	if r.PositionId != nil {
+		tx.PositionId = r.PositionId.String()
+	}
+}
+
+// PositionIdSetter is implemented by the mint and burn events.
+type PositionIdSetter interface {
+	SetPositionId(positionId *big.Int)
+}
+
+/*
+PositionChange is an event of a position manager, with the pool and range of the pool log it belongs to.
+Transfers have no pool log, From and To are zero when the position is minted or burned.
+To is also the recipient of a collect.
+Liquidity and amounts are the ones of the event, Amount0 and Amount1 in wei.
+*/
+type PositionChange struct {
+	Kind         string
+	Manager      common.Address
+	TokenId      *big.Int
+	PoolAddress  common.Address
+	TickLower    int32
+	TickUpper    int32
+	Liquidity    *big.Int
+	Amount0      *big.Int
+	Amount1      *big.Int
+	From         common.Address
+	To           common.Address
+	Block        uint64
+	BlockAt      time.Time
+	TxHash       common.Hash
+	LogIndex     uint
+	PoolLogIndex uint
+}
+
+func (c *PositionChange) hasPool() bool {
+	return c.PoolAddress != (common.Address{})
+}
+
+/*
+MergePositionChanges sums the changes of one block by position, every field but the counters is the latest known.
+The result is a delta applied by the position repository, ordered by manager and token id.
+*/
+func MergePositionChanges(chainId uint64, changes []*PositionChange) []*orm.Position {
+	key2Position := make(map[[2]string]*orm.Position)
+	for _, change := range changes {
+		key := [2]string{change.Manager.String(), change.TokenId.String()}
+		position, ok := key2Position[key]
+		if !ok {
+			position = &orm.Position{
+				ChainId:      int(chainId),
+				Manager:      key[0],
+				TokenId:      key[1],
+				UpdatedBlock: change.Block,
+			}
+			key2Position[key] = position
+		}
+
+		if change.hasPool() {
+			position.PoolAddress = change.PoolAddress.String()
+			position.TickLower = change.TickLower
+			position.TickUpper = change.TickUpper
+		}
+
+		amount0, amount1 := bigIntToDecimal(change.Amount0), bigIntToDecimal(change.Amount1)
+		switch change.Kind {
+		case PositionIncrease:
+			position.Liquidity = position.Liquidity.Add(bigIntToDecimal(change.Liquidity))
+			position.Deposited0 = position.Deposited0.Add(amount0)
+			position.Deposited1 = position.Deposited1.Add(amount1)
+		case PositionDecrease:
+			position.Liquidity = position.Liquidity.Sub(bigIntToDecimal(change.Liquidity))
+			position.Withdrawn0 = position.Withdrawn0.Add(amount0)
+			position.Withdrawn1 = position.Withdrawn1.Add(amount1)
+		case PositionCollect:
+			position.Collected0 = position.Collected0.Add(amount0)
+			position.Collected1 = position.Collected1.Add(amount1)
+		case PositionTransfer:
+			position.Owner = change.To.String()
+			if change.From == (common.Address{}) {
+				position.CreatedBlock = change.Block
+			}
+			if change.To == (common.Address{}) {
+				position.Owner = ""
+				position.Burned = true
+			}
+		}
+	}
+
+	positions := make([]*orm.Position, 0, len(key2Position))
+	for _, position := range key2Position {
+		positions = append(positions, position)
+	}
+	sort.Slice(positions, func(i, j int) bool {
+		if positions[i].Manager != positions[j].Manager {
+			return positions[i].Manager < positions[j].Manager
+		}
+		return positions[i].TokenId < positions[j].TokenId
+	})
+	return positions
+}
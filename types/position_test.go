@@ -0,0 +1,60 @@
+package types
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+)
+
+func TestMergePositionChanges(t *testing.T) {
+	manager := common.HexToAddress("0x03a520b32C04BF3bEEf7BEb72E919cf822Ed34f1")
+	pool := common.HexToAddress("0x00000000000000000000000000000000000000f1")
+	owner := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	change := func(kind string, tokenId int64) *PositionChange {
+		return &PositionChange{
+			Kind:        kind,
+			Manager:     manager,
+			TokenId:     big.NewInt(tokenId),
+			PoolAddress: pool,
+			TickLower:   -600,
+			TickUpper:   600,
+			Liquidity:   big.NewInt(1000),
+			Amount0:     big.NewInt(10),
+			Amount1:     big.NewInt(20),
+			Block:       100,
+		}
+	}
+
+	mint := &PositionChange{Kind: PositionTransfer, Manager: manager, TokenId: big.NewInt(8), To: owner, Block: 100}
+	burn := &PositionChange{Kind: PositionTransfer, Manager: manager, TokenId: big.NewInt(7), From: owner, Block: 100}
+	positions := MergePositionChanges(8453, []*PositionChange{
+		change(PositionDecrease, 7),
+		change(PositionCollect, 7),
+		burn,
+		mint,
+		change(PositionIncrease, 8),
+		change(PositionIncrease, 8),
+	})
+	require.Len(t, positions, 2)
+
+	burned := positions[0]
+	require.Equal(t, "7", burned.TokenId)
+	require.Equal(t, 8453, burned.ChainId)
+	require.True(t, burned.Burned)
+	require.Empty(t, burned.Owner)
+	require.True(t, decimal.NewFromInt(-1000).Equal(burned.Liquidity))
+	require.True(t, decimal.NewFromInt(10).Equal(burned.Withdrawn0))
+	require.True(t, decimal.NewFromInt(20).Equal(burned.Collected1))
+	require.Zero(t, burned.CreatedBlock)
+
+	minted := positions[1]
+	require.Equal(t, "8", minted.TokenId)
+	require.Equal(t, owner.String(), minted.Owner)
+	require.Equal(t, pool.String(), minted.PoolAddress)
+	require.Equal(t, int32(-600), minted.TickLower)
+	require.Equal(t, uint64(100), minted.CreatedBlock)
+	require.True(t, decimal.NewFromInt(2000).Equal(minted.Liquidity))
+	require.True(t, decimal.NewFromInt(20).Equal(minted.Deposited0))
+}
@@ -1,21 +1,163 @@
 package types
 
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sync"
+)
+
 const (
 	ProtocolIdUniswapV2 = iota + 1
 	ProtocolIdUniswapV3
 	ProtocolIdPancakeV2
 	ProtocolIdPancakeV3
 	ProtocolIdAerodrome
+	// pools of a v2 or v3 fork found by their factory, see chain.conf discover_forks
+	ProtocolIdUniswapV2Fork
+	ProtocolIdUniswapV3Fork
+	// multi-asset pools, see Pool
+	ProtocolIdCurve
+	ProtocolIdBalancerV2
+	// bonding curve launchpads, see IsLaunchpad
+	ProtocolIdWow
 )
 
 const (
-	ProtocolNameUniswapV2 = "UniswapV2"
-	ProtocolNameUniswapV3 = "UniswapV3"
-	ProtocolNamePancakeV2 = "PancakeV2"
-	ProtocolNamePancakeV3 = "PancakeV3"
-	ProtocolNameAerodrome = "Aerodrome"
+	ProtocolNameUniswapV2     = "UniswapV2"
+	ProtocolNameUniswapV3     = "UniswapV3"
+	ProtocolNamePancakeV2     = "PancakeV2"
+	ProtocolNamePancakeV3     = "PancakeV3"
+	ProtocolNameAerodrome     = "Aerodrome"
+	ProtocolNameUniswapV2Fork = "UniswapV2Fork"
+	ProtocolNameUniswapV3Fork = "UniswapV3Fork"
+	ProtocolNameCurve         = "Curve"
+	ProtocolNameBalancerV2    = "BalancerV2"
+	ProtocolNameWow           = "Wow"
+)
+
+var (
+	ErrForkConflict = errors.New("fork conflicts with a known protocol")
 )
 
+/*
+Fork is a uniswap v2 or v3 fork declared in config, its pools emit the events of its Base protocol.
+FeePips is the swap fee of the pools of a v2 fork in hundredths of a bip, v3 pools have their own.
+*/
+type Fork struct {
+	Id      int
+	Name    string
+	Base    int
+	FeePips uint32
+}
+
+// the ids of forks start at forkProtocolIdMin
+const forkProtocolIdMin = 100
+
+var (
+	forksMu sync.RWMutex
+	forks   = make(map[int]*Fork)
+)
+
+// ForkProtocolId derives the id of a fork from its name, so that the ids cached with pairs don't depend on the config order.
+func ForkProtocolId(name string) int {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	return forkProtocolIdMin + int(h.Sum32()%1000000)
+}
+
+// RegisterFork makes a fork known to every pipeline of the process, registering the same fork again is allowed.
+func RegisterFork(name string, base int, feePips uint32) (*Fork, error) {
+	if getBuiltinProtocolId(name) != 0 {
+		return nil, fmt.Errorf("%w: %s is a builtin protocol", ErrForkConflict, name)
+	}
+	if base != ProtocolIdUniswapV2 && base != ProtocolIdUniswapV3 {
+		return nil, fmt.Errorf("%w: %s must fork %s or %s", ErrForkConflict, name, ProtocolNameUniswapV2, ProtocolNameUniswapV3)
+	}
+
+	fork := &Fork{Id: ForkProtocolId(name), Name: name, Base: base, FeePips: feePips}
+	forksMu.Lock()
+	defer forksMu.Unlock()
+	if registered, ok := forks[fork.Id]; ok && *registered != *fork {
+		return nil, fmt.Errorf("%w: %s and %s", ErrForkConflict, name, registered.Name)
+	}
+	forks[fork.Id] = fork
+	return fork, nil
+}
+
+func GetFork(protocolId int) (*Fork, bool) {
+	forksMu.RLock()
+	defer forksMu.RUnlock()
+	fork, ok := forks[protocolId]
+	return fork, ok
+}
+
+// BaseProtocolId returns the protocol whose events the pools of a protocol emit, the protocol itself unless it is a fork.
+func BaseProtocolId(protocolId int) int {
+	switch protocolId {
+	case ProtocolIdUniswapV2Fork:
+		return ProtocolIdUniswapV2
+	case ProtocolIdUniswapV3Fork:
+		return ProtocolIdUniswapV3
+	}
+	if fork, ok := GetFork(protocolId); ok {
+		return fork.Base
+	}
+	return protocolId
+}
+
+const (
+	ProtocolVersionV2 = 2
+	ProtocolVersionV3 = 3
+)
+
+/*
+ProtocolVersion is the pool design of a protocol: ProtocolVersionV2 for the constant product pools, aerodrome ones
+included, ProtocolVersionV3 for the concentrated liquidity pools, 0 for multi-asset pools and launchpad curves.
+*/
+func ProtocolVersion(protocolId int) int {
+	switch BaseProtocolId(protocolId) {
+	case ProtocolIdUniswapV2, ProtocolIdPancakeV2, ProtocolIdAerodrome:
+		return ProtocolVersionV2
+	case ProtocolIdUniswapV3, ProtocolIdPancakeV3:
+		return ProtocolVersionV3
+	default:
+		return 0
+	}
+}
+
+// v2FeePips are the swap fees of the constant product protocols, in hundredths of a bip
+var v2FeePips = map[int]uint32{
+	ProtocolIdUniswapV2: 3000,
+	ProtocolIdPancakeV2: 2500,
+}
+
+/*
+V2FeePips returns the swap fee of the v2 pools of a protocol, configured forks have their own fee,
+it is 0 for the pools of discovered forks since their fee is unknown
+*/
+func V2FeePips(protocolId int) uint32 {
+	if fork, ok := GetFork(protocolId); ok && fork.Base == ProtocolIdUniswapV2 {
+		return fork.FeePips
+	}
+	return v2FeePips[protocolId]
+}
+
+/*
+IsLaunchpad reports whether a protocol is a bonding curve launchpad.
+The pair of a launched token is its curve against the native token, it trades until the token graduates to a dex
+pool, see Launch. A launchpad is added with its protocol id, the abi and event parsers of its events, and its factory
+in the chain profile, the factory verifies the launches.
+*/
+func IsLaunchpad(protocolId int) bool {
+	switch protocolId {
+	case ProtocolIdWow:
+		return true
+	default:
+		return false
+	}
+}
+
 func GetProtocolName(protocolId int) string {
 	switch protocolId {
 	case ProtocolIdUniswapV2:
@@ -28,7 +170,57 @@ func GetProtocolName(protocolId int) string {
 		return ProtocolNamePancakeV3
 	case ProtocolIdAerodrome:
 		return ProtocolNameAerodrome
+	case ProtocolIdUniswapV2Fork:
+		return ProtocolNameUniswapV2Fork
+	case ProtocolIdUniswapV3Fork:
+		return ProtocolNameUniswapV3Fork
+	case ProtocolIdCurve:
+		return ProtocolNameCurve
+	case ProtocolIdBalancerV2:
+		return ProtocolNameBalancerV2
+	case ProtocolIdWow:
+		return ProtocolNameWow
 	default:
+		if fork, ok := GetFork(protocolId); ok {
+			return fork.Name
+		}
 		return "Unknown"
 	}
 }
+
+func getBuiltinProtocolId(protocolName string) int {
+	switch protocolName {
+	case ProtocolNameUniswapV2:
+		return ProtocolIdUniswapV2
+	case ProtocolNameUniswapV3:
+		return ProtocolIdUniswapV3
+	case ProtocolNamePancakeV2:
+		return ProtocolIdPancakeV2
+	case ProtocolNamePancakeV3:
+		return ProtocolIdPancakeV3
+	case ProtocolNameAerodrome:
+		return ProtocolIdAerodrome
+	case ProtocolNameUniswapV2Fork:
+		return ProtocolIdUniswapV2Fork
+	case ProtocolNameUniswapV3Fork:
+		return ProtocolIdUniswapV3Fork
+	case ProtocolNameCurve:
+		return ProtocolIdCurve
+	case ProtocolNameBalancerV2:
+		return ProtocolIdBalancerV2
+	case ProtocolNameWow:
+		return ProtocolIdWow
+	default:
+		return 0
+	}
+}
+
+func GetProtocolId(protocolName string) int {
+	if protocolId := getBuiltinProtocolId(protocolName); protocolId != 0 {
+		return protocolId
+	}
+	if fork, ok := GetFork(ForkProtocolId(protocolName)); ok && fork.Name == protocolName {
+		return fork.Id
+	}
+	return 0
+}
@@ -0,0 +1,57 @@
+package types
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestRegisterFork(t *testing.T) {
+	fork, err := RegisterFork("AlienBase", ProtocolIdUniswapV2, 1600)
+	require.NoError(t, err)
+	require.Equal(t, ForkProtocolId("AlienBase"), fork.Id)
+	require.GreaterOrEqual(t, fork.Id, forkProtocolIdMin)
+	require.Equal(t, fork.Id, GetProtocolId("AlienBase"))
+	require.Equal(t, "AlienBase", GetProtocolName(fork.Id))
+	require.Equal(t, ProtocolIdUniswapV2, BaseProtocolId(fork.Id))
+
+	_, err = RegisterFork("AlienBase", ProtocolIdUniswapV2, 1600)
+	require.NoError(t, err)
+	_, err = RegisterFork("AlienBase", ProtocolIdUniswapV3, 0)
+	require.ErrorIs(t, err, ErrForkConflict)
+	_, err = RegisterFork(ProtocolNameUniswapV3, ProtocolIdUniswapV3, 0)
+	require.ErrorIs(t, err, ErrForkConflict)
+	_, err = RegisterFork("AeroFork", ProtocolIdAerodrome, 0)
+	require.ErrorIs(t, err, ErrForkConflict)
+
+	require.Equal(t, ProtocolIdUniswapV3, BaseProtocolId(ProtocolIdUniswapV3Fork))
+	require.Equal(t, ProtocolIdPancakeV2, BaseProtocolId(ProtocolIdPancakeV2))
+	require.Equal(t, 0, GetProtocolId("SwapBased"))
+	require.Equal(t, "Unknown", GetProtocolName(ForkProtocolId("SwapBased")))
+}
+
+func TestTxPairEventForks(t *testing.T) {
+	fork, err := RegisterFork("SwapBased", ProtocolIdUniswapV2, 3000)
+	require.NoError(t, err)
+
+	tpe := &TxPairEvent{}
+	tpe.AddEvent(&EventCommon{Pair: &Pair{ProtocolId: fork.Id}})
+	tpe.AddEvent(&EventCommon{Pair: &Pair{ProtocolId: ProtocolIdUniswapV2Fork}})
+	tpe.AddEvent(&EventCommon{Pair: &Pair{ProtocolId: ForkProtocolId("Unregistered")}})
+	require.Len(t, tpe.Forks, 2)
+	require.Empty(t, tpe.UniswapV2)
+}
+
+func TestProtocolVersionAndV2Fee(t *testing.T) {
+	fork, err := RegisterFork("BaseSwap", ProtocolIdUniswapV2, 2500)
+	require.NoError(t, err)
+
+	require.Equal(t, ProtocolVersionV2, ProtocolVersion(ProtocolIdAerodrome))
+	require.Equal(t, ProtocolVersionV2, ProtocolVersion(fork.Id))
+	require.Equal(t, ProtocolVersionV3, ProtocolVersion(ProtocolIdUniswapV3Fork))
+	require.Equal(t, 0, ProtocolVersion(ProtocolIdCurve))
+
+	require.Equal(t, uint32(3000), V2FeePips(ProtocolIdUniswapV2))
+	require.Equal(t, uint32(2500), V2FeePips(fork.Id))
+	require.Equal(t, uint32(0), V2FeePips(ProtocolIdUniswapV2Fork))
+	require.Equal(t, uint32(0), V2FeePips(ProtocolIdUniswapV3))
+}
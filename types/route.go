@@ -0,0 +1,70 @@
+package types
+
+import (
+	"base_scan/repository/orm"
+	"github.com/shopspring/decimal"
+	"sort"
+)
+
+/*
+Route is the swaps of one tx, or of one user operation of a bundle, in log order.
+Router is the router or aggregator the tx was sent to, Beneficiary the one of the last hop.
+AmountUsd is the largest hop, every hop of a route trades about the same value.
+*/
+type Route struct {
+	Block       uint64
+	BlockIndex  uint
+	TxHash      string
+	UserOpHash  string
+	Maker       string
+	Router      string
+	Beneficiary string
+	Pairs       []string
+	Hops        int
+	AmountUsd   decimal.Decimal
+}
+
+// NewRoutes aggregates the swaps among txs into routes, ordered by position in block.
+func NewRoutes(txs []*orm.Tx) []*Route {
+	swaps := make([]*orm.Tx, 0, len(txs))
+	for _, tx := range txs {
+		if tx.Event == Buy || tx.Event == Sell {
+			swaps = append(swaps, tx)
+		}
+	}
+	sort.Slice(swaps, func(i, j int) bool {
+		if swaps[i].BlockIndex != swaps[j].BlockIndex {
+			return swaps[i].BlockIndex < swaps[j].BlockIndex
+		}
+		return swaps[i].TxIndex < swaps[j].TxIndex
+	})
+
+	routes := make([]*Route, 0)
+	key2Route := make(map[[2]string]*Route)
+	for _, swap := range swaps {
+		key := [2]string{swap.TxHash, swap.UserOpHash}
+		route, ok := key2Route[key]
+		if !ok {
+			route = &Route{
+				Block:      swap.Block,
+				BlockIndex: swap.BlockIndex,
+				TxHash:     swap.TxHash,
+				UserOpHash: swap.UserOpHash,
+				Maker:      swap.Maker,
+				Router:     swap.Router,
+				Pairs:      make([]string, 0, 2),
+				AmountUsd:  decimal.Zero,
+			}
+			key2Route[key] = route
+			routes = append(routes, route)
+		}
+
+		route.Pairs = append(route.Pairs, swap.PairAddress)
+		route.Hops++
+		route.Beneficiary = swap.Beneficiary
+		if swap.AmountUsd.GreaterThan(route.AmountUsd) {
+			route.AmountUsd = swap.AmountUsd
+		}
+	}
+	return routes
+}
@@ -0,0 +1,30 @@
+package types
+
+import (
+	"base_scan/repository/orm"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestNewRoutes(t *testing.T) {
+	txs := []*orm.Tx{
+		{TxHash: "0x2", Event: Buy, BlockIndex: 2, TxIndex: 9, PairAddress: "0xb", Beneficiary: "0xuser", Router: "odos", AmountUsd: decimal.NewFromInt(99)},
+		{TxHash: "0x2", Event: Sell, BlockIndex: 2, TxIndex: 5, PairAddress: "0xa", Beneficiary: "0xb", Router: "odos", AmountUsd: decimal.NewFromInt(100)},
+		{TxHash: "0x2", Event: Add, BlockIndex: 2, TxIndex: 12, PairAddress: "0xc"},
+		{TxHash: "0x1", Event: Buy, BlockIndex: 1, TxIndex: 1, PairAddress: "0xa", UserOpHash: "0xop1"},
+		{TxHash: "0x1", Event: Buy, BlockIndex: 1, TxIndex: 3, PairAddress: "0xa", UserOpHash: "0xop2"},
+	}
+
+	routes := NewRoutes(txs)
+	require.Len(t, routes, 3)
+	require.Equal(t, "0xop1", routes[0].UserOpHash)
+	require.Equal(t, "0xop2", routes[1].UserOpHash)
+
+	route := routes[2]
+	require.Equal(t, "odos", route.Router)
+	require.Equal(t, []string{"0xa", "0xb"}, route.Pairs)
+	require.Equal(t, 2, route.Hops)
+	require.Equal(t, "0xuser", route.Beneficiary)
+	require.True(t, decimal.NewFromInt(100).Equal(route.AmountUsd))
+}
@@ -0,0 +1,45 @@
+package types
+
+import (
+	"base_scan/repository/orm"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+/*
+SwapParties are the addresses in the topics of a swap event, Sender is the caller of the pool, usually a router,
+and Recipient receives the output.
+Beneficiary is the final receiver of a multi-hop route, it is zero when the output stays with the router,
+which then forwards it to the maker.
+*/
+type SwapParties struct {
+	Sender      common.Address
+	Recipient   common.Address
+	Beneficiary common.Address
+}
+
+// BeneficiarySetter is implemented by the events of swaps.
+type BeneficiarySetter interface {
+	SetBeneficiary(beneficiary common.Address)
+}
+
+func SwapPartiesFromTopics(topics []common.Hash) SwapParties {
+	return SwapParties{
+		Sender:    common.BytesToAddress(topics[1].Bytes()[12:]),
+		Recipient: common.BytesToAddress(topics[2].Bytes()[12:]),
+	}
+}
+
+func (p *SwapParties) SetBeneficiary(beneficiary common.Address) {
+	p.Beneficiary = beneficiary
+}
+
+// FillOrmTx sets the parties of a trade, tx.Maker must be set.
+func (p *SwapParties) FillOrmTx(tx *orm.Tx) {
+	tx.Sender = p.Sender.String()
+	tx.Recipient = p.Recipient.String()
+	if p.Beneficiary != (common.Address{}) {
+		tx.Beneficiary = p.Beneficiary.String()
+	} else {
+		tx.Beneficiary = tx.Maker
+	}
+}
@@ -1,7 +1,6 @@
 package types
 
 import (
-	"base_scan/chain"
 	"base_scan/repository/orm"
 	"encoding/json"
 	"github.com/ethereum/go-ethereum/common"
@@ -9,49 +8,46 @@ import (
 	"time"
 )
 
+// base tokens of the base chain, registered by default
 const (
-	WETH_USDC_PAIR = "0x88A43bbDF9D098eEC7bCEda4e2494615dfD9bB9C" // Uniswap v2 WETH/USDC pair
-	WETH           = "0x4200000000000000000000000000000000000006"
-	USDC           = "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913"
+	WETH = "0x4200000000000000000000000000000000000006"
+	USDC = "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913"
 )
 
 var (
-	WETHUSDCPairAddressUniswapV2 = common.HexToAddress(WETH_USDC_PAIR)
-	WETHAddress                  = common.HexToAddress(WETH)
-	USDCAddress                  = common.HexToAddress(USDC)
+	WETHAddress = common.HexToAddress(WETH)
+	USDCAddress = common.HexToAddress(USDC)
 )
 
 func IsSameAddress(address1, address2 common.Address) bool {
 	return address1.Cmp(address2) == 0
 }
 
-func IsWETH(address common.Address) bool {
-	return IsSameAddress(address, WETHAddress)
-}
-
-func IsUSDC(address common.Address) bool {
-	return IsSameAddress(address, USDCAddress)
-}
+/*
+TokenSchemaVersion is the version of the cached token value written by MarshalBinary.
+Bump it together with a migration in cache/migration whenever the cached fields change.
+*/
+const TokenSchemaVersion = 2
 
-func IsBaseToken(address common.Address) bool {
-	if IsWETH(address) || IsUSDC(address) {
-		return true
-	}
-	return false
-}
+const (
+	TokenFilterCodeGetDecimals = iota + 1
+)
 
 type Token struct {
-	Address     common.Address `json:"-"`
-	Creator     common.Address `json:"-"`
-	Name        string
-	Symbol      string
-	Decimals    int8
-	TotalSupply decimal.Decimal
-	BlockNumber uint64
-	BlockTime   time.Time
-	Program     string
-	Filtered    bool
-	Timestamp   time.Time
+	Address      common.Address `json:"-"`
+	Creator      common.Address `json:"-"`
+	Name         string
+	Symbol       string
+	Decimals     int8
+	TotalSupply  decimal.Decimal
+	BlockNumber  uint64
+	BlockTime    time.Time
+	Program      string
+	Filtered     bool
+	FilterCode   int
+	FilteredAt   time.Time
+	FilterReason string
+	Timestamp    time.Time
 }
 
 func (t *Token) MarshalBinary() ([]byte, error) {
@@ -59,10 +55,12 @@ func (t *Token) MarshalBinary() ([]byte, error) {
 	return json.Marshal(&struct {
 		AddressString string `json:"Address"`
 		CreatorString string `json:"Creator"`
+		SchemaVersion int
 		*Alias
 	}{
 		AddressString: t.Address.String(),
 		CreatorString: t.Creator.String(),
+		SchemaVersion: TokenSchemaVersion,
 		Alias:         (*Alias)(t),
 	})
 }
@@ -110,7 +108,22 @@ func (t *Token) Equal(token *Token) bool {
 	return true
 }
 
-func (t *Token) GetOrmToken() *orm.Token {
+func (t *Token) Filter(filterCode int, filterReason string) {
+	t.Filtered = true
+	t.FilterCode = filterCode
+	t.FilteredAt = time.Now()
+	t.FilterReason = filterReason
+}
+
+/*
+FilterExpired reports whether a filtered token should be checked again.
+ttl 0 means the filter never expires.
+*/
+func (t *Token) FilterExpired(ttl time.Duration) bool {
+	return t.Filtered && ttl > 0 && time.Since(t.FilteredAt) > ttl
+}
+
+func (t *Token) GetOrmToken(chainId uint64) *orm.Token {
 	ormToken := &orm.Token{
 		Address:     t.Address.String(),
 		Creator:     t.Creator.String(),
@@ -118,7 +131,7 @@ func (t *Token) GetOrmToken() *orm.Token {
 		Symbol:      t.Symbol,
 		Decimal:     t.Decimals,
 		TotalSupply: t.TotalSupply.String(),
-		ChainId:     chain.Id,
+		ChainId:     int(chainId),
 		Block:       t.BlockNumber,
 		BlockAt:     t.BlockTime,
 		Program:     t.Program,
@@ -126,3 +139,22 @@ func (t *Token) GetOrmToken() *orm.Token {
 
 	return ormToken.Normalize()
 }
+
+func NewTokenFromOrm(ormToken *orm.Token) *Token {
+	totalSupply, err := decimal.NewFromString(ormToken.TotalSupply)
+	if err != nil {
+		totalSupply = decimal.Zero
+	}
+
+	return &Token{
+		Address:     common.HexToAddress(ormToken.Address),
+		Creator:     common.HexToAddress(ormToken.Creator),
+		Name:        ormToken.Name,
+		Symbol:      ormToken.Symbol,
+		Decimals:    ormToken.Decimal,
+		TotalSupply: totalSupply,
+		BlockNumber: ormToken.Block,
+		BlockTime:   ormToken.BlockAt,
+		Program:     ormToken.Program,
+	}
+}
@@ -0,0 +1,106 @@
+package types
+
+import (
+	"base_scan/repository/orm"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/shopspring/decimal"
+	"math/big"
+)
+
+/*
+TxMeta is what the trades of a tx share besides the maker.
+PriorityFee is the tip per gas actually paid, effective gas price minus the block base fee.
+The l1 fields are only set in receipts of op stack chains:
+- L1GasUsed is deprecated since fjord
+- L1BaseFeeScalar, L1BlobBaseFeeScalar and L1BlobBaseFee are set since ecotone
+Deposit txs pay no l1 fee.
+Router is set by the block parser, see package router.
+*/
+type TxMeta struct {
+	ChainId             uint64
+	Type                uint8
+	IsDeposit           bool
+	Nonce               uint64
+	To                  *common.Address
+	Router              string
+	GasUsed             uint64
+	EffectiveGasPrice   *big.Int
+	PriorityFee         *big.Int
+	L1Fee               *big.Int
+	L1GasUsed           *big.Int
+	L1GasPrice          *big.Int
+	L1BlobBaseFee       *big.Int
+	L1BaseFeeScalar     *uint64
+	L1BlobBaseFeeScalar *uint64
+}
+
+func NewTxMeta(chainId uint64, tx *ethtypes.Transaction, receipt *ethtypes.Receipt, baseFee *big.Int) *TxMeta {
+	return &TxMeta{
+		ChainId:             chainId,
+		Type:                tx.Type(),
+		IsDeposit:           tx.IsDepositTx(),
+		Nonce:               tx.Nonce(),
+		To:                  tx.To(),
+		GasUsed:             receipt.GasUsed,
+		EffectiveGasPrice:   receipt.EffectiveGasPrice,
+		PriorityFee:         priorityFee(receipt.EffectiveGasPrice, baseFee),
+		L1Fee:               receipt.L1Fee,
+		L1GasUsed:           receipt.L1GasUsed,
+		L1GasPrice:          receipt.L1GasPrice,
+		L1BlobBaseFee:       receipt.L1BlobBaseFee,
+		L1BaseFeeScalar:     receipt.L1BaseFeeScalar,
+		L1BlobBaseFeeScalar: receipt.L1BlobBaseFeeScalar,
+	}
+}
+
+// priorityFee is nil before london, deposit txs pay no gas price and get zero.
+func priorityFee(effectiveGasPrice, baseFee *big.Int) *big.Int {
+	if effectiveGasPrice == nil || baseFee == nil {
+		return nil
+	}
+
+	fee := new(big.Int).Sub(effectiveGasPrice, baseFee)
+	if fee.Sign() < 0 {
+		return new(big.Int)
+	}
+	return fee
+}
+
+func bigIntToDecimal(v *big.Int) decimal.Decimal {
+	if v == nil {
+		return decimal.Zero
+	}
+	return decimal.NewFromBigInt(v, 0)
+}
+
+func uint64PtrToUint64(v *uint64) uint64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
+// FillOrmTx copies the meta into a trade, m can be nil for events parsed outside a block.
+func (m *TxMeta) FillOrmTx(tx *orm.Tx) {
+	if m == nil {
+		return
+	}
+
+	tx.ChainId = int(m.ChainId)
+	tx.TxType = m.Type
+	tx.Nonce = m.Nonce
+	if m.To != nil {
+		tx.ToAddress = m.To.String()
+	}
+	tx.Router = m.Router
+	tx.GasUsed = m.GasUsed
+	tx.EffectiveGasPrice = bigIntToDecimal(m.EffectiveGasPrice)
+	tx.PriorityFee = bigIntToDecimal(m.PriorityFee)
+	tx.L1Fee = bigIntToDecimal(m.L1Fee)
+	tx.L1GasUsed = bigIntToDecimal(m.L1GasUsed)
+	tx.L1GasPrice = bigIntToDecimal(m.L1GasPrice)
+	tx.L1BlobBaseFee = bigIntToDecimal(m.L1BlobBaseFee)
+	tx.L1BaseFeeScalar = uint64PtrToUint64(m.L1BaseFeeScalar)
+	tx.L1BlobBaseFeeScalar = uint64PtrToUint64(m.L1BlobBaseFeeScalar)
+}
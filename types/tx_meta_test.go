@@ -0,0 +1,82 @@
+package types
+
+import (
+	"base_scan/repository/orm"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+)
+
+func TestGetTxSenderDeposit(t *testing.T) {
+	from := common.HexToAddress("0xDeaDDEaDDeAdDeAdDEAdDEaddeAddEAdDEAd0001")
+	depositTx := ethtypes.NewTx(&ethtypes.DepositTx{
+		SourceHash: common.HexToHash("0x01"),
+		From:       from,
+		Gas:        1000000,
+		Value:      big.NewInt(0),
+	})
+
+	header := &ethtypes.Header{Number: big.NewInt(100), Time: 1000}
+	for _, chainConfig := range []*params.ChainConfig{params.OptimismTestConfig, params.MainnetChainConfig} {
+		c := &ParseBlockContext{
+			ChainConfig:      chainConfig,
+			Block:            ethtypes.NewBlockWithHeader(header).WithBody(ethtypes.Body{Transactions: []*ethtypes.Transaction{depositTx}}),
+			HeightTime:       GetBlockHeightTime(header),
+			TxIndex2TxSender: make(map[uint]common.Address),
+		}
+
+		sender, err := c.GetTxSender(0)
+		require.NoError(t, err)
+		require.Equal(t, from, sender)
+
+		txMeta := c.GetTxMeta(&ethtypes.Receipt{TransactionIndex: 0})
+		require.True(t, txMeta.IsDeposit)
+		require.Equal(t, uint8(ethtypes.DepositTxType), txMeta.Type)
+		require.Equal(t, chainConfig.ChainID.Uint64(), txMeta.ChainId)
+	}
+}
+
+func TestPriorityFee(t *testing.T) {
+	require.Nil(t, priorityFee(big.NewInt(10), nil))
+	require.Equal(t, big.NewInt(3), priorityFee(big.NewInt(10), big.NewInt(7)))
+	// deposit txs have zero effective gas price
+	require.Equal(t, new(big.Int), priorityFee(new(big.Int), big.NewInt(7)))
+}
+
+func TestTxMetaFillOrmTx(t *testing.T) {
+	baseFeeScalar, blobBaseFeeScalar := uint64(2269), uint64(1055762)
+	to := common.HexToAddress("0x6fF5693b99212Da76ad316178A184AB56D299b43")
+	txMeta := NewTxMeta(8453, ethtypes.NewTx(&ethtypes.DynamicFeeTx{Nonce: 9, To: &to}), &ethtypes.Receipt{
+		GasUsed:             150000,
+		EffectiveGasPrice:   big.NewInt(1_000_300),
+		L1Fee:               big.NewInt(123),
+		L1GasPrice:          big.NewInt(456),
+		L1BlobBaseFee:       big.NewInt(7),
+		L1BaseFeeScalar:     &baseFeeScalar,
+		L1BlobBaseFeeScalar: &blobBaseFeeScalar,
+	}, big.NewInt(1_000_000))
+
+	tx := &orm.Tx{}
+	txMeta.FillOrmTx(tx)
+	require.Equal(t, 8453, tx.ChainId)
+	require.Equal(t, uint8(ethtypes.DynamicFeeTxType), tx.TxType)
+	require.Equal(t, uint64(9), tx.Nonce)
+	require.Equal(t, to.String(), tx.ToAddress)
+	require.Equal(t, uint64(150000), tx.GasUsed)
+	require.True(t, decimal.NewFromInt(1_000_300).Equal(tx.EffectiveGasPrice))
+	require.True(t, decimal.NewFromInt(300).Equal(tx.PriorityFee))
+	require.True(t, decimal.NewFromInt(123).Equal(tx.L1Fee))
+	require.True(t, decimal.NewFromInt(456).Equal(tx.L1GasPrice))
+	require.True(t, decimal.NewFromInt(7).Equal(tx.L1BlobBaseFee))
+	require.True(t, tx.L1GasUsed.IsZero())
+	require.Equal(t, baseFeeScalar, tx.L1BaseFeeScalar)
+	require.Equal(t, blobBaseFeeScalar, tx.L1BlobBaseFeeScalar)
+
+	// events parsed outside a block have no meta
+	var nilMeta *TxMeta
+	nilMeta.FillOrmTx(tx)
+}
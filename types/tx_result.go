@@ -12,6 +12,12 @@ type TxPairEvent struct {
 	PancakeV2 []Event
 	PancakeV3 []Event
 	Aerodrome []Event
+	// events of the pools of uniswap v2 and v3 forks
+	Forks []Event
+	// events of the curve and balancer pools
+	Pools []Event
+	// events of the bonding curves of launchpads
+	Launchpads []Event
 }
 
 func (tpe *TxPairEvent) AddEvent(event Event) {
@@ -41,6 +47,24 @@ func (tpe *TxPairEvent) AddEvent(event Event) {
 			tpe.Aerodrome = make([]Event, 0, 10)
 		}
 		tpe.Aerodrome = append(tpe.Aerodrome, event)
+	case ProtocolIdCurve, ProtocolIdBalancerV2:
+		if tpe.Pools == nil {
+			tpe.Pools = make([]Event, 0, 10)
+		}
+		tpe.Pools = append(tpe.Pools, event)
+	case ProtocolIdWow:
+		if tpe.Launchpads == nil {
+			tpe.Launchpads = make([]Event, 0, 10)
+		}
+		tpe.Launchpads = append(tpe.Launchpads, event)
+	default:
+		if BaseProtocolId(event.GetProtocolId()) == event.GetProtocolId() {
+			return
+		}
+		if tpe.Forks == nil {
+			tpe.Forks = make([]Event, 0, 10)
+		}
+		tpe.Forks = append(tpe.Forks, event)
 	}
 }
 
@@ -50,6 +74,7 @@ func (tpe *TxPairEvent) LinkEvents() {
 	tpe.linkEventByProtocol(tpe.PancakeV2)
 	tpe.linkEventByProtocol(tpe.PancakeV3)
 	tpe.linkEventByProtocol(tpe.Aerodrome)
+	tpe.linkEventByProtocol(tpe.Forks)
 }
 
 func LinkPairCreatedEventAndMintEvent(pairCreatedEvents, mintEvents []Event) {
@@ -76,22 +101,35 @@ func (tpe *TxPairEvent) linkEventByProtocol(events []Event) {
 	LinkPairCreatedEventAndMintEvent(pairCreatedEvents, mintEvents)
 }
 
+/*
+TxResult collects the events of one tx.
+Maker is the tx sender, which is the bundler for the events of user operations, their maker is the smart account.
+*/
 type TxResult struct {
 	Maker                   common.Address
+	TxMeta                  *TxMeta
+	UserOperations          UserOperations
 	PairCreatedEvents       []Event
 	PairAddress2TxPairEvent map[common.Address]*TxPairEvent
 }
 
-func NewTxResult(maker common.Address) *TxResult {
+func NewTxResult(maker common.Address, txMeta *TxMeta, userOperations UserOperations) *TxResult {
 	return &TxResult{
 		Maker:                   maker,
+		TxMeta:                  txMeta,
+		UserOperations:          userOperations,
 		PairCreatedEvents:       make([]Event, 0, 10),
 		PairAddress2TxPairEvent: make(map[common.Address]*TxPairEvent),
 	}
 }
 
-func (tr *TxResult) AddEvent(event Event) {
+func (tr *TxResult) AddEvent(event Event, logIndex uint) {
 	event.SetMaker(tr.Maker)
+	event.SetTxMeta(tr.TxMeta)
+	if userOperation := tr.UserOperations.Of(logIndex); userOperation != nil {
+		event.SetMaker(userOperation.Sender)
+		event.SetUserOperation(userOperation)
+	}
 	if event.IsCreatePair() {
 		tr.PairCreatedEvents = append(tr.PairCreatedEvents, event)
 	}
@@ -0,0 +1,57 @@
+package types
+
+import (
+	"base_scan/repository/orm"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+)
+
+/*
+UserOperation is an ERC-4337 user operation executed by an EntryPoint in a bundle tx.
+The EntryPoint executes the operations of a bundle one by one and emits the UserOperationEvent of each right after it,
+so the logs of an operation are the ones after the previous operation of the same EntryPoint, see FirstLogIndex.
+*/
+type UserOperation struct {
+	Hash          common.Hash
+	EntryPoint    common.Address
+	Sender        common.Address
+	Paymaster     common.Address
+	Bundler       common.Address
+	Nonce         *big.Int
+	Success       bool
+	ActualGasCost *big.Int
+	ActualGasUsed *big.Int
+	FirstLogIndex uint
+	LogIndex      uint
+}
+
+// Contains reports if a log of the tx was emitted by the execution of the operation.
+func (op *UserOperation) Contains(logIndex uint) bool {
+	return logIndex >= op.FirstLogIndex && logIndex < op.LogIndex
+}
+
+// FillOrmTx sets the bundler and the operation of a trade, op is nil for trades outside user operations.
+func (op *UserOperation) FillOrmTx(tx *orm.Tx) {
+	if op == nil {
+		return
+	}
+
+	tx.Bundler = op.Bundler.String()
+	tx.UserOpHash = op.Hash.String()
+	if op.Paymaster != (common.Address{}) {
+		tx.Paymaster = op.Paymaster.String()
+	}
+}
+
+// UserOperations are the user operations of one tx.
+type UserOperations []*UserOperation
+
+// Of returns the operation that emitted a log, or nil.
+func (ops UserOperations) Of(logIndex uint) *UserOperation {
+	for _, op := range ops {
+		if op.Contains(logIndex) {
+			return op
+		}
+	}
+	return nil
+}